@@ -7,12 +7,14 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"block-flow/internal/api"
+	"block-flow/internal/api/middleware"
 	"block-flow/internal/config"
 	"block-flow/internal/engine"
+	"block-flow/internal/models"
 	"block-flow/internal/storage"
+	"block-flow/pkg/logger"
 )
 
 func main() {
@@ -27,26 +29,38 @@ func main() {
 
 	log.Printf("Server will listen on %s", cfg.Server.Address)
 
-	// Initialize storage
-	storage := storage.NewFileStorage(cfg.Storage.DataDir)
-
-	// Initialize flow engine with simple logger
-	logger := &engine.SimpleLogger{}
-	flowEngine := engine.New(storage, logger)
+	// Decode JSON numbers before any flow or message is loaded
+	models.SetPreserveNumbers(cfg.Engine.PreserveJSONNumbers)
 
-	// Load and start existing flows on startup
-	ctx := context.Background()
-	if err := flowEngine.LoadAndStartFlows(ctx); err != nil {
-		log.Printf("Warning: Failed to load existing flows: %v", err)
+	// Initialize storage
+	var backend storage.Storage = storage.NewFileStorage(cfg.Storage.DataDir)
+	if cfg.Storage.Backend == "memory" {
+		log.Println("Using in-memory storage: flows and settings are lost on restart")
+		backend = storage.NewMemoryStorage()
+	}
+	storage := storage.NewRetryingStorage(
+		backend,
+		cfg.Storage.RetryAttempts,
+		cfg.Storage.RetryBackoff,
+	)
+
+	// Initialize flow engine with the structured logger, masking secrets
+	logger.Init(cfg.Logging.Level, cfg.Logging.Format)
+	if len(cfg.Logging.RedactFields) > 0 {
+		logger.SetRedactPatterns(cfg.Logging.RedactFields)
 	}
+	flowEngine := engine.New(storage, cfg.Engine, logger.New())
 
 	// Initialize API router
-	router := api.NewRouter(flowEngine, storage)
+	var handler http.Handler = api.NewRouter(flowEngine, storage)
+	if cfg.Server.WaitForReady {
+		handler = middleware.RequireReady(flowEngine.Ready, "/api/v1/health", "/api/v1/ready")(handler)
+	}
 
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         cfg.Server.Address,
-		Handler:      router,
+		Handler:      handler,
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.IdleTimeout,
@@ -60,6 +74,21 @@ func main() {
 		}
 	}()
 
+	// Flows load while the server is up, so /ready answers 503 until they're
+	// started. Register plugin blocks before flows referencing them are started
+	if _, err := flowEngine.LoadPlugins(cfg.Storage.PluginsDir); err != nil {
+		log.Printf("Warning: Failed to load plugins: %v", err)
+	}
+
+	// Load and start existing flows on startup
+	ctx := context.Background()
+	if err := flowEngine.LoadAndStartFlows(ctx); err != nil {
+		log.Printf("Warning: Failed to load existing flows: %v", err)
+	}
+
+	// Purge flows that stayed in the trash past the retention
+	flowEngine.StartJanitor(cfg.Storage.TrashRetention, cfg.Storage.JanitorInterval)
+
 	log.Println("Server started successfully. Press Ctrl+C to stop.")
 
 	// Wait for interrupt signal to gracefully shutdown the server
@@ -69,17 +98,27 @@ func main() {
 
 	log.Println("Server is shutting down...")
 
-	// Give the server 30 seconds to finish handling existing requests
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	// Give running flows time to drain
+	engineCtx, cancelEngine := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancelEngine()
 
 	// Shutdown flow engine
-	flowEngine.Shutdown(ctx)
+	engineErr := flowEngine.Shutdown(engineCtx)
+
+	// Give the server time to finish handling existing requests
+	serverCtx, cancelServer := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancelServer()
 
 	// Shutdown HTTP server
-	if err := server.Shutdown(ctx); err != nil {
+	if err := server.Shutdown(serverCtx); err != nil {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
 
+	// Nodes ignoring the stop signal would keep the process alive
+	if engineErr != nil {
+		log.Printf("Forcing exit: %v", engineErr)
+		os.Exit(1)
+	}
+
 	log.Println("Server exited")
 }
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"block-flow/internal/engine"
+)
+
+// AdminHandler handles administrative HTTP requests
+type AdminHandler struct {
+	engine *engine.Engine
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(engine *engine.Engine) *AdminHandler {
+	return &AdminHandler{
+		engine: engine,
+	}
+}
+
+// ReloadBlocks handles POST /api/v1/admin/blocks/reload
+func (h *AdminHandler) ReloadBlocks(w http.ResponseWriter, r *http.Request) {
+	results, added, err := h.engine.ReloadPlugins()
+	if err != nil {
+		http.Error(w, "Failed to reload plugins: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"plugins": results,
+		"added":   added,
+		"failed":  failed,
+	})
+}
+
+// GetMetrics handles GET /api/v1/metrics
+func (h *AdminHandler) GetMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.engine.GetMetrics())
+}
+
+// GetEventBus handles GET /api/v1/admin/eventbus
+func (h *AdminHandler) GetEventBus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.engine.GetEventBus().Stats())
+}
@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"block-flow/internal/engine"
+	"block-flow/internal/models"
+	"block-flow/internal/storage"
+
+	"github.com/gorilla/mux"
+)
+
+// Kinds of records in an NDJSON export
+const (
+	recordFlow      = "flow"
+	recordExecution = "execution"
+)
+
+// backupRecord is a single line of an NDJSON export
+type backupRecord struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+// BackupHandler handles exporting and importing stored data
+type BackupHandler struct {
+	engine  *engine.Engine
+	storage storage.Storage
+}
+
+// NewBackupHandler creates a new backup handler
+func NewBackupHandler(engine *engine.Engine, storage storage.Storage) *BackupHandler {
+	return &BackupHandler{
+		engine:  engine,
+		storage: storage,
+	}
+}
+
+// Export handles GET /api/v1/export. Records are loaded and written one at a
+// time, so the export is never held in memory as a whole
+func (h *BackupHandler) Export(w http.ResponseWriter, r *http.Request) {
+	includeExecutions := r.URL.Query().Get("include") == "executions"
+
+	flowIDs, err := h.storage.ListFlowIDs(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to list flows", http.StatusInternalServerError)
+		return
+	}
+
+	var executionIDs []string
+	if includeExecutions {
+		executionIDs, err = h.storage.ListFlowExecutionIDs(r.Context())
+		if err != nil {
+			http.Error(w, "Failed to list executions", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	encoder := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+
+	write := func(kind string, value interface{}) bool {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return true // Skip records that can't be encoded
+		}
+		if err := encoder.Encode(backupRecord{Kind: kind, Data: data}); err != nil {
+			return false // Client went away
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return true
+	}
+
+	for _, flowID := range flowIDs {
+		flow, err := h.storage.LoadFlow(r.Context(), flowID)
+		if err != nil {
+			continue // Deleted or unreadable since listing
+		}
+		if !write(recordFlow, flow) {
+			return
+		}
+	}
+
+	for _, executionID := range executionIDs {
+		execution, err := h.storage.LoadFlowExecution(r.Context(), executionID)
+		if err != nil {
+			continue
+		}
+		if !write(recordExecution, execution) {
+			return
+		}
+	}
+}
+
+// Import handles POST /api/v1/import. Each NDJSON record is validated and
+// upserted on its own; failing records are reported and skipped.
+//
+// With ?remap=true the flows are imported under new IDs and references
+// between them are rewritten. The whole stream is then read before anything
+// is saved, so every flow is remapped together and executions can be
+// attached to the remapped flows wherever they appear in the stream
+func (h *BackupHandler) Import(w http.ResponseWriter, r *http.Request) {
+	remap := r.URL.Query().Get("remap") == "true"
+
+	flows, executions := 0, 0
+	failures := make([]string, 0)
+	var unresolved []string
+	var mapping map[string]string
+
+	// Records held back until the stream is read, when remapping
+	type pendingFlow struct {
+		line int
+		flow *models.Flow
+	}
+	type pendingExecution struct {
+		line int
+		data json.RawMessage
+	}
+	var pendingFlows []pendingFlow
+	var pendingExecutions []pendingExecution
+
+	decoder := json.NewDecoder(r.Body)
+	for line := 1; ; line++ {
+		var record backupRecord
+		if err := decoder.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			// The stream can't be resynchronized after a syntax error
+			failures = append(failures, fmt.Sprintf("record %d: invalid JSON: %v", line, err))
+			break
+		}
+
+		switch record.Kind {
+		case recordFlow:
+			flow, err := decodeFlow(record.Data)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("record %d: %v", line, err))
+				continue
+			}
+			if remap {
+				pendingFlows = append(pendingFlows, pendingFlow{line: line, flow: flow})
+				continue
+			}
+			if err := h.saveFlow(r, flow); err != nil {
+				failures = append(failures, fmt.Sprintf("record %d: %v", line, err))
+				continue
+			}
+			flows++
+		case recordExecution:
+			if remap {
+				pendingExecutions = append(pendingExecutions, pendingExecution{line: line, data: record.Data})
+				continue
+			}
+			if err := h.importExecution(r, record.Data, nil); err != nil {
+				failures = append(failures, fmt.Sprintf("record %d: %v", line, err))
+				continue
+			}
+			executions++
+		default:
+			failures = append(failures, fmt.Sprintf("record %d: unknown kind '%s'", line, record.Kind))
+		}
+	}
+
+	if remap {
+		imported := make([]*models.Flow, len(pendingFlows))
+		for i, p := range pendingFlows {
+			imported[i] = p.flow
+		}
+		mapping, unresolved = h.engine.RemapFlowIDs(r.Context(), imported)
+
+		for _, p := range pendingFlows {
+			if err := h.saveFlow(r, p.flow); err != nil {
+				failures = append(failures, fmt.Sprintf("record %d: %v", p.line, err))
+				continue
+			}
+			flows++
+		}
+		for _, p := range pendingExecutions {
+			if err := h.importExecution(r, p.data, mapping); err != nil {
+				failures = append(failures, fmt.Sprintf("record %d: %v", p.line, err))
+				continue
+			}
+			executions++
+		}
+	}
+
+	result := map[string]interface{}{
+		"flows":      flows,
+		"executions": executions,
+		"failed":     failures,
+	}
+	if remap {
+		result["remapped"] = mapping
+		result["unresolved"] = unresolved
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// ExportFlow handles GET /api/v1/flows/{id}/export. The flow is returned as
+// a bundle that POST /api/v1/flows/import accepts on any instance
+func (h *BackupHandler) ExportFlow(w http.ResponseWriter, r *http.Request) {
+	flowID := mux.Vars(r)["id"]
+
+	flow, err := h.storage.LoadFlow(r.Context(), flowID)
+	if err != nil {
+		if storage.IsNotFound(err) {
+			http.Error(w, "Flow not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to load flow: "+err.Error(), http.StatusServiceUnavailable)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.flow.json"`, flowID))
+	w.Header().Set("X-Block-Flow-Bundle-Version", strconv.Itoa(models.FlowBundleVersion))
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(models.NewFlowBundle(flow))
+}
+
+// ImportFlows handles POST /api/v1/flows/import. It accepts a bundle from
+// ExportFlow, a flow or an array of flows. Flows whose ID is already taken
+// are imported under a new ID, with references between the imported flows
+// rewritten; each flow is validated and saved on its own, failing flows are
+// reported and skipped
+func (h *BackupHandler) ImportFlows(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	flows, err := models.DecodeFlowBundle(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(flows) == 0 {
+		http.Error(w, "Bundle contains no flows", http.StatusBadRequest)
+		return
+	}
+
+	failures := make([]string, 0)
+	accepted := make([]*models.Flow, 0, len(flows))
+	seen := make(map[string]bool, len(flows))
+	for i, flow := range flows {
+		switch {
+		case flow == nil:
+			failures = append(failures, fmt.Sprintf("flow %d: empty", i+1))
+		case flow.ID == "":
+			// Flows written by hand may leave the ID to the server
+			flow.ID = models.NewID()
+			accepted = append(accepted, flow)
+		case seen[flow.ID]:
+			failures = append(failures, fmt.Sprintf("flow %d: duplicate id '%s' in bundle", i+1, flow.ID))
+		default:
+			seen[flow.ID] = true
+			accepted = append(accepted, flow)
+		}
+	}
+
+	mapping, unresolved := h.engine.RemapConflictingFlowIDs(r.Context(), accepted)
+
+	imported := make([]*models.Flow, 0, len(accepted))
+	for _, flow := range accepted {
+		if err := h.saveFlow(r, flow); err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		imported = append(imported, flow)
+	}
+
+	status := http.StatusCreated
+	if len(imported) == 0 {
+		status = http.StatusBadRequest
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"imported":   imported,
+		"remapped":   mapping,
+		"unresolved": unresolved,
+		"failed":     failures,
+	})
+}
+
+// decodeFlow decodes a single exported flow
+func decodeFlow(data json.RawMessage) (*models.Flow, error) {
+	var flow models.Flow
+	if err := models.DecodeJSON(data, &flow); err != nil {
+		return nil, fmt.Errorf("invalid flow: %w", err)
+	}
+	if flow.ID == "" {
+		return nil, fmt.Errorf("flow has no id")
+	}
+	return &flow, nil
+}
+
+// saveFlow validates and saves a single imported flow
+func (h *BackupHandler) saveFlow(r *http.Request, flow *models.Flow) error {
+	flow.Normalize()
+	if err := flow.Validate(); err != nil {
+		return fmt.Errorf("flow '%s' validation failed: %w", flow.ID, err)
+	}
+	if err := h.engine.CheckFlowSize(flow); err != nil {
+		return fmt.Errorf("flow '%s' too large: %w", flow.ID, err)
+	}
+
+	if err := h.storage.SaveFlow(r.Context(), flow); err != nil {
+		return fmt.Errorf("failed to save flow '%s': %w", flow.ID, err)
+	}
+	return nil
+}
+
+// importExecution saves a single exported execution. When flows were
+// remapped, the execution gets a new ID and follows its flow's new ID
+func (h *BackupHandler) importExecution(r *http.Request, data json.RawMessage, mapping map[string]string) error {
+	var execution models.FlowExecution
+	if err := json.Unmarshal(data, &execution); err != nil {
+		return fmt.Errorf("invalid execution: %w", err)
+	}
+	if execution.ID == "" {
+		return fmt.Errorf("execution has no id")
+	}
+	if mapping != nil {
+		execution.ID = models.NewID()
+		if flowID, remapped := mapping[execution.FlowID]; remapped {
+			execution.FlowID = flowID
+		}
+	}
+
+	if err := h.storage.SaveFlowExecution(r.Context(), &execution); err != nil {
+		return fmt.Errorf("failed to save execution '%s': %w", execution.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"block-flow/internal/config"
+	"block-flow/internal/engine"
+	"block-flow/internal/models"
+	"block-flow/internal/storage"
+)
+
+func TestImportRemap(t *testing.T) {
+	const (
+		flowA     = `{"kind":"flow","data":{"id":"flow-a","name":"a","nodes":[]}}`
+		flowB     = `{"kind":"flow","data":{"id":"flow-b","name":"b","nodes":[]}}`
+		execution = `{"kind":"execution","data":{"id":"exec-1","flow_id":"flow-b","status":"completed"}}`
+	)
+
+	tests := []struct {
+		name    string
+		records []string
+	}{
+		{name: "flows first", records: []string{flowA, flowB, execution}},
+		{name: "execution first", records: []string{execution, flowA, flowB}},
+		{name: "interleaved", records: []string{flowA, execution, flowB}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := storage.NewMemoryStorage()
+			h := NewBackupHandler(engine.New(store, config.EngineConfig{}, nopLogger{}), store)
+
+			body := strings.Join(tt.records, "\n")
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/import?remap=true", strings.NewReader(body))
+			rec := httptest.NewRecorder()
+			h.Import(rec, req)
+
+			var result struct {
+				Flows      int               `json:"flows"`
+				Executions int               `json:"executions"`
+				Failed     []string          `json:"failed"`
+				Remapped   map[string]string `json:"remapped"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if result.Flows != 2 || result.Executions != 1 || len(result.Failed) != 0 {
+				t.Fatalf("imported %d flows and %d executions, failures %v", result.Flows, result.Executions, result.Failed)
+			}
+
+			for _, oldID := range []string{"flow-a", "flow-b"} {
+				newID, ok := result.Remapped[oldID]
+				if !ok {
+					t.Fatalf("flow %s not remapped", oldID)
+				}
+				if store.FlowExists(ctx, oldID) || !store.FlowExists(ctx, newID) {
+					t.Errorf("flow %s not stored under its new ID %s only", oldID, newID)
+				}
+			}
+			executions, err := store.LoadFlowExecutions(ctx, result.Remapped["flow-b"])
+			if err != nil {
+				t.Fatalf("load executions: %v", err)
+			}
+			if len(executions) != 1 || executions[0].ID == "exec-1" {
+				t.Errorf("execution not attached to the remapped flow under a new ID: %v", executions)
+			}
+		})
+	}
+}
+
+// flushRecorder records the body written before each flush
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushed []string // Body at each flush
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushed = append(r.flushed, r.Body.String())
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := storage.NewMemoryStorage()
+	flowA, flowB := models.NewFlow("a"), models.NewFlow("b")
+	for _, flow := range []*models.Flow{flowA, flowB} {
+		if err := source.SaveFlow(ctx, flow); err != nil {
+			t.Fatalf("save flow: %v", err)
+		}
+	}
+	execution := &models.FlowExecution{ID: "exec-1", FlowID: flowB.ID, Status: models.ExecutionStatusCompleted}
+	if err := source.SaveFlowExecution(ctx, execution); err != nil {
+		t.Fatalf("save execution: %v", err)
+	}
+
+	exporter := NewBackupHandler(engine.New(source, config.EngineConfig{}, nopLogger{}), source)
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	exporter.Export(rec, httptest.NewRequest(http.MethodGet, "/api/v1/export?include=executions", nil))
+
+	if contentType := rec.Header().Get("Content-Type"); contentType != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q, want application/x-ndjson", contentType)
+	}
+	// Streamed: every record is flushed on its own as soon as it is written
+	if len(rec.flushed) != 3 {
+		t.Fatalf("export flushed %d times, want once per record", len(rec.flushed))
+	}
+	for i, body := range rec.flushed {
+		if lines := strings.Count(body, "\n"); lines != i+1 {
+			t.Errorf("flush %d sent %d records, want %d", i+1, lines, i+1)
+		}
+	}
+
+	target := storage.NewMemoryStorage()
+	importer := NewBackupHandler(engine.New(target, config.EngineConfig{}, nopLogger{}), target)
+	importRec := httptest.NewRecorder()
+	importer.Import(importRec, httptest.NewRequest(http.MethodPost, "/api/v1/import", strings.NewReader(rec.Body.String())))
+
+	var result struct {
+		Flows      int      `json:"flows"`
+		Executions int      `json:"executions"`
+		Failed     []string `json:"failed"`
+	}
+	if err := json.NewDecoder(importRec.Body).Decode(&result); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if result.Flows != 2 || result.Executions != 1 || len(result.Failed) != 0 {
+		t.Fatalf("imported %d flows and %d executions, failures %v", result.Flows, result.Executions, result.Failed)
+	}
+
+	for _, want := range []*models.Flow{flowA, flowB} {
+		got, err := target.LoadFlow(ctx, want.ID)
+		if err != nil {
+			t.Fatalf("load imported flow %s: %v", want.Name, err)
+		}
+		if got.Name != want.Name || !got.CreatedAt.Equal(want.CreatedAt) {
+			t.Errorf("imported flow = %s created %s, want %s created %s", got.Name, got.CreatedAt, want.Name, want.CreatedAt)
+		}
+	}
+	got, err := target.LoadFlowExecution(ctx, execution.ID)
+	if err != nil {
+		t.Fatalf("load imported execution: %v", err)
+	}
+	if got.FlowID != flowB.ID || got.Status != execution.Status {
+		t.Errorf("imported execution = %+v, want flow %s with status %s", got, flowB.ID, execution.Status)
+	}
+}
@@ -1,8 +1,15 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"time"
 
 	"block-flow/internal/engine"
 	"block-flow/internal/models"
@@ -27,20 +34,86 @@ func NewFlowHandler(engine *engine.Engine, storage storage.Storage) *FlowHandler
 
 // ListFlows handles GET /api/v1/flows
 func (h *FlowHandler) ListFlows(w http.ResponseWriter, r *http.Request) {
-	flows, err := h.storage.LoadAllFlows(r.Context())
+	query, err := parseFlowQuery(r)
 	if err != nil {
-		http.Error(w, "Failed to load flows", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.URL.Query().Get("summary") == "true" {
+		h.listFlowSummaries(w, r, query)
+		return
+	}
+
+	flows, total, err := h.storage.ListFlows(r.Context(), query)
+	if err != nil {
+		http.Error(w, "Failed to load flows: "+err.Error(), http.StatusServiceUnavailable)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
 	json.NewEncoder(w).Encode(flows)
 }
 
+// listFlowSummaries answers a flow listing with the flows' metadata only,
+// filtered and paged like full flows
+func (h *FlowHandler) listFlowSummaries(w http.ResponseWriter, r *http.Request, query storage.FlowQuery) {
+	summaries, err := h.storage.ListFlowSummaries(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load flows: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	page := make([]*models.FlowSummary, 0)
+	total := 0
+	for _, summary := range summaries {
+		if !query.Selects(summary.Name, summary.Active) {
+			continue
+		}
+		if query.InPage(total) {
+			page = append(page, summary)
+		}
+		total++
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	json.NewEncoder(w).Encode(page)
+}
+
+// parseFlowQuery reads the limit, offset, active and q parameters of a flow
+// listing
+func parseFlowQuery(r *http.Request) (storage.FlowQuery, error) {
+	params := r.URL.Query()
+	query := storage.FlowQuery{Name: params.Get("q")}
+
+	for name, target := range map[string]*int{"limit": &query.Limit, "offset": &query.Offset} {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 0 {
+			return query, fmt.Errorf("%s must be a non-negative integer", name)
+		}
+		*target = number
+	}
+
+	if value := params.Get("active"); value != "" {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			return query, fmt.Errorf("active must be true or false")
+		}
+		query.Active = &active
+	}
+	return query, nil
+}
+
 // CreateFlow handles POST /api/v1/flows
 func (h *FlowHandler) CreateFlow(w http.ResponseWriter, r *http.Request) {
 	var flow models.Flow
-	if err := json.NewDecoder(r.Body).Decode(&flow); err != nil {
+	if err := models.NewDecoder(r.Body).Decode(&flow); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
@@ -50,11 +123,18 @@ func (h *FlowHandler) CreateFlow(w http.ResponseWriter, r *http.Request) {
 		flow = *models.NewFlow(flow.Name)
 	}
 
+	// Sync wires with connections (connections win) before validating
+	flow.Normalize()
+
 	// Validate flow
 	if err := flow.Validate(); err != nil {
 		http.Error(w, "Flow validation failed: "+err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := h.engine.CheckFlowSize(&flow); err != nil {
+		http.Error(w, "Flow too large: "+err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
 
 	// Save flow
 	if err := h.storage.SaveFlow(r.Context(), &flow); err != nil {
@@ -74,7 +154,11 @@ func (h *FlowHandler) GetFlow(w http.ResponseWriter, r *http.Request) {
 
 	flow, err := h.storage.LoadFlow(r.Context(), flowID)
 	if err != nil {
-		http.Error(w, "Flow not found", http.StatusNotFound)
+		if storage.IsNotFound(err) {
+			http.Error(w, "Flow not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to load flow: "+err.Error(), http.StatusServiceUnavailable)
+		}
 		return
 	}
 
@@ -88,7 +172,7 @@ func (h *FlowHandler) UpdateFlow(w http.ResponseWriter, r *http.Request) {
 	flowID := vars["id"]
 
 	var flow models.Flow
-	if err := json.NewDecoder(r.Body).Decode(&flow); err != nil {
+	if err := models.NewDecoder(r.Body).Decode(&flow); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
@@ -96,11 +180,121 @@ func (h *FlowHandler) UpdateFlow(w http.ResponseWriter, r *http.Request) {
 	// Ensure ID matches
 	flow.ID = flowID
 
+	// Sync wires with connections (connections win) before validating
+	flow.Normalize()
+
+	// Validate flow
+	if err := flow.Validate(); err != nil {
+		http.Error(w, "Flow validation failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.engine.CheckFlowSize(&flow); err != nil {
+		http.Error(w, "Flow too large: "+err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// Save flow
+	if err := h.storage.SaveFlow(r.Context(), &flow); err != nil {
+		http.Error(w, "Failed to save flow", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&flow)
+}
+
+// PatchFlow handles PATCH /api/v1/flows/{id}. The body is a JSON Patch
+// (application/json-patch+json, RFC 6902) or a merge patch
+// (application/merge-patch+json or application/json, RFC 7396) applied to
+// the stored flow document
+func (h *FlowHandler) PatchFlow(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = ""
+	}
+	var patch func(doc interface{}) (interface{}, error)
+	switch mediaType {
+	case "application/json-patch+json":
+		var operations []models.PatchOperation
+		if err := models.NewDecoder(r.Body).Decode(&operations); err != nil {
+			http.Error(w, "Invalid JSON Patch", http.StatusBadRequest)
+			return
+		}
+		patch = func(doc interface{}) (interface{}, error) {
+			return models.ApplyJSONPatch(doc, operations)
+		}
+	case "application/merge-patch+json", "application/json":
+		var mergePatch interface{}
+		if err := models.NewDecoder(r.Body).Decode(&mergePatch); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		patch = func(doc interface{}) (interface{}, error) {
+			return models.MergePatch(doc, mergePatch), nil
+		}
+	default:
+		http.Error(w, "Content-Type must be application/json-patch+json or application/merge-patch+json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	stored, err := h.storage.LoadFlow(r.Context(), flowID)
+	if err != nil {
+		if storage.IsNotFound(err) {
+			http.Error(w, "Flow not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to load flow: "+err.Error(), http.StatusServiceUnavailable)
+		}
+		return
+	}
+
+	// Patch the flow's JSON document, then decode the result as a flow
+	data, err := json.Marshal(stored)
+	if err != nil {
+		http.Error(w, "Failed to encode flow", http.StatusInternalServerError)
+		return
+	}
+	var doc interface{}
+	if err := models.DecodeJSON(data, &doc); err != nil {
+		http.Error(w, "Failed to encode flow", http.StatusInternalServerError)
+		return
+	}
+	patched, err := patch(doc)
+	if err != nil {
+		status := http.StatusUnprocessableEntity
+		if errors.Is(err, models.ErrPatchTestFailed) {
+			status = http.StatusConflict
+		}
+		http.Error(w, "Failed to apply patch: "+err.Error(), status)
+		return
+	}
+	if data, err = json.Marshal(patched); err != nil {
+		http.Error(w, "Failed to encode patched flow", http.StatusInternalServerError)
+		return
+	}
+	var flow models.Flow
+	if err := models.DecodeJSON(data, &flow); err != nil {
+		http.Error(w, "Patched document is not a valid flow: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Ensure ID matches
+	flow.ID = flowID
+
+	// Sync wires with connections (connections win) before validating
+	flow.Normalize()
+
 	// Validate flow
 	if err := flow.Validate(); err != nil {
 		http.Error(w, "Flow validation failed: "+err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := h.engine.CheckFlowSize(&flow); err != nil {
+		http.Error(w, "Flow too large: "+err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
 
 	// Save flow
 	if err := h.storage.SaveFlow(r.Context(), &flow); err != nil {
@@ -112,7 +306,8 @@ func (h *FlowHandler) UpdateFlow(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(&flow)
 }
 
-// DeleteFlow handles DELETE /api/v1/flows/{id}
+// DeleteFlow handles DELETE /api/v1/flows/{id}. The flow is moved to the
+// trash, from where it can be restored; ?permanent=true deletes it outright
 func (h *FlowHandler) DeleteFlow(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	flowID := vars["id"]
@@ -121,20 +316,111 @@ func (h *FlowHandler) DeleteFlow(w http.ResponseWriter, r *http.Request) {
 	h.engine.StopFlow(r.Context(), flowID)
 
 	// Delete flow
-	if err := h.storage.DeleteFlow(r.Context(), flowID); err != nil {
-		http.Error(w, "Failed to delete flow", http.StatusInternalServerError)
+	var err error
+	if r.URL.Query().Get("permanent") == "true" {
+		err = h.storage.DeleteFlow(r.Context(), flowID)
+	} else {
+		err = h.storage.TrashFlow(r.Context(), flowID)
+	}
+	if err != nil {
+		if storage.IsNotFound(err) {
+			http.Error(w, "Flow not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to delete flow", http.StatusInternalServerError)
+		}
 		return
 	}
+	h.engine.ReleaseFlow(flowID)
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// ListTrash handles GET /api/v1/flows/trash
+func (h *FlowHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	trashed, err := h.storage.ListTrash(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to list trash: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trashed)
+}
+
+// RestoreFlow handles POST /api/v1/flows/{id}/restore. The restored flow is
+// not started
+func (h *FlowHandler) RestoreFlow(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+
+	flow, err := h.storage.RestoreFlow(r.Context(), flowID)
+	if err != nil {
+		switch {
+		case storage.IsNotFound(err):
+			http.Error(w, "Flow not found in trash", http.StatusNotFound)
+		case errors.Is(err, storage.ErrExists):
+			http.Error(w, "A flow with this ID already exists", http.StatusConflict)
+		default:
+			http.Error(w, "Failed to restore flow: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	// A flow trashed as raw bytes is stored again but has nothing to show
+	if flow == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(flow)
+}
+
+// DuplicateFlow handles POST /api/v1/flows/{id}/duplicate. It saves a copy of
+// the flow under new flow, node and connection IDs, named "<name> (copy)" and
+// inactive
+func (h *FlowHandler) DuplicateFlow(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+
+	flow, err := h.storage.LoadFlow(r.Context(), flowID)
+	if err != nil {
+		if storage.IsNotFound(err) {
+			http.Error(w, "Flow not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to load flow: "+err.Error(), http.StatusServiceUnavailable)
+		}
+		return
+	}
+
+	// Wires and connections are rewritten together, so sync them first
+	flow.Normalize()
+	h.engine.RemapNodeReferences(flow, flow.RemapNodeIDs())
+	h.engine.RemapFlowIDs(r.Context(), []*models.Flow{flow})
+	flow.Name += " (copy)"
+	flow.Active = false
+	flow.CreatedAt = time.Now()
+	flow.UpdatedAt = flow.CreatedAt
+
+	if err := h.storage.SaveFlow(r.Context(), flow); err != nil {
+		http.Error(w, "Failed to save flow", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(flow)
+}
+
 // StartFlow handles POST /api/v1/flows/{id}/run
 func (h *FlowHandler) StartFlow(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	flowID := vars["id"]
 
-	if err := h.engine.StartFlow(r.Context(), flowID); err != nil {
+	start := h.engine.StartFlow
+	if r.URL.Query().Get("paused") == "true" {
+		start = h.engine.StartFlowPaused
+	}
+	if err := start(r.Context(), flowID); err != nil {
 		http.Error(w, "Failed to start flow: "+err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -159,25 +445,173 @@ func (h *FlowHandler) StopFlow(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "stopped"})
 }
 
+// EnableFlow handles POST /api/v1/flows/{id}/enable
+func (h *FlowHandler) EnableFlow(w http.ResponseWriter, r *http.Request) {
+	h.setFlowActive(w, r, h.engine.EnableFlow, "enable")
+}
+
+// DisableFlow handles POST /api/v1/flows/{id}/disable
+func (h *FlowHandler) DisableFlow(w http.ResponseWriter, r *http.Request) {
+	h.setFlowActive(w, r, h.engine.DisableFlow, "disable")
+}
+
+// setFlowActive answers an enable or disable request with the saved flow
+func (h *FlowHandler) setFlowActive(w http.ResponseWriter, r *http.Request, apply func(context.Context, string) (*models.Flow, error), action string) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+
+	flow, err := apply(r.Context(), flowID)
+	if err != nil {
+		if storage.IsNotFound(err) {
+			http.Error(w, "Flow not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to "+action+" flow: "+err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(flow)
+}
+
+// PauseInput handles POST /api/v1/flows/{id}/input/pause
+func (h *FlowHandler) PauseInput(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+
+	if err := h.engine.PauseInputs(r.Context(), flowID); err != nil {
+		http.Error(w, "Failed to pause inputs: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "input_paused"})
+}
+
+// ResumeInput handles POST /api/v1/flows/{id}/input/resume
+func (h *FlowHandler) ResumeInput(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+
+	if err := h.engine.ResumeInputs(r.Context(), flowID); err != nil {
+		http.Error(w, "Failed to resume inputs: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "input_resumed"})
+}
+
 // TriggerFlow handles POST /api/v1/flows/{id}/trigger
 func (h *FlowHandler) TriggerFlow(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	flowID := vars["id"]
 
+	// Without a body the input nodes fire themselves; a body is the message
+	// they emit instead
+	inputs := []*models.Message{nil}
 	var input models.Message
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		// If no input provided, create a default message
-		input = *models.NewMessage(map[string]interface{}{"trigger": true})
+	if err := json.NewDecoder(r.Body).Decode(&input); err == nil {
+		inputs[0] = &input
+
+		// With ?expand=true an array payload triggers once per element
+		if r.URL.Query().Get("expand") == "true" {
+			inputs = input.Expand()
+		}
 	}
 
-	if err := h.engine.TriggerFlow(r.Context(), flowID, &input); err != nil {
+	if err := h.engine.TriggerFlow(r.Context(), flowID, inputs...); err != nil {
+		if errors.Is(err, engine.ErrTriggerQueueFull) {
+			http.Error(w, "Failed to trigger flow: "+err.Error(), http.StatusTooManyRequests)
+			return
+		}
 		http.Error(w, "Failed to trigger flow: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "triggered"})
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":   "triggered",
+		"triggers": len(inputs),
+	})
+}
+
+// TriggerNode handles POST /api/v1/flows/{id}/nodes/{nodeID}/trigger
+func (h *FlowHandler) TriggerNode(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+	nodeID := vars["nodeID"]
+
+	messages, err := h.engine.TriggerNode(r.Context(), flowID, nodeID)
+	if err != nil {
+		http.Error(w, "Failed to trigger node: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":   "triggered",
+		"messages": messages,
+	})
+}
+
+// breakpointRequest is the optional body of POST
+// /api/v1/flows/{id}/nodes/{nodeID}/breakpoint; without it the breakpoint is toggled
+type breakpointRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// SetBreakpoint handles POST /api/v1/flows/{id}/nodes/{nodeID}/breakpoint
+func (h *FlowHandler) SetBreakpoint(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+	nodeID := vars["nodeID"]
+
+	var req breakpointRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+	}
+
+	enabled, err := h.engine.SetBreakpoint(flowID, nodeID, req.Enabled)
+	if err != nil {
+		http.Error(w, "Failed to set breakpoint: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"flow_id": flowID,
+		"node_id": nodeID,
+		"enabled": enabled,
+	})
+}
+
+// StepBreakpoint handles POST /api/v1/flows/{id}/nodes/{nodeID}/step
+func (h *FlowHandler) StepBreakpoint(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+	nodeID := vars["nodeID"]
+
+	msg, err := h.engine.StepBreakpoint(flowID, nodeID)
+	if err != nil {
+		if errors.Is(err, engine.ErrNotAtBreakpoint) {
+			http.Error(w, "Failed to step: "+err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed to step: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "released",
+		"message": msg,
+	})
 }
 
 // GetFlowStatus handles GET /api/v1/flows/{id}/status
@@ -194,3 +628,170 @@ func (h *FlowHandler) GetFlowStatus(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(status)
 }
+
+// GetFlowSummary handles GET /api/v1/flows/{id}/summary
+func (h *FlowHandler) GetFlowSummary(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+
+	summary, err := h.engine.GetFlowSummary(r.Context(), flowID)
+	if err != nil {
+		if storage.IsNotFound(err) {
+			http.Error(w, "Flow not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to summarize flow: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// CloneNode handles POST /api/v1/flows/{id}/nodes/{nodeID}/clone
+func (h *FlowHandler) CloneNode(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+	nodeID := vars["nodeID"]
+
+	flow, err := h.storage.LoadFlow(r.Context(), flowID)
+	if err != nil {
+		http.Error(w, "Flow not found", http.StatusNotFound)
+		return
+	}
+
+	clone, exists := flow.CloneNode(nodeID)
+	if !exists {
+		http.Error(w, "Node not found", http.StatusNotFound)
+		return
+	}
+	if err := h.engine.CheckFlowSize(flow); err != nil {
+		http.Error(w, "Flow too large: "+err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if err := h.storage.SaveFlow(r.Context(), flow); err != nil {
+		http.Error(w, "Failed to save flow", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(clone)
+}
+
+// GetEffectiveProperties handles GET /api/v1/flows/{id}/nodes/{nodeID}/effective-properties
+func (h *FlowHandler) GetEffectiveProperties(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+	nodeID := vars["nodeID"]
+
+	properties, err := h.engine.GetEffectiveProperties(r.Context(), flowID, nodeID)
+	if err != nil {
+		http.Error(w, "Failed to resolve properties: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"flow_id":    flowID,
+		"node_id":    nodeID,
+		"properties": properties,
+	})
+}
+
+// GetDeadLetters handles GET /api/v1/flows/{id}/dead-letters
+func (h *FlowHandler) GetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+
+	deadLetters, err := h.engine.GetDeadLetters(flowID)
+	if err != nil {
+		http.Error(w, "Flow not running", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deadLetters)
+}
+
+// debugRequest is the body of POST /api/v1/flows/{id}/debug
+type debugRequest struct {
+	Enabled     bool   `json:"enabled"`
+	Duration    string `json:"duration,omitempty"` // Go duration, e.g. "2m"
+	MaxMessages int    `json:"max_messages,omitempty"`
+	MaxBytes    int    `json:"max_bytes,omitempty"`
+}
+
+// SetDebug handles POST /api/v1/flows/{id}/debug
+func (h *FlowHandler) SetDebug(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+
+	var req debugRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	var duration time.Duration
+	if req.Duration != "" {
+		parsed, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			http.Error(w, "Invalid duration: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		duration = parsed
+	}
+
+	status, err := h.engine.SetDebugRecording(flowID, req.Enabled, duration, req.MaxMessages, req.MaxBytes)
+	if err != nil {
+		http.Error(w, "Flow not running", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// GetDebugMessages handles GET /api/v1/flows/{id}/debug/messages
+func (h *FlowHandler) GetDebugMessages(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+
+	status, messages, err := h.engine.GetDebugMessages(flowID)
+	if err != nil {
+		http.Error(w, "Flow not running", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"recording": status,
+		"messages":  messages,
+	})
+}
+
+// ValidateConnection handles POST /api/v1/flows/{id}/connections/validate
+func (h *FlowHandler) ValidateConnection(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	flowID := vars["id"]
+
+	var conn models.Connection
+	if err := json.NewDecoder(r.Body).Decode(&conn); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	reasons, err := h.engine.ValidateConnection(r.Context(), flowID, conn)
+	if err != nil {
+		http.Error(w, "Flow not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"valid":   len(reasons) == 0,
+		"reasons": reasons,
+	})
+}
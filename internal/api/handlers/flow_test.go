@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"block-flow/internal/config"
+	"block-flow/internal/engine"
+	"block-flow/internal/models"
+	"block-flow/internal/storage"
+
+	"github.com/gorilla/mux"
+)
+
+// nopLogger discards engine logs
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, map[string]interface{}) {}
+func (nopLogger) Info(string, map[string]interface{})  {}
+func (nopLogger) Warn(string, map[string]interface{})  {}
+func (nopLogger) Error(string, map[string]interface{}) {}
+
+func TestFlowSizeLimit(t *testing.T) {
+	store := storage.NewFileStorage(t.TempDir())
+	h := NewFlowHandler(engine.New(store, config.EngineConfig{MaxFlowNodes: 2}, nopLogger{}), store)
+
+	small := `{"id":"flow-1","name":"small","nodes":[{"id":"in","type":"inject"},{"id":"out","type":"debug"}]}`
+	large := `{"id":"flow-1","name":"large","nodes":[{"id":"in","type":"inject"},{"id":"f","type":"add"},{"id":"out","type":"debug"}]}`
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/flows", strings.NewReader(large))
+	rec := httptest.NewRecorder()
+	h.CreateFlow(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("create over the cap = %d, want 413", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "3 nodes, exceeding the limit of 2") {
+		t.Errorf("error = %q, want the node count and limit", rec.Body)
+	}
+	if _, err := store.LoadFlow(context.Background(), "flow-1"); err == nil {
+		t.Error("oversized flow saved")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/flows", strings.NewReader(small))
+	rec = httptest.NewRecorder()
+	h.CreateFlow(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create within the cap = %d: %s", rec.Code, rec.Body)
+	}
+
+	// Growing a saved flow past the cap is rejected as well
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/flows/flow-1", strings.NewReader(large))
+	req = mux.SetURLVars(req, map[string]string{"id": "flow-1"})
+	rec = httptest.NewRecorder()
+	h.UpdateFlow(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("update over the cap = %d, want 413", rec.Code)
+	}
+	saved, err := store.LoadFlow(context.Background(), "flow-1")
+	if err != nil {
+		t.Fatalf("load flow: %v", err)
+	}
+	if len(saved.Nodes) != 2 {
+		t.Errorf("saved flow has %d nodes after a rejected update, want 2", len(saved.Nodes))
+	}
+}
+
+func TestValidateConnection(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	flow := &models.Flow{
+		ID:   "flow-1",
+		Name: "validate",
+		Nodes: []models.Node{
+			{ID: "in", Type: "inject"},
+			{ID: "f1", Type: "add"},
+			{ID: "f2", Type: "add"},
+			{ID: "out", Type: "debug"},
+		},
+		Connections: []models.Connection{
+			{ID: "c1", Source: "in", Target: "f1"},
+			{ID: "c2", Source: "f1", Target: "f2"},
+			{ID: "c3", Source: "f2", Target: "out"},
+		},
+	}
+	if err := store.SaveFlow(context.Background(), flow); err != nil {
+		t.Fatalf("save flow: %v", err)
+	}
+	h := NewFlowHandler(engine.New(store, config.EngineConfig{}, nopLogger{}), store)
+
+	tests := []struct {
+		name       string
+		flowID     string
+		body       string
+		wantStatus int
+		wantValid  bool
+		wantReason string // Substring of the only reason
+	}{
+		{name: "valid edge", flowID: "flow-1", body: `{"source":"f1","target":"out"}`, wantStatus: http.StatusOK, wantValid: true},
+		{name: "out-of-range port", flowID: "flow-1", body: `{"source":"in","source_port":3,"target":"out"}`, wantStatus: http.StatusOK, wantReason: "source port 3 is out of range"},
+		{name: "cycle", flowID: "flow-1", body: `{"source":"f2","target":"f1"}`, wantStatus: http.StatusOK, wantReason: "would create a cycle"},
+		{name: "unknown flow", flowID: "missing", body: `{"source":"f1","target":"out"}`, wantStatus: http.StatusNotFound},
+		{name: "invalid JSON", flowID: "flow-1", body: `{"source":`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/flows/"+tt.flowID+"/connections/validate", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": tt.flowID})
+			rec := httptest.NewRecorder()
+			h.ValidateConnection(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var result struct {
+				Valid   bool     `json:"valid"`
+				Reasons []string `json:"reasons"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if result.Valid != tt.wantValid {
+				t.Errorf("valid = %v, want %v (reasons %v)", result.Valid, tt.wantValid, result.Reasons)
+			}
+			if tt.wantValid {
+				if len(result.Reasons) != 0 {
+					t.Errorf("reasons = %v, want none", result.Reasons)
+				}
+				return
+			}
+			if len(result.Reasons) != 1 || !strings.Contains(result.Reasons[0], tt.wantReason) {
+				t.Errorf("reasons = %v, want one containing %q", result.Reasons, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestGetEffectiveProperties(t *testing.T) {
+	store := storage.NewFileStorage(t.TempDir())
+	flow := &models.Flow{
+		ID:    "flow-1",
+		Name:  "effective",
+		Nodes: []models.Node{{ID: "in", Type: "inject", Properties: map[string]interface{}{"payload": "42", "topic": "mine"}}},
+	}
+	if err := store.SaveFlow(context.Background(), flow); err != nil {
+		t.Fatalf("save flow: %v", err)
+	}
+	cfg := config.EngineConfig{BlockPropertyOverrides: map[string]map[string]interface{}{
+		"inject": {"payloadType": "string", "topic": "deployment"},
+	}}
+	h := NewFlowHandler(engine.New(store, cfg, nopLogger{}), store)
+
+	get := func(nodeID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/flows/flow-1/nodes/"+nodeID+"/effective-properties", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "flow-1", "nodeID": nodeID})
+		rec := httptest.NewRecorder()
+		h.GetEffectiveProperties(rec, req)
+		return rec
+	}
+
+	rec := get("in")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var result struct {
+		Properties map[string]interface{} `json:"properties"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	want := map[string]interface{}{
+		"payload":     "42",     // Set on the node
+		"topic":       "mine",   // Set on the node, over the override
+		"payloadType": "string", // Deployment override
+		"name":        "Inject", // Block default
+	}
+	for name, value := range want {
+		if got := result.Properties[name]; got != value {
+			t.Errorf("%s = %v, want %v", name, got, value)
+		}
+	}
+	saved, err := store.LoadFlow(context.Background(), "flow-1")
+	if err != nil {
+		t.Fatalf("load flow: %v", err)
+	}
+	if stored := saved.Nodes[0].Properties; len(stored) != 2 {
+		t.Errorf("defaults written to the stored node: %v", stored)
+	}
+
+	if rec := get("missing"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown node = %d, want 404", rec.Code)
+	}
+}
+
+func TestTriggerFlowExpand(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	flow := &models.Flow{
+		ID:          "flow-1",
+		Name:        "expand",
+		Nodes:       []models.Node{{ID: "in", Type: "inject", Properties: map[string]interface{}{"payload": "tick"}}, {ID: "out", Type: "debug"}},
+		Connections: []models.Connection{{ID: "c1", Source: "in", Target: "out"}},
+	}
+	if err := store.SaveFlow(context.Background(), flow); err != nil {
+		t.Fatalf("save flow: %v", err)
+	}
+	flowEngine := engine.New(store, config.EngineConfig{}, nopLogger{})
+	defer flowEngine.Shutdown(context.Background())
+	h := NewFlowHandler(flowEngine, store)
+
+	for query, want := range map[string]int{"?expand=true": 3, "": 1} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/flows/flow-1/trigger"+query, strings.NewReader(`{"payload": [1, 2, 3]}`))
+		req = mux.SetURLVars(req, map[string]string{"id": "flow-1"})
+		rec := httptest.NewRecorder()
+		h.TriggerFlow(rec, req)
+
+		var result struct {
+			Triggers int `json:"triggers"`
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("trigger%s = %d: %s", query, rec.Code, rec.Body)
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if result.Triggers != want {
+			t.Errorf("trigger%s fired %d times, want %d", query, result.Triggers, want)
+		}
+	}
+}
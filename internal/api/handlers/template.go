@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"block-flow/internal/engine"
+	"block-flow/internal/models"
+	"block-flow/internal/storage"
+	"block-flow/internal/templates"
+
+	"github.com/gorilla/mux"
+)
+
+// TemplateHandler serves the built-in flow template catalog
+type TemplateHandler struct {
+	engine  *engine.Engine
+	storage storage.Storage
+}
+
+// NewTemplateHandler creates a new template handler
+func NewTemplateHandler(engine *engine.Engine, storage storage.Storage) *TemplateHandler {
+	return &TemplateHandler{
+		engine:  engine,
+		storage: storage,
+	}
+}
+
+// ListTemplates handles GET /api/v1/templates
+func (h *TemplateHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates.List())
+}
+
+// GetTemplate handles GET /api/v1/templates/{name}
+func (h *TemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["name"]
+
+	template, exists := templates.Get(name)
+	if !exists {
+		http.Error(w, "Template not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(template)
+}
+
+// InstantiateTemplate handles POST /api/v1/templates/{name}/instantiate. It
+// saves a new flow built from the template under fresh IDs; the optional
+// body {"name": "..."} names the flow
+func (h *TemplateHandler) InstantiateTemplate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["name"]
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	flow, exists := templates.Instantiate(name)
+	if !exists {
+		http.Error(w, "Template not found", http.StatusNotFound)
+		return
+	}
+	h.engine.RemapFlowIDs(r.Context(), []*models.Flow{flow})
+	if req.Name != "" {
+		flow.Name = req.Name
+	}
+
+	if err := flow.Validate(); err != nil {
+		http.Error(w, "Flow validation failed: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.engine.CheckFlowSize(flow); err != nil {
+		http.Error(w, "Flow too large: "+err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if err := h.storage.SaveFlow(r.Context(), flow); err != nil {
+		http.Error(w, "Failed to save flow", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(flow)
+}
@@ -1,9 +1,15 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
 	"log"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
+
+	"block-flow/pkg/logger"
 )
 
 // CORS middleware adds CORS headers
@@ -11,8 +17,9 @@ func CORS() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, X-Block-Flow-Bundle-Version")
 
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
@@ -24,6 +31,45 @@ func CORS() func(http.Handler) http.Handler {
 	}
 }
 
+// RequireReady middleware answers 503 until ready reports true, except for
+// the exempt paths (e.g. health and readiness probes)
+func RequireReady(ready func() bool, exempt ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !ready() && !slices.Contains(exempt, r.URL.Path) {
+				w.Header().Set("Retry-After", "1")
+				WriteError(w, http.StatusServiceUnavailable, "Service starting, not ready")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// JSONErrors middleware turns the plain-text errors written with http.Error
+// into the API's JSON error format
+func JSONErrors() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			wrapped := &errorWriter{ResponseWriter: w}
+
+			next.ServeHTTP(wrapped, r)
+
+			if wrapped.status != 0 {
+				WriteError(w, wrapped.status, strings.TrimSpace(wrapped.message.String()))
+			}
+		})
+	}
+}
+
+// WriteError writes an error response in the API's JSON error format
+func WriteError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
 // Logging middleware logs HTTP requests
 func Logging() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -36,7 +82,11 @@ func Logging() func(http.Handler) http.Handler {
 			next.ServeHTTP(wrapped, r)
 
 			duration := time.Since(start)
-			log.Printf("%s %s %d %v", r.Method, r.URL.Path, wrapped.statusCode, duration)
+			target := r.URL.Path
+			if query := r.URL.Query(); len(query) > 0 {
+				target += "?" + logger.RedactQuery(query) // Tokens are often passed as query params
+			}
+			log.Printf("%s %s %d %v", r.Method, target, wrapped.statusCode, duration)
 		})
 	}
 }
@@ -48,7 +98,7 @@ func Recovery() func(http.Handler) http.Handler {
 			defer func() {
 				if err := recover(); err != nil {
 					log.Printf("Panic recovered: %v", err)
-					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					WriteError(w, http.StatusInternalServerError, "Internal Server Error")
 				}
 			}()
 
@@ -67,3 +117,26 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+// errorWriter wraps http.ResponseWriter to hold back plain-text error
+// responses, recording their status and message instead
+type errorWriter struct {
+	http.ResponseWriter
+	status  int // Status of a held back error, 0 if none
+	message bytes.Buffer
+}
+
+func (ew *errorWriter) WriteHeader(code int) {
+	if code >= http.StatusBadRequest && strings.HasPrefix(ew.Header().Get("Content-Type"), "text/plain") {
+		ew.status = code
+		return
+	}
+	ew.ResponseWriter.WriteHeader(code)
+}
+
+func (ew *errorWriter) Write(data []byte) (int, error) {
+	if ew.status != 0 {
+		return ew.message.Write(data)
+	}
+	return ew.ResponseWriter.Write(data)
+}
@@ -0,0 +1,297 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/events"
+	"block-flow/internal/models"
+	"block-flow/internal/templates"
+
+	"github.com/gorilla/mux"
+)
+
+// apiPrefix is the path prefix of all documented routes
+const apiPrefix = "/api/v1"
+
+// openAPIOperation holds the hand-written parts of an operation. Paths and
+// methods come from the router itself, so the spec can't miss a route
+type openAPIOperation struct {
+	Summary  string
+	Request  string // Component schema name of the request body
+	Response string // Component schema name of the response; "[]Name" for arrays
+}
+
+// openAPIOperations describes operations keyed by "METHOD /path"
+var openAPIOperations = map[string]openAPIOperation{
+	"GET /flows":                                          {Summary: "List flows (?limit=&offset=&active=&q=; ?summary=true lists FlowSummary metadata; X-Total-Count has the match count)", Response: "[]Flow"},
+	"POST /flows":                                         {Summary: "Create a flow", Request: "Flow", Response: "Flow"},
+	"GET /flows/{id}":                                     {Summary: "Get a flow", Response: "Flow"},
+	"PUT /flows/{id}":                                     {Summary: "Update a flow", Request: "Flow", Response: "Flow"},
+	"PATCH /flows/{id}":                                   {Summary: "Patch a flow with a JSON Patch or merge patch", Response: "Flow"},
+	"GET /flows/trash":                                    {Summary: "List deleted flows awaiting purge", Response: "[]TrashedFlow"},
+	"POST /flows/{id}/restore":                            {Summary: "Restore a deleted flow from the trash", Response: "Flow"},
+	"POST /flows/{id}/duplicate":                          {Summary: "Save a copy of a flow under new flow and node IDs", Response: "Flow"},
+	"GET /flows/{id}/export":                              {Summary: "Export a flow as a portable bundle", Response: "FlowBundle"},
+	"POST /flows/import":                                  {Summary: "Import a flow bundle, a flow or an array of flows", Request: "FlowBundle"},
+	"DELETE /flows/{id}":                                  {Summary: "Move a flow to the trash (?permanent=true deletes it)"},
+	"POST /flows/{id}/start":                              {Summary: "Start a flow (?paused=true starts it with inputs paused)"},
+	"POST /flows/{id}/run":                                {Summary: "Start a flow (alias of start)"},
+	"POST /flows/{id}/stop":                               {Summary: "Stop a flow"},
+	"POST /flows/{id}/enable":                             {Summary: "Mark a flow active and start it", Response: "Flow"},
+	"POST /flows/{id}/disable":                            {Summary: "Mark a flow inactive and stop it", Response: "Flow"},
+	"POST /flows/{id}/input/pause":                        {Summary: "Stop input nodes, letting the flow drain"},
+	"POST /flows/{id}/input/resume":                       {Summary: "Resume input nodes"},
+	"POST /flows/{id}/trigger":                            {Summary: "Trigger a flow", Request: "Message"},
+	"GET /flows/{id}/status":                              {Summary: "Get the execution status of a flow"},
+	"GET /flows/{id}/summary":                             {Summary: "Get a compact overview of a flow"},
+	"POST /flows/{id}/debug":                              {Summary: "Toggle message recording"},
+	"GET /flows/{id}/debug/messages":                      {Summary: "Get recorded messages"},
+	"GET /flows/{id}/dead-letters":                        {Summary: "List dead-lettered messages", Response: "[]ExecutionMessage"},
+	"POST /flows/{id}/connections/validate":               {Summary: "Check a candidate connection without saving it", Request: "Connection"},
+	"POST /flows/{id}/nodes/{nodeID}/trigger":             {Summary: "Fire an input node once"},
+	"POST /flows/{id}/nodes/{nodeID}/clone":               {Summary: "Copy a node within its flow", Response: "Node"},
+	"POST /flows/{id}/nodes/{nodeID}/breakpoint":          {Summary: "Set, clear or toggle a debug breakpoint on a node of a running flow"},
+	"POST /flows/{id}/nodes/{nodeID}/step":                {Summary: "Release the message held at a node's breakpoint"},
+	"GET /flows/{id}/nodes/{nodeID}/effective-properties": {Summary: "Get the defaults-merged properties of a node"},
+	"GET /blocks":                                         {Summary: "List available block types", Response: "[]BlockInfo"},
+	"GET /templates":                                      {Summary: "List the built-in flow templates", Response: "[]Template"},
+	"GET /templates/{name}":                               {Summary: "Get a flow template with its definition", Response: "Template"},
+	"POST /templates/{name}/instantiate":                  {Summary: "Create a flow from a template with fresh IDs", Response: "Flow"},
+	"GET /blocks/{type}":                                  {Summary: "Get a block type", Response: "BlockInfo"},
+	"POST /admin/blocks/reload":                           {Summary: "Reload plugin blocks"},
+	"GET /admin/eventbus":                                 {Summary: "Get event bus statistics", Response: "EventBusStats"},
+	"GET /metrics":                                        {Summary: "Get engine metrics"},
+	"GET /export":                                         {Summary: "Export flows (and executions) as NDJSON"},
+	"POST /import":                                        {Summary: "Import flows and executions from NDJSON (remap=true imports copies under new IDs)"},
+	"GET /openapi.json":                                   {Summary: "Get this OpenAPI document"},
+	"GET /ws":                                             {Summary: "Open a WebSocket for live updates"},
+	"GET /health":                                         {Summary: "Check API health"},
+	"GET /ready":                                          {Summary: "Check whether startup has finished loading flows (503 until then)"},
+}
+
+// openAPIComponents are the model types published as component schemas
+var openAPIComponents = map[string]reflect.Type{
+	"Flow":                reflect.TypeOf(models.Flow{}),
+	"Node":                reflect.TypeOf(models.Node{}),
+	"Connection":          reflect.TypeOf(models.Connection{}),
+	"ConnectionTransform": reflect.TypeOf(models.ConnectionTransform{}),
+	"Message":             reflect.TypeOf(models.Message{}),
+	"FlowExecution":       reflect.TypeOf(models.FlowExecution{}),
+	"NodeState":           reflect.TypeOf(models.NodeState{}),
+	"ExecutionMessage":    reflect.TypeOf(models.ExecutionMessage{}),
+	"BlockInfo":           reflect.TypeOf(blocks.BlockInfo{}),
+	"Template":            reflect.TypeOf(templates.Template{}),
+	"TrashedFlow":         reflect.TypeOf(models.TrashedFlow{}),
+	"FlowSummary":         reflect.TypeOf(models.FlowSummary{}),
+	"FlowBundle":          reflect.TypeOf(models.FlowBundle{}),
+	"EventBusStats":       reflect.TypeOf(events.Stats{}),
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// newOpenAPIHandler serves an OpenAPI 3 document generated from the routes
+// registered on router
+func newOpenAPIHandler(router *mux.Router) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		spec, err := buildOpenAPISpec(router)
+		if err != nil {
+			http.Error(w, "Failed to build OpenAPI document", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(spec)
+	}
+}
+
+// buildOpenAPISpec walks the router and describes every API route
+func buildOpenAPISpec(router *mux.Router) (map[string]interface{}, error) {
+	paths := make(map[string]map[string]interface{})
+
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil || !strings.HasPrefix(template, apiPrefix+"/") {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil // Prefix-only routes such as the static file handler
+		}
+
+		path := pathParamPattern.ReplaceAllString(strings.TrimPrefix(template, apiPrefix), "{$1}")
+		if paths[path] == nil {
+			paths[path] = make(map[string]interface{})
+		}
+		for _, method := range methods {
+			paths[path][strings.ToLower(method)] = openAPIOperationFor(method, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make(map[string]interface{}, len(openAPIComponents))
+	for name, t := range openAPIComponents {
+		schemas[name] = schemaForStruct(t)
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Block-Flow API",
+			"version": "1.0.0",
+		},
+		"servers":    []map[string]interface{}{{"url": apiPrefix}},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas},
+	}, nil
+}
+
+// openAPIOperationFor builds the operation object of a route
+func openAPIOperationFor(method, path string) map[string]interface{} {
+	doc := openAPIOperations[method+" "+path]
+
+	operation := map[string]interface{}{
+		"summary": doc.Summary,
+		"responses": map[string]interface{}{
+			"default": map[string]interface{}{"description": "Error", "content": jsonContent(map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"error": map[string]interface{}{"type": "string"}},
+			})},
+		},
+	}
+	if doc.Summary == "" {
+		operation["summary"] = method + " " + path
+	}
+
+	success := map[string]interface{}{"description": "Successful operation"}
+	if doc.Response != "" {
+		success["content"] = jsonContent(componentRef(doc.Response))
+	}
+	operation["responses"].(map[string]interface{})["200"] = success
+
+	if doc.Request != "" {
+		operation["requestBody"] = map[string]interface{}{
+			"content": jsonContent(componentRef(doc.Request)),
+		}
+	}
+
+	var parameters []map[string]interface{}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		parameters = append(parameters, map[string]interface{}{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+
+	return operation
+}
+
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
+
+// componentRef references a component schema; "[]Name" references an array
+func componentRef(name string) map[string]interface{} {
+	if item, ok := strings.CutPrefix(name, "[]"); ok {
+		return map[string]interface{}{"type": "array", "items": componentRef(item)}
+	}
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaForStruct describes a struct's JSON encoding, following its json tags
+func schemaForStruct(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := schemaForStruct(field.Type)
+			for key, value := range embedded["properties"].(map[string]interface{}) {
+				properties[key] = value
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = schemaForType(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+// schemaForType maps a Go type to a schema, referencing published components
+func schemaForType(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for name, component := range openAPIComponents {
+		if component == t {
+			return componentRef(name)
+		}
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": schemaForType(t.Elem())}
+	case t.Kind() == reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaForType(t.Elem())}
+	case t.Kind() == reflect.Struct:
+		return schemaForStruct(t)
+	default:
+		return map[string]interface{}{} // Any JSON value
+	}
+}
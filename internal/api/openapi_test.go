@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"block-flow/internal/config"
+	"block-flow/internal/engine"
+	"block-flow/internal/storage"
+
+	"github.com/gorilla/mux"
+)
+
+// openAPIDocument is the part of the served spec the tests look at
+type openAPIDocument struct {
+	OpenAPI string `json:"openapi"`
+	Paths   map[string]map[string]struct {
+		Summary    string `json:"summary"`
+		Parameters []struct {
+			Name string `json:"name"`
+			In   string `json:"in"`
+		} `json:"parameters"`
+		RequestBody map[string]interface{} `json:"requestBody"`
+	} `json:"paths"`
+	Components struct {
+		Schemas map[string]interface{} `json:"schemas"`
+	} `json:"components"`
+}
+
+// fetchOpenAPI builds the API router and fetches its OpenAPI document
+func fetchOpenAPI(t *testing.T) (*mux.Router, openAPIDocument) {
+	t.Helper()
+	store := storage.NewMemoryStorage()
+	router := NewRouter(engine.New(store, config.EngineConfig{}, nopLogger{}), store).(*mux.Router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, apiPrefix+"/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET openapi.json = %d", rec.Code)
+	}
+	var doc openAPIDocument
+	if err := json.NewDecoder(rec.Body).Decode(&doc); err != nil {
+		t.Fatalf("decode OpenAPI document: %v", err)
+	}
+	return router, doc
+}
+
+func TestOpenAPICoversRegisteredRoutes(t *testing.T) {
+	router, doc := fetchOpenAPI(t)
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Errorf("openapi = %q, want a 3.x version", doc.OpenAPI)
+	}
+
+	documented := make(map[string]bool)
+	router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil || !strings.HasPrefix(template, apiPrefix+"/") {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		path := strings.TrimPrefix(template, apiPrefix)
+		for _, method := range methods {
+			key := method + " " + path
+			documented[key] = true
+			if _, ok := doc.Paths[path][strings.ToLower(method)]; !ok {
+				t.Errorf("route %s missing from the OpenAPI document", key)
+			}
+			if _, ok := openAPIOperations[key]; !ok {
+				t.Errorf("route %s has no entry in openAPIOperations", key)
+			}
+		}
+		return nil
+	})
+
+	for key := range openAPIOperations {
+		if !documented[key] {
+			t.Errorf("openAPIOperations describes %s, which isn't registered", key)
+		}
+	}
+	for name := range openAPIComponents {
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("component schema %s missing", name)
+		}
+	}
+}
+
+func TestOpenAPIOperations(t *testing.T) {
+	_, doc := fetchOpenAPI(t)
+
+	tests := []struct {
+		method      string
+		path        string
+		wantParams  []string
+		wantRequest bool
+	}{
+		{method: "get", path: "/flows"},
+		{method: "post", path: "/flows", wantRequest: true},
+		{method: "get", path: "/flows/{id}", wantParams: []string{"id"}},
+		{method: "post", path: "/flows/{id}/nodes/{nodeID}/trigger", wantParams: []string{"id", "nodeID"}},
+		{method: "get", path: "/blocks/{type}", wantParams: []string{"type"}},
+		{method: "get", path: "/health"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			operation, ok := doc.Paths[tt.path][tt.method]
+			if !ok {
+				t.Fatal("operation missing")
+			}
+			if operation.Summary == "" {
+				t.Error("operation has no summary")
+			}
+			params := make([]string, 0, len(operation.Parameters))
+			for _, param := range operation.Parameters {
+				if param.In != "path" {
+					t.Errorf("parameter %s in %s, want path", param.Name, param.In)
+				}
+				params = append(params, param.Name)
+			}
+			if strings.Join(params, ",") != strings.Join(tt.wantParams, ",") {
+				t.Errorf("parameters = %v, want %v", params, tt.wantParams)
+			}
+			if (operation.RequestBody != nil) != tt.wantRequest {
+				t.Errorf("request body = %v, want one %v", operation.RequestBody != nil, tt.wantRequest)
+			}
+		})
+	}
+}
@@ -24,26 +24,67 @@ func NewRouter(engine *engine.Engine, storage storage.Storage) http.Handler {
 	flowHandler := handlers.NewFlowHandler(engine, storage)
 	blockHandler := handlers.NewBlockHandler(engine)
 	wsHandler := handlers.NewWebSocketHandler(engine)
+	adminHandler := handlers.NewAdminHandler(engine)
+	backupHandler := handlers.NewBackupHandler(engine, storage)
+	templateHandler := handlers.NewTemplateHandler(engine, storage)
 
 	// API routes
 	api := r.PathPrefix("/api/v1").Subrouter()
+	api.Use(middleware.JSONErrors())
 
 	// Flow routes
 	api.HandleFunc("/flows", flowHandler.ListFlows).Methods("GET")
 	api.HandleFunc("/flows", flowHandler.CreateFlow).Methods("POST")
+	api.HandleFunc("/flows/trash", flowHandler.ListTrash).Methods("GET") // Before /flows/{id}
+	api.HandleFunc("/flows/import", backupHandler.ImportFlows).Methods("POST")
 	api.HandleFunc("/flows/{id}", flowHandler.GetFlow).Methods("GET")
 	api.HandleFunc("/flows/{id}", flowHandler.UpdateFlow).Methods("PUT")
+	api.HandleFunc("/flows/{id}", flowHandler.PatchFlow).Methods("PATCH")
 	api.HandleFunc("/flows/{id}", flowHandler.DeleteFlow).Methods("DELETE")
+	api.HandleFunc("/flows/{id}/restore", flowHandler.RestoreFlow).Methods("POST")
+	api.HandleFunc("/flows/{id}/duplicate", flowHandler.DuplicateFlow).Methods("POST")
+	api.HandleFunc("/flows/{id}/export", backupHandler.ExportFlow).Methods("GET")
 	api.HandleFunc("/flows/{id}/start", flowHandler.StartFlow).Methods("POST")
 	api.HandleFunc("/flows/{id}/run", flowHandler.StartFlow).Methods("POST") // Alias for start
 	api.HandleFunc("/flows/{id}/stop", flowHandler.StopFlow).Methods("POST")
+	api.HandleFunc("/flows/{id}/enable", flowHandler.EnableFlow).Methods("POST")
+	api.HandleFunc("/flows/{id}/disable", flowHandler.DisableFlow).Methods("POST")
+	api.HandleFunc("/flows/{id}/input/pause", flowHandler.PauseInput).Methods("POST")
+	api.HandleFunc("/flows/{id}/input/resume", flowHandler.ResumeInput).Methods("POST")
 	api.HandleFunc("/flows/{id}/trigger", flowHandler.TriggerFlow).Methods("POST")
 	api.HandleFunc("/flows/{id}/status", flowHandler.GetFlowStatus).Methods("GET")
+	api.HandleFunc("/flows/{id}/summary", flowHandler.GetFlowSummary).Methods("GET")
+	api.HandleFunc("/flows/{id}/debug", flowHandler.SetDebug).Methods("POST")
+	api.HandleFunc("/flows/{id}/debug/messages", flowHandler.GetDebugMessages).Methods("GET")
+	api.HandleFunc("/flows/{id}/dead-letters", flowHandler.GetDeadLetters).Methods("GET")
+	api.HandleFunc("/flows/{id}/connections/validate", flowHandler.ValidateConnection).Methods("POST")
+	api.HandleFunc("/flows/{id}/nodes/{nodeID}/trigger", flowHandler.TriggerNode).Methods("POST")
+	api.HandleFunc("/flows/{id}/nodes/{nodeID}/clone", flowHandler.CloneNode).Methods("POST")
+	api.HandleFunc("/flows/{id}/nodes/{nodeID}/breakpoint", flowHandler.SetBreakpoint).Methods("POST")
+	api.HandleFunc("/flows/{id}/nodes/{nodeID}/step", flowHandler.StepBreakpoint).Methods("POST")
+	api.HandleFunc("/flows/{id}/nodes/{nodeID}/effective-properties", flowHandler.GetEffectiveProperties).Methods("GET")
 
 	// Block routes
 	api.HandleFunc("/blocks", blockHandler.ListBlocks).Methods("GET")
 	api.HandleFunc("/blocks/{type}", blockHandler.GetBlockInfo).Methods("GET")
 
+	// Template routes
+	api.HandleFunc("/templates", templateHandler.ListTemplates).Methods("GET")
+	api.HandleFunc("/templates/{name}", templateHandler.GetTemplate).Methods("GET")
+	api.HandleFunc("/templates/{name}/instantiate", templateHandler.InstantiateTemplate).Methods("POST")
+
+	// Admin routes
+	api.HandleFunc("/admin/blocks/reload", adminHandler.ReloadBlocks).Methods("POST")
+	api.HandleFunc("/admin/eventbus", adminHandler.GetEventBus).Methods("GET")
+	api.HandleFunc("/metrics", adminHandler.GetMetrics).Methods("GET")
+
+	// Backup routes
+	api.HandleFunc("/export", backupHandler.Export).Methods("GET")
+	api.HandleFunc("/import", backupHandler.Import).Methods("POST")
+
+	// API description, generated from the routes registered on r
+	api.HandleFunc("/openapi.json", newOpenAPIHandler(r)).Methods("GET")
+
 	// WebSocket route
 	api.HandleFunc("/ws", wsHandler.HandleWebSocket).Methods("GET")
 
@@ -54,8 +95,25 @@ func NewRouter(engine *engine.Engine, storage storage.Storage) http.Handler {
 		w.Write([]byte(`{"status": "ok"}`))
 	}).Methods("GET")
 
-	// Static files (for future frontend)
-	r.PathPrefix("/").Handler(http.FileServer(http.Dir("./web/public/")))
+	// Readiness check: 503 until startup flows have been loaded
+	api.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if !engine.Ready() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"status": "starting"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status": "ready"}`))
+	}).Methods("GET")
+
+	// Endpoints served by HTTP In nodes of running flows
+	r.PathPrefix("/endpoints/").Handler(http.StripPrefix("/endpoints", engine.GetEndpoints()))
+
+	// Static files (for future frontend). Unknown API paths get a JSON 404,
+	// a known API path with the wrong method a 405
+	r.PathPrefix("/").MatcherFunc(outsideAPI).Handler(newSPAHandler("./web/public/"))
+	r.NotFoundHandler = newAPINotFoundHandler(r)
 
 	return r
 }
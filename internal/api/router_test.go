@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"block-flow/internal/api/middleware"
+	"block-flow/internal/config"
+	"block-flow/internal/engine"
+	"block-flow/internal/storage"
+)
+
+func TestReadiness(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	flowEngine := engine.New(store, config.EngineConfig{}, nopLogger{})
+	defer flowEngine.Shutdown(context.Background())
+	router := middleware.RequireReady(flowEngine.Ready, "/api/v1/health", "/api/v1/ready")(NewRouter(flowEngine, store))
+
+	status := func(path string) int {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	starting := map[string]int{
+		"/api/v1/ready":  http.StatusServiceUnavailable,
+		"/api/v1/health": http.StatusOK,
+		"/api/v1/flows":  http.StatusServiceUnavailable,
+	}
+	for path, want := range starting {
+		if got := status(path); got != want {
+			t.Errorf("during startup GET %s = %d, want %d", path, got, want)
+		}
+	}
+
+	if err := flowEngine.LoadAndStartFlows(context.Background()); err != nil {
+		t.Fatalf("load flows: %v", err)
+	}
+
+	for _, path := range []string{"/api/v1/ready", "/api/v1/health", "/api/v1/flows"} {
+		if got := status(path); got != http.StatusOK {
+			t.Errorf("after startup GET %s = %d, want 200", path, got)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"block-flow/internal/api/middleware"
+
+	"github.com/gorilla/mux"
+)
+
+// spaHandler serves the frontend from a static directory. Directories are
+// never listed, and paths without a file extension fall back to index.html
+// so client-side routes work
+type spaHandler struct {
+	root  string
+	files http.Handler
+}
+
+// newSPAHandler creates a static file handler rooted at dir
+func newSPAHandler(dir string) http.Handler {
+	return &spaHandler{
+		root:  dir,
+		files: http.FileServer(http.Dir(dir)),
+	}
+}
+
+func (h *spaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	urlPath := path.Clean("/" + r.URL.Path)
+
+	info, err := os.Stat(filepath.Join(h.root, filepath.FromSlash(urlPath)))
+	switch {
+	case err == nil && !info.IsDir():
+		h.files.ServeHTTP(w, r)
+	case path.Ext(urlPath) != "":
+		// Missing asset: a real 404 rather than the index page
+		http.NotFound(w, r)
+	default:
+		// Directories and client-side routes get the SPA entry point
+		http.ServeFile(w, r, filepath.Join(h.root, "index.html"))
+	}
+}
+
+// outsideAPI matches the requests the static fallback may answer: any path
+// but the API's, so API typos are never shadowed by the frontend. CORS
+// preflights match everywhere; the CORS middleware answers them
+func outsideAPI(r *http.Request, _ *mux.RouteMatch) bool {
+	urlPath := path.Clean("/" + r.URL.Path)
+	return r.Method == http.MethodOptions || (urlPath != "/api" && !strings.HasPrefix(urlPath, "/api/"))
+}
+
+// newAPINotFoundHandler answers the requests no route of router matched,
+// i.e. API requests: a 405 listing the allowed methods when a route exists
+// for the path, otherwise a 404. mux itself loses track of method mismatches
+// on subrouters
+func newAPINotFoundHandler(router *mux.Router) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if allowed := allowedMethods(router, r); len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			middleware.WriteError(w, http.StatusMethodNotAllowed, "method not allowed: "+r.Method+" "+r.URL.Path)
+			return
+		}
+		middleware.WriteError(w, http.StatusNotFound, "endpoint not found: "+r.Method+" "+path.Clean("/"+r.URL.Path))
+	}
+}
+
+// allowedMethods returns the methods for which a route of router matches the
+// request's path
+func allowedMethods(router *mux.Router, r *http.Request) []string {
+	allowed := make([]string, 0)
+	router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		for _, method := range methods {
+			probe := r.Clone(r.Context())
+			probe.Method = method
+			var match mux.RouteMatch
+			if route.Match(probe, &match) && match.MatchErr == nil && !slices.Contains(allowed, method) {
+				allowed = append(allowed, method)
+			}
+		}
+		return nil
+	})
+	return allowed
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"block-flow/internal/config"
+	"block-flow/internal/engine"
+	"block-flow/internal/storage"
+)
+
+// nopLogger discards engine logs
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, map[string]interface{}) {}
+func (nopLogger) Info(string, map[string]interface{})  {}
+func (nopLogger) Warn(string, map[string]interface{})  {}
+func (nopLogger) Error(string, map[string]interface{}) {}
+
+func TestRouterErrors(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	router := NewRouter(engine.New(store, config.EngineConfig{}, nopLogger{}), store)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantJSON   bool   // Body is a JSON error object
+		wantAllow  string // Allow header of a 405
+	}{
+		{name: "unknown API path", method: http.MethodGet, path: "/api/v1/bogus", wantStatus: http.StatusNotFound, wantJSON: true},
+		{name: "API path without version", method: http.MethodGet, path: "/api", wantStatus: http.StatusNotFound, wantJSON: true},
+		{name: "handler error", method: http.MethodGet, path: "/api/v1/flows/missing", wantStatus: http.StatusNotFound, wantJSON: true},
+		{name: "wrong method on an API route", method: http.MethodDelete, path: "/api/v1/flows", wantStatus: http.StatusMethodNotAllowed, wantJSON: true, wantAllow: "GET, POST"},
+		{name: "CORS preflight", method: http.MethodOptions, path: "/api/v1/flows", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("%s %s = %d, want %d", tt.method, tt.path, rec.Code, tt.wantStatus)
+			}
+			if allow := rec.Header().Get("Allow"); allow != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", allow, tt.wantAllow)
+			}
+			if !tt.wantJSON {
+				return
+			}
+
+			if contentType := rec.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", contentType)
+			}
+			var body struct {
+				Error string `json:"error"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body.Error == "" {
+				t.Errorf("body is not a JSON error (%v)", err)
+			}
+		})
+	}
+}
+
+func TestSPAHandler(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"index.html":        "spa index",
+		"web/app.js":        "app script",
+		"web/secret.config": "secret",
+	} {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := newSPAHandler(root)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "directory is not listed", path: "/web/", wantStatus: http.StatusOK, wantBody: "spa index"},
+		{name: "asset", path: "/web/app.js", wantStatus: http.StatusOK, wantBody: "app script"},
+		{name: "missing asset", path: "/web/missing.js", wantStatus: http.StatusNotFound},
+		{name: "client-side route", path: "/flows/editor", wantStatus: http.StatusOK, wantBody: "spa index"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("GET %s = %d, want %d", tt.path, rec.Code, tt.wantStatus)
+			}
+			body := rec.Body.String()
+			if tt.wantBody != "" && body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+			if strings.Contains(body, "secret.config") {
+				t.Error("directory contents listed")
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package builtin
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// MessageAgeBlock measures how long a message has been in flight, from its
+// timestamp or from a start time carried in a header, and attaches the age
+// to the message. Messages older than a threshold are routed to port 1 so
+// flows can react to processing lag instead of silently dropping them
+type MessageAgeBlock struct{}
+
+func (b *MessageAgeBlock) GetType() string {
+	return "message-age"
+}
+
+func (b *MessageAgeBlock) GetName() string {
+	return "Message Age"
+}
+
+func (b *MessageAgeBlock) GetDescription() string {
+	return "Measure how long a message has been in flight and route stale ones"
+}
+
+func (b *MessageAgeBlock) GetCategory() string {
+	return "function"
+}
+
+func (b *MessageAgeBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *MessageAgeBlock) GetInputs() int {
+	return 1
+}
+
+func (b *MessageAgeBlock) GetOutputs() int {
+	return 2
+}
+
+func (b *MessageAgeBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Message Age",
+		},
+		{
+			Name:         "startHeader",
+			Type:         "string",
+			DisplayName:  "Start Header",
+			Description:  "Header holding the start time (RFC 3339 or Unix milliseconds); empty uses the message timestamp",
+			Required:     false,
+			DefaultValue: "",
+		},
+		{
+			Name:         "attach",
+			Type:         "select",
+			DisplayName:  "Attach To",
+			Description:  "Where the age in milliseconds is stored",
+			Required:     false,
+			DefaultValue: "header",
+			Options: []blocks.Option{
+				{Label: "Header", Value: "header"},
+				{Label: "Payload field", Value: "payload"},
+				{Label: "Nowhere", Value: "none"},
+			},
+		},
+		{
+			Name:         "field",
+			Type:         "string",
+			DisplayName:  "Field",
+			Description:  "Header name or payload path receiving the age",
+			Required:     false,
+			DefaultValue: "age_ms",
+		},
+		{
+			Name:         "threshold",
+			Type:         "number",
+			DisplayName:  "Stale After (ms)",
+			Description:  "Messages older than this go to output 2 (0 never marks messages stale)",
+			Required:     false,
+			DefaultValue: 0,
+			Validation: blocks.Validation{
+				Min: &[]float64{0}[0],
+			},
+		},
+	}
+}
+
+func (b *MessageAgeBlock) Validate(properties map[string]interface{}) error {
+	switch stringProperty(properties, "attach", "header") {
+	case "header":
+		if stringProperty(properties, "field", "age_ms") == "" {
+			return fmt.Errorf("field property is required to attach the age as a header")
+		}
+	case "payload":
+		if err := models.ValidatePath(stringProperty(properties, "field", "age_ms")); err != nil {
+			return err
+		}
+	case "none":
+	default:
+		return fmt.Errorf("invalid attach '%s'", stringProperty(properties, "attach", "header"))
+	}
+	return nil
+}
+
+func (b *MessageAgeBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	start := ctx.Message.Timestamp
+	if header := stringProperty(properties, "startHeader", ""); header != "" {
+		value, ok := ctx.Message.GetHeader(header)
+		if !ok {
+			return nil, blocks.Invalidf("start header '%s' not found", header)
+		}
+		parsed, err := parseStartTime(value)
+		if err != nil {
+			return nil, blocks.Invalidf("start header '%s': %w", header, err)
+		}
+		start = parsed
+	}
+	age := time.Since(start).Milliseconds()
+
+	outputMsg := ctx.Message.Clone()
+	outputMsg.Source = ctx.NodeID
+
+	field := stringProperty(properties, "field", "age_ms")
+	switch stringProperty(properties, "attach", "header") {
+	case "header":
+		outputMsg.SetHeader(field, strconv.FormatInt(age, 10))
+	case "payload":
+		payload, err := models.SetPath(outputMsg.Payload, field, age)
+		if err != nil {
+			return nil, blocks.Invalidf("cannot store age: %w", err)
+		}
+		outputMsg.Payload = payload
+	}
+
+	threshold := intProperty(properties, "threshold", 0)
+	if threshold > 0 && age > int64(threshold) {
+		outputMsg.Port = 1
+		ctx.Logger.Debug("Stale message", map[string]interface{}{
+			"age_ms":    age,
+			"threshold": threshold,
+		})
+	}
+
+	return []*models.Message{outputMsg}, nil
+}
+
+// parseStartTime reads a time as RFC 3339 or as Unix milliseconds
+func parseStartTime(value string) (time.Time, error) {
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(millis), nil
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("'%s' is neither RFC 3339 nor Unix milliseconds", value)
+	}
+	return parsed, nil
+}
+
+// MessageAgeBlockFactory creates message age block instances
+type MessageAgeBlockFactory struct{}
+
+func (f *MessageAgeBlockFactory) CreateBlock() blocks.Block {
+	return &MessageAgeBlock{}
+}
+
+func (f *MessageAgeBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &MessageAgeBlock{}
+	return blocks.BlockInfo{
+		Type:        "message-age",
+		Name:        "Message Age",
+		Description: "Measure how long a message has been in flight and route stale ones",
+		Category:    "function",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "clock",
+		Color:       "#9C27B0",
+	}
+}
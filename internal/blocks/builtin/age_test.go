@@ -0,0 +1,79 @@
+package builtin
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+func TestMessageAgeRouting(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+		message    func() *models.Message
+		wantPort   int
+	}{
+		{
+			name:       "fresh message",
+			properties: map[string]interface{}{"threshold": 1000.0},
+			message:    func() *models.Message { return models.NewMessage("fresh") },
+			wantPort:   0,
+		},
+		{
+			name:       "stale by timestamp",
+			properties: map[string]interface{}{"threshold": 1000.0},
+			message: func() *models.Message {
+				msg := models.NewMessage("stale")
+				msg.Timestamp = now.Add(-5 * time.Second)
+				return msg
+			},
+			wantPort: 1,
+		},
+		{
+			name:       "stale by start header",
+			properties: map[string]interface{}{"threshold": 1000.0, "startHeader": "sent_at"},
+			message: func() *models.Message {
+				msg := models.NewMessage("stale")
+				msg.SetHeader("sent_at", strconv.FormatInt(now.Add(-time.Minute).UnixMilli(), 10))
+				return msg
+			},
+			wantPort: 1,
+		},
+		{
+			name:       "no threshold never stale",
+			properties: map[string]interface{}{},
+			message: func() *models.Message {
+				msg := models.NewMessage("old")
+				msg.Timestamp = now.Add(-time.Hour)
+				return msg
+			},
+			wantPort: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := &MessageAgeBlock{}
+			if err := block.Validate(tt.properties); err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+			ctx := &models.BlockExecutionContext{NodeID: "age", Message: tt.message(), Logger: nopLogger{}}
+			out, err := block.Execute(ctx, tt.properties)
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if len(out) != 1 {
+				t.Fatalf("emitted %d messages", len(out))
+			}
+			if out[0].Port != tt.wantPort {
+				t.Errorf("port = %d, want %d", out[0].Port, tt.wantPort)
+			}
+			if _, ok := out[0].GetHeader("age_ms"); !ok {
+				t.Error("age_ms header not attached")
+			}
+		})
+	}
+}
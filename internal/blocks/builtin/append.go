@@ -0,0 +1,231 @@
+package builtin
+
+import (
+	"fmt"
+	"sync"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// AppendBlock accumulates incoming payloads into a single growing array or
+// string. Unlike batching, it keeps one value that grows with every message.
+// A message on the reset topic clears the accumulator; a message on the
+// flush topic emits the accumulated value and clears it
+type AppendBlock struct {
+	items []interface{} // Array mode
+	text  []rune        // String mode
+	mu    sync.Mutex
+}
+
+func (b *AppendBlock) GetType() string {
+	return "append"
+}
+
+func (b *AppendBlock) GetName() string {
+	return "Append"
+}
+
+func (b *AppendBlock) GetDescription() string {
+	return "Append each payload to a growing array or string"
+}
+
+func (b *AppendBlock) GetCategory() string {
+	return "sequence"
+}
+
+func (b *AppendBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *AppendBlock) GetInputs() int {
+	return 1
+}
+
+func (b *AppendBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *AppendBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Append",
+		},
+		{
+			Name:         "mode",
+			Type:         "select",
+			DisplayName:  "Mode",
+			Description:  "Accumulate payloads as array items or concatenate them as text",
+			Required:     false,
+			DefaultValue: "array",
+			Options: []blocks.Option{
+				{Label: "Array", Value: "array"},
+				{Label: "String", Value: "string"},
+			},
+		},
+		{
+			Name:         "separator",
+			Type:         "string",
+			DisplayName:  "Separator",
+			Description:  "Inserted between payloads in string mode",
+			Required:     false,
+			DefaultValue: "",
+		},
+		{
+			Name:         "maxLength",
+			Type:         "number",
+			DisplayName:  "Max Length",
+			Description:  "Maximum items (array) or characters (string); the oldest content is dropped when exceeded",
+			Required:     false,
+			DefaultValue: 1000,
+			Validation: blocks.Validation{
+				Min: &[]float64{1}[0],
+			},
+		},
+		{
+			Name:         "emit",
+			Type:         "select",
+			DisplayName:  "Emit",
+			Description:  "Emit the accumulated value on every message or only on flush",
+			Required:     false,
+			DefaultValue: "each",
+			Options: []blocks.Option{
+				{Label: "Each message", Value: "each"},
+				{Label: "On flush", Value: "flush"},
+			},
+		},
+		{
+			Name:         "resetTopic",
+			Type:         "string",
+			DisplayName:  "Reset Topic",
+			Description:  "Messages with this topic clear the accumulator",
+			Required:     false,
+			DefaultValue: "reset",
+		},
+		{
+			Name:         "flushTopic",
+			Type:         "string",
+			DisplayName:  "Flush Topic",
+			Description:  "Messages with this topic emit the accumulated value and clear it",
+			Required:     false,
+			DefaultValue: "flush",
+		},
+	}
+}
+
+func (b *AppendBlock) Validate(properties map[string]interface{}) error {
+	mode := stringProperty(properties, "mode", "array")
+	if mode != "array" && mode != "string" {
+		return fmt.Errorf("invalid mode '%s'", mode)
+	}
+	emit := stringProperty(properties, "emit", "each")
+	if emit != "each" && emit != "flush" {
+		return fmt.Errorf("invalid emit '%s'", emit)
+	}
+	return nil
+}
+
+func (b *AppendBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	mode := stringProperty(properties, "mode", "array")
+
+	switch ctx.Message.Topic {
+	case stringProperty(properties, "resetTopic", "reset"):
+		b.reset()
+		return []*models.Message{}, nil
+	case stringProperty(properties, "flushTopic", "flush"):
+		output := b.output(ctx, mode)
+		b.reset()
+		return []*models.Message{output}, nil
+	}
+
+	maxLength := intProperty(properties, "maxLength", 1000)
+	if mode == "string" {
+		if len(b.text) > 0 {
+			b.text = append(b.text, []rune(stringProperty(properties, "separator", ""))...)
+		}
+		b.text = append(b.text, []rune(payloadText(ctx.Message))...)
+		if len(b.text) > maxLength {
+			b.text = append([]rune(nil), b.text[len(b.text)-maxLength:]...)
+		}
+	} else {
+		b.items = append(b.items, ctx.Message.Payload)
+		if len(b.items) > maxLength {
+			b.items = append([]interface{}(nil), b.items[len(b.items)-maxLength:]...)
+		}
+	}
+
+	if stringProperty(properties, "emit", "each") == "flush" {
+		return []*models.Message{}, nil
+	}
+	return []*models.Message{b.output(ctx, mode)}, nil
+}
+
+// output builds a message carrying a copy of the accumulated value.
+// Callers must hold b.mu
+func (b *AppendBlock) output(ctx *models.BlockExecutionContext, mode string) *models.Message {
+	output := ctx.Message.Clone()
+	output.ContentType = ""
+	if mode == "string" {
+		output.Payload = string(b.text)
+	} else {
+		output.Payload = append([]interface{}{}, b.items...)
+	}
+	output.Source = ctx.NodeID
+	return output
+}
+
+// reset clears the accumulator. Callers must hold b.mu
+func (b *AppendBlock) reset() {
+	b.items = nil
+	b.text = nil
+}
+
+// payloadText renders a payload for string concatenation
+func payloadText(msg *models.Message) string {
+	switch payload := msg.Payload.(type) {
+	case string:
+		return payload
+	case []byte:
+		return string(payload)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(payload)
+	}
+}
+
+// AppendBlockFactory creates append block instances
+type AppendBlockFactory struct{}
+
+func (f *AppendBlockFactory) CreateBlock() blocks.Block {
+	return &AppendBlock{}
+}
+
+func (f *AppendBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &AppendBlock{}
+	return blocks.BlockInfo{
+		Type:        "append",
+		Name:        "Append",
+		Description: "Append each payload to a growing array or string",
+		Category:    "sequence",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "playlist-plus",
+		Color:       "#795548",
+	}
+}
@@ -0,0 +1,99 @@
+package builtin
+
+import (
+	"reflect"
+	"testing"
+
+	"block-flow/internal/models"
+)
+
+// appendSender sends payloads on topics to an append block, returning what it
+// emitted (nil when nothing was)
+func appendSender(t *testing.T, properties map[string]interface{}) func(topic string, payload interface{}) interface{} {
+	t.Helper()
+	block := &AppendBlock{}
+	if err := block.Validate(properties); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	return func(topic string, payload interface{}) interface{} {
+		t.Helper()
+		msg := models.NewMessage(payload)
+		msg.Topic = topic
+		out, err := block.Execute(&models.BlockExecutionContext{NodeID: "append", Message: msg, Logger: nopLogger{}}, properties)
+		if err != nil {
+			t.Fatalf("execute: %v", err)
+		}
+		switch len(out) {
+		case 0:
+			return nil
+		case 1:
+			return out[0].Payload
+		default:
+			t.Fatalf("emitted %d messages", len(out))
+			return nil
+		}
+	}
+}
+
+func TestAppendString(t *testing.T) {
+	send := appendSender(t, map[string]interface{}{"mode": "string", "separator": ", ", "maxLength": 12.0})
+
+	if got := send("", "a"); got != "a" {
+		t.Errorf("first append = %v", got)
+	}
+	if got := send("", 2.0); got != "a, 2" {
+		t.Errorf("second append = %v", got)
+	}
+	if got := send("", "long"); got != "a, 2, long" {
+		t.Errorf("third append = %v", got)
+	}
+	// Past maxLength the oldest characters are dropped
+	if got := send("", "xyz"); got != "2, long, xyz" {
+		t.Errorf("bounded append = %q", got)
+	}
+
+	if got := send("reset", nil); got != nil {
+		t.Errorf("reset emitted %v", got)
+	}
+	if got := send("", "b"); got != "b" {
+		t.Errorf("append after reset = %v, want no leading separator", got)
+	}
+}
+
+func TestAppendArray(t *testing.T) {
+	send := appendSender(t, map[string]interface{}{"maxLength": 3.0})
+
+	first := send("", 1.0)
+	send("", "two")
+	if got := send("", 3.0); !reflect.DeepEqual(got, []interface{}{1.0, "two", 3.0}) {
+		t.Errorf("array = %v", got)
+	}
+	if !reflect.DeepEqual(first, []interface{}{1.0}) {
+		t.Errorf("earlier output changed to %v, want a copy", first)
+	}
+	if got := send("", 4.0); !reflect.DeepEqual(got, []interface{}{"two", 3.0, 4.0}) {
+		t.Errorf("bounded array = %v", got)
+	}
+
+	send("reset", nil)
+	if got := send("", 5.0); !reflect.DeepEqual(got, []interface{}{5.0}) {
+		t.Errorf("array after reset = %v", got)
+	}
+}
+
+func TestAppendFlush(t *testing.T) {
+	send := appendSender(t, map[string]interface{}{"emit": "flush"})
+
+	for _, payload := range []interface{}{1.0, 2.0} {
+		if got := send("", payload); got != nil {
+			t.Errorf("emitted %v before the flush", got)
+		}
+	}
+	if got := send("flush", nil); !reflect.DeepEqual(got, []interface{}{1.0, 2.0}) {
+		t.Errorf("flush = %v", got)
+	}
+	// Flushing clears the accumulator
+	if got := send("flush", nil); !reflect.DeepEqual(got, []interface{}{}) {
+		t.Errorf("second flush = %v, want empty", got)
+	}
+}
@@ -0,0 +1,164 @@
+package builtin
+
+import (
+	"fmt"
+	"strings"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// CatchBlock emits a message when another node of its flow fails to process
+// a message. The payload describes the failure: the failing node's ID and
+// type, the error text and category, and the message being processed. It
+// catches errors of every node of the flow, or only of the listed nodes
+type CatchBlock struct{}
+
+func (b *CatchBlock) GetType() string {
+	return "catch"
+}
+
+func (b *CatchBlock) GetName() string {
+	return "Catch"
+}
+
+func (b *CatchBlock) GetDescription() string {
+	return "Emit a message when a node of the flow fails"
+}
+
+func (b *CatchBlock) GetCategory() string {
+	return "input"
+}
+
+func (b *CatchBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.InputGroup
+}
+
+func (b *CatchBlock) GetInputs() int {
+	return 0
+}
+
+func (b *CatchBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *CatchBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Catch",
+		},
+		{
+			Name:         "scope",
+			Type:         "select",
+			DisplayName:  "Scope",
+			Description:  "Catch errors of all nodes of the flow, or only of the listed nodes",
+			Required:     false,
+			DefaultValue: "all",
+			Options: []blocks.Option{
+				{Label: "All nodes", Value: "all"},
+				{Label: "Selected nodes", Value: "nodes"},
+			},
+		},
+		{
+			Name:         "nodes",
+			Type:         "string",
+			DisplayName:  "Nodes",
+			Description:  "Comma-separated IDs of the nodes to catch errors of, with the selected nodes scope",
+			Required:     false,
+			DefaultValue: "",
+		},
+		{
+			Name:         "topic",
+			Type:         "string",
+			DisplayName:  "Topic",
+			Description:  "Optional topic for the message",
+			Required:     false,
+			DefaultValue: "",
+		},
+	}
+}
+
+func (b *CatchBlock) Validate(properties map[string]interface{}) error {
+	switch scope := stringProperty(properties, "scope", "all"); scope {
+	case "all":
+	case "nodes":
+		if len(parseNodeList(stringProperty(properties, "nodes", ""))) == 0 {
+			return fmt.Errorf("nodes property is required with the selected nodes scope")
+		}
+	default:
+		return fmt.Errorf("invalid scope '%s'", scope)
+	}
+	return nil
+}
+
+// Catches reports whether the node is in the block's scope
+func (b *CatchBlock) Catches(nodeID string, properties map[string]interface{}) bool {
+	if stringProperty(properties, "scope", "all") == "all" {
+		return true
+	}
+	for _, id := range parseNodeList(stringProperty(properties, "nodes", "")) {
+		if id == nodeID {
+			return true
+		}
+	}
+	return false
+}
+
+// NodeReferenceProperties lists the property holding the caught node IDs
+func (b *CatchBlock) NodeReferenceProperties() []string {
+	return []string{"nodes"}
+}
+
+func (b *CatchBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no error to handle")
+	}
+
+	outputMsg := ctx.Message.Clone()
+	outputMsg.Source = ctx.NodeID
+	if topic := stringProperty(properties, "topic", ""); topic != "" {
+		outputMsg.Topic = topic
+	}
+
+	return []*models.Message{outputMsg}, nil
+}
+
+// parseNodeList reads a comma-separated list of node IDs
+func parseNodeList(value string) []string {
+	ids := make([]string, 0)
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// CatchBlockFactory creates catch block instances
+type CatchBlockFactory struct{}
+
+func (f *CatchBlockFactory) CreateBlock() blocks.Block {
+	return &CatchBlock{}
+}
+
+func (f *CatchBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &CatchBlock{}
+	return blocks.BlockInfo{
+		Type:        "catch",
+		Name:        "Catch",
+		Description: "Emit a message when a node of the flow fails",
+		Category:    "input",
+		BlockGroup:  blocks.InputGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "alert-triangle",
+		Color:       "#F44336",
+	}
+}
@@ -0,0 +1,296 @@
+package builtin
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// pendingMessage is a message waiting for its correlated partner
+type pendingMessage struct {
+	msg     *models.Message
+	arrived time.Time
+}
+
+// CorrelateBlock pairs messages sharing a correlation key (e.g. a request and
+// its later response), emitting the combined pair on port 0 once both have
+// arrived. Unmatched messages expire after a timeout and are optionally
+// emitted on port 1
+type CorrelateBlock struct {
+	pending map[string]pendingMessage
+	order   []string // Keys in arrival order, for eviction
+	mu      sync.Mutex
+}
+
+func (b *CorrelateBlock) GetType() string {
+	return "correlate"
+}
+
+func (b *CorrelateBlock) GetName() string {
+	return "Correlate"
+}
+
+func (b *CorrelateBlock) GetDescription() string {
+	return "Join pairs of messages that share a correlation key"
+}
+
+func (b *CorrelateBlock) GetCategory() string {
+	return "sequence"
+}
+
+func (b *CorrelateBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *CorrelateBlock) GetInputs() int {
+	return 1
+}
+
+func (b *CorrelateBlock) GetOutputs() int {
+	return 2
+}
+
+func (b *CorrelateBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Correlate",
+		},
+		{
+			Name:         "keySource",
+			Type:         "select",
+			DisplayName:  "Key Source",
+			Description:  "Where the correlation key is read from",
+			Required:     false,
+			DefaultValue: "header",
+			Options: []blocks.Option{
+				{Label: "Header", Value: "header"},
+				{Label: "Payload field", Value: "payload"},
+				{Label: "Topic", Value: "topic"},
+			},
+		},
+		{
+			Name:         "key",
+			Type:         "string",
+			DisplayName:  "Key",
+			Description:  "Header name or payload path holding the correlation key",
+			Required:     false,
+			DefaultValue: "correlationId",
+		},
+		{
+			Name:         "timeout",
+			Type:         "number",
+			DisplayName:  "Timeout (ms)",
+			Description:  "How long an unmatched message waits for its partner",
+			Required:     false,
+			DefaultValue: 30000,
+			Validation: blocks.Validation{
+				Min: &[]float64{1}[0],
+			},
+		},
+		{
+			Name:         "emitExpired",
+			Type:         "boolean",
+			DisplayName:  "Emit Expired",
+			Description:  "Send unmatched messages to output 2 when they time out",
+			Required:     false,
+			DefaultValue: true,
+		},
+		{
+			Name:         "maxPending",
+			Type:         "number",
+			DisplayName:  "Max Pending",
+			Description:  "Maximum unmatched messages held; the oldest is evicted when exceeded",
+			Required:     false,
+			DefaultValue: 1000,
+			Validation: blocks.Validation{
+				Min: &[]float64{1}[0],
+			},
+		},
+	}
+}
+
+func (b *CorrelateBlock) Validate(properties map[string]interface{}) error {
+	keySource := stringProperty(properties, "keySource", "header")
+	if keySource != "header" && keySource != "payload" && keySource != "topic" {
+		return fmt.Errorf("invalid keySource '%s'", keySource)
+	}
+	if keySource == "payload" {
+		if err := models.ValidatePath(stringProperty(properties, "key", "")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *CorrelateBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	key, err := b.correlationKey(ctx.Message, properties)
+	if err != nil {
+		return nil, blocks.Invalid(err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pending == nil {
+		b.pending = make(map[string]pendingMessage)
+	}
+
+	outputs := b.expire(properties, ctx.NodeID)
+
+	first, matched := b.pending[key]
+	if !matched {
+		// Evict the oldest pending message when the store is full
+		maxPending := intProperty(properties, "maxPending", 1000)
+		for len(b.pending) >= maxPending && len(b.order) > 0 {
+			oldest := b.order[0]
+			b.order = b.order[1:]
+			delete(b.pending, oldest)
+			ctx.Logger.Warn("Correlation store full, evicted oldest message", map[string]interface{}{
+				"key": oldest,
+			})
+		}
+
+		b.pending[key] = pendingMessage{msg: ctx.Message, arrived: time.Now()}
+		b.order = append(b.order, key)
+		return outputs, nil
+	}
+
+	delete(b.pending, key)
+	b.removeFromOrder(key)
+
+	outputMsg := ctx.Message.Clone()
+	outputMsg.Payload = map[string]interface{}{
+		"key":    key,
+		"first":  first.msg.Payload,
+		"second": ctx.Message.Payload,
+	}
+	outputMsg.Source = ctx.NodeID
+
+	ctx.Logger.Debug("Correlated message pair", map[string]interface{}{
+		"key":     key,
+		"waited":  time.Since(first.arrived).String(),
+		"pending": len(b.pending),
+	})
+
+	return append(outputs, outputMsg), nil
+}
+
+// TickInterval checks for expired messages a few times per timeout
+func (b *CorrelateBlock) TickInterval(properties map[string]interface{}) time.Duration {
+	interval := time.Duration(intProperty(properties, "timeout", 30000)) * time.Millisecond / 4
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	return interval
+}
+
+// Tick expires unmatched messages that waited longer than the timeout
+func (b *CorrelateBlock) Tick(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.expire(properties, ctx.NodeID), nil
+}
+
+// expire removes timed-out pending messages, returning them for output 2
+// when emitExpired is set. Callers must hold b.mu
+func (b *CorrelateBlock) expire(properties map[string]interface{}, nodeID string) []*models.Message {
+	timeout := time.Duration(intProperty(properties, "timeout", 30000)) * time.Millisecond
+	emitExpired := boolProperty(properties, "emitExpired", true)
+
+	outputs := make([]*models.Message, 0)
+	for len(b.order) > 0 {
+		key := b.order[0]
+		entry, ok := b.pending[key]
+		if ok && time.Since(entry.arrived) < timeout {
+			break
+		}
+
+		b.order = b.order[1:]
+		if !ok {
+			continue
+		}
+		delete(b.pending, key)
+
+		if emitExpired {
+			expired := entry.msg.Clone()
+			expired.Port = 1
+			expired.Source = nodeID
+			expired.SetContext("correlationKey", key)
+			outputs = append(outputs, expired)
+		}
+	}
+	return outputs
+}
+
+func (b *CorrelateBlock) removeFromOrder(key string) {
+	for i, k := range b.order {
+		if k == key {
+			b.order = append(b.order[:i], b.order[i+1:]...)
+			return
+		}
+	}
+}
+
+// correlationKey extracts the correlation key from a message
+func (b *CorrelateBlock) correlationKey(msg *models.Message, properties map[string]interface{}) (string, error) {
+	key := stringProperty(properties, "key", "correlationId")
+
+	switch stringProperty(properties, "keySource", "header") {
+	case "topic":
+		if msg.Topic == "" {
+			return "", fmt.Errorf("message has no topic to correlate on")
+		}
+		return msg.Topic, nil
+	case "payload":
+		value, found, err := models.ResolvePath(msg.Payload, key)
+		if err != nil {
+			return "", err
+		}
+		if !found || value == nil {
+			return "", fmt.Errorf("correlation key '%s' not found in payload", key)
+		}
+		return fmt.Sprint(value), nil
+	default:
+		value, ok := msg.GetHeader(key)
+		if !ok || value == "" {
+			return "", fmt.Errorf("correlation header '%s' not found", key)
+		}
+		return value, nil
+	}
+}
+
+// CorrelateBlockFactory creates correlate block instances
+type CorrelateBlockFactory struct{}
+
+func (f *CorrelateBlockFactory) CreateBlock() blocks.Block {
+	return &CorrelateBlock{}
+}
+
+func (f *CorrelateBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &CorrelateBlock{}
+	return blocks.BlockInfo{
+		Type:        "correlate",
+		Name:        "Correlate",
+		Description: "Join pairs of messages that share a correlation key",
+		Category:    "sequence",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "link",
+		Color:       "#795548",
+	}
+}
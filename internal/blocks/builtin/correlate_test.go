@@ -0,0 +1,109 @@
+package builtin
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+func TestCorrelate(t *testing.T) {
+	// send executes block on a payload correlated by its id field
+	send := func(t *testing.T, block *CorrelateBlock, properties map[string]interface{}, id, value string) []*models.Message {
+		t.Helper()
+		ctx := &models.BlockExecutionContext{
+			NodeID:  "correlate",
+			Message: models.NewMessage(map[string]interface{}{"id": id, "value": value}),
+			Logger:  nopLogger{},
+		}
+		out, err := block.Execute(ctx, properties)
+		if err != nil {
+			t.Fatalf("execute %s/%s: %v", id, value, err)
+		}
+		return out
+	}
+
+	t.Run("matched pair", func(t *testing.T) {
+		block := &CorrelateBlock{}
+		properties := map[string]interface{}{"keySource": "payload", "key": "id"}
+
+		if out := send(t, block, properties, "a", "request"); len(out) != 0 {
+			t.Fatalf("first of a pair emitted %d messages", len(out))
+		}
+		if out := send(t, block, properties, "b", "other"); len(out) != 0 {
+			t.Fatalf("unrelated message emitted %d messages", len(out))
+		}
+		out := send(t, block, properties, "a", "response")
+		if len(out) != 1 || out[0].Port != 0 {
+			t.Fatalf("pair emitted %d messages", len(out))
+		}
+		want := map[string]interface{}{
+			"key":    "a",
+			"first":  map[string]interface{}{"id": "a", "value": "request"},
+			"second": map[string]interface{}{"id": "a", "value": "response"},
+		}
+		if !reflect.DeepEqual(out[0].Payload, want) {
+			t.Errorf("pair payload = %v, want %v", out[0].Payload, want)
+		}
+		if len(block.pending) != 1 {
+			t.Errorf("%d messages pending, want only b", len(block.pending))
+		}
+	})
+
+	t.Run("timed-out single", func(t *testing.T) {
+		block := &CorrelateBlock{}
+		properties := map[string]interface{}{"keySource": "payload", "key": "id", "timeout": 20.0}
+		ctx := &models.BlockExecutionContext{NodeID: "correlate", Logger: nopLogger{}}
+
+		send(t, block, properties, "a", "request")
+		if out, _ := block.Tick(ctx, properties); len(out) != 0 {
+			t.Fatalf("expired %d messages before the timeout", len(out))
+		}
+		time.Sleep(30 * time.Millisecond)
+
+		out, err := block.Tick(ctx, properties)
+		if err != nil || len(out) != 1 {
+			t.Fatalf("tick = %d messages, %v; want the expired one", len(out), err)
+		}
+		if out[0].Port != 1 || out[0].Context["correlationKey"] != "a" {
+			t.Errorf("expired message on port %d with key %v, want port 1, key a", out[0].Port, out[0].Context["correlationKey"])
+		}
+
+		// The partner arriving late starts a new pair instead of matching
+		if out := send(t, block, properties, "a", "response"); len(out) != 0 {
+			t.Errorf("late partner emitted %d messages", len(out))
+		}
+	})
+
+	t.Run("timed-out single dropped when emitExpired is off", func(t *testing.T) {
+		block := &CorrelateBlock{}
+		properties := map[string]interface{}{"keySource": "payload", "key": "id", "timeout": 1.0, "emitExpired": false}
+		send(t, block, properties, "a", "request")
+		time.Sleep(5 * time.Millisecond)
+
+		out, _ := block.Tick(&models.BlockExecutionContext{NodeID: "correlate", Logger: nopLogger{}}, properties)
+		if len(out) != 0 || len(block.pending) != 0 {
+			t.Errorf("tick emitted %d messages with %d pending, want none", len(out), len(block.pending))
+		}
+	})
+
+	t.Run("eviction at maxPending", func(t *testing.T) {
+		block := &CorrelateBlock{}
+		properties := map[string]interface{}{"keySource": "payload", "key": "id", "maxPending": 2.0}
+
+		for _, id := range []string{"a", "b", "c"} {
+			send(t, block, properties, id, "request")
+		}
+		if _, ok := block.pending["a"]; ok || len(block.pending) != 2 {
+			t.Fatalf("pending keys %v, want the oldest evicted", block.order)
+		}
+
+		if out := send(t, block, properties, "a", "response"); len(out) != 0 {
+			t.Errorf("evicted key matched, emitted %d messages", len(out))
+		}
+		if out := send(t, block, properties, "c", "response"); len(out) != 1 {
+			t.Errorf("kept key emitted %d messages, want the pair", len(out))
+		}
+	})
+}
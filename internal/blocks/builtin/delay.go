@@ -0,0 +1,154 @@
+package builtin
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// DelayBlock forwards messages unchanged after holding them, either for a
+// fixed time each or spaced at least an interval apart (rate limiting). The
+// executor does the holding, so messages keep their order and those still
+// held when the flow stops are rejected
+type DelayBlock struct {
+	next time.Time // Rate limit mode: earliest time of the next release
+	mu   sync.Mutex
+}
+
+func (b *DelayBlock) GetType() string {
+	return "delay"
+}
+
+func (b *DelayBlock) GetName() string {
+	return "Delay"
+}
+
+func (b *DelayBlock) GetDescription() string {
+	return "Hold messages for a time or limit their rate"
+}
+
+func (b *DelayBlock) GetCategory() string {
+	return "function"
+}
+
+func (b *DelayBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *DelayBlock) GetInputs() int {
+	return 1
+}
+
+func (b *DelayBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *DelayBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Delay",
+		},
+		{
+			Name:         "mode",
+			Type:         "select",
+			DisplayName:  "Mode",
+			Description:  "Delay each message, or release at most one message per interval",
+			Required:     false,
+			DefaultValue: "delay",
+			Options: []blocks.Option{
+				{Label: "Delay each message", Value: "delay"},
+				{Label: "Rate limit", Value: "rateLimit"},
+			},
+		},
+		{
+			Name:         "delay",
+			Type:         "number",
+			DisplayName:  "Delay (ms)",
+			Description:  "How long each message is held in delay mode",
+			Required:     false,
+			DefaultValue: 1000,
+			Validation: blocks.Validation{
+				Min: &[]float64{0}[0],
+			},
+		},
+		{
+			Name:         "interval",
+			Type:         "number",
+			DisplayName:  "Interval (ms)",
+			Description:  "Minimum time between released messages in rate limit mode",
+			Required:     false,
+			DefaultValue: 1000,
+			Validation: blocks.Validation{
+				Min: &[]float64{1}[0],
+			},
+		},
+	}
+}
+
+func (b *DelayBlock) Validate(properties map[string]interface{}) error {
+	mode := stringProperty(properties, "mode", "delay")
+	if mode != "delay" && mode != "rateLimit" {
+		return fmt.Errorf("invalid mode '%s'", mode)
+	}
+	return nil
+}
+
+// ReleaseAt holds messages for the delay, or queues them behind the
+// previous release in rate limit mode
+func (b *DelayBlock) ReleaseAt(now time.Time, properties map[string]interface{}) time.Time {
+	if stringProperty(properties, "mode", "delay") != "rateLimit" {
+		return now.Add(time.Duration(intProperty(properties, "delay", 1000)) * time.Millisecond)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	release := now
+	if b.next.After(now) {
+		release = b.next
+	}
+	b.next = release.Add(time.Duration(max(intProperty(properties, "interval", 1000), 1)) * time.Millisecond)
+	return release
+}
+
+func (b *DelayBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	outputMsg := ctx.Message.Clone()
+	outputMsg.Source = ctx.NodeID
+	return []*models.Message{outputMsg}, nil
+}
+
+// DelayBlockFactory creates delay block instances
+type DelayBlockFactory struct{}
+
+func (f *DelayBlockFactory) CreateBlock() blocks.Block {
+	return &DelayBlock{}
+}
+
+func (f *DelayBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &DelayBlock{}
+	return blocks.BlockInfo{
+		Type:        "delay",
+		Name:        "Delay",
+		Description: "Hold messages for a time or limit their rate",
+		Category:    "function",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "hourglass",
+		Color:       "#9C27B0",
+	}
+}
@@ -0,0 +1,77 @@
+package builtin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDelayReleaseAt(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ms := time.Millisecond
+
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+		arrivals   []time.Duration // Arrival times after start
+		want       []time.Duration // Release times after start
+	}{
+		{
+			name:       "default delay",
+			properties: map[string]interface{}{},
+			arrivals:   []time.Duration{0, 10 * ms},
+			want:       []time.Duration{1000 * ms, 1010 * ms},
+		},
+		{
+			name:       "fixed delay",
+			properties: map[string]interface{}{"mode": "delay", "delay": 50.0},
+			arrivals:   []time.Duration{0, 0, 20 * ms},
+			want:       []time.Duration{50 * ms, 50 * ms, 70 * ms},
+		},
+		{
+			name:       "rate limit queues a burst",
+			properties: map[string]interface{}{"mode": "rateLimit", "interval": 100.0},
+			arrivals:   []time.Duration{0, 0, 0},
+			want:       []time.Duration{0, 100 * ms, 200 * ms},
+		},
+		{
+			name:       "rate limit releases spaced arrivals at once",
+			properties: map[string]interface{}{"mode": "rateLimit", "interval": 100.0},
+			arrivals:   []time.Duration{0, 150 * ms, 180 * ms},
+			want:       []time.Duration{0, 150 * ms, 250 * ms},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := &DelayBlock{}
+			for i, arrival := range tt.arrivals {
+				got := block.ReleaseAt(start.Add(arrival), tt.properties).Sub(start)
+				if got != tt.want[i] {
+					t.Errorf("message %d released at %s, want %s", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDelayValidate(t *testing.T) {
+	tests := []struct {
+		mode    interface{}
+		wantErr bool
+	}{
+		{mode: nil},
+		{mode: "delay"},
+		{mode: "rateLimit"},
+		{mode: "throttle", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		properties := map[string]interface{}{}
+		if tt.mode != nil {
+			properties["mode"] = tt.mode
+		}
+		if err := (&DelayBlock{}).Validate(properties); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(mode %v) error = %v, want error %v", tt.mode, err, tt.wantErr)
+		}
+	}
+}
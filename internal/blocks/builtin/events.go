@@ -0,0 +1,184 @@
+package builtin
+
+import (
+	"fmt"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/events"
+	"block-flow/internal/models"
+)
+
+// EventListenerBlock emits a message for every engine event matching its filter
+type EventListenerBlock struct {
+	bus *events.Bus
+}
+
+func (b *EventListenerBlock) GetType() string {
+	return "event-listener"
+}
+
+func (b *EventListenerBlock) GetName() string {
+	return "Event Listener"
+}
+
+func (b *EventListenerBlock) GetDescription() string {
+	return "Emit a message for each matching engine event (flow started/stopped, node errors)"
+}
+
+func (b *EventListenerBlock) GetCategory() string {
+	return "input"
+}
+
+func (b *EventListenerBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.InputGroup
+}
+
+func (b *EventListenerBlock) GetInputs() int {
+	return 0
+}
+
+func (b *EventListenerBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *EventListenerBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Event Listener",
+		},
+		{
+			Name:         "eventType",
+			Type:         "select",
+			DisplayName:  "Event Type",
+			Description:  "Only emit events of this type",
+			Required:     false,
+			DefaultValue: "",
+			Options: []blocks.Option{
+				{Label: "Any", Value: ""},
+				{Label: "Flow started", Value: events.FlowStarted},
+				{Label: "Flow stopped", Value: events.FlowStopped},
+				{Label: "Node error", Value: events.NodeError},
+			},
+		},
+		{
+			Name:         "flowId",
+			Type:         "string",
+			DisplayName:  "Flow ID",
+			Description:  "Only emit events from this flow (empty = any flow)",
+			Required:     false,
+			DefaultValue: "",
+		},
+		{
+			Name:         "nodeId",
+			Type:         "string",
+			DisplayName:  "Node ID",
+			Description:  "Only emit events from this node (empty = any node)",
+			Required:     false,
+			DefaultValue: "",
+		},
+		{
+			Name:         "includeOwnFlow",
+			Type:         "boolean",
+			DisplayName:  "Include Own Flow",
+			Description:  "Also emit events raised by this flow (may cause feedback loops)",
+			Required:     false,
+			DefaultValue: false,
+		},
+	}
+}
+
+func (b *EventListenerBlock) Validate(properties map[string]interface{}) error {
+	if b.bus == nil {
+		return fmt.Errorf("event bus is not available")
+	}
+	return nil
+}
+
+// FlowReferenceProperties marks flowId as a reference to another flow
+func (b *EventListenerBlock) FlowReferenceProperties() []string {
+	return []string{"flowId"}
+}
+
+// Execute emits nothing; events are delivered through Run
+func (b *EventListenerBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	return []*models.Message{}, nil
+}
+
+// Run subscribes to the event bus and emits matching events until the flow stops
+func (b *EventListenerBlock) Run(ctx *models.BlockExecutionContext, properties map[string]interface{}, emit func(*models.Message)) error {
+	if b.bus == nil {
+		return blocks.Fatal(fmt.Errorf("event bus is not available"))
+	}
+
+	eventType, _ := properties["eventType"].(string)
+	flowID, _ := properties["flowId"].(string)
+	nodeID, _ := properties["nodeId"].(string)
+	includeOwnFlow, _ := properties["includeOwnFlow"].(bool)
+
+	subscription, unsubscribe := b.bus.Subscribe("event-listener "+ctx.FlowID+"/"+ctx.NodeID, 100)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Context.Done():
+			return nil
+		case event := <-subscription:
+			// Guard against feedback loops: never react to events this node caused
+			// and ignore the rest of the own flow unless explicitly requested
+			if event.FlowID == ctx.FlowID && (!includeOwnFlow || event.NodeID == ctx.NodeID) {
+				continue
+			}
+			if eventType != "" && event.Type != eventType {
+				continue
+			}
+			if flowID != "" && event.FlowID != flowID {
+				continue
+			}
+			if nodeID != "" && event.NodeID != nodeID {
+				continue
+			}
+
+			msg := models.NewMessage(map[string]interface{}{
+				"type":      event.Type,
+				"flow_id":   event.FlowID,
+				"node_id":   event.NodeID,
+				"timestamp": event.Timestamp,
+				"data":      event.Data,
+			})
+			msg.Topic = "event/" + event.Type
+			msg.Source = ctx.NodeID
+			emit(msg)
+		}
+	}
+}
+
+// EventListenerBlockFactory creates event listener block instances
+type EventListenerBlockFactory struct {
+	bus *events.Bus
+}
+
+func (f *EventListenerBlockFactory) CreateBlock() blocks.Block {
+	return &EventListenerBlock{bus: f.bus}
+}
+
+func (f *EventListenerBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &EventListenerBlock{}
+	return blocks.BlockInfo{
+		Type:        "event-listener",
+		Name:        "Event Listener",
+		Description: "Emit a message for each matching engine event",
+		Category:    "input",
+		BlockGroup:  blocks.InputGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "bell",
+		Color:       "#009688",
+	}
+}
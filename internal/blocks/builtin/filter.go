@@ -0,0 +1,177 @@
+package builtin
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// FilterBlock reports by exception: it forwards a message only when its value
+// differs from the last one forwarded for the same topic, or for numbers
+// differs by more than a deadband. The last values are kept in the node state
+type FilterBlock struct{}
+
+func (b *FilterBlock) GetType() string {
+	return "filter"
+}
+
+func (b *FilterBlock) GetName() string {
+	return "Filter"
+}
+
+func (b *FilterBlock) GetDescription() string {
+	return "Forward messages only when their value changes"
+}
+
+func (b *FilterBlock) GetCategory() string {
+	return "function"
+}
+
+func (b *FilterBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *FilterBlock) GetInputs() int {
+	return 1
+}
+
+func (b *FilterBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *FilterBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Filter",
+		},
+		{
+			Name:         "mode",
+			Type:         "select",
+			DisplayName:  "Mode",
+			Description:  "Forward on any change, or on numeric changes larger than the deadband",
+			Required:     false,
+			DefaultValue: "rbe",
+			Options: []blocks.Option{
+				{Label: "Any change", Value: "rbe"},
+				{Label: "Deadband", Value: "deadband"},
+			},
+		},
+		{
+			Name:         "field",
+			Type:         "string",
+			DisplayName:  "Field",
+			Description:  "Payload path of the value to compare (empty for the payload itself)",
+			Required:     false,
+			DefaultValue: "",
+		},
+		{
+			Name:         "delta",
+			Type:         "number",
+			DisplayName:  "Deadband",
+			Description:  "Minimum change from the last forwarded value in deadband mode",
+			Required:     false,
+			DefaultValue: 0,
+			Validation: blocks.Validation{
+				Min: &[]float64{0}[0],
+			},
+		},
+	}
+}
+
+func (b *FilterBlock) Validate(properties map[string]interface{}) error {
+	mode := stringProperty(properties, "mode", "rbe")
+	if mode != "rbe" && mode != "deadband" {
+		return fmt.Errorf("invalid mode '%s'", mode)
+	}
+	if field := stringProperty(properties, "field", ""); field != "" {
+		return models.ValidatePath(field)
+	}
+	return nil
+}
+
+func (b *FilterBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	value := ctx.Message.Payload
+	if field := stringProperty(properties, "field", ""); field != "" {
+		resolved, found, err := models.ResolvePath(value, field)
+		if err != nil {
+			return nil, blocks.Invalid(err)
+		}
+		if !found {
+			return nil, blocks.Invalidf("field '%s' not found in payload", field)
+		}
+		value = resolved
+	}
+
+	// Topics are tracked independently
+	key := "filter:" + ctx.Message.Topic
+	last, seen := ctx.GetState(key)
+
+	var current interface{}
+	changed := !seen
+	if stringProperty(properties, "mode", "rbe") == "deadband" {
+		number, err := models.ToNumber(value)
+		if err != nil {
+			return nil, blocks.Invalid(err)
+		}
+		current = number
+		if previous, ok := last.(float64); ok {
+			delta, _ := models.ToNumber(properties["delta"])
+			changed = math.Abs(number-previous) > delta
+		}
+	} else {
+		// Encoded values compare maps and numbers by content
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, blocks.Invalidf("value is not comparable: %w", err)
+		}
+		current = string(data)
+		if seen {
+			changed = current != last
+		}
+	}
+
+	if !changed {
+		return []*models.Message{}, nil
+	}
+	ctx.SetState(key, current)
+
+	outputMsg := ctx.Message.Clone()
+	outputMsg.Source = ctx.NodeID
+	return []*models.Message{outputMsg}, nil
+}
+
+// FilterBlockFactory creates filter block instances
+type FilterBlockFactory struct{}
+
+func (f *FilterBlockFactory) CreateBlock() blocks.Block {
+	return &FilterBlock{}
+}
+
+func (f *FilterBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &FilterBlock{}
+	return blocks.BlockInfo{
+		Type:        "filter",
+		Name:        "Filter",
+		Description: "Forward messages only when their value changes",
+		Category:    "function",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "filter",
+		Color:       "#9C27B0",
+	}
+}
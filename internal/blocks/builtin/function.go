@@ -0,0 +1,176 @@
+package builtin
+
+import (
+	"fmt"
+	"sync"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// FunctionBlock evaluates an expression against each message and emits the
+// result as the new payload. The expression sees the message as msg (payload,
+// topic, headers, context, id) and the flow properties as flow
+type FunctionBlock struct {
+	mu         sync.Mutex
+	expression string
+	program    *vm.Program
+}
+
+func (b *FunctionBlock) GetType() string {
+	return "function"
+}
+
+func (b *FunctionBlock) GetName() string {
+	return "Function"
+}
+
+func (b *FunctionBlock) GetDescription() string {
+	return "Compute the payload from an expression over the message"
+}
+
+func (b *FunctionBlock) GetCategory() string {
+	return "function"
+}
+
+func (b *FunctionBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *FunctionBlock) GetInputs() int {
+	return 1
+}
+
+func (b *FunctionBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *FunctionBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Function",
+		},
+		{
+			Name:         "expression",
+			Type:         "string",
+			DisplayName:  "Expression",
+			Description:  `Expression computing the new payload, e.g. msg.payload.temp * 1.8 + 32 or msg.topic == "alarm" ? flow.threshold : 0`,
+			Required:     true,
+			DefaultValue: "msg.payload",
+		},
+	}
+}
+
+func (b *FunctionBlock) Validate(properties map[string]interface{}) error {
+	_, err := compileFunctionExpression(properties)
+	return err
+}
+
+func (b *FunctionBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	program, err := b.compiled(properties)
+	if err != nil {
+		return nil, blocks.Invalid(err)
+	}
+
+	result, err := expr.Run(program, functionEnv(ctx))
+	if err != nil {
+		return nil, blocks.Invalidf("evaluate expression: %w", err)
+	}
+
+	outputMsg := ctx.Message.Clone()
+	outputMsg.Payload = result
+	outputMsg.Source = ctx.NodeID
+	return []*models.Message{outputMsg}, nil
+}
+
+// compiled returns the program for the current expression, compiling it only
+// when the expression changes
+func (b *FunctionBlock) compiled(properties map[string]interface{}) (*vm.Program, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	expression := stringProperty(properties, "expression", "")
+	if b.program != nil && b.expression == expression {
+		return b.program, nil
+	}
+	program, err := compileFunctionExpression(properties)
+	if err != nil {
+		return nil, err
+	}
+	b.expression = expression
+	b.program = program
+	return program, nil
+}
+
+// compileFunctionExpression parses the expression property. Only msg and
+// flow are defined, so misspelled variables are rejected here
+func compileFunctionExpression(properties map[string]interface{}) (*vm.Program, error) {
+	expression := stringProperty(properties, "expression", "")
+	if expression == "" {
+		return nil, fmt.Errorf("expression property is required")
+	}
+	env := map[string]interface{}{
+		"msg":  map[string]interface{}{},
+		"flow": map[string]interface{}{},
+	}
+	program, err := expr.Compile(expression, expr.Env(env))
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	return program, nil
+}
+
+// functionEnv exposes the message and the flow properties to an expression
+func functionEnv(ctx *models.BlockExecutionContext) map[string]interface{} {
+	msg := ctx.Message
+	flow := make(map[string]interface{}, len(ctx.FlowProperties))
+	for key, value := range ctx.FlowProperties {
+		flow[key] = value
+	}
+	return map[string]interface{}{
+		"msg": map[string]interface{}{
+			"id":      msg.ID,
+			"payload": msg.Payload,
+			"topic":   msg.Topic,
+			"headers": msg.Headers,
+			"context": msg.Context,
+		},
+		"flow": flow,
+	}
+}
+
+// FunctionBlockFactory creates function block instances
+type FunctionBlockFactory struct{}
+
+func (f *FunctionBlockFactory) CreateBlock() blocks.Block {
+	return &FunctionBlock{}
+}
+
+func (f *FunctionBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &FunctionBlock{}
+	return blocks.BlockInfo{
+		Type:        "function",
+		Name:        "Function",
+		Description: "Compute the payload from an expression over the message",
+		Category:    "function",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "code",
+		Color:       "#9C27B0",
+	}
+}
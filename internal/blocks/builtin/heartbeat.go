@@ -0,0 +1,141 @@
+package builtin
+
+import (
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// HeartbeatBlock emits a timestamped ping on a fixed interval, independent of
+// any data flowing through the flow, so downstream liveness checks can tell a
+// stalled flow from a quiet one
+type HeartbeatBlock struct{}
+
+func (b *HeartbeatBlock) GetType() string {
+	return "heartbeat"
+}
+
+func (b *HeartbeatBlock) GetName() string {
+	return "Heartbeat"
+}
+
+func (b *HeartbeatBlock) GetDescription() string {
+	return "Emit a timestamped ping on an interval"
+}
+
+func (b *HeartbeatBlock) GetCategory() string {
+	return "input"
+}
+
+func (b *HeartbeatBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.InputGroup
+}
+
+func (b *HeartbeatBlock) GetInputs() int {
+	return 0
+}
+
+func (b *HeartbeatBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *HeartbeatBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Heartbeat",
+		},
+		{
+			Name:         "interval",
+			Type:         "number",
+			DisplayName:  "Interval (ms)",
+			Description:  "Time between heartbeats",
+			Required:     false,
+			DefaultValue: 10000,
+			Validation: blocks.Validation{
+				Min: &[]float64{1}[0],
+			},
+		},
+		{
+			Name:         "topic",
+			Type:         "string",
+			DisplayName:  "Topic",
+			Description:  "Optional topic for the messages",
+			Required:     false,
+			DefaultValue: "heartbeat",
+		},
+	}
+}
+
+// Validate has nothing to add to the interval's definition, which the engine
+// enforces before the flow starts
+func (b *HeartbeatBlock) Validate(properties map[string]interface{}) error {
+	return nil
+}
+
+// Execute emits a single heartbeat, e.g. when the node is triggered manually
+func (b *HeartbeatBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	return []*models.Message{b.beat(ctx, properties, 0)}, nil
+}
+
+// Run emits a heartbeat every interval until the flow stops
+func (b *HeartbeatBlock) Run(ctx *models.BlockExecutionContext, properties map[string]interface{}, emit func(*models.Message)) error {
+	if err := blocks.ValidateProperties(b.GetProperties(), properties); err != nil {
+		return blocks.Invalid(err)
+	}
+	interval := time.Duration(intProperty(properties, "interval", 10000)) * time.Millisecond
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for sequence := 1; ; sequence++ {
+		select {
+		case <-ctx.Context.Done():
+			return nil
+		case <-ticker.C:
+			emit(b.beat(ctx, properties, sequence))
+		}
+	}
+}
+
+// beat builds a heartbeat message. sequence counts heartbeats since the flow
+// started; manual triggers use 0
+func (b *HeartbeatBlock) beat(ctx *models.BlockExecutionContext, properties map[string]interface{}, sequence int) *models.Message {
+	msg := models.NewMessage(map[string]interface{}{
+		"timestamp": time.Now().UTC().Format(time.RFC3339Nano),
+		"sequence":  sequence,
+		"flow_id":   ctx.FlowID,
+	})
+	msg.Topic = stringProperty(properties, "topic", "heartbeat")
+	msg.Source = ctx.NodeID
+	return msg
+}
+
+// HeartbeatBlockFactory creates heartbeat block instances
+type HeartbeatBlockFactory struct{}
+
+func (f *HeartbeatBlockFactory) CreateBlock() blocks.Block {
+	return &HeartbeatBlock{}
+}
+
+func (f *HeartbeatBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &HeartbeatBlock{}
+	return blocks.BlockInfo{
+		Type:        "heartbeat",
+		Name:        "Heartbeat",
+		Description: "Emit a timestamped ping on an interval",
+		Category:    "input",
+		BlockGroup:  blocks.InputGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "heart",
+		Color:       "#4CAF50",
+	}
+}
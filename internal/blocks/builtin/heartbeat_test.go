@@ -0,0 +1,64 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+func TestHeartbeatRun(t *testing.T) {
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	block := &HeartbeatBlock{}
+	properties := map[string]interface{}{"interval": 20.0, "topic": "alive"}
+	ctx := &models.BlockExecutionContext{Context: runCtx, NodeID: "beat", FlowID: "flow", Logger: nopLogger{}}
+
+	beats := make(chan *models.Message, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- block.Run(ctx, properties, func(msg *models.Message) { beats <- msg })
+	}()
+
+	// No input ever arrives: the beats come from the interval alone
+	start := time.Now()
+	for want := 1; want <= 3; want++ {
+		select {
+		case msg := <-beats:
+			payload := msg.Payload.(map[string]interface{})
+			if payload["sequence"] != want {
+				t.Errorf("beat %d has sequence %v", want, payload["sequence"])
+			}
+			if payload["flow_id"] != "flow" || msg.Topic != "alive" || msg.Source != "beat" {
+				t.Errorf("beat %d = %v topic %q source %q", want, payload, msg.Topic, msg.Source)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("beat %d not emitted", want)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("3 beats in %v, faster than the 20ms interval", elapsed)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run returned %v after the flow stopped", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after the flow stopped")
+	}
+
+	// Drain anything emitted before the cancel landed, then expect silence
+	for len(beats) > 0 {
+		<-beats
+	}
+	select {
+	case msg := <-beats:
+		t.Errorf("beat %v emitted after stopping", msg.Payload)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
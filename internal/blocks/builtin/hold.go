@@ -0,0 +1,148 @@
+package builtin
+
+import (
+	"sync"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// HoldBlock remembers the last message it saw so late subscribers can get the
+// current value without waiting for the next one. Connections are static, so
+// a consumer that joins late asks for the value by sending a message on the
+// replay topic (e.g. from an inject node triggered when a dashboard connects)
+type HoldBlock struct {
+	last *models.Message
+	mu   sync.Mutex
+}
+
+func (b *HoldBlock) GetType() string {
+	return "hold"
+}
+
+func (b *HoldBlock) GetName() string {
+	return "Hold"
+}
+
+func (b *HoldBlock) GetDescription() string {
+	return "Remember the last message and replay it on request"
+}
+
+func (b *HoldBlock) GetCategory() string {
+	return "sequence"
+}
+
+func (b *HoldBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *HoldBlock) GetInputs() int {
+	return 1
+}
+
+func (b *HoldBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *HoldBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Hold",
+		},
+		{
+			Name:         "passThrough",
+			Type:         "boolean",
+			DisplayName:  "Pass Through",
+			Description:  "Also forward each value as it arrives",
+			Required:     false,
+			DefaultValue: true,
+		},
+		{
+			Name:         "replayTopic",
+			Type:         "string",
+			DisplayName:  "Replay Topic",
+			Description:  "Messages with this topic emit the held message again",
+			Required:     false,
+			DefaultValue: "replay",
+		},
+		{
+			Name:         "clearTopic",
+			Type:         "string",
+			DisplayName:  "Clear Topic",
+			Description:  "Messages with this topic forget the held message",
+			Required:     false,
+			DefaultValue: "clear",
+		},
+	}
+}
+
+func (b *HoldBlock) Validate(properties map[string]interface{}) error {
+	return nil
+}
+
+func (b *HoldBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch ctx.Message.Topic {
+	case stringProperty(properties, "replayTopic", "replay"):
+		if b.last == nil {
+			return []*models.Message{}, nil // Nothing held yet
+		}
+		return []*models.Message{b.replay(ctx)}, nil
+	case stringProperty(properties, "clearTopic", "clear"):
+		b.last = nil
+		return []*models.Message{}, nil
+	}
+
+	// Keep a private copy; downstream blocks may modify the forwarded message
+	b.last = ctx.Message.Clone()
+
+	if !boolProperty(properties, "passThrough", true) {
+		return []*models.Message{}, nil
+	}
+	output := ctx.Message.Clone()
+	output.Source = ctx.NodeID
+	return []*models.Message{output}, nil
+}
+
+// replay builds a copy of the held message. Callers must hold b.mu
+func (b *HoldBlock) replay(ctx *models.BlockExecutionContext) *models.Message {
+	output := b.last.Clone()
+	output.Source = ctx.NodeID
+	output.SetHeader("replayed", "true")
+	return output
+}
+
+// HoldBlockFactory creates hold block instances
+type HoldBlockFactory struct{}
+
+func (f *HoldBlockFactory) CreateBlock() blocks.Block {
+	return &HoldBlock{}
+}
+
+func (f *HoldBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &HoldBlock{}
+	return blocks.BlockInfo{
+		Type:        "hold",
+		Name:        "Hold",
+		Description: "Remember the last message and replay it on request",
+		Category:    "sequence",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "pin",
+		Color:       "#795548",
+	}
+}
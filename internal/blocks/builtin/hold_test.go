@@ -0,0 +1,75 @@
+package builtin
+
+import (
+	"testing"
+
+	"block-flow/internal/models"
+)
+
+func TestHoldReplay(t *testing.T) {
+	block := &HoldBlock{}
+	properties := map[string]interface{}{}
+
+	// send passes a message on topic to the block
+	send := func(topic string, payload interface{}) []*models.Message {
+		t.Helper()
+		msg := models.NewMessage(payload)
+		msg.Topic = topic
+		out, err := block.Execute(&models.BlockExecutionContext{NodeID: "hold", Message: msg, Logger: nopLogger{}}, properties)
+		if err != nil {
+			t.Fatalf("execute: %v", err)
+		}
+		return out
+	}
+
+	if out := send("replay", nil); len(out) != 0 {
+		t.Errorf("replay with nothing held emitted %v", out[0].Payload)
+	}
+
+	value := map[string]interface{}{"temp": 21.5}
+	out := send("reading", value)
+	if len(out) != 1 || out[0].Payload.(map[string]interface{})["temp"] != 21.5 {
+		t.Fatalf("value not passed through: %v", out)
+	}
+	// Downstream changes to the forwarded message don't reach the held copy
+	out[0].Topic = "changed"
+
+	out = send("replay", nil)
+	if len(out) != 1 {
+		t.Fatalf("replay emitted %d messages", len(out))
+	}
+	if got := out[0].Payload.(map[string]interface{})["temp"]; got != 21.5 {
+		t.Errorf("replayed temp = %v, want 21.5", got)
+	}
+	if out[0].Topic != "reading" || out[0].Source != "hold" {
+		t.Errorf("replayed topic %q source %q", out[0].Topic, out[0].Source)
+	}
+	if replayed, _ := out[0].GetHeader("replayed"); replayed != "true" {
+		t.Error("replayed message not marked")
+	}
+
+	// Replaying again gives the same value
+	if out := send("replay", nil); len(out) != 1 {
+		t.Errorf("second replay emitted %d messages", len(out))
+	}
+
+	send("clear", nil)
+	if out := send("replay", nil); len(out) != 0 {
+		t.Errorf("replay after clear emitted %v", out[0].Payload)
+	}
+
+	t.Run("without pass-through", func(t *testing.T) {
+		block := &HoldBlock{}
+		properties := map[string]interface{}{"passThrough": false}
+		ctx := &models.BlockExecutionContext{NodeID: "hold", Message: models.NewMessage(1.0), Logger: nopLogger{}}
+		if out, _ := block.Execute(ctx, properties); len(out) != 0 {
+			t.Errorf("held value forwarded: %v", out[0].Payload)
+		}
+		replay := models.NewMessage(nil)
+		replay.Topic = "replay"
+		ctx.Message = replay
+		if out, _ := block.Execute(ctx, properties); len(out) != 1 || out[0].Payload != 1.0 {
+			t.Errorf("replay = %v, want the held value", out)
+		}
+	})
+}
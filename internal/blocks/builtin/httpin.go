@@ -0,0 +1,479 @@
+package builtin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/endpoints"
+	"block-flow/internal/models"
+)
+
+// httpRequestContextKey is the message context entry carrying the ID of the
+// HTTP request a message answers, from HTTP In to HTTP Response
+const httpRequestContextKey = "http_request_id"
+
+// httpInMaxBody limits the size of request bodies read by HTTP In nodes
+const httpInMaxBody = 10 << 20
+
+// HTTPInBlock serves an HTTP endpoint while its flow runs and emits a message
+// for every request. The request waits for an HTTP Response node to answer
+// it, or is accepted straight away
+type HTTPInBlock struct {
+	endpoints *endpoints.Registry
+}
+
+func (b *HTTPInBlock) GetType() string {
+	return "http-in"
+}
+
+func (b *HTTPInBlock) GetName() string {
+	return "HTTP In"
+}
+
+func (b *HTTPInBlock) GetDescription() string {
+	return "Serve an HTTP endpoint and emit a message for each request"
+}
+
+func (b *HTTPInBlock) GetCategory() string {
+	return "input"
+}
+
+func (b *HTTPInBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.InputGroup
+}
+
+func (b *HTTPInBlock) GetInputs() int {
+	return 0
+}
+
+func (b *HTTPInBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *HTTPInBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "HTTP In",
+		},
+		{
+			Name:         "method",
+			Type:         "select",
+			DisplayName:  "Method",
+			Description:  "HTTP method the endpoint accepts",
+			Required:     false,
+			DefaultValue: "POST",
+			Options: []blocks.Option{
+				{Label: "GET", Value: "GET"},
+				{Label: "POST", Value: "POST"},
+				{Label: "PUT", Value: "PUT"},
+				{Label: "PATCH", Value: "PATCH"},
+				{Label: "DELETE", Value: "DELETE"},
+			},
+		},
+		{
+			Name:         "path",
+			Type:         "string",
+			DisplayName:  "Path",
+			Description:  "Endpoint path, served under /endpoints (e.g. /orders)",
+			Required:     true,
+			DefaultValue: "",
+		},
+		{
+			Name:         "respond",
+			Type:         "select",
+			DisplayName:  "Respond",
+			Description:  "Wait for an HTTP Response node, or answer 202 Accepted as soon as the message is emitted",
+			Required:     false,
+			DefaultValue: "flow",
+			Options: []blocks.Option{
+				{Label: "From the flow", Value: "flow"},
+				{Label: "Immediately", Value: "immediately"},
+			},
+		},
+		{
+			Name:         "timeout",
+			Type:         "number",
+			DisplayName:  "Timeout (ms)",
+			Description:  "How long a request waits for the flow's response before failing with 504",
+			Required:     false,
+			DefaultValue: 10000,
+			Validation: blocks.Validation{
+				Min: &[]float64{1}[0],
+			},
+		},
+		{
+			Name:         "topic",
+			Type:         "string",
+			DisplayName:  "Topic",
+			Description:  "Topic set on emitted messages",
+			Required:     false,
+			DefaultValue: "",
+		},
+	}
+}
+
+func (b *HTTPInBlock) Validate(properties map[string]interface{}) error {
+	if strings.TrimSpace(stringProperty(properties, "path", "")) == "" {
+		return fmt.Errorf("path property is required")
+	}
+	switch strings.ToUpper(stringProperty(properties, "method", "POST")) {
+	case "GET", "POST", "PUT", "PATCH", "DELETE":
+	default:
+		return fmt.Errorf("invalid method '%s'", stringProperty(properties, "method", "POST"))
+	}
+	respond := stringProperty(properties, "respond", "flow")
+	if respond != "flow" && respond != "immediately" {
+		return fmt.Errorf("invalid respond '%s'", respond)
+	}
+	return nil
+}
+
+// Execute emits nothing; requests are delivered through Run
+func (b *HTTPInBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	return []*models.Message{}, nil
+}
+
+// Run serves the endpoint until the flow stops. Requests are handed to this
+// goroutine, which emits them one at a time
+func (b *HTTPInBlock) Run(ctx *models.BlockExecutionContext, properties map[string]interface{}, emit func(*models.Message)) error {
+	if b.endpoints == nil {
+		return blocks.Fatal(fmt.Errorf("HTTP endpoints are not available"))
+	}
+	if err := b.Validate(properties); err != nil {
+		return blocks.Invalid(err)
+	}
+	method := strings.ToUpper(stringProperty(properties, "method", "POST"))
+	path := stringProperty(properties, "path", "")
+	waitForFlow := stringProperty(properties, "respond", "flow") == "flow"
+	timeout := time.Duration(intProperty(properties, "timeout", 10000)) * time.Millisecond
+	topic := stringProperty(properties, "topic", "")
+
+	requests := make(chan *models.Message)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		msg, err := httpInMessage(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		msg.Topic = topic
+		msg.Source = ctx.NodeID
+
+		var replies <-chan endpoints.Response
+		if waitForFlow {
+			id := msg.ID
+			var cancel func()
+			replies, cancel = b.endpoints.Await(id)
+			defer cancel()
+			msg.Context[httpRequestContextKey] = id
+
+			// Answer requests the flow failed or finished without responding
+			msg.Delivery = models.NewDelivery(func(err error) {
+				if err != nil {
+					b.endpoints.Respond(id, endpoints.Response{Status: http.StatusInternalServerError, Body: []byte(err.Error())})
+					return
+				}
+				b.endpoints.Respond(id, endpoints.Response{Status: http.StatusNoContent})
+			})
+		}
+
+		select {
+		case requests <- msg:
+		case <-ctx.Context.Done():
+			http.Error(w, "Flow is stopping", http.StatusServiceUnavailable)
+			return
+		case <-r.Context().Done():
+			return
+		}
+		if !waitForFlow {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case reply := <-replies:
+			writeHTTPResponse(w, reply)
+		case <-timer.C:
+			http.Error(w, "Flow did not respond in time", http.StatusGatewayTimeout)
+		case <-ctx.Context.Done():
+			http.Error(w, "Flow is stopping", http.StatusServiceUnavailable)
+		case <-r.Context().Done():
+		}
+	}
+
+	unregister, err := b.endpoints.Register(method, path, http.HandlerFunc(handler))
+	if err != nil {
+		return blocks.Fatal(err)
+	}
+	defer unregister()
+
+	for {
+		select {
+		case <-ctx.Context.Done():
+			return nil
+		case msg := <-requests:
+			emit(msg)
+		}
+	}
+}
+
+// httpInMessage converts a request into a message whose payload holds the
+// method, path, query, headers and body. JSON bodies are decoded, anything
+// else is kept as text. Repeated query parameters and headers become arrays
+func httpInMessage(w http.ResponseWriter, r *http.Request) (*models.Message, error) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, httpInMaxBody))
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	var body interface{}
+	if len(data) > 0 {
+		body = string(data)
+		if strings.HasPrefix(r.Header.Get("Content-Type"), models.ContentTypeJSON) {
+			if err := models.DecodeJSON(data, &body); err != nil {
+				return nil, fmt.Errorf("invalid JSON body: %w", err)
+			}
+		}
+	}
+
+	return models.NewMessage(map[string]interface{}{
+		"method":  r.Method,
+		"path":    r.URL.Path,
+		"query":   httpValues(r.URL.Query()),
+		"headers": httpValues(r.Header),
+		"body":    body,
+	}), nil
+}
+
+// httpValues flattens multi-valued query parameters or headers
+func httpValues(values map[string][]string) map[string]interface{} {
+	flat := make(map[string]interface{}, len(values))
+	for name, list := range values {
+		if len(list) == 1 {
+			flat[name] = list[0]
+			continue
+		}
+		items := make([]interface{}, len(list))
+		for i, value := range list {
+			items[i] = value
+		}
+		flat[name] = items
+	}
+	return flat
+}
+
+// writeHTTPResponse writes a flow's reply to the waiting request
+func writeHTTPResponse(w http.ResponseWriter, reply endpoints.Response) {
+	for name, value := range reply.Headers {
+		w.Header().Set(name, value)
+	}
+	w.WriteHeader(reply.Status)
+	w.Write(reply.Body)
+}
+
+// HTTPInBlockFactory creates HTTP In block instances serving their endpoints
+// from the engine's registry
+type HTTPInBlockFactory struct {
+	endpoints *endpoints.Registry
+}
+
+func (f *HTTPInBlockFactory) CreateBlock() blocks.Block {
+	return &HTTPInBlock{endpoints: f.endpoints}
+}
+
+func (f *HTTPInBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &HTTPInBlock{}
+	return blocks.BlockInfo{
+		Type:        "http-in",
+		Name:        "HTTP In",
+		Description: "Serve an HTTP endpoint and emit a message for each request",
+		Category:    "input",
+		BlockGroup:  blocks.InputGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "globe",
+		Color:       "#4CAF50",
+	}
+}
+
+// HTTPResponseBlock answers the HTTP In request a message originates from,
+// with the message payload as the response body
+type HTTPResponseBlock struct {
+	endpoints *endpoints.Registry
+}
+
+func (b *HTTPResponseBlock) GetType() string {
+	return "http-response"
+}
+
+func (b *HTTPResponseBlock) GetName() string {
+	return "HTTP Response"
+}
+
+func (b *HTTPResponseBlock) GetDescription() string {
+	return "Send the payload as the response to the originating HTTP In request"
+}
+
+func (b *HTTPResponseBlock) GetCategory() string {
+	return "output"
+}
+
+func (b *HTTPResponseBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.ActionGroup
+}
+
+func (b *HTTPResponseBlock) GetInputs() int {
+	return 1
+}
+
+func (b *HTTPResponseBlock) GetOutputs() int {
+	return 0
+}
+
+func (b *HTTPResponseBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "HTTP Response",
+		},
+		{
+			Name:         "status",
+			Type:         "number",
+			DisplayName:  "Status Code",
+			Description:  "HTTP status code of the response",
+			Required:     false,
+			DefaultValue: 200,
+			Validation: blocks.Validation{
+				Min: &[]float64{100}[0],
+				Max: &[]float64{599}[0],
+			},
+		},
+		{
+			Name:         "headers",
+			Type:         "json",
+			DisplayName:  "Headers",
+			Description:  `Response headers, e.g. {"Cache-Control": "no-cache"}`,
+			Required:     false,
+			DefaultValue: map[string]interface{}{},
+		},
+	}
+}
+
+func (b *HTTPResponseBlock) Validate(properties map[string]interface{}) error {
+	if status := intProperty(properties, "status", 200); status < 100 || status > 599 {
+		return fmt.Errorf("invalid status %d", status)
+	}
+	if _, err := pollHeaders(properties); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (b *HTTPResponseBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+	if b.endpoints == nil {
+		return nil, blocks.Fatal(fmt.Errorf("HTTP endpoints are not available"))
+	}
+
+	id, _ := ctx.Message.Context[httpRequestContextKey].(string)
+	if id == "" {
+		return nil, blocks.Invalidf("message does not originate from an HTTP In request")
+	}
+	headers, err := pollHeaders(properties)
+	if err != nil {
+		return nil, blocks.Invalid(err)
+	}
+
+	body, contentType, err := httpResponseBody(ctx.Message)
+	if err != nil {
+		return nil, blocks.Invalid(err)
+	}
+	response := endpoints.Response{
+		Status:  intProperty(properties, "status", 200),
+		Headers: map[string]string{},
+		Body:    body,
+	}
+	if contentType != "" {
+		response.Headers["Content-Type"] = contentType
+	}
+	for name, value := range headers {
+		response.Headers[name] = value
+	}
+
+	if !b.endpoints.Respond(id, response) {
+		return nil, blocks.Invalidf("request %s is no longer waiting for a response", id)
+	}
+	return []*models.Message{}, nil
+}
+
+// httpResponseBody encodes a payload as a response body: text and binary
+// payloads are sent as they are, with the message content type if it has
+// one, anything else as JSON
+func httpResponseBody(msg *models.Message) ([]byte, string, error) {
+	switch payload := msg.Payload.(type) {
+	case nil:
+		return nil, "", nil
+	case string:
+		contentType := msg.ContentType
+		if contentType == "" {
+			contentType = models.ContentTypeText
+		}
+		return []byte(payload), contentType, nil
+	case []byte:
+		contentType := msg.ContentType
+		if contentType == "" {
+			contentType = models.ContentTypeBinary
+		}
+		return payload, contentType, nil
+	}
+	data, err := json.Marshal(msg.Payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("encode payload: %w", err)
+	}
+	return data, models.ContentTypeJSON, nil
+}
+
+// HTTPResponseBlockFactory creates HTTP Response block instances answering
+// requests from the engine's registry
+type HTTPResponseBlockFactory struct {
+	endpoints *endpoints.Registry
+}
+
+func (f *HTTPResponseBlockFactory) CreateBlock() blocks.Block {
+	return &HTTPResponseBlock{endpoints: f.endpoints}
+}
+
+func (f *HTTPResponseBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &HTTPResponseBlock{}
+	return blocks.BlockInfo{
+		Type:        "http-response",
+		Name:        "HTTP Response",
+		Description: "Send the payload as the response to the originating HTTP In request",
+		Category:    "output",
+		BlockGroup:  blocks.ActionGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "send",
+		Color:       "#FF9800",
+	}
+}
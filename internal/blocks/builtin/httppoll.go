@@ -0,0 +1,303 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// httpPollMaxBody caps the size of a single poll response
+const httpPollMaxBody = 10 << 20
+
+// HTTPPollBlock long-polls an HTTP endpoint: it issues a GET, waits for the
+// server to answer with data, emits it and polls again right away. Polls that
+// end without data (timeout, 204, 304) are repeated immediately; failures are
+// retried with exponential backoff
+type HTTPPollBlock struct {
+	client *http.Client
+}
+
+func (b *HTTPPollBlock) GetType() string {
+	return "http-poll"
+}
+
+func (b *HTTPPollBlock) GetName() string {
+	return "HTTP Long Poll"
+}
+
+func (b *HTTPPollBlock) GetDescription() string {
+	return "Long-poll an HTTP endpoint and emit each response"
+}
+
+func (b *HTTPPollBlock) GetCategory() string {
+	return "input"
+}
+
+func (b *HTTPPollBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.InputGroup
+}
+
+func (b *HTTPPollBlock) GetInputs() int {
+	return 0
+}
+
+func (b *HTTPPollBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *HTTPPollBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "HTTP Long Poll",
+		},
+		{
+			Name:        "url",
+			Type:        "string",
+			DisplayName: "URL",
+			Description: "Long-poll endpoint (http or https)",
+			Required:    true,
+		},
+		{
+			Name:         "timeout",
+			Type:         "number",
+			DisplayName:  "Timeout (ms)",
+			Description:  "How long to wait for a response before polling again",
+			Required:     false,
+			DefaultValue: 30000,
+			Validation: blocks.Validation{
+				Min: &[]float64{1}[0],
+			},
+		},
+		{
+			Name:        "headers",
+			Type:        "json",
+			DisplayName: "Headers",
+			Description: `Request headers, e.g. {"Authorization": "Bearer ..."}`,
+			Required:    false,
+		},
+		{
+			Name:         "backoff",
+			Type:         "number",
+			DisplayName:  "Backoff (ms)",
+			Description:  "Delay before reconnecting after a failure, doubled on each consecutive failure",
+			Required:     false,
+			DefaultValue: 1000,
+			Validation: blocks.Validation{
+				Min: &[]float64{0}[0],
+			},
+		},
+		{
+			Name:         "maxBackoff",
+			Type:         "number",
+			DisplayName:  "Max Backoff (ms)",
+			Description:  "Upper bound of the reconnect delay",
+			Required:     false,
+			DefaultValue: 30000,
+			Validation: blocks.Validation{
+				Min: &[]float64{0}[0],
+			},
+		},
+		{
+			Name:         "topic",
+			Type:         "string",
+			DisplayName:  "Topic",
+			Description:  "Optional topic for the messages",
+			Required:     false,
+			DefaultValue: "",
+		},
+	}
+}
+
+func (b *HTTPPollBlock) Validate(properties map[string]interface{}) error {
+	target := stringProperty(properties, "url", "")
+	if target == "" {
+		return fmt.Errorf("url property is required")
+	}
+	parsed, err := url.Parse(target)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+	if _, err := pollHeaders(properties); err != nil {
+		return err
+	}
+	return nil
+}
+
+// pollHeaders reads the headers property: an object of strings, or its JSON text
+func pollHeaders(properties map[string]interface{}) (map[string]string, error) {
+	value := properties["headers"]
+	if text, ok := value.(string); ok {
+		if text == "" {
+			return nil, nil
+		}
+		var decoded interface{}
+		if err := models.DecodeJSON([]byte(text), &decoded); err != nil {
+			return nil, fmt.Errorf("headers is not valid JSON: %w", err)
+		}
+		value = decoded
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	object, err := models.ToMap(value)
+	if err != nil {
+		return nil, fmt.Errorf("headers: %w", err)
+	}
+	headers := make(map[string]string, len(object))
+	for name, raw := range object {
+		text, err := models.ToString(raw)
+		if err != nil {
+			return nil, fmt.Errorf("header '%s': %w", name, err)
+		}
+		headers[name] = text
+	}
+	return headers, nil
+}
+
+// Execute emits nothing; responses are delivered through Run
+func (b *HTTPPollBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	return []*models.Message{}, nil
+}
+
+// Run polls until the flow stops
+func (b *HTTPPollBlock) Run(ctx *models.BlockExecutionContext, properties map[string]interface{}, emit func(*models.Message)) error {
+	if err := b.Validate(properties); err != nil {
+		return blocks.Invalid(err)
+	}
+	headers, _ := pollHeaders(properties)
+	target := stringProperty(properties, "url", "")
+	timeout := time.Duration(intProperty(properties, "timeout", 30000)) * time.Millisecond
+	backoff := time.Duration(intProperty(properties, "backoff", 1000)) * time.Millisecond
+	maxBackoff := time.Duration(intProperty(properties, "maxBackoff", 30000)) * time.Millisecond
+	topic := stringProperty(properties, "topic", "")
+
+	failures := 0
+	for ctx.Context.Err() == nil {
+		msg, err := b.poll(ctx.Context, target, headers, timeout)
+		if err != nil {
+			if ctx.Context.Err() != nil {
+				return nil
+			}
+			delay := backoff << min(failures, 16)
+			if delay > maxBackoff {
+				delay = maxBackoff
+			}
+			failures++
+			ctx.Logger.Warn("HTTP poll failed, reconnecting", map[string]interface{}{
+				"url":      target,
+				"failures": failures,
+				"backoff":  delay.String(),
+				"error":    err.Error(),
+			})
+
+			select {
+			case <-ctx.Context.Done():
+				return nil
+			case <-time.After(delay):
+			}
+			continue
+		}
+
+		failures = 0
+		if msg == nil {
+			continue // No data this round
+		}
+		msg.Topic = topic
+		msg.Source = ctx.NodeID
+		emit(msg)
+	}
+	return nil
+}
+
+// poll issues one long-poll request. It returns a nil message when the poll
+// ended without data
+func (b *HTTPPollBlock) poll(ctx context.Context, target string, headers map[string]string, timeout time.Duration) (*models.Message, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	client := b.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return nil, nil // The server had nothing within the timeout
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotModified:
+		return nil, nil
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, httpPollMaxBody))
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return nil, fmt.Errorf("response not completed within timeout")
+		}
+		return nil, err
+	}
+
+	var payload interface{} = string(body)
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), models.ContentTypeJSON) {
+		if err := models.DecodeJSON(body, &payload); err != nil {
+			return nil, fmt.Errorf("invalid JSON response: %w", err)
+		}
+	}
+
+	msg := models.NewMessage(payload)
+	msg.SetHeader("status_code", strconv.Itoa(resp.StatusCode))
+	return msg, nil
+}
+
+// HTTPPollBlockFactory creates HTTP long-poll block instances
+type HTTPPollBlockFactory struct{}
+
+func (f *HTTPPollBlockFactory) CreateBlock() blocks.Block {
+	return &HTTPPollBlock{}
+}
+
+func (f *HTTPPollBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &HTTPPollBlock{}
+	return blocks.BlockInfo{
+		Type:        "http-poll",
+		Name:        "HTTP Long Poll",
+		Description: "Long-poll an HTTP endpoint and emit each response",
+		Category:    "input",
+		BlockGroup:  blocks.InputGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "globe",
+		Color:       "#4CAF50",
+	}
+}
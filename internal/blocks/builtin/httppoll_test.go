@@ -0,0 +1,103 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+func TestHTTPPollRun(t *testing.T) {
+	// Responses in order; once exhausted the server answers 204
+	responses := []func(w http.ResponseWriter){
+		func(w http.ResponseWriter) { w.WriteHeader(http.StatusNoContent) },
+		func(w http.ResponseWriter) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"n": 1}`)
+		},
+		func(w http.ResponseWriter) { w.WriteHeader(http.StatusInternalServerError) },
+		func(w http.ResponseWriter) { fmt.Fprint(w, "two") },
+	}
+
+	var (
+		requests []time.Time
+		mu       sync.Mutex
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		i := len(requests)
+		requests = append(requests, time.Now())
+		mu.Unlock()
+
+		if r.Header.Get("X-Token") != "abc" {
+			t.Errorf("request %d missing the configured header", i)
+		}
+		if i < len(responses) {
+			responses[i](w)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	const backoff = 100 * time.Millisecond
+	properties := map[string]interface{}{
+		"url":     server.URL,
+		"headers": `{"X-Token": "abc"}`,
+		"backoff": float64(backoff / time.Millisecond),
+		"topic":   "poll",
+	}
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx := &models.BlockExecutionContext{Context: runCtx, NodeID: "poll", Logger: nopLogger{}}
+
+	emitted := make(chan *models.Message, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- (&HTTPPollBlock{}).Run(ctx, properties, func(msg *models.Message) { emitted <- msg })
+	}()
+
+	var payloads []interface{}
+	for len(payloads) < 2 {
+		select {
+		case msg := <-emitted:
+			if msg.Topic != "poll" || msg.Source != "poll" {
+				t.Errorf("message topic %q, source %q; want poll, poll", msg.Topic, msg.Source)
+			}
+			payloads = append(payloads, msg.Payload)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("emitted %v before timing out", payloads)
+		}
+	}
+	want := []interface{}{map[string]interface{}{"n": 1.0}, "two"}
+	if !reflect.DeepEqual(payloads, want) {
+		t.Errorf("emitted %v, want %v", payloads, want)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run returned %v after the flow stopped", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return once cancelled")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The failed third poll is retried after the backoff, the others at once
+	if gap := requests[3].Sub(requests[2]); gap < backoff {
+		t.Errorf("reconnected %s after a failure, want at least %s", gap, backoff)
+	}
+	if gap := requests[1].Sub(requests[0]); gap >= backoff {
+		t.Errorf("polled again %s after an empty response, want immediately", gap)
+	}
+}
@@ -3,13 +3,19 @@ package builtin
 import (
 	"fmt"
 	"strconv"
+	"sync"
 
 	"block-flow/internal/blocks"
 	"block-flow/internal/models"
 )
 
-// InjectBlock provides manual input trigger with configurable payload
-type InjectBlock struct{}
+// InjectBlock provides manual input trigger with configurable payload. With a
+// sequence configured it emits the next element on every trigger instead
+type InjectBlock struct {
+	next      int  // Index of the sequence element emitted next
+	exhausted bool // A non-looping sequence has emitted its last element
+	mu        sync.Mutex
+}
 
 func (b *InjectBlock) GetType() string {
 	return "inject"
@@ -54,7 +60,7 @@ func (b *InjectBlock) GetProperties() []blocks.PropertyDefinition {
 			Type:         "string",
 			DisplayName:  "Output Value",
 			Description:  "The value to inject (will be converted to number if possible)",
-			Required:     true,
+			Required:     false, // Unless a sequence is set, checked by Validate
 			DefaultValue: "0",
 		},
 		{
@@ -89,17 +95,78 @@ func (b *InjectBlock) GetProperties() []blocks.PropertyDefinition {
 				{Label: "Boolean", Value: "boolean"},
 			},
 		},
+		{
+			Name:        "sequence",
+			Type:        "json",
+			DisplayName: "Sequence",
+			Description: `Payloads emitted in turn, one per trigger, e.g. ["idle", "running", "stopped"]; replaces payload when set`,
+			Required:    false,
+		},
+		{
+			Name:         "loop",
+			Type:         "boolean",
+			DisplayName:  "Loop",
+			Description:  "Start the sequence over after its last element instead of stopping",
+			Required:     false,
+			DefaultValue: true,
+		},
 	}
 }
 
 func (b *InjectBlock) Validate(properties map[string]interface{}) error {
+	sequence, err := parseInjectSequence(properties)
+	if err != nil {
+		return err
+	}
+	if sequence != nil {
+		return nil
+	}
 	if _, ok := properties["payload"]; !ok {
 		return fmt.Errorf("payload property is required")
 	}
 	return nil
 }
 
+// parseInjectSequence decodes the sequence property, which may be an array or
+// its JSON text. It returns nil when no sequence is configured
+func parseInjectSequence(properties map[string]interface{}) ([]interface{}, error) {
+	var data []byte
+	switch value := properties["sequence"].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if value == "" {
+			return nil, nil
+		}
+		data = []byte(value)
+	case []interface{}:
+		if len(value) == 0 {
+			return nil, fmt.Errorf("sequence must not be empty")
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("sequence must be an array of payloads")
+	}
+
+	var sequence []interface{}
+	if err := models.DecodeJSON(data, &sequence); err != nil {
+		return nil, fmt.Errorf("sequence must be an array of payloads: %w", err)
+	}
+	if len(sequence) == 0 {
+		return nil, fmt.Errorf("sequence must not be empty")
+	}
+	return sequence, nil
+}
+
 func (b *InjectBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	sequence, err := parseInjectSequence(properties)
+	if err != nil {
+		return nil, blocks.Invalid(err)
+	}
+	if sequence != nil {
+		return b.executeSequence(ctx, properties, sequence)
+	}
+
 	// Get properties
 	payloadStr, _ := properties["payload"].(string)
 	topic, _ := properties["topic"].(string)
@@ -109,19 +176,18 @@ func (b *InjectBlock) Execute(ctx *models.BlockExecutionContext, properties map[
 	}
 
 	var payload interface{}
-	var err error
 
 	// Convert payload based on type
 	switch payloadType {
 	case "number":
 		payload, err = strconv.ParseFloat(payloadStr, 64)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse payload as number: %w", err)
+			return nil, blocks.Invalidf("failed to parse payload as number: %w", err)
 		}
 	case "boolean":
 		payload, err = strconv.ParseBool(payloadStr)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse payload as boolean: %w", err)
+			return nil, blocks.Invalidf("failed to parse payload as boolean: %w", err)
 		}
 	default:
 		payload = payloadStr
@@ -141,6 +207,39 @@ func (b *InjectBlock) Execute(ctx *models.BlockExecutionContext, properties map[
 	return []*models.Message{outputMsg}, nil
 }
 
+// executeSequence emits the next sequence element. A non-looping sequence
+// emits nothing once its last element has been sent
+func (b *InjectBlock) executeSequence(ctx *models.BlockExecutionContext, properties map[string]interface{}, sequence []interface{}) ([]*models.Message, error) {
+	b.mu.Lock()
+	if b.next >= len(sequence) { // The sequence may have shrunk since the last trigger
+		b.next = 0
+	}
+	if b.exhausted {
+		b.mu.Unlock()
+		return []*models.Message{}, nil
+	}
+	index := b.next
+	b.next++
+	if b.next == len(sequence) {
+		b.next = 0
+		b.exhausted = !boolProperty(properties, "loop", true)
+	}
+	b.mu.Unlock()
+
+	outputMsg := models.NewMessage(sequence[index])
+	outputMsg.Topic = stringProperty(properties, "topic", "")
+	outputMsg.Source = ctx.NodeID
+	outputMsg.SetHeader("sequence_index", strconv.Itoa(index))
+
+	ctx.Logger.Debug("Inject block executed", map[string]interface{}{
+		"payload":        sequence[index],
+		"sequence_index": index,
+		"topic":          outputMsg.Topic,
+	})
+
+	return []*models.Message{outputMsg}, nil
+}
+
 // InjectBlockFactory creates inject block instances
 type InjectBlockFactory struct{}
 
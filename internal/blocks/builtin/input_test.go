@@ -0,0 +1,64 @@
+package builtin
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"block-flow/internal/models"
+)
+
+func TestInjectSequence(t *testing.T) {
+	// fire triggers the block n times, returning the payloads it emitted
+	fire := func(t *testing.T, block *InjectBlock, properties map[string]interface{}, n int) []interface{} {
+		t.Helper()
+		var payloads []interface{}
+		for i := 0; i < n; i++ {
+			out, err := block.Execute(&models.BlockExecutionContext{NodeID: "inject", Logger: nopLogger{}}, properties)
+			if err != nil {
+				t.Fatalf("trigger %d: %v", i, err)
+			}
+			for _, msg := range out {
+				index, _ := msg.GetHeader("sequence_index")
+				if want := strconv.Itoa(len(payloads) % 3); index != want {
+					t.Errorf("payload %v has sequence_index %q, want %s", msg.Payload, index, want)
+				}
+				payloads = append(payloads, msg.Payload)
+			}
+		}
+		return payloads
+	}
+
+	t.Run("loops", func(t *testing.T) {
+		block := &InjectBlock{}
+		properties := map[string]interface{}{"sequence": `["idle", "running", "stopped"]`}
+		if err := block.Validate(properties); err != nil {
+			t.Fatalf("validate: %v", err)
+		}
+		got := fire(t, block, properties, 7)
+		want := []interface{}{"idle", "running", "stopped", "idle", "running", "stopped", "idle"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("emitted %v, want %v", got, want)
+		}
+	})
+
+	t.Run("stops without loop", func(t *testing.T) {
+		block := &InjectBlock{}
+		properties := map[string]interface{}{"sequence": []interface{}{1.0, 2.0, 3.0}, "loop": false}
+		if err := block.Validate(properties); err != nil {
+			t.Fatalf("validate: %v", err)
+		}
+		got := fire(t, block, properties, 5)
+		if want := []interface{}{1.0, 2.0, 3.0}; !reflect.DeepEqual(got, want) {
+			t.Errorf("emitted %v, want [1 2 3] then nothing", got)
+		}
+	})
+
+	t.Run("invalid sequence", func(t *testing.T) {
+		for _, sequence := range []interface{}{`[]`, `{"a": 1}`, []interface{}{}, 3.0} {
+			if err := (&InjectBlock{}).Validate(map[string]interface{}{"sequence": sequence}); err == nil {
+				t.Errorf("sequence %#v accepted", sequence)
+			}
+		}
+	})
+}
@@ -0,0 +1,303 @@
+package builtin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// joinStatePrefix prefixes the node state entries holding partial groups
+const joinStatePrefix = "join:"
+
+// joinGroup is a partially received sequence, held in the node state
+type joinGroup struct {
+	parts     map[int]joinPart // By index, so repeated parts count once
+	count     int
+	build     string // array, object or string
+	delimiter string
+	started   time.Time
+	last      *models.Message
+}
+
+// joinPart is one received message of a group
+type joinPart struct {
+	key     string
+	payload interface{}
+}
+
+// JoinBlock reassembles sequences of messages, such as those emitted by a
+// Split block, into one message. Groups are complete once all their parts
+// arrived; groups still incomplete after the timeout are emitted with the
+// parts received so far and dropped from the node state
+type JoinBlock struct{}
+
+func (b *JoinBlock) GetType() string {
+	return "join"
+}
+
+func (b *JoinBlock) GetName() string {
+	return "Join"
+}
+
+func (b *JoinBlock) GetDescription() string {
+	return "Combine a sequence of messages into an array, object or string"
+}
+
+func (b *JoinBlock) GetCategory() string {
+	return "sequence"
+}
+
+func (b *JoinBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *JoinBlock) GetInputs() int {
+	return 1
+}
+
+func (b *JoinBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *JoinBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Join",
+		},
+		{
+			Name:         "mode",
+			Type:         "select",
+			DisplayName:  "Mode",
+			Description:  "Rebuild sequences from their parts, or join every few messages",
+			Required:     false,
+			DefaultValue: "auto",
+			Options: []blocks.Option{
+				{Label: "Automatic (from parts)", Value: "auto"},
+				{Label: "Manual", Value: "manual"},
+			},
+		},
+		{
+			Name:         "count",
+			Type:         "number",
+			DisplayName:  "Count",
+			Description:  "Messages per group in manual mode",
+			Required:     false,
+			DefaultValue: 10,
+			Validation: blocks.Validation{
+				Min: &[]float64{1}[0],
+			},
+		},
+		{
+			Name:         "build",
+			Type:         "select",
+			DisplayName:  "Build",
+			Description:  "What manual mode builds: an array, an object keyed by topic, or a string",
+			Required:     false,
+			DefaultValue: "array",
+			Options: []blocks.Option{
+				{Label: "Array", Value: models.PartsArray},
+				{Label: "Object", Value: models.PartsObject},
+				{Label: "String", Value: models.PartsString},
+			},
+		},
+		{
+			Name:         "joiner",
+			Type:         "string",
+			DisplayName:  "Joiner",
+			Description:  "Separator between parts when manual mode builds a string",
+			Required:     false,
+			DefaultValue: "\n",
+		},
+		{
+			Name:         "timeout",
+			Type:         "number",
+			DisplayName:  "Timeout (ms)",
+			Description:  "How long a group waits for its missing parts before it is emitted incomplete",
+			Required:     false,
+			DefaultValue: 30000,
+			Validation: blocks.Validation{
+				Min: &[]float64{1}[0],
+			},
+		},
+	}
+}
+
+func (b *JoinBlock) Validate(properties map[string]interface{}) error {
+	mode := stringProperty(properties, "mode", "auto")
+	if mode != "auto" && mode != "manual" {
+		return fmt.Errorf("invalid mode '%s'", mode)
+	}
+	switch build := stringProperty(properties, "build", models.PartsArray); build {
+	case models.PartsArray, models.PartsObject, models.PartsString:
+	default:
+		return fmt.Errorf("invalid build '%s'", build)
+	}
+	return nil
+}
+
+func (b *JoinBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	outputs := b.expire(ctx, properties)
+
+	var groupID string
+	var index int
+	part := joinPart{payload: ctx.Message.Payload}
+
+	var group *joinGroup
+	if stringProperty(properties, "mode", "auto") == "manual" {
+		groupID = "manual"
+		group = b.group(ctx, groupID)
+		group.count = max(intProperty(properties, "count", 10), 1)
+		group.build = stringProperty(properties, "build", models.PartsArray)
+		group.delimiter = stringProperty(properties, "joiner", "\n")
+		index = len(group.parts)
+		part.key = ctx.Message.Topic
+	} else {
+		parts, ok := ctx.Message.GetParts()
+		if !ok {
+			return nil, blocks.Invalidf("message is not part of a sequence")
+		}
+		if parts.Count <= 0 || parts.Index < 0 || parts.Index >= parts.Count {
+			return nil, blocks.Invalidf("invalid parts: index %d of %d", parts.Index, parts.Count)
+		}
+		groupID = parts.ID
+		group = b.group(ctx, groupID)
+		group.count = parts.Count
+		group.build = parts.Type
+		group.delimiter = parts.Delimiter
+		index = parts.Index
+		part.key = parts.Key
+	}
+
+	group.parts[index] = part
+	group.last = ctx.Message
+	if len(group.parts) < group.count {
+		return outputs, nil
+	}
+
+	delete(ctx.State, joinStatePrefix+groupID)
+	return append(outputs, group.combine(ctx.NodeID)), nil
+}
+
+// group returns the partial group stored under id, starting it if needed
+func (b *JoinBlock) group(ctx *models.BlockExecutionContext, id string) *joinGroup {
+	if value, ok := ctx.GetState(joinStatePrefix + id); ok {
+		if group, ok := value.(*joinGroup); ok {
+			return group
+		}
+	}
+	group := &joinGroup{parts: make(map[int]joinPart), started: time.Now()}
+	ctx.SetState(joinStatePrefix+id, group)
+	return group
+}
+
+// TickInterval checks for timed-out groups a few times per timeout
+func (b *JoinBlock) TickInterval(properties map[string]interface{}) time.Duration {
+	interval := time.Duration(intProperty(properties, "timeout", 30000)) * time.Millisecond / 4
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	return interval
+}
+
+// Tick emits and forgets groups that waited longer than the timeout
+func (b *JoinBlock) Tick(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	return b.expire(ctx, properties), nil
+}
+
+// expire removes the groups that timed out and returns them combined from
+// the parts received so far
+func (b *JoinBlock) expire(ctx *models.BlockExecutionContext, properties map[string]interface{}) []*models.Message {
+	timeout := time.Duration(intProperty(properties, "timeout", 30000)) * time.Millisecond
+
+	outputs := make([]*models.Message, 0)
+	for key, value := range ctx.State {
+		group, ok := value.(*joinGroup)
+		if !ok || !strings.HasPrefix(key, joinStatePrefix) || time.Since(group.started) < timeout {
+			continue
+		}
+		delete(ctx.State, key)
+
+		ctx.Logger.Debug("Join timed out, emitting incomplete group", map[string]interface{}{
+			"group":    strings.TrimPrefix(key, joinStatePrefix),
+			"received": len(group.parts),
+			"count":    group.count,
+		})
+		outputs = append(outputs, group.combine(ctx.NodeID))
+	}
+	return outputs
+}
+
+// combine builds the joined message from the parts received, in index
+// order. It copies the last part received, without its parts entry
+func (g *joinGroup) combine(nodeID string) *models.Message {
+	indexes := make([]int, 0, len(g.parts))
+	for index := range g.parts {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	var payload interface{}
+	switch g.build {
+	case models.PartsObject:
+		object := make(map[string]interface{}, len(indexes))
+		for _, index := range indexes {
+			object[g.parts[index].key] = g.parts[index].payload
+		}
+		payload = object
+	case models.PartsString:
+		pieces := make([]string, len(indexes))
+		for i, index := range indexes {
+			pieces[i] = fmt.Sprint(g.parts[index].payload)
+		}
+		payload = strings.Join(pieces, g.delimiter)
+	default:
+		items := make([]interface{}, len(indexes))
+		for i, index := range indexes {
+			items[i] = g.parts[index].payload
+		}
+		payload = items
+	}
+
+	outputMsg := g.last.Clone()
+	outputMsg.Payload = payload
+	outputMsg.Source = nodeID
+	outputMsg.ClearParts()
+	return outputMsg
+}
+
+// JoinBlockFactory creates join block instances
+type JoinBlockFactory struct{}
+
+func (f *JoinBlockFactory) CreateBlock() blocks.Block {
+	return &JoinBlock{}
+}
+
+func (f *JoinBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &JoinBlock{}
+	return blocks.BlockInfo{
+		Type:        "join",
+		Name:        "Join",
+		Description: "Combine a sequence of messages into an array, object or string",
+		Category:    "sequence",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "merge",
+		Color:       "#795548",
+	}
+}
@@ -0,0 +1,155 @@
+package builtin
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// JSONBlock converts the payload between JSON text and structured values
+type JSONBlock struct{}
+
+func (b *JSONBlock) GetType() string {
+	return "json"
+}
+
+func (b *JSONBlock) GetName() string {
+	return "JSON"
+}
+
+func (b *JSONBlock) GetDescription() string {
+	return "Parse JSON text into a value or stringify a value into JSON text"
+}
+
+func (b *JSONBlock) GetCategory() string {
+	return "function"
+}
+
+func (b *JSONBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *JSONBlock) GetInputs() int {
+	return 1
+}
+
+func (b *JSONBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *JSONBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "JSON",
+		},
+		{
+			Name:         "direction",
+			Type:         "select",
+			DisplayName:  "Direction",
+			Description:  "Parse text, stringify values, or pick by payload type",
+			Required:     false,
+			DefaultValue: "auto",
+			Options: []blocks.Option{
+				{Label: "Auto", Value: "auto"},
+				{Label: "Parse", Value: "parse"},
+				{Label: "Stringify", Value: "stringify"},
+			},
+		},
+		{
+			Name:         "pretty",
+			Type:         "boolean",
+			DisplayName:  "Pretty Print",
+			Description:  "Indent stringified JSON",
+			Required:     false,
+			DefaultValue: false,
+		},
+	}
+}
+
+func (b *JSONBlock) Validate(properties map[string]interface{}) error {
+	switch direction := stringProperty(properties, "direction", "auto"); direction {
+	case "auto", "parse", "stringify":
+		return nil
+	default:
+		return fmt.Errorf("invalid direction '%s'", direction)
+	}
+}
+
+func (b *JSONBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	text, isText := ctx.Message.Payload.(string)
+	if data, ok := ctx.Message.Payload.([]byte); ok {
+		text, isText = string(data), true
+	}
+
+	direction := stringProperty(properties, "direction", "auto")
+	if direction == "auto" {
+		direction = "stringify"
+		if isText {
+			direction = "parse"
+		}
+	}
+
+	outputMsg := ctx.Message.Clone()
+	outputMsg.Source = ctx.NodeID
+	if direction == "parse" {
+		if !isText {
+			return nil, blocks.Invalidf("cannot parse a %T payload: expected JSON text", ctx.Message.Payload)
+		}
+		var payload interface{}
+		if err := models.DecodeJSON([]byte(text), &payload); err != nil {
+			return nil, blocks.Invalidf("payload is not valid JSON: %w", err)
+		}
+		outputMsg.Payload = payload
+		outputMsg.ContentType = ""
+		return []*models.Message{outputMsg}, nil
+	}
+
+	var data []byte
+	var err error
+	if boolProperty(properties, "pretty", false) {
+		data, err = json.MarshalIndent(ctx.Message.Payload, "", "  ")
+	} else {
+		data, err = json.Marshal(ctx.Message.Payload)
+	}
+	if err != nil {
+		return nil, blocks.Invalidf("payload cannot be stringified: %w", err)
+	}
+	outputMsg.Payload = string(data)
+	outputMsg.ContentType = models.ContentTypeJSON
+	return []*models.Message{outputMsg}, nil
+}
+
+// JSONBlockFactory creates JSON block instances
+type JSONBlockFactory struct{}
+
+func (f *JSONBlockFactory) CreateBlock() blocks.Block {
+	return &JSONBlock{}
+}
+
+func (f *JSONBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &JSONBlock{}
+	return blocks.BlockInfo{
+		Type:        "json",
+		Name:        "JSON",
+		Description: "Parse JSON text into a value or stringify a value into JSON text",
+		Category:    "function",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "braces",
+		Color:       "#9C27B0",
+	}
+}
@@ -0,0 +1,330 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+	"block-flow/internal/storage"
+)
+
+// kvKeyProperties are the key settings shared by the KV blocks
+var kvKeyProperties = []blocks.PropertyDefinition{
+	{
+		Name:         "key",
+		Type:         "string",
+		DisplayName:  "Key",
+		Description:  "Fixed key (used when Key Path is empty)",
+		Required:     false,
+		DefaultValue: "",
+	},
+	{
+		Name:         "keyPath",
+		Type:         "string",
+		DisplayName:  "Key Path",
+		Description:  "Payload path holding the key, e.g. user.id",
+		Required:     false,
+		DefaultValue: "",
+	},
+}
+
+// validateKVKey checks the storage and key settings shared by the KV blocks
+func validateKVKey(store storage.Storage, properties map[string]interface{}) error {
+	if store == nil {
+		return fmt.Errorf("storage is not available")
+	}
+	keyPath := stringProperty(properties, "keyPath", "")
+	if keyPath == "" && stringProperty(properties, "key", "") == "" {
+		return fmt.Errorf("key or keyPath is required")
+	}
+	if keyPath != "" {
+		if err := models.ValidatePath(keyPath); err != nil {
+			return fmt.Errorf("invalid keyPath: %w", err)
+		}
+	}
+	return nil
+}
+
+// kvKey derives the key for a message from keyPath or the fixed key
+func kvKey(msg *models.Message, properties map[string]interface{}) (string, error) {
+	keyPath := stringProperty(properties, "keyPath", "")
+	if keyPath == "" {
+		return stringProperty(properties, "key", ""), nil
+	}
+
+	value, found, err := models.ResolvePath(msg.Payload, keyPath)
+	if err != nil {
+		return "", err
+	}
+	if !found || value == nil {
+		return "", fmt.Errorf("key path '%s' not found in payload", keyPath)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	if n, err := models.ToNumber(value); err == nil {
+		return fmt.Sprint(n), nil
+	}
+	return "", fmt.Errorf("key at '%s' must be a string or number, got %T", keyPath, value)
+}
+
+// KVSetBlock stores a value from the message in the flow's key/value store
+// and passes the message on
+type KVSetBlock struct {
+	storage storage.Storage
+}
+
+func (b *KVSetBlock) GetType() string {
+	return "kv-set"
+}
+
+func (b *KVSetBlock) GetName() string {
+	return "KV Set"
+}
+
+func (b *KVSetBlock) GetDescription() string {
+	return "Store the payload (or part of it) under a key in this flow's key/value store"
+}
+
+func (b *KVSetBlock) GetCategory() string {
+	return "storage"
+}
+
+func (b *KVSetBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *KVSetBlock) GetInputs() int {
+	return 1
+}
+
+func (b *KVSetBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *KVSetBlock) GetProperties() []blocks.PropertyDefinition {
+	properties := []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "KV Set",
+		},
+	}
+	properties = append(properties, kvKeyProperties...)
+	return append(properties, blocks.PropertyDefinition{
+		Name:         "valuePath",
+		Type:         "string",
+		DisplayName:  "Value Path",
+		Description:  "Payload path of the value to store (empty = whole payload)",
+		Required:     false,
+		DefaultValue: "",
+	})
+}
+
+func (b *KVSetBlock) Validate(properties map[string]interface{}) error {
+	if err := validateKVKey(b.storage, properties); err != nil {
+		return err
+	}
+	if path := stringProperty(properties, "valuePath", ""); path != "" {
+		if err := models.ValidatePath(path); err != nil {
+			return fmt.Errorf("invalid valuePath: %w", err)
+		}
+	}
+	return nil
+}
+
+func (b *KVSetBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	key, err := kvKey(ctx.Message, properties)
+	if err != nil {
+		return nil, blocks.Invalid(err)
+	}
+
+	value, found, err := models.ResolvePath(ctx.Message.Payload, stringProperty(properties, "valuePath", ""))
+	if err != nil {
+		return nil, blocks.Invalidf("invalid valuePath: %w", err)
+	}
+	if !found {
+		return nil, blocks.Invalidf("value path not found in payload")
+	}
+
+	if err := b.storage.SetValue(executionContext(ctx), ctx.FlowID, key, value); err != nil {
+		return nil, blocks.Transient(fmt.Errorf("failed to store %q: %w", key, err))
+	}
+
+	output := ctx.Message.Clone()
+	output.Source = ctx.NodeID
+	return []*models.Message{output}, nil
+}
+
+// KVSetBlockFactory creates KV set block instances
+type KVSetBlockFactory struct {
+	storage storage.Storage
+}
+
+func (f *KVSetBlockFactory) CreateBlock() blocks.Block {
+	return &KVSetBlock{storage: f.storage}
+}
+
+func (f *KVSetBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &KVSetBlock{}
+	return blocks.BlockInfo{
+		Type:        "kv-set",
+		Name:        "KV Set",
+		Description: "Store a value in this flow's key/value store",
+		Category:    "storage",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "key-plus",
+		Color:       "#607D8B",
+	}
+}
+
+// KVGetBlock looks up a key in the flow's key/value store. Hits are emitted
+// on port 0; misses use the default value when set and go to port 1 otherwise
+type KVGetBlock struct {
+	storage storage.Storage
+}
+
+func (b *KVGetBlock) GetType() string {
+	return "kv-get"
+}
+
+func (b *KVGetBlock) GetName() string {
+	return "KV Get"
+}
+
+func (b *KVGetBlock) GetDescription() string {
+	return "Look up a key in this flow's key/value store; misses go to output 2"
+}
+
+func (b *KVGetBlock) GetCategory() string {
+	return "storage"
+}
+
+func (b *KVGetBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *KVGetBlock) GetInputs() int {
+	return 1
+}
+
+func (b *KVGetBlock) GetOutputs() int {
+	return 2
+}
+
+func (b *KVGetBlock) GetProperties() []blocks.PropertyDefinition {
+	properties := []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "KV Get",
+		},
+	}
+	properties = append(properties, kvKeyProperties...)
+	return append(properties,
+		blocks.PropertyDefinition{
+			Name:         "resultPath",
+			Type:         "string",
+			DisplayName:  "Result Path",
+			Description:  "Payload path to store the value at (empty = replace the payload)",
+			Required:     false,
+			DefaultValue: "",
+		},
+		blocks.PropertyDefinition{
+			Name:        "defaultValue",
+			Type:        "json",
+			DisplayName: "Default Value",
+			Description: "Value used on a miss; when unset, misses are sent to output 2 unchanged",
+			Required:    false,
+		},
+	)
+}
+
+func (b *KVGetBlock) Validate(properties map[string]interface{}) error {
+	if err := validateKVKey(b.storage, properties); err != nil {
+		return err
+	}
+	if path := stringProperty(properties, "resultPath", ""); path != "" {
+		if err := models.ValidatePath(path); err != nil {
+			return fmt.Errorf("invalid resultPath: %w", err)
+		}
+	}
+	return nil
+}
+
+func (b *KVGetBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	key, err := kvKey(ctx.Message, properties)
+	if err != nil {
+		return nil, blocks.Invalid(err)
+	}
+
+	output := ctx.Message.Clone()
+	output.Source = ctx.NodeID
+
+	var value interface{}
+	err = b.storage.GetValue(executionContext(ctx), ctx.FlowID, key, &value)
+	if errors.Is(err, os.ErrNotExist) {
+		defaultValue, ok := properties["defaultValue"]
+		if !ok || defaultValue == nil {
+			output.Port = 1
+			return []*models.Message{output}, nil
+		}
+		value = defaultValue
+	} else if err != nil {
+		return nil, blocks.Transient(fmt.Errorf("failed to load %q: %w", key, err))
+	}
+
+	payload, err := models.SetPath(output.Payload, stringProperty(properties, "resultPath", ""), value)
+	if err != nil {
+		return nil, blocks.Invalidf("cannot store result: %w", err)
+	}
+	output.Payload = payload
+
+	return []*models.Message{output}, nil
+}
+
+// KVGetBlockFactory creates KV get block instances
+type KVGetBlockFactory struct {
+	storage storage.Storage
+}
+
+func (f *KVGetBlockFactory) CreateBlock() blocks.Block {
+	return &KVGetBlock{storage: f.storage}
+}
+
+func (f *KVGetBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &KVGetBlock{}
+	return blocks.BlockInfo{
+		Type:        "kv-get",
+		Name:        "KV Get",
+		Description: "Look up a value in this flow's key/value store",
+		Category:    "storage",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "key",
+		Color:       "#607D8B",
+	}
+}
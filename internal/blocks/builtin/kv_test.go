@@ -0,0 +1,150 @@
+package builtin
+
+import (
+	"reflect"
+	"testing"
+
+	"block-flow/internal/models"
+	"block-flow/internal/storage"
+)
+
+func TestKVSetThenGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		setFlow     string
+		setProps    map[string]interface{}
+		setPayload  interface{}
+		getFlow     string
+		getProps    map[string]interface{}
+		getPayload  interface{}
+		wantPort    int
+		wantPayload interface{}
+	}{
+		{
+			name:        "fixed key",
+			setFlow:     "f1",
+			setProps:    map[string]interface{}{"key": "k"},
+			setPayload:  "v",
+			getFlow:     "f1",
+			getProps:    map[string]interface{}{"key": "k"},
+			getPayload:  "ignored",
+			wantPayload: "v",
+		},
+		{
+			name:        "key and value from the payload",
+			setFlow:     "f1",
+			setProps:    map[string]interface{}{"keyPath": "id", "valuePath": "name"},
+			setPayload:  map[string]interface{}{"id": "u1", "name": "Ada"},
+			getFlow:     "f1",
+			getProps:    map[string]interface{}{"keyPath": "user", "resultPath": "name"},
+			getPayload:  map[string]interface{}{"user": "u1"},
+			wantPayload: map[string]interface{}{"user": "u1", "name": "Ada"},
+		},
+		{
+			name:        "numeric key",
+			setFlow:     "f1",
+			setProps:    map[string]interface{}{"keyPath": "id"},
+			setPayload:  map[string]interface{}{"id": 7.0},
+			getFlow:     "f1",
+			getProps:    map[string]interface{}{"key": "7", "resultPath": "found"},
+			getPayload:  map[string]interface{}{},
+			wantPayload: map[string]interface{}{"found": map[string]interface{}{"id": 7.0}},
+		},
+		{
+			name:        "miss goes to the second port",
+			setFlow:     "f1",
+			setProps:    map[string]interface{}{"key": "k"},
+			setPayload:  "v",
+			getFlow:     "f1",
+			getProps:    map[string]interface{}{"key": "other"},
+			getPayload:  "unchanged",
+			wantPort:    1,
+			wantPayload: "unchanged",
+		},
+		{
+			name:        "miss with a default",
+			setFlow:     "f1",
+			setProps:    map[string]interface{}{"key": "k"},
+			setPayload:  "v",
+			getFlow:     "f1",
+			getProps:    map[string]interface{}{"key": "other", "defaultValue": "fallback"},
+			getPayload:  "ignored",
+			wantPayload: "fallback",
+		},
+		{
+			name:        "keys are namespaced per flow",
+			setFlow:     "f1",
+			setProps:    map[string]interface{}{"key": "k"},
+			setPayload:  "v",
+			getFlow:     "f2",
+			getProps:    map[string]interface{}{"key": "k"},
+			getPayload:  "unchanged",
+			wantPort:    1,
+			wantPayload: "unchanged",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := storage.NewMemoryStorage()
+			set := &KVSetBlock{storage: store}
+			get := &KVGetBlock{storage: store}
+			if err := set.Validate(tt.setProps); err != nil {
+				t.Fatalf("validate set: %v", err)
+			}
+			if err := get.Validate(tt.getProps); err != nil {
+				t.Fatalf("validate get: %v", err)
+			}
+
+			input := models.NewMessage(tt.setPayload)
+			out, err := set.Execute(&models.BlockExecutionContext{FlowID: tt.setFlow, NodeID: "set", Message: input}, tt.setProps)
+			if err != nil {
+				t.Fatalf("set: %v", err)
+			}
+			if len(out) != 1 || !reflect.DeepEqual(out[0].Payload, tt.setPayload) {
+				t.Errorf("set emitted %v, want the input passed on", out)
+			}
+
+			out, err = get.Execute(&models.BlockExecutionContext{FlowID: tt.getFlow, NodeID: "get", Message: models.NewMessage(tt.getPayload)}, tt.getProps)
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			if len(out) != 1 {
+				t.Fatalf("get emitted %d messages", len(out))
+			}
+			if out[0].Port != tt.wantPort || !reflect.DeepEqual(out[0].Payload, tt.wantPayload) {
+				t.Errorf("get emitted %v on port %d, want %v on port %d", out[0].Payload, out[0].Port, tt.wantPayload, tt.wantPort)
+			}
+		})
+	}
+}
+
+func TestKVValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+		noStorage  bool
+		wantErr    bool
+	}{
+		{name: "fixed key", properties: map[string]interface{}{"key": "k"}},
+		{name: "key path", properties: map[string]interface{}{"keyPath": "id"}},
+		{name: "no key", properties: map[string]interface{}{}, wantErr: true},
+		{name: "no storage", properties: map[string]interface{}{"key": "k"}, noStorage: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var store storage.Storage
+			if !tt.noStorage {
+				store = storage.NewMemoryStorage()
+			}
+			for _, block := range []interface {
+				Validate(map[string]interface{}) error
+			}{&KVSetBlock{storage: store}, &KVGetBlock{storage: store}} {
+				if err := block.Validate(tt.properties); (err != nil) != tt.wantErr {
+					t.Errorf("%T.Validate error = %v, want error %v", block, err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
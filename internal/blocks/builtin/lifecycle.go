@@ -0,0 +1,177 @@
+package builtin
+
+import (
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// lifecycleBlock emits a single message on a flow transition. The payload
+// defaults to {"event": "start"|"stop", "flow_id": ...}
+type lifecycleBlock struct {
+	event blocks.LifecycleEvent
+}
+
+func (b *lifecycleBlock) GetCategory() string {
+	return "input"
+}
+
+func (b *lifecycleBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.InputGroup
+}
+
+func (b *lifecycleBlock) GetInputs() int {
+	return 0
+}
+
+func (b *lifecycleBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *lifecycleBlock) LifecycleEvent() blocks.LifecycleEvent {
+	return b.event
+}
+
+func (b *lifecycleBlock) properties(name string) []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: name,
+		},
+		{
+			Name:        "payload",
+			Type:        "json",
+			DisplayName: "Payload",
+			Description: "Value to emit; defaults to the event name and flow ID",
+			Required:    false,
+		},
+		{
+			Name:         "topic",
+			Type:         "string",
+			DisplayName:  "Topic",
+			Description:  "Optional topic for the message",
+			Required:     false,
+			DefaultValue: "",
+		},
+	}
+}
+
+func (b *lifecycleBlock) Validate(properties map[string]interface{}) error {
+	return nil
+}
+
+func (b *lifecycleBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	payload, ok := properties["payload"]
+	if !ok || payload == nil {
+		payload = map[string]interface{}{
+			"event":   string(b.event),
+			"flow_id": ctx.FlowID,
+		}
+	}
+
+	outputMsg := models.NewMessage(payload)
+	outputMsg.Topic = stringProperty(properties, "topic", "")
+	outputMsg.Source = ctx.NodeID
+	outputMsg.SetHeader("lifecycle", string(b.event))
+
+	ctx.Logger.Debug("Lifecycle hook fired", map[string]interface{}{
+		"event": string(b.event),
+	})
+
+	return []*models.Message{outputMsg}, nil
+}
+
+// OnStartBlock emits one message when its flow starts, e.g. to load config
+// or announce presence
+type OnStartBlock struct {
+	lifecycleBlock
+}
+
+func (b *OnStartBlock) GetType() string {
+	return "on-start"
+}
+
+func (b *OnStartBlock) GetName() string {
+	return "On Start"
+}
+
+func (b *OnStartBlock) GetDescription() string {
+	return "Emit a message once when the flow starts"
+}
+
+func (b *OnStartBlock) GetProperties() []blocks.PropertyDefinition {
+	return b.properties("On Start")
+}
+
+// OnStopBlock emits one message when its flow is stopped, before its nodes
+// stop, e.g. to flush buffers or send a shutdown notice
+type OnStopBlock struct {
+	lifecycleBlock
+}
+
+func (b *OnStopBlock) GetType() string {
+	return "on-stop"
+}
+
+func (b *OnStopBlock) GetName() string {
+	return "On Stop"
+}
+
+func (b *OnStopBlock) GetDescription() string {
+	return "Emit a message once when the flow is stopped, before its nodes stop"
+}
+
+func (b *OnStopBlock) GetProperties() []blocks.PropertyDefinition {
+	return b.properties("On Stop")
+}
+
+// OnStartBlockFactory creates on-start block instances
+type OnStartBlockFactory struct{}
+
+func (f *OnStartBlockFactory) CreateBlock() blocks.Block {
+	return &OnStartBlock{lifecycleBlock{event: blocks.LifecycleStart}}
+}
+
+func (f *OnStartBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := f.CreateBlock()
+	return blocks.BlockInfo{
+		Type:        block.GetType(),
+		Name:        block.GetName(),
+		Description: block.GetDescription(),
+		Category:    "input",
+		BlockGroup:  blocks.InputGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "power",
+		Color:       "#4CAF50",
+	}
+}
+
+// OnStopBlockFactory creates on-stop block instances
+type OnStopBlockFactory struct{}
+
+func (f *OnStopBlockFactory) CreateBlock() blocks.Block {
+	return &OnStopBlock{lifecycleBlock{event: blocks.LifecycleStop}}
+}
+
+func (f *OnStopBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := f.CreateBlock()
+	return blocks.BlockInfo{
+		Type:        block.GetType(),
+		Name:        block.GetName(),
+		Description: block.GetDescription(),
+		Category:    "input",
+		BlockGroup:  blocks.InputGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "power-off",
+		Color:       "#4CAF50",
+	}
+}
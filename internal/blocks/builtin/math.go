@@ -7,24 +7,6 @@ import (
 	"block-flow/internal/models"
 )
 
-// Helper function to extract number from message or property
-func extractNumber(value interface{}) (float64, error) {
-	switch v := value.(type) {
-	case float64:
-		return v, nil
-	case float32:
-		return float64(v), nil
-	case int:
-		return float64(v), nil
-	case int32:
-		return float64(v), nil
-	case int64:
-		return float64(v), nil
-	default:
-		return 0, fmt.Errorf("value is not a number: %T", value)
-	}
-}
-
 // AdditionBlock performs addition operation
 type AdditionBlock struct{}
 
@@ -86,19 +68,19 @@ func (b *AdditionBlock) Validate(properties map[string]interface{}) error {
 
 func (b *AdditionBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
 	if ctx.Message == nil {
-		return nil, fmt.Errorf("no input message")
+		return nil, blocks.Invalidf("no input message")
 	}
 
 	// Extract input number
-	inputNum, err := extractNumber(ctx.Message.Payload)
+	inputNum, err := ctx.Message.PayloadAsNumber()
 	if err != nil {
-		return nil, fmt.Errorf("input payload is not a number: %w", err)
+		return nil, blocks.Invalid(err)
 	}
 
 	// Extract value to add
-	addValue, err := extractNumber(properties["value"])
+	addValue, err := models.ToNumber(properties["value"])
 	if err != nil {
-		return nil, fmt.Errorf("add value is not a number: %w", err)
+		return nil, blocks.Invalidf("add value is not a number: %w", err)
 	}
 
 	// Perform addition
@@ -179,19 +161,19 @@ func (b *SubtractionBlock) Validate(properties map[string]interface{}) error {
 
 func (b *SubtractionBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
 	if ctx.Message == nil {
-		return nil, fmt.Errorf("no input message")
+		return nil, blocks.Invalidf("no input message")
 	}
 
 	// Extract input number
-	inputNum, err := extractNumber(ctx.Message.Payload)
+	inputNum, err := ctx.Message.PayloadAsNumber()
 	if err != nil {
-		return nil, fmt.Errorf("input payload is not a number: %w", err)
+		return nil, blocks.Invalid(err)
 	}
 
 	// Extract value to subtract
-	subValue, err := extractNumber(properties["value"])
+	subValue, err := models.ToNumber(properties["value"])
 	if err != nil {
-		return nil, fmt.Errorf("subtract value is not a number: %w", err)
+		return nil, blocks.Invalidf("subtract value is not a number: %w", err)
 	}
 
 	// Perform subtraction
@@ -272,19 +254,19 @@ func (b *MultiplicationBlock) Validate(properties map[string]interface{}) error
 
 func (b *MultiplicationBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
 	if ctx.Message == nil {
-		return nil, fmt.Errorf("no input message")
+		return nil, blocks.Invalidf("no input message")
 	}
 
 	// Extract input number
-	inputNum, err := extractNumber(ctx.Message.Payload)
+	inputNum, err := ctx.Message.PayloadAsNumber()
 	if err != nil {
-		return nil, fmt.Errorf("input payload is not a number: %w", err)
+		return nil, blocks.Invalid(err)
 	}
 
 	// Extract multiplier
-	mulValue, err := extractNumber(properties["value"])
+	mulValue, err := models.ToNumber(properties["value"])
 	if err != nil {
-		return nil, fmt.Errorf("multiplier is not a number: %w", err)
+		return nil, blocks.Invalidf("multiplier is not a number: %w", err)
 	}
 
 	// Perform multiplication
@@ -304,7 +286,9 @@ func (b *MultiplicationBlock) Execute(ctx *models.BlockExecutionContext, propert
 	return []*models.Message{outputMsg}, nil
 }
 
-// DivisionBlock performs division operation
+// DivisionBlock performs division operation. By default a failed division
+// (zero divisor, non-numeric input) fails the execution; with onError set to
+// "route" the original message goes to a second output instead
 type DivisionBlock struct{}
 
 func (b *DivisionBlock) GetType() string {
@@ -335,6 +319,14 @@ func (b *DivisionBlock) GetOutputs() int {
 	return 1
 }
 
+// GetPortCounts adds the error output in route mode
+func (b *DivisionBlock) GetPortCounts(properties map[string]interface{}) (inputs, outputs int) {
+	if stringProperty(properties, "onError", "fail") == "route" {
+		return 1, 2
+	}
+	return 1, 1
+}
+
 func (b *DivisionBlock) GetProperties() []blocks.PropertyDefinition {
 	return []blocks.PropertyDefinition{
 		{
@@ -352,8 +344,17 @@ func (b *DivisionBlock) GetProperties() []blocks.PropertyDefinition {
 			Description:  "The number to divide the input by",
 			Required:     true,
 			DefaultValue: 1.0,
-			Validation: blocks.Validation{
-				Min: &[]float64{0.000001}[0], // Prevent division by zero
+		},
+		{
+			Name:         "onError",
+			Type:         "select",
+			DisplayName:  "On Error",
+			Description:  "Fail the execution on a failed division, or route the original message to output 2",
+			Required:     false,
+			DefaultValue: "fail",
+			Options: []blocks.Option{
+				{Label: "Fail", Value: "fail"},
+				{Label: "Route to output 2", Value: "route"},
 			},
 		},
 	}
@@ -365,12 +366,18 @@ func (b *DivisionBlock) Validate(properties map[string]interface{}) error {
 		return fmt.Errorf("value property is required")
 	}
 
-	divisor, err := extractNumber(value)
+	divisor, err := models.ToNumber(value)
 	if err != nil {
 		return fmt.Errorf("divisor must be a number: %w", err)
 	}
 
-	if divisor == 0 {
+	onError := stringProperty(properties, "onError", "fail")
+	if onError != "fail" && onError != "route" {
+		return fmt.Errorf("invalid onError '%s'", onError)
+	}
+
+	// Routed failures are handled by the flow, so a zero divisor is allowed
+	if divisor == 0 && onError == "fail" {
 		return fmt.Errorf("division by zero is not allowed")
 	}
 
@@ -379,24 +386,24 @@ func (b *DivisionBlock) Validate(properties map[string]interface{}) error {
 
 func (b *DivisionBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
 	if ctx.Message == nil {
-		return nil, fmt.Errorf("no input message")
+		return nil, blocks.Invalidf("no input message")
 	}
 
 	// Extract input number
-	inputNum, err := extractNumber(ctx.Message.Payload)
+	inputNum, err := ctx.Message.PayloadAsNumber()
 	if err != nil {
-		return nil, fmt.Errorf("input payload is not a number: %w", err)
+		return b.fail(ctx, properties, err)
 	}
 
 	// Extract divisor
-	divValue, err := extractNumber(properties["value"])
+	divValue, err := models.ToNumber(properties["value"])
 	if err != nil {
-		return nil, fmt.Errorf("divisor is not a number: %w", err)
+		return b.fail(ctx, properties, fmt.Errorf("divisor is not a number: %w", err))
 	}
 
 	// Check for division by zero
 	if divValue == 0 {
-		return nil, fmt.Errorf("division by zero")
+		return b.fail(ctx, properties, fmt.Errorf("division by zero"))
 	}
 
 	// Perform division
@@ -416,6 +423,24 @@ func (b *DivisionBlock) Execute(ctx *models.BlockExecutionContext, properties ma
 	return []*models.Message{outputMsg}, nil
 }
 
+// fail reports a failed division: as an error by default, or in route mode
+// by sending the original message to output 2 with the error in its context
+func (b *DivisionBlock) fail(ctx *models.BlockExecutionContext, properties map[string]interface{}, err error) ([]*models.Message, error) {
+	if stringProperty(properties, "onError", "fail") != "route" {
+		return nil, blocks.Invalid(err)
+	}
+
+	errorMsg := ctx.Message.Clone()
+	errorMsg.Source = ctx.NodeID
+	errorMsg.Port = 1
+	errorMsg.SetContext("error", err.Error())
+
+	ctx.Logger.Debug("Division failed, routing to error output", map[string]interface{}{
+		"error": err.Error(),
+	})
+	return []*models.Message{errorMsg}, nil
+}
+
 // Block factories
 
 type AdditionBlockFactory struct{}
@@ -0,0 +1,71 @@
+package builtin
+
+import (
+	"testing"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestDivisionOnError(t *testing.T) {
+	tests := []struct {
+		name        string
+		properties  map[string]interface{}
+		payload     interface{}
+		wantPort    int
+		wantPayload interface{}
+		wantError   bool // Error in the routed message's context
+		wantFail    bool // Execute returns an error
+	}{
+		{name: "route mode divides", properties: map[string]interface{}{"value": 4.0, "onError": "route"}, payload: 10.0, wantPort: 0, wantPayload: 2.5},
+		{name: "zero divisor routed", properties: map[string]interface{}{"value": 0.0, "onError": "route"}, payload: 10.0, wantPort: 1, wantPayload: 10.0, wantError: true},
+		{name: "non-number routed", properties: map[string]interface{}{"value": 2.0, "onError": "route"}, payload: "ten", wantPort: 1, wantPayload: "ten", wantError: true},
+		{name: "non-number fails by default", properties: map[string]interface{}{"value": 2.0}, payload: "ten", wantFail: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := &DivisionBlock{}
+			if err := block.Validate(tt.properties); err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+
+			ctx := &models.BlockExecutionContext{NodeID: "divide", Message: models.NewMessage(tt.payload), Logger: nopLogger{}}
+			out, err := block.Execute(ctx, tt.properties)
+			if tt.wantFail {
+				if blocks.CategoryOf(err) != blocks.ErrorInvalid {
+					t.Errorf("execute error = %v, want an invalid-input error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if len(out) != 1 {
+				t.Fatalf("emitted %d messages", len(out))
+			}
+			if out[0].Port != tt.wantPort || out[0].Payload != tt.wantPayload {
+				t.Errorf("emitted %v on port %d, want %v on port %d", out[0].Payload, out[0].Port, tt.wantPayload, tt.wantPort)
+			}
+			if _, hasError := out[0].Context["error"]; hasError != tt.wantError {
+				t.Errorf("error in context = %v, want %v", out[0].Context["error"], tt.wantError)
+			}
+		})
+	}
+
+	t.Run("zero divisor rejected without routing", func(t *testing.T) {
+		if err := (&DivisionBlock{}).Validate(map[string]interface{}{"value": 0.0}); err == nil {
+			t.Error("zero divisor accepted in fail mode")
+		}
+	})
+
+	t.Run("error output only in route mode", func(t *testing.T) {
+		block := &DivisionBlock{}
+		if _, outputs := block.GetPortCounts(map[string]interface{}{"onError": "route"}); outputs != 2 {
+			t.Errorf("route mode has %d outputs, want 2", outputs)
+		}
+		if _, outputs := block.GetPortCounts(map[string]interface{}{}); outputs != 1 {
+			t.Errorf("fail mode has %d outputs, want 1", outputs)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package builtin
+
+import (
+	"reflect"
+	"testing"
+
+	"block-flow/internal/models"
+)
+
+func TestMergeObject(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		payload map[string]interface{}
+		want    map[string]interface{}
+	}{
+		{
+			name:    "nested merge",
+			patch:   `{"config": {"retries": 3, "http": {"timeout": 10}}}`,
+			payload: map[string]interface{}{"config": map[string]interface{}{"retries": 1.0, "http": map[string]interface{}{"proxy": "p"}}, "id": "x"},
+			want: map[string]interface{}{
+				"config": map[string]interface{}{"retries": 3.0, "http": map[string]interface{}{"proxy": "p", "timeout": 10.0}},
+				"id":     "x",
+			},
+		},
+		{
+			name:    "null deletes",
+			patch:   `{"secret": null, "config": {"debug": null}, "absent": null}`,
+			payload: map[string]interface{}{"secret": "s", "config": map[string]interface{}{"debug": true, "level": "info"}},
+			want:    map[string]interface{}{"config": map[string]interface{}{"level": "info"}},
+		},
+		{
+			name:    "arrays replaced wholesale",
+			patch:   `{"tags": ["b"], "matrix": [[1]]}`,
+			payload: map[string]interface{}{"tags": []interface{}{"a", "c"}, "matrix": []interface{}{[]interface{}{0.0, 0.0}}},
+			want:    map[string]interface{}{"tags": []interface{}{"b"}, "matrix": []interface{}{[]interface{}{1.0}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := &MergeObjectBlock{}
+			properties := map[string]interface{}{"patch": tt.patch}
+			if err := block.Validate(properties); err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+
+			ctx := &models.BlockExecutionContext{NodeID: "merge", Message: models.NewMessage(tt.payload), Logger: nopLogger{}}
+			out, err := block.Execute(ctx, properties)
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if len(out) != 1 {
+				t.Fatalf("emitted %d messages", len(out))
+			}
+			if !reflect.DeepEqual(out[0].Payload, tt.want) {
+				t.Errorf("merged payload = %v, want %v", out[0].Payload, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package builtin
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
 
 	"block-flow/internal/blocks"
 	"block-flow/internal/models"
@@ -69,6 +71,20 @@ func (b *DebugBlock) GetProperties() []blocks.PropertyDefinition {
 				{Label: "Complete message", Value: "complete"},
 			},
 		},
+		payloadPathProperty(),
+		{
+			Name:         "format",
+			Type:         "select",
+			DisplayName:  "Format",
+			Description:  "How to render the output",
+			Required:     false,
+			DefaultValue: "auto",
+			Options: []blocks.Option{
+				{Label: "Auto (by payload type)", Value: "auto"},
+				{Label: "JSON", Value: "json"},
+				{Label: "Raw", Value: "raw"},
+			},
+		},
 		{
 			Name:         "prefix",
 			Type:         "string",
@@ -81,13 +97,16 @@ func (b *DebugBlock) GetProperties() []blocks.PropertyDefinition {
 }
 
 func (b *DebugBlock) Validate(properties map[string]interface{}) error {
-	// No validation needed for debug block
-	return nil
+	format := stringProperty(properties, "format", "auto")
+	if format != "auto" && format != "json" && format != "raw" {
+		return fmt.Errorf("invalid format '%s'", format)
+	}
+	return validatePayloadPath(properties)
 }
 
 func (b *DebugBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
 	if ctx.Message == nil {
-		return nil, fmt.Errorf("no input message to debug")
+		return nil, blocks.Invalidf("no input message to debug")
 	}
 
 	// Get properties
@@ -101,10 +120,18 @@ func (b *DebugBlock) Execute(ctx *models.BlockExecutionContext, properties map[s
 
 	// Prepare output
 	var output interface{}
+	var formatted string
 	if complete == "complete" {
 		output = ctx.Message
 	} else {
-		output = ctx.Message.Payload
+		value, found, err := resolvePayload(ctx.Message, properties)
+		if err != nil {
+			return nil, err
+		}
+		output = value
+		if !found {
+			formatted = fmt.Sprintf("<missing: %s>", stringProperty(properties, "payloadPath", ""))
+		}
 	}
 
 	// Format debug message
@@ -113,23 +140,69 @@ func (b *DebugBlock) Execute(ctx *models.BlockExecutionContext, properties map[s
 		debugMsg = fmt.Sprintf("[%s] %s", ctx.NodeID, prefix)
 	}
 
+	if formatted == "" {
+		formatted = formatDebugOutput(output, stringProperty(properties, "format", "auto"))
+	}
+
 	// Output to console if enabled
 	if console {
-		log.Printf("%s: %v", debugMsg, output)
+		log.Printf("%s: %s", debugMsg, formatted)
 	}
 
 	// Log debug information
 	ctx.Logger.Debug("Debug block output", map[string]interface{}{
-		"node_id": ctx.NodeID,
-		"prefix":  prefix,
-		"output":  output,
-		"topic":   ctx.Message.Topic,
+		"node_id":   ctx.NodeID,
+		"prefix":    prefix,
+		"output":    output,
+		"formatted": formatted,
+		"topic":     ctx.Message.Topic,
 	})
 
 	// Debug blocks don't pass messages forward
 	return []*models.Message{}, nil
 }
 
+// formatDebugOutput renders a debug value. "raw" uses Go formatting, "json"
+// pretty-prints it as JSON and "auto" also prefixes scalars with their type
+// (e.g. number: 42) and quotes strings
+func formatDebugOutput(value interface{}, format string) string {
+	switch format {
+	case "raw":
+		return fmt.Sprintf("%v", value)
+	case "json":
+		return prettyJSON(value)
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string: " + strconv.Quote(v)
+	case bool:
+		return "boolean: " + strconv.FormatBool(v)
+	case float64, float32, int, int64, int32, json.Number:
+		return fmt.Sprintf("number: %v", v)
+	case []byte:
+		return fmt.Sprintf("binary: %d bytes", len(v))
+	case []interface{}:
+		return fmt.Sprintf("array[%d]: %s", len(v), prettyJSON(v))
+	case map[string]interface{}:
+		return "object: " + prettyJSON(v)
+	default:
+		return prettyJSON(v)
+	}
+}
+
+// prettyJSON indents a value as JSON, falling back to Go formatting for
+// values JSON can't represent
+func prettyJSON(value interface{}) string {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
 // DebugBlockFactory creates debug block instances
 type DebugBlockFactory struct{}
 
@@ -0,0 +1,108 @@
+package builtin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"block-flow/internal/models"
+)
+
+// debugLogger keeps the fields of the last debug log
+type debugLogger struct {
+	nopLogger
+	fields map[string]interface{}
+}
+
+func (l *debugLogger) Debug(_ string, fields map[string]interface{}) {
+	l.fields = fields
+}
+
+// debugOutput runs a debug block over payload, returning its formatted output
+func debugOutput(t *testing.T, payload interface{}, properties map[string]interface{}) string {
+	t.Helper()
+	block := &DebugBlock{}
+	if err := block.Validate(properties); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	logger := &debugLogger{}
+	ctx := &models.BlockExecutionContext{NodeID: "debug", Message: models.NewMessage(payload), Logger: logger}
+	if _, err := block.Execute(ctx, properties); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	formatted, _ := logger.fields["formatted"].(string)
+	return formatted
+}
+
+func TestDebugFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload interface{}
+		format  string
+		want    string
+	}{
+		{name: "map", payload: map[string]interface{}{"a": 1.0}, want: "object: {\n  \"a\": 1\n}"},
+		{name: "slice", payload: []interface{}{1.0, "x"}, want: "array[2]: [\n  1,\n  \"x\"\n]"},
+		{name: "string", payload: "hi \"there\"", want: `string: "hi \"there\""`},
+		{name: "number", payload: 42.0, want: "number: 42"},
+		{name: "json.Number", payload: json.Number("9007199254740993"), want: "number: 9007199254740993"},
+		{name: "boolean", payload: false, want: "boolean: false"},
+		{name: "null", payload: nil, want: "null"},
+		{name: "json string", payload: "hi", format: "json", want: `"hi"`},
+		{name: "json map", payload: map[string]interface{}{"a": true}, format: "json", want: "{\n  \"a\": true\n}"},
+		{name: "raw map", payload: map[string]interface{}{"a": 1.0}, format: "raw", want: "map[a:1]"},
+		{name: "raw string", payload: "hi", format: "raw", want: "hi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			properties := map[string]interface{}{}
+			if tt.format != "" {
+				properties["format"] = tt.format
+			}
+			if got := debugOutput(t, tt.payload, properties); got != tt.want {
+				t.Errorf("formatted = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := (&DebugBlock{}).Validate(map[string]interface{}{"format": "yaml"}); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
+
+func TestDebugPayloadPath(t *testing.T) {
+	payload := map[string]interface{}{
+		"sensor":  map[string]interface{}{"temperature": 21.5},
+		"samples": []interface{}{1.0, 2.0},
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "sensor.temperature", want: "number: 21.5"},
+		{path: "samples[1]", want: "number: 2"},
+		{path: "sensor", want: "object: {\n  \"temperature\": 21.5\n}"},
+		{path: "sensor.humidity", want: "<missing: sensor.humidity>"},
+		{path: "samples[5]", want: "<missing: samples[5]>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := debugOutput(t, payload, map[string]interface{}{"payloadPath": tt.path}); got != tt.want {
+				t.Errorf("formatted = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("empty path uses the whole payload", func(t *testing.T) {
+		if got := debugOutput(t, 7.0, map[string]interface{}{"payloadPath": ""}); got != "number: 7" {
+			t.Errorf("formatted = %q", got)
+		}
+	})
+
+	t.Run("malformed path rejected", func(t *testing.T) {
+		if err := (&DebugBlock{}).Validate(map[string]interface{}{"payloadPath": "a[x"}); err == nil {
+			t.Error("malformed path accepted")
+		}
+	})
+}
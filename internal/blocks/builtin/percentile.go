@@ -0,0 +1,323 @@
+package builtin
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// sample is one value held by a quantileWindow
+type sample struct {
+	value   float64
+	arrived time.Time
+}
+
+// quantileWindow holds the most recent samples of a stream, both in arrival
+// order (for eviction) and sorted (for order statistics), so quantiles are
+// exact over the window. Its size is bounded by the caller
+type quantileWindow struct {
+	arrivals []sample  // Oldest first
+	sorted   []float64 // Ascending
+}
+
+// add inserts a sample
+func (w *quantileWindow) add(value float64, now time.Time) {
+	w.arrivals = append(w.arrivals, sample{value: value, arrived: now})
+	i := sort.SearchFloat64s(w.sorted, value)
+	w.sorted = append(w.sorted, 0)
+	copy(w.sorted[i+1:], w.sorted[i:])
+	w.sorted[i] = value
+}
+
+// evict drops the oldest samples until at most maxCount remain and none is
+// older than maxAge (0 keeps samples regardless of age)
+func (w *quantileWindow) evict(maxCount int, maxAge time.Duration, now time.Time) {
+	drop := 0
+	for drop < len(w.arrivals) {
+		oldest := w.arrivals[drop]
+		if len(w.arrivals)-drop <= maxCount && (maxAge <= 0 || now.Sub(oldest.arrived) <= maxAge) {
+			break
+		}
+		i := sort.SearchFloat64s(w.sorted, oldest.value)
+		w.sorted = append(w.sorted[:i], w.sorted[i+1:]...)
+		drop++
+	}
+	if drop > 0 {
+		w.arrivals = append(w.arrivals[:0], w.arrivals[drop:]...)
+	}
+}
+
+// quantile returns the q-th quantile (0..1) interpolated linearly between
+// the closest ranks. The window must not be empty
+func (w *quantileWindow) quantile(q float64) float64 {
+	rank := q * float64(len(w.sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	fraction := rank - float64(lower)
+	return w.sorted[lower] + (w.sorted[upper]-w.sorted[lower])*fraction
+}
+
+// PercentileBlock keeps a sliding window of recent numeric values and emits
+// the configured percentiles over it, either for every message or on an
+// interval. The window is bounded by a sample count and optionally by age
+type PercentileBlock struct {
+	window quantileWindow
+	mu     sync.Mutex
+}
+
+func (b *PercentileBlock) GetType() string {
+	return "percentile"
+}
+
+func (b *PercentileBlock) GetName() string {
+	return "Percentile"
+}
+
+func (b *PercentileBlock) GetDescription() string {
+	return "Emit percentiles of recent numeric values"
+}
+
+func (b *PercentileBlock) GetCategory() string {
+	return "math"
+}
+
+func (b *PercentileBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *PercentileBlock) GetInputs() int {
+	return 1
+}
+
+func (b *PercentileBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *PercentileBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Percentile",
+		},
+		{
+			Name:         "field",
+			Type:         "string",
+			DisplayName:  "Field",
+			Description:  "Payload path of the value (empty for the payload itself)",
+			Required:     false,
+			DefaultValue: "",
+		},
+		{
+			Name:         "percentiles",
+			Type:         "string",
+			DisplayName:  "Percentiles",
+			Description:  "Comma-separated percentiles to emit, each between 0 and 100",
+			Required:     false,
+			DefaultValue: "50,90,99",
+		},
+		{
+			Name:         "windowSize",
+			Type:         "number",
+			DisplayName:  "Window Size",
+			Description:  "Most recent values kept; older ones are evicted",
+			Required:     false,
+			DefaultValue: 1000,
+			Validation: blocks.Validation{
+				Min: &[]float64{1}[0],
+			},
+		},
+		{
+			Name:         "windowDuration",
+			Type:         "number",
+			DisplayName:  "Window Duration (ms)",
+			Description:  "Values older than this are evicted (0 for a count-only window)",
+			Required:     false,
+			DefaultValue: 0,
+			Validation: blocks.Validation{
+				Min: &[]float64{0}[0],
+			},
+		},
+		{
+			Name:         "emit",
+			Type:         "select",
+			DisplayName:  "Emit",
+			Description:  "Emit percentiles on every message or on an interval",
+			Required:     false,
+			DefaultValue: "each",
+			Options: []blocks.Option{
+				{Label: "Each message", Value: "each"},
+				{Label: "On interval", Value: "interval"},
+			},
+		},
+		{
+			Name:         "interval",
+			Type:         "number",
+			DisplayName:  "Interval (ms)",
+			Description:  "How often percentiles are emitted in interval mode",
+			Required:     false,
+			DefaultValue: 10000,
+			Validation: blocks.Validation{
+				Min: &[]float64{1}[0],
+			},
+		},
+	}
+}
+
+func (b *PercentileBlock) Validate(properties map[string]interface{}) error {
+	if field := stringProperty(properties, "field", ""); field != "" {
+		if err := models.ValidatePath(field); err != nil {
+			return err
+		}
+	}
+	if _, err := parsePercentiles(stringProperty(properties, "percentiles", "50,90,99")); err != nil {
+		return err
+	}
+	emit := stringProperty(properties, "emit", "each")
+	if emit != "each" && emit != "interval" {
+		return fmt.Errorf("invalid emit '%s'", emit)
+	}
+	return nil
+}
+
+// parsePercentiles reads a comma-separated list of percentiles
+func parsePercentiles(text string) ([]float64, error) {
+	percentiles := make([]float64, 0)
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		p, err := strconv.ParseFloat(part, 64)
+		if err != nil || p < 0 || p > 100 {
+			return nil, fmt.Errorf("percentile '%s' must be a number between 0 and 100", part)
+		}
+		percentiles = append(percentiles, p)
+	}
+	if len(percentiles) == 0 {
+		return nil, fmt.Errorf("at least one percentile is required")
+	}
+	return percentiles, nil
+}
+
+func (b *PercentileBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	value := ctx.Message.Payload
+	if field := stringProperty(properties, "field", ""); field != "" {
+		resolved, found, err := models.ResolvePath(value, field)
+		if err != nil {
+			return nil, blocks.Invalid(err)
+		}
+		if !found {
+			return nil, blocks.Invalidf("field '%s' not found in payload", field)
+		}
+		value = resolved
+	}
+	number, err := models.ToNumber(value)
+	if err != nil {
+		return nil, blocks.Invalid(err)
+	}
+	if math.IsNaN(number) {
+		return nil, blocks.Invalidf("value is NaN")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.window.add(number, now)
+	b.evict(properties, now)
+
+	if stringProperty(properties, "emit", "each") != "each" {
+		return []*models.Message{}, nil
+	}
+
+	outputMsg := ctx.Message.Clone()
+	outputMsg.Payload = b.summary(properties)
+	outputMsg.Source = ctx.NodeID
+	return []*models.Message{outputMsg}, nil
+}
+
+// TickInterval ticks only in interval mode
+func (b *PercentileBlock) TickInterval(properties map[string]interface{}) time.Duration {
+	if stringProperty(properties, "emit", "each") != "interval" {
+		return 0
+	}
+	return time.Duration(intProperty(properties, "interval", 10000)) * time.Millisecond
+}
+
+// Tick emits the percentiles of the current window, if it holds any values
+func (b *PercentileBlock) Tick(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.evict(properties, time.Now())
+	if len(b.window.sorted) == 0 {
+		return []*models.Message{}, nil
+	}
+
+	outputMsg := models.NewMessage(b.summary(properties))
+	outputMsg.Source = ctx.NodeID
+	return []*models.Message{outputMsg}, nil
+}
+
+// evict applies the window bounds. Callers must hold b.mu
+func (b *PercentileBlock) evict(properties map[string]interface{}, now time.Time) {
+	maxCount := intProperty(properties, "windowSize", 1000)
+	maxAge := time.Duration(intProperty(properties, "windowDuration", 0)) * time.Millisecond
+	b.window.evict(max(maxCount, 1), maxAge, now)
+}
+
+// summary describes the window: one "p<N>" entry per percentile plus count,
+// min and max. Callers must hold b.mu and the window must not be empty
+func (b *PercentileBlock) summary(properties map[string]interface{}) map[string]interface{} {
+	percentiles, _ := parsePercentiles(stringProperty(properties, "percentiles", "50,90,99"))
+
+	sorted := b.window.sorted
+	result := map[string]interface{}{
+		"count": len(sorted),
+		"min":   sorted[0],
+		"max":   sorted[len(sorted)-1],
+	}
+	for _, p := range percentiles {
+		result["p"+strconv.FormatFloat(p, 'f', -1, 64)] = b.window.quantile(p / 100)
+	}
+	return result
+}
+
+// PercentileBlockFactory creates percentile block instances
+type PercentileBlockFactory struct{}
+
+func (f *PercentileBlockFactory) CreateBlock() blocks.Block {
+	return &PercentileBlock{}
+}
+
+func (f *PercentileBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &PercentileBlock{}
+	return blocks.BlockInfo{
+		Type:        "percentile",
+		Name:        "Percentile",
+		Description: "Emit percentiles of recent numeric values",
+		Category:    "math",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "bar-chart",
+		Color:       "#2196F3",
+	}
+}
@@ -0,0 +1,100 @@
+package builtin
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+// runPercentile feeds values through a fresh percentile block and returns
+// the summary emitted for the last one
+func runPercentile(t *testing.T, properties map[string]interface{}, values []float64) map[string]interface{} {
+	t.Helper()
+	block := &PercentileBlock{}
+	if err := block.Validate(properties); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	var out []*models.Message
+	for _, value := range values {
+		ctx := &models.BlockExecutionContext{NodeID: "p", Message: models.NewMessage(value), Logger: nopLogger{}}
+		var err error
+		if out, err = block.Execute(ctx, properties); err != nil {
+			t.Fatalf("execute %v: %v", value, err)
+		}
+	}
+	if len(out) != 1 {
+		t.Fatalf("emitted %d messages", len(out))
+	}
+	return out[0].Payload.(map[string]interface{})
+}
+
+func TestPercentileDistributions(t *testing.T) {
+	uniform := make([]float64, 100)
+	for i := range uniform {
+		uniform[i] = float64(i + 1)
+	}
+	shuffled := append([]float64(nil), uniform...)
+	rand.New(rand.NewSource(1)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	tests := []struct {
+		name   string
+		values []float64
+		want   map[string]float64
+	}{
+		{name: "uniform 1..100", values: uniform, want: map[string]float64{"p50": 50.5, "p90": 90.1, "p99": 99.01, "min": 1, "max": 100}},
+		{name: "arrival order does not matter", values: shuffled, want: map[string]float64{"p50": 50.5, "p90": 90.1, "p99": 99.01}},
+		{name: "constant", values: []float64{7, 7, 7, 7}, want: map[string]float64{"p50": 7, "p99": 7}},
+		{name: "two points interpolate", values: []float64{10, 0}, want: map[string]float64{"p50": 5, "p90": 9}},
+		{name: "single value", values: []float64{3}, want: map[string]float64{"p50": 3, "p99": 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := runPercentile(t, map[string]interface{}{}, tt.values)
+			if summary["count"] != len(tt.values) {
+				t.Errorf("count = %v, want %d", summary["count"], len(tt.values))
+			}
+			for key, want := range tt.want {
+				if got, _ := summary[key].(float64); math.Abs(got-want) > 1e-9 {
+					t.Errorf("%s = %v, want %v", key, summary[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestPercentileWindowEviction(t *testing.T) {
+	t.Run("by count", func(t *testing.T) {
+		summary := runPercentile(t, map[string]interface{}{"windowSize": 3.0, "percentiles": "50"}, []float64{100, 1, 2, 3, 4})
+		if summary["count"] != 3 || summary["min"] != 2.0 || summary["max"] != 4.0 || summary["p50"] != 3.0 {
+			t.Errorf("summary = %v, want the last 3 values 2..4", summary)
+		}
+	})
+
+	t.Run("by age", func(t *testing.T) {
+		var window quantileWindow
+		start := time.Now()
+		for i, value := range []float64{50, 10, 20} {
+			window.add(value, start.Add(time.Duration(i)*time.Second))
+		}
+
+		window.evict(1000, 1500*time.Millisecond, start.Add(2*time.Second))
+		if len(window.sorted) != 2 || window.sorted[0] != 10 || window.sorted[1] != 20 {
+			t.Errorf("window after evicting older than 1.5s = %v, want [10 20]", window.sorted)
+		}
+		if got := window.quantile(0.5); got != 15 {
+			t.Errorf("p50 = %v, want 15", got)
+		}
+
+		window.evict(1000, 1500*time.Millisecond, start.Add(10*time.Second))
+		if len(window.sorted) != 0 || len(window.arrivals) != 0 {
+			t.Errorf("window not emptied once every value expired: %v", window.sorted)
+		}
+	})
+}
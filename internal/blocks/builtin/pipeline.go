@@ -0,0 +1,272 @@
+package builtin
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// pipelineStep is a single operation of a pipeline
+type pipelineStep struct {
+	Op    string      `json:"op"`              // extract, cast, set, add, subtract, multiply, divide
+	Path  string      `json:"path,omitempty"`  // extract, set: payload path
+	To    string      `json:"to,omitempty"`    // cast: number, string or boolean
+	Value interface{} `json:"value,omitempty"` // set, arithmetic: operand
+}
+
+// pipelineMathBlocks maps arithmetic ops to the math blocks implementing them,
+// so a pipeline step behaves exactly like the standalone block
+var pipelineMathBlocks = map[string]blocks.Block{
+	"add":      &AdditionBlock{},
+	"subtract": &SubtractionBlock{},
+	"multiply": &MultiplicationBlock{},
+	"divide":   &DivisionBlock{},
+}
+
+// PipelineBlock applies an ordered list of simple transforms to each message,
+// replacing a chain of small blocks with a single node
+type PipelineBlock struct{}
+
+func (b *PipelineBlock) GetType() string {
+	return "pipeline"
+}
+
+func (b *PipelineBlock) GetName() string {
+	return "Pipeline"
+}
+
+func (b *PipelineBlock) GetDescription() string {
+	return "Apply a sequence of operations (extract, cast, set, arithmetic) to the message"
+}
+
+func (b *PipelineBlock) GetCategory() string {
+	return "function"
+}
+
+func (b *PipelineBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *PipelineBlock) GetInputs() int {
+	return 1
+}
+
+func (b *PipelineBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *PipelineBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Pipeline",
+		},
+		{
+			Name:         "steps",
+			Type:         "json",
+			DisplayName:  "Steps",
+			Description:  `Ordered operations, e.g. [{"op":"extract","path":"data.temp"},{"op":"cast","to":"number"},{"op":"multiply","value":1.8}]`,
+			Required:     true,
+			DefaultValue: []interface{}{},
+		},
+	}
+}
+
+func (b *PipelineBlock) Validate(properties map[string]interface{}) error {
+	_, err := parsePipelineSteps(properties)
+	return err
+}
+
+func (b *PipelineBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	steps, err := parsePipelineSteps(properties)
+	if err != nil {
+		return nil, blocks.Invalid(err)
+	}
+
+	msg := ctx.Message.Clone()
+	for i, step := range steps {
+		msg, err = applyPipelineStep(ctx, msg, step)
+		if err != nil {
+			return nil, fmt.Errorf("step %d (%s): %w", i+1, step.Op, err)
+		}
+	}
+	msg.Source = ctx.NodeID
+
+	return []*models.Message{msg}, nil
+}
+
+// parsePipelineSteps decodes and validates the steps property, which may be
+// a JSON array or a string containing one
+func parsePipelineSteps(properties map[string]interface{}) ([]pipelineStep, error) {
+	var data []byte
+	switch value := properties["steps"].(type) {
+	case nil:
+		return nil, fmt.Errorf("steps property is required")
+	case string:
+		data = []byte(value)
+	default:
+		var err error
+		if data, err = json.Marshal(value); err != nil {
+			return nil, fmt.Errorf("steps is not valid JSON: %w", err)
+		}
+	}
+
+	var steps []pipelineStep
+	if err := json.Unmarshal(data, &steps); err != nil {
+		return nil, fmt.Errorf("steps must be an array of operations: %w", err)
+	}
+
+	for i, step := range steps {
+		if err := validatePipelineStep(step); err != nil {
+			return nil, fmt.Errorf("step %d (%s): %w", i+1, step.Op, err)
+		}
+	}
+	return steps, nil
+}
+
+func validatePipelineStep(step pipelineStep) error {
+	switch step.Op {
+	case "extract", "set":
+		if step.Path == "" && step.Op == "set" {
+			return fmt.Errorf("path is required")
+		}
+		return models.ValidatePath(step.Path)
+	case "cast":
+		if step.To != "number" && step.To != "string" && step.To != "boolean" {
+			return fmt.Errorf("invalid cast target '%s'", step.To)
+		}
+		return nil
+	default:
+		block, ok := pipelineMathBlocks[step.Op]
+		if !ok {
+			return fmt.Errorf("unknown operation")
+		}
+		return block.Validate(map[string]interface{}{"value": step.Value})
+	}
+}
+
+// applyPipelineStep applies one step to msg, which the pipeline owns
+func applyPipelineStep(ctx *models.BlockExecutionContext, msg *models.Message, step pipelineStep) (*models.Message, error) {
+	switch step.Op {
+	case "extract":
+		value, found, err := models.ResolvePath(msg.Payload, step.Path)
+		if err != nil {
+			return nil, blocks.Invalid(err)
+		}
+		if !found {
+			return nil, blocks.Invalidf("path '%s' not found in payload", step.Path)
+		}
+		msg.Payload = value
+	case "set":
+		payload, err := models.SetPath(msg.Payload, step.Path, step.Value)
+		if err != nil {
+			return nil, blocks.Invalid(err)
+		}
+		msg.Payload = payload
+	case "cast":
+		value, err := castValue(msg.Payload, step.To)
+		if err != nil {
+			return nil, blocks.Invalid(err)
+		}
+		msg.Payload = value
+	default:
+		stepCtx := *ctx
+		stepCtx.Message = msg
+		outputs, err := pipelineMathBlocks[step.Op].Execute(&stepCtx, map[string]interface{}{"value": step.Value})
+		if err != nil {
+			return nil, err
+		}
+		msg.Payload = outputs[0].Payload
+	}
+	return msg, nil
+}
+
+// castValue converts a payload to number, string or boolean
+func castValue(value interface{}, to string) (interface{}, error) {
+	switch to {
+	case "number":
+		if n, err := models.ToNumber(value); err == nil {
+			return n, nil
+		}
+		switch v := value.(type) {
+		case string:
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot cast '%s' to number", v)
+			}
+			return n, nil
+		case bool:
+			if v {
+				return 1.0, nil
+			}
+			return 0.0, nil
+		}
+	case "string":
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case []byte:
+			return string(v), nil
+		case nil:
+			return "", nil
+		case map[string]interface{}, []interface{}:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			return string(data), nil
+		default:
+			return fmt.Sprint(v), nil
+		}
+	case "boolean":
+		if n, err := models.ToNumber(value); err == nil {
+			return n != 0, nil
+		}
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("cannot cast '%s' to boolean", v)
+			}
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot cast %T to %s", value, to)
+}
+
+// PipelineBlockFactory creates pipeline block instances
+type PipelineBlockFactory struct{}
+
+func (f *PipelineBlockFactory) CreateBlock() blocks.Block {
+	return &PipelineBlock{}
+}
+
+func (f *PipelineBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &PipelineBlock{}
+	return blocks.BlockInfo{
+		Type:        "pipeline",
+		Name:        "Pipeline",
+		Description: "Apply a sequence of operations to the message",
+		Category:    "function",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "pipe",
+		Color:       "#3F51B5",
+	}
+}
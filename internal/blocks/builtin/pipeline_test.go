@@ -0,0 +1,97 @@
+package builtin
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestPipeline(t *testing.T) {
+	tests := []struct {
+		name      string
+		steps     string
+		payload   interface{}
+		want      interface{}
+		wantErr   string // Substring of the error naming the failed step
+		wantCause blocks.ErrorCategory
+	}{
+		{
+			name:    "extract, cast and multiply",
+			steps:   `[{"op":"extract","path":"data.temp"},{"op":"cast","to":"number"},{"op":"multiply","value":2}]`,
+			payload: map[string]interface{}{"data": map[string]interface{}{"temp": "21.5"}},
+			want:    43.0,
+		},
+		{
+			name:    "set then extract",
+			steps:   `[{"op":"set","path":"unit","value":"C"},{"op":"extract","path":"unit"},{"op":"cast","to":"string"}]`,
+			payload: map[string]interface{}{"temp": 20.0},
+			want:    "C",
+		},
+		{
+			name:      "cast fails mid-chain",
+			steps:     `[{"op":"extract","path":"data.temp"},{"op":"cast","to":"number"},{"op":"add","value":1}]`,
+			payload:   map[string]interface{}{"data": map[string]interface{}{"temp": "warm"}},
+			wantErr:   "step 2 (cast)",
+			wantCause: blocks.ErrorInvalid,
+		},
+		{
+			name:      "extract of a missing path",
+			steps:     `[{"op":"extract","path":"data.humidity"},{"op":"cast","to":"number"}]`,
+			payload:   map[string]interface{}{"data": map[string]interface{}{}},
+			wantErr:   "step 1 (extract)",
+			wantCause: blocks.ErrorInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := &PipelineBlock{}
+			properties := map[string]interface{}{"steps": tt.steps}
+			if err := block.Validate(properties); err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+
+			input := models.NewMessage(tt.payload)
+			ctx := &models.BlockExecutionContext{NodeID: "pipeline", Message: input, Logger: nopLogger{}}
+			out, err := block.Execute(ctx, properties)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one naming %q", err, tt.wantErr)
+				}
+				if got := blocks.CategoryOf(err); got != tt.wantCause {
+					t.Errorf("error category = %s, want %s", got, tt.wantCause)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+
+			if len(out) != 1 || !reflect.DeepEqual(out[0].Payload, tt.want) {
+				t.Fatalf("output = %v, want one message with %v", out, tt.want)
+			}
+			if out[0].Source != "pipeline" {
+				t.Errorf("source = %q, want pipeline", out[0].Source)
+			}
+			if !reflect.DeepEqual(input.Payload, tt.payload) {
+				t.Errorf("input payload changed to %v", input.Payload)
+			}
+		})
+	}
+}
+
+func TestPipelineValidate(t *testing.T) {
+	for _, steps := range []string{
+		`{"op":"extract"}`,
+		`[{"op":"explode"}]`,
+		`[{"op":"cast","to":"date"}]`,
+		`[{"op":"set"}]`,
+	} {
+		if err := (&PipelineBlock{}).Validate(map[string]interface{}{"steps": steps}); err == nil {
+			t.Errorf("steps %s accepted", steps)
+		}
+	}
+}
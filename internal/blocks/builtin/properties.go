@@ -0,0 +1,71 @@
+package builtin
+
+import (
+	"fmt"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// Helpers to read block properties with defaults. Numbers decoded from JSON
+// arrive as float64 (or json.Number when numbers are preserved), so numeric
+// helpers accept any numeric type
+
+// stringProperty returns a string property or the default when unset
+func stringProperty(properties map[string]interface{}, name, defaultValue string) string {
+	if value, ok := properties[name].(string); ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// intProperty returns a numeric property as int or the default when unset or invalid
+func intProperty(properties map[string]interface{}, name string, defaultValue int) int {
+	value, err := models.ToNumber(properties[name])
+	if err != nil {
+		return defaultValue
+	}
+	return int(value)
+}
+
+// boolProperty returns a boolean property or the default when unset
+func boolProperty(properties map[string]interface{}, name string, defaultValue bool) bool {
+	if value, ok := properties[name].(bool); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// payloadPathProperty defines the payloadPath property shared by sink blocks
+// that can act on part of the payload instead of all of it
+func payloadPathProperty() blocks.PropertyDefinition {
+	return blocks.PropertyDefinition{
+		Name:         "payloadPath",
+		Type:         "string",
+		DisplayName:  "Payload Path",
+		Description:  "Path within the payload to use, e.g. temperature or items[0] (empty = whole payload)",
+		Required:     false,
+		DefaultValue: "",
+	}
+}
+
+// validatePayloadPath checks the payloadPath property
+func validatePayloadPath(properties map[string]interface{}) error {
+	if path := stringProperty(properties, "payloadPath", ""); path != "" {
+		if err := models.ValidatePath(path); err != nil {
+			return fmt.Errorf("invalid payloadPath: %w", err)
+		}
+	}
+	return nil
+}
+
+// resolvePayload returns the part of the message payload selected by the
+// payloadPath property, or the whole payload when it is empty. found is false
+// when the path doesn't exist in the payload
+func resolvePayload(msg *models.Message, properties map[string]interface{}) (value interface{}, found bool, err error) {
+	value, found, err = models.ResolvePath(msg.Payload, stringProperty(properties, "payloadPath", ""))
+	if err != nil {
+		return nil, false, blocks.Invalidf("invalid payloadPath: %w", err)
+	}
+	return value, found, nil
+}
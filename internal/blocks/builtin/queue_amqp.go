@@ -0,0 +1,83 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// amqpConsumer consumes an AMQP 0-9-1 queue with manual acknowledgement
+type amqpConsumer struct {
+	conn       *amqp.Connection
+	deliveries <-chan amqp.Delivery
+}
+
+// dialAMQP connects to the broker and starts consuming the queue, limiting
+// unacknowledged deliveries to the prefetch count
+func dialAMQP(opts queueConsumerOptions) (queueConsumer, error) {
+	conn, err := amqp.Dial(opts.URL)
+	if err != nil {
+		return nil, err
+	}
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := channel.Qos(opts.Prefetch, 0, false); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	deliveries, err := channel.Consume(opts.Queue, opts.Consumer, false, false, false, false, nil)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("consume queue '%s': %w", opts.Queue, err)
+	}
+	return &amqpConsumer{conn: conn, deliveries: deliveries}, nil
+}
+
+func (c *amqpConsumer) Consume(ctx context.Context, handle func(queueDelivery)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-c.deliveries:
+			if !ok {
+				return errors.New("AMQP connection closed")
+			}
+
+			headers := map[string]string{"routing_key": d.RoutingKey}
+			if d.MessageId != "" {
+				headers["message_id"] = d.MessageId
+			}
+			for name, value := range d.Headers {
+				if text, ok := value.(string); ok {
+					headers[name] = text
+				}
+			}
+			// Quorum queues count earlier deliveries in x-delivery-count;
+			// classic queues only flag redeliveries
+			deliveries := 0
+			if count, ok := d.Headers["x-delivery-count"].(int64); ok {
+				deliveries = int(count) + 1
+			} else if !d.Redelivered {
+				deliveries = 1
+			}
+			handle(queueDelivery{
+				Body:        d.Body,
+				ContentType: d.ContentType,
+				Headers:     headers,
+				Redelivered: d.Redelivered,
+				Deliveries:  deliveries,
+				Ack:         func() error { return d.Ack(false) },
+				Nack:        func(requeue bool) error { return d.Nack(false, requeue) },
+			})
+		}
+	}
+}
+
+func (c *amqpConsumer) Close() error {
+	return c.conn.Close()
+}
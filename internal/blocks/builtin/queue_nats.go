@@ -0,0 +1,89 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// natsConsumer consumes a NATS JetStream subject with explicit
+// acknowledgement. Dropped connections are re-established by the client
+type natsConsumer struct {
+	conn     *nats.Conn
+	messages chan *nats.Msg
+	closed   chan struct{}
+}
+
+// dialNATS connects to the server and subscribes to the subject through a
+// JetStream consumer, limiting unacknowledged deliveries to the prefetch
+// count. A named consumer is durable and resumes where it left off
+func dialNATS(opts queueConsumerOptions) (queueConsumer, error) {
+	closed := make(chan struct{})
+	conn, err := nats.Connect(opts.URL,
+		nats.Name("block-flow"),
+		nats.MaxReconnects(-1),
+		nats.ClosedHandler(func(*nats.Conn) { close(closed) }),
+	)
+	if err != nil {
+		return nil, err
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	messages := make(chan *nats.Msg, opts.Prefetch)
+	subOpts := []nats.SubOpt{nats.ManualAck(), nats.AckExplicit(), nats.MaxAckPending(opts.Prefetch)}
+	if opts.Consumer != "" {
+		subOpts = append(subOpts, nats.Durable(opts.Consumer))
+	}
+	// Closing the connection without unsubscribing keeps a durable consumer
+	if _, err := js.ChanSubscribe(opts.Queue, messages, subOpts...); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("subscribe to '%s': %w", opts.Queue, err)
+	}
+	return &natsConsumer{conn: conn, messages: messages, closed: closed}, nil
+}
+
+func (c *natsConsumer) Consume(ctx context.Context, handle func(queueDelivery)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.closed:
+			return errors.New("NATS connection closed")
+		case m := <-c.messages:
+			headers := map[string]string{"subject": m.Subject}
+			for name := range m.Header {
+				headers[name] = m.Header.Get(name)
+			}
+			redelivered, deliveries := false, 0
+			if meta, err := m.Metadata(); err == nil {
+				redelivered = meta.NumDelivered > 1
+				deliveries = int(meta.NumDelivered)
+			}
+			handle(queueDelivery{
+				Body:        m.Data,
+				ContentType: m.Header.Get("Content-Type"),
+				Headers:     headers,
+				Redelivered: redelivered,
+				Deliveries:  deliveries,
+				Ack:         func() error { return m.Ack() },
+				Nack: func(requeue bool) error {
+					if requeue {
+						return m.Nak()
+					}
+					return m.Term()
+				},
+			})
+		}
+	}
+}
+
+func (c *natsConsumer) Close() error {
+	c.conn.Close()
+	return nil
+}
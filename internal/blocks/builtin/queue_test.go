@@ -0,0 +1,39 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"block-flow/internal/blocks"
+)
+
+func TestRequeueQueueDelivery(t *testing.T) {
+	base := errors.New("boom")
+
+	tests := []struct {
+		name          string
+		err           error
+		deliveries    int
+		maxDeliveries int
+		want          bool
+	}{
+		{name: "transient", err: blocks.Transient(base), deliveries: 1, maxDeliveries: 5, want: true},
+		{name: "invalid", err: blocks.Invalid(base), deliveries: 1, maxDeliveries: 5},
+		{name: "fatal", err: blocks.Fatal(base), deliveries: 1, maxDeliveries: 5},
+		{name: "unknown", err: base, deliveries: 1, maxDeliveries: 5},
+		{name: "transient wrapped", err: fmt.Errorf("node n: %w", blocks.Transient(base)), deliveries: 1, maxDeliveries: 5, want: true},
+		{name: "transient below the limit", err: blocks.Transient(base), deliveries: 4, maxDeliveries: 5, want: true},
+		{name: "transient at the limit", err: blocks.Transient(base), deliveries: 5, maxDeliveries: 5},
+		{name: "count unknown", err: blocks.Transient(base), deliveries: 0, maxDeliveries: 5, want: true},
+		{name: "no limit", err: blocks.Transient(base), deliveries: 100, maxDeliveries: 0, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requeueQueueDelivery(tt.err, tt.deliveries, tt.maxDeliveries); got != tt.want {
+				t.Errorf("requeueQueueDelivery = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,19 +2,65 @@ package builtin
 
 import (
 	"block-flow/internal/blocks"
+	"block-flow/internal/endpoints"
+	"block-flow/internal/events"
+	"block-flow/internal/storage"
 )
 
-// RegisterBuiltinBlocks registers all built-in blocks with the registry
-func RegisterBuiltinBlocks(registry *blocks.Registry) {
+// Services provides engine facilities to blocks that need them
+type Services struct {
+	Events    *events.Bus
+	Storage   storage.Storage
+	Endpoints *endpoints.Registry
+}
+
+// RegisterBuiltinBlocks registers all built-in blocks with the registry. It
+// panics if a built-in type is registered twice
+func RegisterBuiltinBlocks(registry *blocks.Registry, services Services) {
 	// Input blocks
-	registry.Register(&InjectBlockFactory{})
+	registry.MustRegister(&InjectBlockFactory{})
+	registry.MustRegister(&EventListenerBlockFactory{bus: services.Events})
+	registry.MustRegister(&OnStartBlockFactory{})
+	registry.MustRegister(&OnStopBlockFactory{})
+	registry.MustRegister(&CatchBlockFactory{})
+	registry.MustRegister(&HTTPPollBlockFactory{})
+	registry.MustRegister(&HeartbeatBlockFactory{})
+	registry.MustRegister(&QueueInBlockFactory{})
+	registry.MustRegister(&HTTPInBlockFactory{endpoints: services.Endpoints})
 
 	// Output blocks
-	registry.Register(&DebugBlockFactory{})
+	registry.MustRegister(&DebugBlockFactory{})
+	registry.MustRegister(&HTTPResponseBlockFactory{endpoints: services.Endpoints})
 
 	// Math blocks
-	registry.Register(&AdditionBlockFactory{})
-	registry.Register(&SubtractionBlockFactory{})
-	registry.Register(&MultiplicationBlockFactory{})
-	registry.Register(&DivisionBlockFactory{})
+	registry.MustRegister(&AdditionBlockFactory{})
+	registry.MustRegister(&SubtractionBlockFactory{})
+	registry.MustRegister(&MultiplicationBlockFactory{})
+	registry.MustRegister(&DivisionBlockFactory{})
+	registry.MustRegister(&PercentileBlockFactory{})
+
+	// Function blocks
+	registry.MustRegister(&SchemaValidateBlockFactory{})
+	registry.MustRegister(&PipelineBlockFactory{})
+	registry.MustRegister(&MergeObjectBlockFactory{})
+	registry.MustRegister(&MessageAgeBlockFactory{})
+	registry.MustRegister(&SwitchBlockFactory{})
+	registry.MustRegister(&FunctionBlockFactory{})
+	registry.MustRegister(&DelayBlockFactory{})
+	registry.MustRegister(&FilterBlockFactory{})
+	registry.MustRegister(&TemplateBlockFactory{})
+	registry.MustRegister(&JSONBlockFactory{})
+
+	// Sequence blocks
+	registry.MustRegister(&CorrelateBlockFactory{})
+	registry.MustRegister(&AppendBlockFactory{})
+	registry.MustRegister(&HoldBlockFactory{})
+	registry.MustRegister(&SplitBlockFactory{})
+	registry.MustRegister(&JoinBlockFactory{})
+
+	// Storage blocks
+	registry.MustRegister(&ConfigReadBlockFactory{storage: services.Storage})
+	registry.MustRegister(&ConfigWriteBlockFactory{storage: services.Storage})
+	registry.MustRegister(&KVGetBlockFactory{storage: services.Storage})
+	registry.MustRegister(&KVSetBlockFactory{storage: services.Storage})
 }
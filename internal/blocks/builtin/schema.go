@@ -0,0 +1,200 @@
+package builtin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// schemaResourceURL identifies the inline schema inside the compiler
+const schemaResourceURL = "inline://block-flow/schema.json"
+
+// SchemaValidateBlock validates message payloads against a JSON Schema,
+// forwarding valid messages on port 0 and invalid ones on port 1
+type SchemaValidateBlock struct {
+	compiled *jsonschema.Schema
+	source   string
+	mu       sync.Mutex
+}
+
+func (b *SchemaValidateBlock) GetType() string {
+	return "schema-validate"
+}
+
+func (b *SchemaValidateBlock) GetName() string {
+	return "Schema Validate"
+}
+
+func (b *SchemaValidateBlock) GetDescription() string {
+	return "Validate the payload against a JSON Schema and route valid/invalid messages"
+}
+
+func (b *SchemaValidateBlock) GetCategory() string {
+	return "function"
+}
+
+func (b *SchemaValidateBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *SchemaValidateBlock) GetInputs() int {
+	return 1
+}
+
+func (b *SchemaValidateBlock) GetOutputs() int {
+	return 2
+}
+
+func (b *SchemaValidateBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Schema Validate",
+		},
+		{
+			Name:         "schema",
+			Type:         "json",
+			DisplayName:  "Schema",
+			Description:  "JSON Schema the payload must satisfy (valid → port 0, invalid → port 1)",
+			Required:     true,
+			DefaultValue: `{"type": "object"}`,
+		},
+	}
+}
+
+func (b *SchemaValidateBlock) Validate(properties map[string]interface{}) error {
+	_, err := b.schema(properties)
+	return err
+}
+
+func (b *SchemaValidateBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	schema, err := b.schema(properties)
+	if err != nil {
+		return nil, blocks.Invalid(err)
+	}
+
+	// Normalize the payload to generic JSON values (float64, map[string]interface{}, ...)
+	data, err := json.Marshal(ctx.Message.Payload)
+	if err != nil {
+		return nil, blocks.Invalidf("payload is not JSON-serializable: %w", err)
+	}
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, blocks.Invalidf("payload is not JSON-serializable: %w", err)
+	}
+
+	outputMsg := ctx.Message.Clone()
+	outputMsg.Source = ctx.NodeID
+
+	if err := schema.Validate(document); err != nil {
+		outputMsg.Port = 1
+		outputMsg.SetContext("schemaErrors", schemaErrors(err))
+
+		ctx.Logger.Debug("Payload failed schema validation", map[string]interface{}{
+			"errors": outputMsg.Context["schemaErrors"],
+		})
+	}
+
+	return []*models.Message{outputMsg}, nil
+}
+
+// schema returns the compiled schema for the configured source, compiling
+// and caching it when the source changes
+func (b *SchemaValidateBlock) schema(properties map[string]interface{}) (*jsonschema.Schema, error) {
+	var source string
+	switch value := properties["schema"].(type) {
+	case string:
+		source = value
+	case nil:
+		return nil, fmt.Errorf("schema property is required")
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+		}
+		source = string(data)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.compiled != nil && b.source == source {
+		return b.compiled, nil
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaResourceURL, strings.NewReader(source)); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	compiled, err := compiler.Compile(schemaResourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	b.compiled = compiled
+	b.source = source
+	return compiled, nil
+}
+
+// schemaErrors flattens a validation error into readable messages
+func schemaErrors(err error) []string {
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return []string{err.Error()}
+	}
+
+	messages := make([]string, 0)
+	for _, basic := range validationErr.BasicOutput().Errors {
+		if basic.Error == "" || strings.HasPrefix(basic.Error, "doesn't validate with") {
+			continue
+		}
+		location := basic.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		messages = append(messages, location+": "+basic.Error)
+	}
+	if len(messages) == 0 {
+		messages = append(messages, validationErr.Error())
+	}
+	return messages
+}
+
+// SchemaValidateBlockFactory creates schema validate block instances
+type SchemaValidateBlockFactory struct{}
+
+func (f *SchemaValidateBlockFactory) CreateBlock() blocks.Block {
+	return &SchemaValidateBlock{}
+}
+
+func (f *SchemaValidateBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &SchemaValidateBlock{}
+	return blocks.BlockInfo{
+		Type:        "schema-validate",
+		Name:        "Schema Validate",
+		Description: "Validate the payload against a JSON Schema",
+		Category:    "function",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "check-square",
+		Color:       "#607D8B",
+	}
+}
@@ -0,0 +1,105 @@
+package builtin
+
+import (
+	"testing"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// nopLogger discards block logs
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, map[string]interface{})        {}
+func (nopLogger) Info(string, map[string]interface{})         {}
+func (nopLogger) Warn(string, map[string]interface{})         {}
+func (nopLogger) Error(string, error, map[string]interface{}) {}
+
+func TestSchemaValidate(t *testing.T) {
+	const personSchema = `{
+		"type": "object",
+		"required": ["name"],
+		"properties": {"name": {"type": "string"}, "age": {"type": "integer", "minimum": 0}}
+	}`
+
+	tests := []struct {
+		name       string
+		schema     string
+		payload    interface{}
+		wantPort   int
+		wantErrors bool // schemaErrors set on the message
+	}{
+		{
+			name:     "valid payload",
+			schema:   personSchema,
+			payload:  map[string]interface{}{"name": "Ada", "age": 36},
+			wantPort: 0,
+		},
+		{
+			name:       "invalid payload",
+			schema:     personSchema,
+			payload:    map[string]interface{}{"age": -1},
+			wantPort:   1,
+			wantErrors: true,
+		},
+		{
+			name:       "wrong payload type",
+			schema:     personSchema,
+			payload:    "Ada",
+			wantPort:   1,
+			wantErrors: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := &SchemaValidateBlock{}
+			properties := map[string]interface{}{"schema": tt.schema}
+			if err := block.Validate(properties); err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+
+			ctx := &models.BlockExecutionContext{NodeID: "schema", Message: models.NewMessage(tt.payload), Logger: nopLogger{}}
+			out, err := block.Execute(ctx, properties)
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+			if len(out) != 1 {
+				t.Fatalf("emitted %d messages", len(out))
+			}
+			if out[0].Port != tt.wantPort {
+				t.Errorf("port = %d, want %d", out[0].Port, tt.wantPort)
+			}
+			errs, _ := out[0].Context["schemaErrors"].([]string)
+			if (len(errs) > 0) != tt.wantErrors {
+				t.Errorf("schemaErrors = %v, want errors %v", errs, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestSchemaValidateBadSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema interface{}
+	}{
+		{name: "not JSON", schema: `{"type": `},
+		{name: "unknown type", schema: `{"type": "person"}`},
+		{name: "missing", schema: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := &SchemaValidateBlock{}
+			properties := map[string]interface{}{"schema": tt.schema}
+			if err := block.Validate(properties); err == nil {
+				t.Error("validate accepted the schema")
+			}
+
+			ctx := &models.BlockExecutionContext{NodeID: "schema", Message: models.NewMessage(map[string]interface{}{}), Logger: nopLogger{}}
+			if _, err := block.Execute(ctx, properties); blocks.CategoryOf(err) != blocks.ErrorInvalid {
+				t.Errorf("execute error = %v, want an invalid error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package builtin
+
+import (
+	"sort"
+	"strings"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// SplitBlock fans a payload out into one message per array element, object
+// entry or delimited piece of text. Every message carries its parts so a Join
+// block can rebuild the original
+type SplitBlock struct{}
+
+func (b *SplitBlock) GetType() string {
+	return "split"
+}
+
+func (b *SplitBlock) GetName() string {
+	return "Split"
+}
+
+func (b *SplitBlock) GetDescription() string {
+	return "Split an array, object or text payload into one message per part"
+}
+
+func (b *SplitBlock) GetCategory() string {
+	return "sequence"
+}
+
+func (b *SplitBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *SplitBlock) GetInputs() int {
+	return 1
+}
+
+func (b *SplitBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *SplitBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Split",
+		},
+		{
+			Name:         "delimiter",
+			Type:         "string",
+			DisplayName:  "Delimiter",
+			Description:  "Separator text payloads are split on",
+			Required:     false,
+			DefaultValue: "\n",
+		},
+	}
+}
+
+func (b *SplitBlock) Validate(properties map[string]interface{}) error {
+	return nil
+}
+
+func (b *SplitBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	sequenceID := models.NewID()
+	part := func(payload interface{}, parts models.Parts) *models.Message {
+		msg := ctx.Message.Clone()
+		msg.Payload = payload
+		msg.Source = ctx.NodeID
+		parts.ID = sequenceID
+		msg.SetParts(parts)
+		return msg
+	}
+
+	var outputs []*models.Message
+	switch payload := ctx.Message.Payload.(type) {
+	case []interface{}:
+		outputs = make([]*models.Message, len(payload))
+		for i, element := range payload {
+			outputs[i] = part(element, models.Parts{Index: i, Count: len(payload), Type: models.PartsArray})
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(payload))
+		for key := range payload {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		outputs = make([]*models.Message, len(keys))
+		for i, key := range keys {
+			outputs[i] = part(payload[key], models.Parts{Index: i, Count: len(keys), Type: models.PartsObject, Key: key})
+		}
+	case string:
+		delimiter := stringProperty(properties, "delimiter", "\n")
+		pieces := strings.Split(payload, delimiter)
+		outputs = make([]*models.Message, len(pieces))
+		for i, piece := range pieces {
+			outputs[i] = part(piece, models.Parts{Index: i, Count: len(pieces), Type: models.PartsString, Delimiter: delimiter})
+		}
+	default:
+		// Nothing to split: forward the message as a single part
+		outputMsg := ctx.Message.Clone()
+		outputMsg.Source = ctx.NodeID
+		return []*models.Message{outputMsg}, nil
+	}
+
+	return outputs, nil
+}
+
+// SplitBlockFactory creates split block instances
+type SplitBlockFactory struct{}
+
+func (f *SplitBlockFactory) CreateBlock() blocks.Block {
+	return &SplitBlock{}
+}
+
+func (f *SplitBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &SplitBlock{}
+	return blocks.BlockInfo{
+		Type:        "split",
+		Name:        "Split",
+		Description: "Split an array, object or text payload into one message per part",
+		Category:    "sequence",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "scissors",
+		Color:       "#795548",
+	}
+}
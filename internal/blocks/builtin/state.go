@@ -0,0 +1,279 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+	"block-flow/internal/storage"
+)
+
+// stateKeyPattern restricts keys to characters that are safe in file names.
+// Dots are excluded so that namespaced keys can't collide across flows
+var stateKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// stateKey namespaces a key per flow so flows can't read each other's state
+func stateKey(flowID, key string) string {
+	return "flow." + flowID + "." + key
+}
+
+// validateStateKey checks the key property shared by the config blocks
+func validateStateKey(store storage.Storage, properties map[string]interface{}) error {
+	if store == nil {
+		return fmt.Errorf("storage is not available")
+	}
+	key, _ := properties["key"].(string)
+	if key == "" {
+		return fmt.Errorf("key is required")
+	}
+	if !stateKeyPattern.MatchString(key) {
+		return fmt.Errorf("key may only contain letters, digits, '-' and '_'")
+	}
+	return nil
+}
+
+// executionContext returns the flow context of an execution, or a background
+// context when the block runs outside a flow
+func executionContext(ctx *models.BlockExecutionContext) context.Context {
+	if ctx.Context != nil {
+		return ctx.Context
+	}
+	return context.Background()
+}
+
+// ConfigWriteBlock persists a value from the message under a per-flow key
+type ConfigWriteBlock struct {
+	storage storage.Storage
+}
+
+func (b *ConfigWriteBlock) GetType() string {
+	return "config-write"
+}
+
+func (b *ConfigWriteBlock) GetName() string {
+	return "Config Write"
+}
+
+func (b *ConfigWriteBlock) GetDescription() string {
+	return "Persist a value (e.g. last processed ID) so the flow can resume after a restart"
+}
+
+func (b *ConfigWriteBlock) GetCategory() string {
+	return "storage"
+}
+
+func (b *ConfigWriteBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.ActionGroup
+}
+
+func (b *ConfigWriteBlock) GetInputs() int {
+	return 1
+}
+
+func (b *ConfigWriteBlock) GetOutputs() int {
+	return 0
+}
+
+func (b *ConfigWriteBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Config Write",
+		},
+		{
+			Name:        "key",
+			Type:        "string",
+			DisplayName: "Key",
+			Description: "Key to store the value under, scoped to this flow",
+			Required:    true,
+		},
+		{
+			Name:         "valuePath",
+			Type:         "string",
+			DisplayName:  "Value Path",
+			Description:  "Payload path of the value to store (empty = whole payload)",
+			Required:     false,
+			DefaultValue: "",
+		},
+	}
+}
+
+func (b *ConfigWriteBlock) Validate(properties map[string]interface{}) error {
+	if err := validateStateKey(b.storage, properties); err != nil {
+		return err
+	}
+	if path, _ := properties["valuePath"].(string); path != "" {
+		if err := models.ValidatePath(path); err != nil {
+			return fmt.Errorf("invalid valuePath: %w", err)
+		}
+	}
+	return nil
+}
+
+func (b *ConfigWriteBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	key, _ := properties["key"].(string)
+	value, found, err := models.ResolvePath(ctx.Message.Payload, stringProperty(properties, "valuePath", ""))
+	if err != nil {
+		return nil, blocks.Invalidf("invalid valuePath: %w", err)
+	}
+	if !found {
+		return nil, blocks.Invalidf("value path not found in payload")
+	}
+
+	if err := b.storage.SaveConfig(executionContext(ctx), stateKey(ctx.FlowID, key), value); err != nil {
+		return nil, blocks.Transient(fmt.Errorf("failed to save %q: %w", key, err))
+	}
+
+	return []*models.Message{}, nil
+}
+
+// ConfigWriteBlockFactory creates config write block instances
+type ConfigWriteBlockFactory struct {
+	storage storage.Storage
+}
+
+func (f *ConfigWriteBlockFactory) CreateBlock() blocks.Block {
+	return &ConfigWriteBlock{storage: f.storage}
+}
+
+func (f *ConfigWriteBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &ConfigWriteBlock{}
+	return blocks.BlockInfo{
+		Type:        "config-write",
+		Name:        "Config Write",
+		Description: "Persist a value for this flow",
+		Category:    "storage",
+		BlockGroup:  blocks.ActionGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "save",
+		Color:       "#607D8B",
+	}
+}
+
+// ConfigReadBlock loads a value persisted by ConfigWriteBlock in the same flow
+type ConfigReadBlock struct {
+	storage storage.Storage
+}
+
+func (b *ConfigReadBlock) GetType() string {
+	return "config-read"
+}
+
+func (b *ConfigReadBlock) GetName() string {
+	return "Config Read"
+}
+
+func (b *ConfigReadBlock) GetDescription() string {
+	return "Load a value persisted by Config Write in this flow into the payload"
+}
+
+func (b *ConfigReadBlock) GetCategory() string {
+	return "storage"
+}
+
+func (b *ConfigReadBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *ConfigReadBlock) GetInputs() int {
+	return 1
+}
+
+func (b *ConfigReadBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *ConfigReadBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Config Read",
+		},
+		{
+			Name:        "key",
+			Type:        "string",
+			DisplayName: "Key",
+			Description: "Key to load, scoped to this flow",
+			Required:    true,
+		},
+		{
+			Name:        "defaultValue",
+			Type:        "json",
+			DisplayName: "Default Value",
+			Description: "Payload to emit when nothing has been stored yet",
+			Required:    false,
+		},
+	}
+}
+
+func (b *ConfigReadBlock) Validate(properties map[string]interface{}) error {
+	return validateStateKey(b.storage, properties)
+}
+
+func (b *ConfigReadBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	key, _ := properties["key"].(string)
+
+	var value interface{}
+	err := b.storage.LoadConfig(executionContext(ctx), stateKey(ctx.FlowID, key), &value)
+	if errors.Is(err, os.ErrNotExist) {
+		value = properties["defaultValue"]
+	} else if err != nil {
+		return nil, blocks.Transient(fmt.Errorf("failed to load %q: %w", key, err))
+	}
+
+	var output *models.Message
+	if ctx.Message != nil {
+		output = ctx.Message.Clone()
+		output.Payload = value
+	} else {
+		output = models.NewMessage(value)
+	}
+	output.Source = ctx.NodeID
+
+	return []*models.Message{output}, nil
+}
+
+// ConfigReadBlockFactory creates config read block instances
+type ConfigReadBlockFactory struct {
+	storage storage.Storage
+}
+
+func (f *ConfigReadBlockFactory) CreateBlock() blocks.Block {
+	return &ConfigReadBlock{storage: f.storage}
+}
+
+func (f *ConfigReadBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &ConfigReadBlock{}
+	return blocks.BlockInfo{
+		Type:        "config-read",
+		Name:        "Config Read",
+		Description: "Load a value persisted for this flow",
+		Category:    "storage",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "database",
+		Color:       "#607D8B",
+	}
+}
@@ -0,0 +1,71 @@
+package builtin
+
+import (
+	"reflect"
+	"testing"
+
+	"block-flow/internal/models"
+	"block-flow/internal/storage"
+)
+
+func TestConfigWriteRead(t *testing.T) {
+	store := storage.NewFileStorage(t.TempDir())
+	write := &ConfigWriteBlock{storage: store}
+	read := &ConfigReadBlock{storage: store}
+
+	writeProps := map[string]interface{}{"key": "cursor", "valuePath": "last.id"}
+	readProps := map[string]interface{}{"key": "cursor", "defaultValue": "none"}
+	if err := write.Validate(writeProps); err != nil {
+		t.Fatalf("validate write: %v", err)
+	}
+	if err := read.Validate(readProps); err != nil {
+		t.Fatalf("validate read: %v", err)
+	}
+
+	// readIn reads the cursor as seen by flowID
+	readIn := func(flowID string) interface{} {
+		t.Helper()
+		ctx := &models.BlockExecutionContext{NodeID: "read", FlowID: flowID, Logger: nopLogger{}}
+		out, err := read.Execute(ctx, readProps)
+		if err != nil {
+			t.Fatalf("read in %s: %v", flowID, err)
+		}
+		if len(out) != 1 {
+			t.Fatalf("read in %s emitted %d messages", flowID, len(out))
+		}
+		return out[0].Payload
+	}
+
+	if got := readIn("flow-a"); got != "none" {
+		t.Errorf("read before any write = %v, want the default", got)
+	}
+
+	payload := map[string]interface{}{"last": map[string]interface{}{"id": 42.0, "tags": []interface{}{"x"}}}
+	ctx := &models.BlockExecutionContext{NodeID: "write", FlowID: "flow-a", Message: models.NewMessage(payload), Logger: nopLogger{}}
+	if _, err := write.Execute(ctx, writeProps); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got := readIn("flow-a"); got != 42.0 {
+		t.Errorf("read after write = %v, want 42", got)
+	}
+
+	// A whole payload round-trips as well
+	wholeProps := map[string]interface{}{"key": "cursor"}
+	if _, err := write.Execute(ctx, wholeProps); err != nil {
+		t.Fatalf("write whole payload: %v", err)
+	}
+	if got := readIn("flow-a"); !reflect.DeepEqual(got, payload) {
+		t.Errorf("read after writing the payload = %v, want %v", got, payload)
+	}
+
+	// The same key in another flow is a different value
+	if got := readIn("flow-b"); got != "none" {
+		t.Errorf("flow-b read flow-a's value %v", got)
+	}
+
+	t.Run("key with a dot rejected", func(t *testing.T) {
+		if err := read.Validate(map[string]interface{}{"key": "other.cursor"}); err == nil {
+			t.Error("dotted key accepted, it could reach into another flow's namespace")
+		}
+	})
+}
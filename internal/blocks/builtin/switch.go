@@ -0,0 +1,324 @@
+package builtin
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// switchRule routes messages whose value satisfies the operator to an
+// output port
+type switchRule struct {
+	Operator string      `json:"operator"`         // eq, neq, gt, lt, gte, lte, contains, matches
+	Value    interface{} `json:"value"`            // Operand compared against the message value
+	Output   *int        `json:"output,omitempty"` // Output port, defaults to the rule's position
+
+	pattern *regexp.Regexp
+}
+
+// SwitchBlock routes each message to the outputs of the rules its payload
+// matches. There is one output per rule; a message matching no rule is
+// dropped
+type SwitchBlock struct{}
+
+func (b *SwitchBlock) GetType() string {
+	return "switch"
+}
+
+func (b *SwitchBlock) GetName() string {
+	return "Switch"
+}
+
+func (b *SwitchBlock) GetDescription() string {
+	return "Route messages to outputs by comparing the payload against rules"
+}
+
+func (b *SwitchBlock) GetCategory() string {
+	return "function"
+}
+
+func (b *SwitchBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *SwitchBlock) GetInputs() int {
+	return 1
+}
+
+func (b *SwitchBlock) GetOutputs() int {
+	return 1
+}
+
+// GetPortCounts has one output per rule
+func (b *SwitchBlock) GetPortCounts(properties map[string]interface{}) (inputs, outputs int) {
+	rules, err := parseSwitchRules(properties)
+	if err != nil || len(rules) == 0 {
+		return 1, 1
+	}
+	return 1, len(rules)
+}
+
+func (b *SwitchBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Switch",
+		},
+		{
+			Name:         "field",
+			Type:         "string",
+			DisplayName:  "Field",
+			Description:  "Payload path of the value to test (empty for the payload itself)",
+			Required:     false,
+			DefaultValue: "",
+		},
+		{
+			Name:         "rules",
+			Type:         "json",
+			DisplayName:  "Rules",
+			Description:  `Rules in order, each with its own output, e.g. [{"operator":"gt","value":30},{"operator":"matches","value":"^err"}]`,
+			Required:     true,
+			DefaultValue: []interface{}{},
+		},
+		{
+			Name:         "stopOnFirstMatch",
+			Type:         "boolean",
+			DisplayName:  "Stop On First Match",
+			Description:  "Send the message to the first matching rule only instead of to every matching rule",
+			Required:     false,
+			DefaultValue: false,
+		},
+	}
+}
+
+func (b *SwitchBlock) Validate(properties map[string]interface{}) error {
+	if field := stringProperty(properties, "field", ""); field != "" {
+		if err := models.ValidatePath(field); err != nil {
+			return err
+		}
+	}
+	rules, err := parseSwitchRules(properties)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return fmt.Errorf("at least one rule is required")
+	}
+	return nil
+}
+
+func (b *SwitchBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	rules, err := parseSwitchRules(properties)
+	if err != nil {
+		return nil, blocks.Invalid(err)
+	}
+
+	value := ctx.Message.Payload
+	if field := stringProperty(properties, "field", ""); field != "" {
+		resolved, found, err := models.ResolvePath(value, field)
+		if err != nil {
+			return nil, blocks.Invalid(err)
+		}
+		if !found {
+			return nil, blocks.Invalidf("field '%s' not found in payload", field)
+		}
+		value = resolved
+	}
+
+	stopOnFirstMatch := boolProperty(properties, "stopOnFirstMatch", false)
+	outputs := make([]*models.Message, 0, 1)
+	routed := make(map[int]bool)
+	for _, rule := range rules {
+		if !rule.match(value) {
+			continue
+		}
+		// Rules sharing an output send the message once
+		if !routed[*rule.Output] {
+			routed[*rule.Output] = true
+			outputMsg := ctx.Message.Clone()
+			outputMsg.Source = ctx.NodeID
+			outputMsg.Port = *rule.Output
+			outputs = append(outputs, outputMsg)
+		}
+		if stopOnFirstMatch {
+			break
+		}
+	}
+
+	if len(outputs) == 0 {
+		ctx.Logger.Debug("No rule matched", map[string]interface{}{
+			"rules": len(rules),
+		})
+	}
+	return outputs, nil
+}
+
+// parseSwitchRules decodes and validates the rules property, which may be a
+// JSON array or a string containing one
+func parseSwitchRules(properties map[string]interface{}) ([]switchRule, error) {
+	var data []byte
+	switch value := properties["rules"].(type) {
+	case nil:
+		return nil, fmt.Errorf("rules property is required")
+	case string:
+		data = []byte(value)
+	default:
+		var err error
+		if data, err = json.Marshal(value); err != nil {
+			return nil, fmt.Errorf("rules is not valid JSON: %w", err)
+		}
+	}
+
+	var rules []switchRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("rules must be an array of rules: %w", err)
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Output == nil {
+			rule.Output = &[]int{i}[0]
+		}
+		if *rule.Output < 0 || *rule.Output >= len(rules) {
+			return nil, fmt.Errorf("rule %d: output %d out of range 0-%d", i+1, *rule.Output, len(rules)-1)
+		}
+		switch rule.Operator {
+		case "eq", "neq", "gt", "lt", "gte", "lte", "contains":
+		case "matches":
+			expr, ok := rule.Value.(string)
+			if !ok {
+				return nil, fmt.Errorf("rule %d (matches): value must be a regular expression", i+1)
+			}
+			pattern, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d (matches): %w", i+1, err)
+			}
+			rule.pattern = pattern
+		default:
+			return nil, fmt.Errorf("rule %d: unknown operator '%s'", i+1, rule.Operator)
+		}
+	}
+	return rules, nil
+}
+
+// match reports whether value satisfies the rule. Values of a type the
+// operator can't handle never match
+func (r switchRule) match(value interface{}) bool {
+	switch r.Operator {
+	case "eq":
+		return switchEqual(value, r.Value)
+	case "neq":
+		return !switchEqual(value, r.Value)
+	case "gt", "lt", "gte", "lte":
+		cmp, ok := switchCompare(value, r.Value)
+		if !ok {
+			return false
+		}
+		switch r.Operator {
+		case "gt":
+			return cmp > 0
+		case "lt":
+			return cmp < 0
+		case "gte":
+			return cmp >= 0
+		default:
+			return cmp <= 0
+		}
+	case "contains":
+		switch v := value.(type) {
+		case string:
+			substring, ok := r.Value.(string)
+			return ok && strings.Contains(v, substring)
+		case []interface{}:
+			for _, item := range v {
+				if switchEqual(item, r.Value) {
+					return true
+				}
+			}
+			return false
+		case map[string]interface{}:
+			key, ok := r.Value.(string)
+			if !ok {
+				return false
+			}
+			_, found := v[key]
+			return found
+		}
+		return false
+	case "matches":
+		text, err := models.ToString(value)
+		return err == nil && r.pattern.MatchString(text)
+	}
+	return false
+}
+
+// switchEqual compares values, treating numbers of any representation as
+// equal when their values are
+func switchEqual(a, b interface{}) bool {
+	if x, err := models.ToNumber(a); err == nil {
+		y, err := models.ToNumber(b)
+		return err == nil && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// switchCompare orders two numbers or two strings. ok is false for any
+// other combination
+func switchCompare(a, b interface{}) (cmp int, ok bool) {
+	if x, err := models.ToNumber(a); err == nil {
+		y, err := models.ToNumber(b)
+		if err != nil {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	}
+	x, errX := models.ToString(a)
+	y, errY := models.ToString(b)
+	if errX != nil || errY != nil {
+		return 0, false
+	}
+	return strings.Compare(x, y), true
+}
+
+// SwitchBlockFactory creates switch block instances
+type SwitchBlockFactory struct{}
+
+func (f *SwitchBlockFactory) CreateBlock() blocks.Block {
+	return &SwitchBlock{}
+}
+
+func (f *SwitchBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &SwitchBlock{}
+	return blocks.BlockInfo{
+		Type:        "switch",
+		Name:        "Switch",
+		Description: "Route messages to outputs by comparing the payload against rules",
+		Category:    "function",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "git-branch",
+		Color:       "#9C27B0",
+	}
+}
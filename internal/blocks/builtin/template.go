@@ -0,0 +1,199 @@
+package builtin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"text/template"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// templateData is what a template is rendered against
+type templateData struct {
+	Payload interface{}
+	Topic   string
+	Headers map[string]string
+}
+
+// templateFuncs are the functions available to templates besides the
+// text/template built-ins
+var templateFuncs = template.FuncMap{
+	// json encodes a value, e.g. to embed a payload field in a JSON body
+	"json": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+}
+
+// TemplateBlock renders a text/template against each message and emits the
+// result as the new payload, either as text or parsed as JSON
+type TemplateBlock struct {
+	mu       sync.Mutex
+	source   string
+	compiled *template.Template
+}
+
+func (b *TemplateBlock) GetType() string {
+	return "template"
+}
+
+func (b *TemplateBlock) GetName() string {
+	return "Template"
+}
+
+func (b *TemplateBlock) GetDescription() string {
+	return "Render a template against the message to build the payload"
+}
+
+func (b *TemplateBlock) GetCategory() string {
+	return "function"
+}
+
+func (b *TemplateBlock) GetBlockGroup() blocks.BlockGroup {
+	return blocks.PropagationGroup
+}
+
+func (b *TemplateBlock) GetInputs() int {
+	return 1
+}
+
+func (b *TemplateBlock) GetOutputs() int {
+	return 1
+}
+
+func (b *TemplateBlock) GetProperties() []blocks.PropertyDefinition {
+	return []blocks.PropertyDefinition{
+		{
+			Name:         "name",
+			Type:         "string",
+			DisplayName:  "Name",
+			Description:  "Block name for identification",
+			Required:     false,
+			DefaultValue: "Template",
+		},
+		{
+			Name:         "template",
+			Type:         "string",
+			DisplayName:  "Template",
+			Description:  `Go text/template over .Payload, .Topic and .Headers, e.g. {"id": {{json .Payload.id}}, "topic": "{{.Topic}}"}`,
+			Required:     true,
+			DefaultValue: "",
+		},
+		{
+			Name:         "output",
+			Type:         "select",
+			DisplayName:  "Output",
+			Description:  "Emit the rendered text, or parse it as JSON",
+			Required:     false,
+			DefaultValue: "text",
+			Options: []blocks.Option{
+				{Label: "Text", Value: "text"},
+				{Label: "Parsed JSON", Value: "json"},
+			},
+		},
+	}
+}
+
+func (b *TemplateBlock) Validate(properties map[string]interface{}) error {
+	output := stringProperty(properties, "output", "text")
+	if output != "text" && output != "json" {
+		return fmt.Errorf("invalid output '%s'", output)
+	}
+	_, err := parseMessageTemplate(properties)
+	return err
+}
+
+func (b *TemplateBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	if ctx.Message == nil {
+		return nil, blocks.Invalidf("no input message")
+	}
+
+	tmpl, err := b.parsed(properties)
+	if err != nil {
+		return nil, blocks.Invalid(err)
+	}
+
+	var rendered bytes.Buffer
+	data := templateData{
+		Payload: ctx.Message.Payload,
+		Topic:   ctx.Message.Topic,
+		Headers: ctx.Message.Headers,
+	}
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return nil, blocks.Invalidf("render template: %w", err)
+	}
+
+	outputMsg := ctx.Message.Clone()
+	outputMsg.Source = ctx.NodeID
+	if stringProperty(properties, "output", "text") == "json" {
+		var payload interface{}
+		if err := models.DecodeJSON(rendered.Bytes(), &payload); err != nil {
+			return nil, blocks.Invalidf("rendered template is not valid JSON: %w", err)
+		}
+		outputMsg.Payload = payload
+		outputMsg.ContentType = ""
+	} else {
+		outputMsg.Payload = rendered.String()
+		outputMsg.ContentType = models.ContentTypeText
+	}
+	return []*models.Message{outputMsg}, nil
+}
+
+// parsed returns the template for the current source, parsing it only when
+// the source changes
+func (b *TemplateBlock) parsed(properties map[string]interface{}) (*template.Template, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	source := stringProperty(properties, "template", "")
+	if b.compiled != nil && b.source == source {
+		return b.compiled, nil
+	}
+	tmpl, err := parseMessageTemplate(properties)
+	if err != nil {
+		return nil, err
+	}
+	b.source = source
+	b.compiled = tmpl
+	return tmpl, nil
+}
+
+// parseMessageTemplate parses the template property
+func parseMessageTemplate(properties map[string]interface{}) (*template.Template, error) {
+	source := stringProperty(properties, "template", "")
+	if source == "" {
+		return nil, fmt.Errorf("template property is required")
+	}
+	tmpl, err := template.New("template").Funcs(templateFuncs).Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// TemplateBlockFactory creates template block instances
+type TemplateBlockFactory struct{}
+
+func (f *TemplateBlockFactory) CreateBlock() blocks.Block {
+	return &TemplateBlock{}
+}
+
+func (f *TemplateBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	block := &TemplateBlock{}
+	return blocks.BlockInfo{
+		Type:        "template",
+		Name:        "Template",
+		Description: "Render a template against the message to build the payload",
+		Category:    "function",
+		BlockGroup:  blocks.PropagationGroup,
+		Inputs:      block.GetInputs(),
+		Outputs:     block.GetOutputs(),
+		Version:     "1.0.0",
+		Author:      "Block-Flow",
+		Icon:        "file-text",
+		Color:       "#9C27B0",
+	}
+}
@@ -0,0 +1,76 @@
+package blocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"block-flow/internal/models"
+)
+
+// ErrorCategory classifies block execution failures so the executor can
+// decide whether to retry, dead-letter, or stop the flow
+type ErrorCategory = models.ErrorCategory
+
+const (
+	// ErrorTransient - temporary system failure (timeouts, unavailable services); safe to retry
+	ErrorTransient ErrorCategory = "transient"
+	// ErrorInvalid - permanent failure caused by the message or configuration; dead-lettered
+	ErrorInvalid ErrorCategory = "invalid"
+	// ErrorFatal - unrecoverable failure; the flow is stopped
+	ErrorFatal ErrorCategory = "fatal"
+	// ErrorUnknown - unclassified failure; handled like invalid ones, as
+	// before blocks classified their errors
+	ErrorUnknown ErrorCategory = "unknown"
+)
+
+// Transient marks an error as a retryable system failure
+func Transient(err error) error {
+	return categorize(ErrorTransient, err)
+}
+
+// Invalid marks an error as a permanent user failure (bad payload or configuration)
+func Invalid(err error) error {
+	return categorize(ErrorInvalid, err)
+}
+
+// Fatal marks an error as unrecoverable for the whole flow
+func Fatal(err error) error {
+	return categorize(ErrorFatal, err)
+}
+
+// Invalidf formats an error and marks it as invalid
+func Invalidf(format string, args ...interface{}) error {
+	return Invalid(fmt.Errorf(format, args...))
+}
+
+// categorize wraps err in a BlockError carrying only the category, so the
+// error reads as err
+func categorize(category ErrorCategory, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &BlockError{Cause: err, Category: category}
+}
+
+// CategoryOf returns the category of an error: the first category set on a
+// BlockError or ExecutionError in its chain. Unclassified context deadline
+// errors are treated as transient, anything else as unknown
+func CategoryOf(err error) ErrorCategory {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch categorized := e.(type) {
+		case *BlockError:
+			if categorized.Category != "" {
+				return categorized.Category
+			}
+		case *models.ExecutionError:
+			if categorized.Category != "" {
+				return categorized.Category
+			}
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorTransient
+	}
+	return ErrorUnknown
+}
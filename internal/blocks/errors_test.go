@@ -0,0 +1,59 @@
+package blocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"block-flow/internal/models"
+)
+
+func TestCategoryOf(t *testing.T) {
+	base := errors.New("boom")
+
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCategory
+	}{
+		{"transient", Transient(base), ErrorTransient},
+		{"invalid", Invalid(base), ErrorInvalid},
+		{"invalidf", Invalidf("bad %s", "payload"), ErrorInvalid},
+		{"fatal", Fatal(base), ErrorFatal},
+		{"wrapped classification", fmt.Errorf("node: %w", Fatal(base)), ErrorFatal},
+		{"block error category", &BlockError{Message: "failed", BlockType: "test", Category: ErrorTransient}, ErrorTransient},
+		{"block error without category", NewBlockError("failed", "test", base), ErrorUnknown},
+		{"block error around classified cause", NewBlockError("failed", "test", Invalid(base)), ErrorInvalid},
+		{"execution error category", &models.ExecutionError{NodeID: "n", Message: "failed", Category: ErrorFatal}, ErrorFatal},
+		{"execution error without category", models.NewExecutionError("n", "failed", base), ErrorUnknown},
+		{"outermost category wins", Fatal(Transient(base)), ErrorFatal},
+		{"deadline exceeded", fmt.Errorf("call: %w", context.DeadlineExceeded), ErrorTransient},
+		{"classified deadline", Invalid(context.DeadlineExceeded), ErrorInvalid},
+		{"unclassified", base, ErrorUnknown},
+		{"nil", nil, ErrorUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CategoryOf(tt.err); got != tt.want {
+				t.Errorf("CategoryOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassificationKeepsMessage(t *testing.T) {
+	base := errors.New("division by zero")
+	for _, err := range []error{Transient(base), Invalid(base), Fatal(base)} {
+		if err.Error() != base.Error() {
+			t.Errorf("Error() = %q, want %q", err.Error(), base.Error())
+		}
+		if !errors.Is(err, base) {
+			t.Errorf("%q does not wrap its cause", err)
+		}
+	}
+	if Transient(nil) != nil {
+		t.Error("classifying nil returned an error")
+	}
+}
@@ -2,6 +2,8 @@ package blocks
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"block-flow/internal/models"
 )
@@ -51,6 +53,122 @@ type Block interface {
 	Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error)
 }
 
+// StreamingBlock is implemented by input blocks that produce messages on
+// their own schedule (subscriptions, servers, long polls) instead of on the
+// executor's interval ticker. Run blocks until ctx.Context is cancelled and
+// passes each produced message to emit
+type StreamingBlock interface {
+	Block
+
+	// Run produces messages until the flow stops
+	Run(ctx *models.BlockExecutionContext, properties map[string]interface{}, emit func(*models.Message)) error
+}
+
+// LifecycleEvent names the flow transition a LifecycleBlock fires on
+type LifecycleEvent string
+
+const (
+	LifecycleStart LifecycleEvent = "start" // Once, when the flow starts
+	LifecycleStop  LifecycleEvent = "stop"  // Once, before the flow's nodes stop
+)
+
+// LifecycleBlock is implemented by input blocks that fire on a flow
+// transition instead of on the executor's interval ticker or a manual trigger.
+// Stop blocks fire while the flow is still running, so their messages are
+// processed downstream before the nodes stop
+type LifecycleBlock interface {
+	Block
+
+	// LifecycleEvent returns the transition the block fires on
+	LifecycleEvent() LifecycleEvent
+}
+
+// CatchBlock is implemented by input blocks that fire when another node of
+// their flow fails to process a message, instead of on the executor's interval
+// ticker or a manual trigger. Execute receives the failure as its input
+// message, with the failing node, the error and the message being processed
+type CatchBlock interface {
+	Block
+
+	// Catches reports whether the block handles errors of the node
+	Catches(nodeID string, properties map[string]interface{}) bool
+}
+
+// TickingBlock is implemented by propagation blocks that need to emit
+// messages without new input, e.g. to flush timed-out state. The executor
+// calls Tick every TickInterval from the node's goroutine, so Tick never runs
+// concurrently with Execute
+type TickingBlock interface {
+	Block
+
+	// TickInterval returns how often Tick is called (0 disables ticking)
+	TickInterval(properties map[string]interface{}) time.Duration
+
+	// Tick is called periodically and returns messages to emit
+	Tick(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error)
+}
+
+// DelayingBlock is implemented by propagation blocks that hold each message
+// before processing it. The executor asks for a release time when a message
+// arrives and calls Execute once it is due. Release times must not decrease,
+// so messages are processed in arrival order
+type DelayingBlock interface {
+	Block
+
+	// ReleaseAt returns when a message arriving at now is processed
+	ReleaseAt(now time.Time, properties map[string]interface{}) time.Time
+}
+
+// DynamicPortBlock is implemented by blocks whose port counts depend on their
+// configuration (e.g. one output per switch rule). For these blocks a node's
+// declared Inputs/Outputs override the computed defaults
+type DynamicPortBlock interface {
+	Block
+
+	// GetPortCounts returns the number of input and output ports for the given properties
+	GetPortCounts(properties map[string]interface{}) (inputs, outputs int)
+}
+
+// FlowReferenceBlock is implemented by blocks with properties holding the ID
+// of another flow, so the references can be rewritten when flows are imported
+// under new IDs
+type FlowReferenceBlock interface {
+	Block
+
+	// FlowReferenceProperties returns the names of properties holding flow IDs
+	FlowReferenceProperties() []string
+}
+
+// NodeReferenceBlock is implemented by blocks with properties holding
+// comma-separated IDs of other nodes of their flow, so the references can be
+// rewritten when the flow's nodes get new IDs
+type NodeReferenceBlock interface {
+	Block
+
+	// NodeReferenceProperties returns the names of properties holding node IDs
+	NodeReferenceProperties() []string
+}
+
+// PortCounts returns the effective number of input and output ports of a
+// block for a node. Static blocks always use their fixed counts; dynamic-port
+// blocks use the node's declared counts when set, otherwise the counts
+// computed from the node's properties
+func PortCounts(block Block, declaredInputs, declaredOutputs int, properties map[string]interface{}) (inputs, outputs int) {
+	dynamic, ok := block.(DynamicPortBlock)
+	if !ok {
+		return block.GetInputs(), block.GetOutputs()
+	}
+
+	inputs, outputs = dynamic.GetPortCounts(properties)
+	if declaredInputs > 0 {
+		inputs = declaredInputs
+	}
+	if declaredOutputs > 0 {
+		outputs = declaredOutputs
+	}
+	return inputs, outputs
+}
+
 // PropertyDefinition defines a configurable property of a block
 type PropertyDefinition struct {
 	Name         string      `json:"name"`
@@ -63,13 +181,34 @@ type PropertyDefinition struct {
 	Validation   Validation  `json:"validation,omitempty"`
 }
 
+// EffectiveProperties merges a node's properties with deployment overrides and
+// block defaults. Precedence is: node property > deployment override > block
+// default. The node's map is not modified
+func EffectiveProperties(defs []PropertyDefinition, overrides, properties map[string]interface{}) map[string]interface{} {
+	effective := make(map[string]interface{}, len(defs)+len(properties))
+	for _, def := range defs {
+		if def.DefaultValue != nil {
+			effective[def.Name] = def.DefaultValue
+		}
+	}
+	for name, value := range overrides {
+		effective[name] = value
+	}
+	for name, value := range properties {
+		effective[name] = value
+	}
+	return effective
+}
+
 // Option represents a select option
 type Option struct {
 	Label string      `json:"label"`
 	Value interface{} `json:"value"`
 }
 
-// Validation defines validation rules for properties
+// Validation defines validation rules for properties, enforced by
+// ValidateProperties. Min and Max bound numbers; Regex and Length (the
+// maximum number of characters) apply to strings
 type Validation struct {
 	Min    *float64 `json:"min,omitempty"`
 	Max    *float64 `json:"max,omitempty"`
@@ -85,22 +224,25 @@ type BlockFactory interface {
 
 // BlockInfo provides metadata about a block type
 type BlockInfo struct {
-	Type        string     `json:"type"`
-	Name        string     `json:"name"`
-	Description string     `json:"description"`
-	Category    string     `json:"category"`
-	BlockGroup  BlockGroup `json:"block_group"`
-	Inputs      int        `json:"inputs"`
-	Outputs     int        `json:"outputs"`
-	Version     string     `json:"version"`
-	Author      string     `json:"author,omitempty"`
-	Icon        string     `json:"icon,omitempty"`
-	Color       string     `json:"color,omitempty"`
-}
-
-// Registry manages available blocks
+	Type         string     `json:"type"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Category     string     `json:"category"`
+	BlockGroup   BlockGroup `json:"block_group"`
+	Inputs       int        `json:"inputs"`
+	Outputs      int        `json:"outputs"`
+	DynamicPorts bool       `json:"dynamic_ports,omitempty"` // Port counts depend on node configuration
+	Version      string     `json:"version"`
+	Author       string     `json:"author,omitempty"`
+	Icon         string     `json:"icon,omitempty"`
+	Color        string     `json:"color,omitempty"`
+}
+
+// Registry manages available blocks. It is safe for concurrent use, so
+// plugins can be reloaded while flows create blocks
 type Registry struct {
 	blocks map[string]BlockFactory
+	mu     sync.RWMutex
 }
 
 // NewRegistry creates a new block registry
@@ -110,15 +252,57 @@ func NewRegistry() *Registry {
 	}
 }
 
-// Register registers a block factory
-func (r *Registry) Register(factory BlockFactory) {
+// Register registers a block factory. It fails if the block type is
+// already registered; use Replace to override a type intentionally
+func (r *Registry) Register(factory BlockFactory) error {
+	info := factory.GetBlockInfo()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.blocks[info.Type]; exists {
+		return NewBlockError("block type already registered", info.Type, nil)
+	}
+	r.blocks[info.Type] = factory
+	return nil
+}
+
+// MustRegister registers a block factory, panicking on a duplicate type
+func (r *Registry) MustRegister(factory BlockFactory) {
+	if err := r.Register(factory); err != nil {
+		panic(err)
+	}
+}
+
+// Replace registers a block factory, overriding any factory already
+// registered for the same type. It reports whether a factory was replaced
+func (r *Registry) Replace(factory BlockFactory) bool {
 	info := factory.GetBlockInfo()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, exists := r.blocks[info.Type]
 	r.blocks[info.Type] = factory
+	return exists
+}
+
+// Unregister removes a block type, reporting whether it was registered.
+// Blocks already created from its factory keep working
+func (r *Registry) Unregister(blockType string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, exists := r.blocks[blockType]
+	delete(r.blocks, blockType)
+	return exists
 }
 
 // CreateBlock creates a new block instance by type
 func (r *Registry) CreateBlock(blockType string) (Block, error) {
+	r.mu.RLock()
 	factory, exists := r.blocks[blockType]
+	r.mu.RUnlock()
 	if !exists {
 		return nil, NewBlockError("unknown block type", blockType, nil)
 	}
@@ -127,6 +311,9 @@ func (r *Registry) CreateBlock(blockType string) (Block, error) {
 
 // GetBlockTypes returns all registered block types
 func (r *Registry) GetBlockTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	types := make([]string, 0, len(r.blocks))
 	for blockType := range r.blocks {
 		types = append(types, blockType)
@@ -136,30 +323,50 @@ func (r *Registry) GetBlockTypes() []string {
 
 // GetBlockInfo returns information about all registered blocks
 func (r *Registry) GetBlockInfo() []BlockInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	info := make([]BlockInfo, 0, len(r.blocks))
 	for _, factory := range r.blocks {
-		info = append(info, factory.GetBlockInfo())
+		info = append(info, describe(factory))
 	}
 	return info
 }
 
 // GetBlockInfoByType returns information about a specific block type
 func (r *Registry) GetBlockInfoByType(blockType string) (BlockInfo, error) {
+	r.mu.RLock()
 	factory, exists := r.blocks[blockType]
+	r.mu.RUnlock()
 	if !exists {
 		return BlockInfo{}, NewBlockError("unknown block type", blockType, nil)
 	}
-	return factory.GetBlockInfo(), nil
+	return describe(factory), nil
+}
+
+// describe returns a factory's block info with registry-derived fields filled in
+func describe(factory BlockFactory) BlockInfo {
+	info := factory.GetBlockInfo()
+	if _, ok := factory.CreateBlock().(DynamicPortBlock); ok {
+		info.DynamicPorts = true
+	}
+	return info
 }
 
-// BlockError represents a block-related error
+// BlockError represents a block-related error. Category classifies
+// execution failures; a BlockError with only a Cause and a Category, as made
+// by Transient, Invalid and Fatal, reads as its cause
 type BlockError struct {
 	Message   string
 	BlockType string
 	Cause     error
+	Category  ErrorCategory
 }
 
 func (e *BlockError) Error() string {
+	if e.Message == "" && e.BlockType == "" && e.Cause != nil {
+		return e.Cause.Error()
+	}
 	if e.Cause != nil {
 		return fmt.Sprintf("block error [%s]: %s: %v", e.BlockType, e.Message, e.Cause)
 	}
@@ -0,0 +1,73 @@
+package blocks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"block-flow/internal/models"
+)
+
+// ValidateProperties checks properties against their definitions: required
+// properties must be set, number properties must be numbers within Min and
+// Max, and string properties must match Regex and fit in Length characters.
+// Unset optional properties are not checked, their defaults apply. Every
+// failing property is reported
+func ValidateProperties(defs []PropertyDefinition, properties map[string]interface{}) error {
+	var problems []string
+	for _, def := range defs {
+		if err := validateProperty(def, properties[def.Name]); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// validateProperty checks one property value against its definition
+func validateProperty(def PropertyDefinition, value interface{}) error {
+	if value == nil || value == "" {
+		if def.Required {
+			return fmt.Errorf("%s property is required", def.Name)
+		}
+		return nil
+	}
+
+	rules := def.Validation
+	if def.Type == "number" || rules.Min != nil || rules.Max != nil {
+		number, err := models.ToNumber(value)
+		if err != nil {
+			if def.Type == "number" {
+				return fmt.Errorf("%s must be a number", def.Name)
+			}
+		} else {
+			if rules.Min != nil && number < *rules.Min {
+				return fmt.Errorf("%s must be at least %v", def.Name, *rules.Min)
+			}
+			if rules.Max != nil && number > *rules.Max {
+				return fmt.Errorf("%s must be at most %v", def.Name, *rules.Max)
+			}
+		}
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	if rules.Length != nil && utf8.RuneCountInString(text) > *rules.Length {
+		return fmt.Errorf("%s must be at most %d characters", def.Name, *rules.Length)
+	}
+	if rules.Regex != "" {
+		pattern, err := regexp.Compile(rules.Regex)
+		if err != nil {
+			return fmt.Errorf("%s has an invalid validation pattern: %w", def.Name, err)
+		}
+		if !pattern.MatchString(text) {
+			return fmt.Errorf("%s must match %s", def.Name, rules.Regex)
+		}
+	}
+	return nil
+}
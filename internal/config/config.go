@@ -1,8 +1,12 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -20,12 +24,31 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+
+	// Applied separately to draining flows and to HTTP server shutdown
+	ShutdownTimeout time.Duration
+
+	// Answer API requests with 503 until startup flows have been loaded
+	WaitForReady bool
 }
 
 // StorageConfig holds storage configuration
 type StorageConfig struct {
+	// Where flows, executions and settings are kept: "file" (under
+	// DataDir) or "memory" (lost on restart)
+	Backend string
+
 	DataDir    string
 	PluginsDir string
+
+	// Failed storage operations are retried with a linear backoff
+	RetryAttempts int
+	RetryBackoff  time.Duration
+
+	// Deleted flows stay in the trash this long (0 = until restored); the
+	// janitor checks for expired entries every JanitorInterval
+	TrashRetention  time.Duration
+	JanitorInterval time.Duration
 }
 
 // EngineConfig holds flow engine configuration
@@ -33,35 +56,142 @@ type EngineConfig struct {
 	MaxConcurrentFlows int
 	DefaultTimeout     time.Duration
 	DebugMode          bool
+	MaxPayloadSize     int // Max JSON-serialized payload size in bytes (0 = unlimited)
+	TransientRetries   int // Retries for block errors classified as transient
+
+	// Runtime debug recording of all messages in a flow
+	DebugRecordDuration    time.Duration // Recording auto-disables after this duration
+	DebugRecordMaxMessages int           // Recording auto-disables after capturing this many messages
+	DebugRecordMaxBytes    int           // Recording auto-disables after capturing this many JSON-serialized bytes (0 = unlimited)
+
+	// Deployment-wide block property overrides: block type → property → value.
+	// Precedence is node property > override > block default
+	BlockPropertyOverrides map[string]map[string]interface{}
+
+	// Per-flow resource caps (0 = unlimited), overridable by flow properties
+	MaxFlowGoroutines   int // Flows needing more goroutines refuse to start
+	MaxInFlightMessages int // Inputs pause while more messages are queued or processing
+	MaxInFlightBytes    int // Inputs pause while in-flight payloads exceed this size
+
+	// Global cap on concurrent executions per block type across all flows
+	BlockConcurrency map[string]int
+
+	// What happens when a node's input buffer is full, as "group=strategy"
+	// pairs (drop, block, block_with_timeout or dead-letter); flows'
+	// backpressure property overrides
+	Backpressure string
+
+	// How long block_with_timeout waits for room before dropping a message;
+	// flows' backpressure_timeout property overrides
+	BackpressureTimeout time.Duration
+
+	// Largest flow accepted when saving or starting (0 = unlimited)
+	MaxFlowNodes       int
+	MaxFlowConnections int
+
+	// Accept flows whose connections form a cycle (feedback loops), only
+	// logging a warning when they start
+	AllowCycles bool
+
+	// Decode JSON numbers in payloads and flows as json.Number so integers
+	// keep full precision instead of becoming float64
+	PreserveJSONNumbers bool
+
+	// Max time a flow's on-stop hooks get to finish before its nodes stop
+	StopHookTimeout time.Duration
+
+	// How often a running flow's execution record (node states) is saved;
+	// it is always saved when the flow stops. 0 saves only on stop
+	ExecutionSaveInterval time.Duration
+
+	// Per-flow log files ({FlowLogDir}/{flowID}.log), overridable by the
+	// flow's log_file property, rotated at FlowLogMaxSize bytes
+	FlowLogFiles    bool
+	FlowLogDir      string
+	FlowLogMaxSize  int
+	FlowLogMaxFiles int // Rotated files kept per flow
 }
 
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
 	Level  string
 	Format string
+
+	// Field-name glob patterns whose values are masked in logs (empty = defaults)
+	RedactFields []string
 }
 
 // Load loads configuration from environment variables with defaults
 func Load() (*Config, error) {
+	backend := getEnv("STORAGE_BACKEND", "file")
+	if backend != "file" && backend != "memory" {
+		return nil, fmt.Errorf("STORAGE_BACKEND must be 'file' or 'memory', got '%s'", backend)
+	}
+	overrides, err := getOverridesEnv("BLOCK_PROPERTY_OVERRIDES")
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		Server: ServerConfig{
 			Address:      getEnv("SERVER_ADDRESS", ":8080"),
 			ReadTimeout:  getDurationEnv("SERVER_READ_TIMEOUT", 15*time.Second),
 			WriteTimeout: getDurationEnv("SERVER_WRITE_TIMEOUT", 15*time.Second),
 			IdleTimeout:  getDurationEnv("SERVER_IDLE_TIMEOUT", 60*time.Second),
+
+			ShutdownTimeout: getDurationEnv("SHUTDOWN_TIMEOUT", 30*time.Second),
+			WaitForReady:    getBoolEnv("SERVER_WAIT_FOR_READY", false),
 		},
 		Storage: StorageConfig{
+			Backend:    backend,
 			DataDir:    getEnv("DATA_DIR", "./data"),
 			PluginsDir: getEnv("PLUGINS_DIR", "./data/plugins"),
+
+			RetryAttempts: getIntEnv("STORAGE_RETRY_ATTEMPTS", 3),
+			RetryBackoff:  getDurationEnv("STORAGE_RETRY_BACKOFF", 100*time.Millisecond),
+
+			TrashRetention:  getDurationEnv("TRASH_RETENTION", 30*24*time.Hour),
+			JanitorInterval: getDurationEnv("JANITOR_INTERVAL", time.Hour),
 		},
 		Engine: EngineConfig{
 			MaxConcurrentFlows: getIntEnv("MAX_CONCURRENT_FLOWS", 10),
 			DefaultTimeout:     getDurationEnv("DEFAULT_TIMEOUT", 30*time.Second),
 			DebugMode:          getBoolEnv("DEBUG_MODE", true),
+			MaxPayloadSize:     getIntEnv("MAX_PAYLOAD_SIZE", 0),
+			TransientRetries:   getIntEnv("TRANSIENT_RETRIES", 2),
+
+			DebugRecordDuration:    getDurationEnv("DEBUG_RECORD_DURATION", 5*time.Minute),
+			DebugRecordMaxMessages: getIntEnv("DEBUG_RECORD_MAX_MESSAGES", 1000),
+			DebugRecordMaxBytes:    getIntEnv("DEBUG_RECORD_MAX_BYTES", 1<<20),
+			BlockPropertyOverrides: overrides,
+			BlockConcurrency:       getLimitsEnv("BLOCK_CONCURRENCY"),
+			Backpressure:           getEnv("BACKPRESSURE", ""),
+			BackpressureTimeout:    getDurationEnv("BACKPRESSURE_TIMEOUT", 5*time.Second),
+
+			MaxFlowGoroutines:   getIntEnv("MAX_FLOW_GOROUTINES", 0),
+			MaxInFlightMessages: getIntEnv("MAX_INFLIGHT_MESSAGES", 0),
+			MaxInFlightBytes:    getIntEnv("MAX_INFLIGHT_BYTES", 0),
+
+			MaxFlowNodes:       getIntEnv("MAX_FLOW_NODES", 1000),
+			MaxFlowConnections: getIntEnv("MAX_FLOW_CONNECTIONS", 5000),
+			AllowCycles:        getBoolEnv("ALLOW_CYCLES", false),
+
+			PreserveJSONNumbers: getBoolEnv("JSON_PRESERVE_NUMBERS", false),
+
+			StopHookTimeout: getDurationEnv("STOP_HOOK_TIMEOUT", 5*time.Second),
+
+			ExecutionSaveInterval: getDurationEnv("EXECUTION_SAVE_INTERVAL", 30*time.Second),
+
+			FlowLogFiles:    getBoolEnv("FLOW_LOG_FILES", false),
+			FlowLogDir:      getEnv("FLOW_LOG_DIR", filepath.Join(getEnv("DATA_DIR", "./data"), "logs")),
+			FlowLogMaxSize:  getIntEnv("FLOW_LOG_MAX_SIZE", 10<<20),
+			FlowLogMaxFiles: getIntEnv("FLOW_LOG_MAX_FILES", 5),
 		},
 		Logging: LoggingConfig{
 			Level:  getEnv("LOG_LEVEL", "info"),
 			Format: getEnv("LOG_FORMAT", "json"),
+
+			RedactFields: getListEnv("LOG_REDACT_FIELDS"),
 		},
 	}, nil
 }
@@ -101,3 +231,97 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+// getListEnv parses a comma-separated list, skipping empty entries
+func getListEnv(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getOverridesEnv parses block property overrides, either as a JSON object
+// mapping block types to property values, e.g. {"debug":{"console":false}},
+// or as "type.property=value" pairs separated by commas. Pair values are
+// decoded as JSON when possible and kept as strings otherwise; commas inside
+// JSON strings, arrays and objects don't separate pairs
+func getOverridesEnv(key string) (map[string]map[string]interface{}, error) {
+	raw := strings.TrimSpace(os.Getenv(key))
+	overrides := make(map[string]map[string]interface{})
+	if strings.HasPrefix(raw, "{") {
+		if err := json.Unmarshal([]byte(raw), &overrides); err != nil {
+			return nil, fmt.Errorf("%s must be a JSON object of block types to property values: %w", key, err)
+		}
+		return overrides, nil
+	}
+
+	for _, pair := range splitTopLevel(raw) {
+		name, raw, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		blockType, property, ok := strings.Cut(name, ".")
+		if !ok || blockType == "" || property == "" {
+			continue
+		}
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+
+		if overrides[blockType] == nil {
+			overrides[blockType] = make(map[string]interface{})
+		}
+		overrides[blockType][property] = value
+	}
+	return overrides, nil
+}
+
+// splitTopLevel splits a list on the commas outside JSON strings, arrays and
+// objects
+func splitTopLevel(value string) []string {
+	parts := make([]string, 0)
+	depth, start := 0, 0
+	inString, escaped := false, false
+	for i, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && r == '\\':
+			escaped = true
+		case r == '"':
+			inString = !inString
+		case inString:
+		case r == '[' || r == '{':
+			depth++
+		case r == ']' || r == '}':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// getLimitsEnv parses "type=limit" pairs separated by commas. Pairs with a
+// non-positive or malformed limit are ignored
+func getLimitsEnv(key string) map[string]int {
+	limits := make(map[string]int)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		name, raw, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || name == "" {
+			continue
+		}
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			continue
+		}
+		limits[name] = limit
+	}
+	return limits
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetOverridesEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			value: "",
+			want:  map[string]map[string]interface{}{},
+		},
+		{
+			name:  "scalar pairs",
+			value: "debug.console=false, http-request.timeout=5000,http-request.method=POST",
+			want: map[string]map[string]interface{}{
+				"debug":        {"console": false},
+				"http-request": {"timeout": 5000.0, "method": "POST"},
+			},
+		},
+		{
+			name:  "pair values with commas",
+			value: `http-request.headers={"Accept":"a,b"},switch.cases=[1,2],template.text="x,y",debug.console=true`,
+			want: map[string]map[string]interface{}{
+				"http-request": {"headers": map[string]interface{}{"Accept": "a,b"}},
+				"switch":       {"cases": []interface{}{1.0, 2.0}},
+				"template":     {"text": "x,y"},
+				"debug":        {"console": true},
+			},
+		},
+		{
+			name:  "malformed pairs are ignored",
+			value: "debug,console=true,.timeout=1,debug.console=true",
+			want: map[string]map[string]interface{}{
+				"debug": {"console": true},
+			},
+		},
+		{
+			name:  "JSON object",
+			value: ` {"http-request":{"headers":{"Accept":"a,b"},"timeout":5000}}`,
+			want: map[string]map[string]interface{}{
+				"http-request": {"headers": map[string]interface{}{"Accept": "a,b"}, "timeout": 5000.0},
+			},
+		},
+		{
+			name:    "invalid JSON object",
+			value:   `{"debug":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BLOCK_PROPERTY_OVERRIDES", tt.value)
+			got, err := getOverridesEnv("BLOCK_PROPERTY_OVERRIDES")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("overrides = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
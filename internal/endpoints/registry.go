@@ -0,0 +1,125 @@
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Response is the reply a flow sends to a waiting request
+type Response struct {
+	Status  int
+	Headers map[string]string
+	Body    []byte
+}
+
+// Registry serves HTTP endpoints on behalf of running flows. Input nodes
+// register a handler for a method and path while their flow runs, and
+// requests waiting for the flow's reply are matched to it by ID
+type Registry struct {
+	routes  map[string]http.Handler // Keyed by "METHOD /path"
+	pending map[string]chan Response
+	mu      sync.RWMutex
+}
+
+// NewRegistry creates an empty endpoint registry
+func NewRegistry() *Registry {
+	return &Registry{
+		routes:  make(map[string]http.Handler),
+		pending: make(map[string]chan Response),
+	}
+}
+
+// NormalizePath cleans an endpoint path and makes it absolute
+func NormalizePath(p string) string {
+	return path.Clean("/" + strings.TrimSpace(p))
+}
+
+func routeKey(method, p string) string {
+	return strings.ToUpper(method) + " " + NormalizePath(p)
+}
+
+// Register serves method and path with handler until the returned function
+// is called. Each method and path can be served by one handler only
+func (r *Registry) Register(method, p string, handler http.Handler) (func(), error) {
+	key := routeKey(method, p)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.routes[key]; exists {
+		return nil, fmt.Errorf("endpoint %s is already registered", key)
+	}
+	r.routes[key] = handler
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			delete(r.routes, key)
+		})
+	}, nil
+}
+
+// Routes lists the registered endpoints as "METHOD /path", sorted
+func (r *Registry) Routes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	routes := make([]string, 0, len(r.routes))
+	for key := range r.routes {
+		routes = append(routes, key)
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// ServeHTTP dispatches a request to the handler registered for its method
+// and path
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.RLock()
+	handler, ok := r.routes[routeKey(req.Method, req.URL.Path)]
+	r.mu.RUnlock()
+
+	if !ok {
+		http.Error(w, "No flow serves this endpoint", http.StatusNotFound)
+		return
+	}
+	handler.ServeHTTP(w, req)
+}
+
+// Await registers a request waiting for a reply under id. The reply arrives
+// on the returned channel; cancel must be called once the caller stops
+// waiting
+func (r *Registry) Await(id string) (replies <-chan Response, cancel func()) {
+	ch := make(chan Response, 1)
+
+	r.mu.Lock()
+	r.pending[id] = ch
+	r.mu.Unlock()
+
+	return ch, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.pending, id)
+	}
+}
+
+// Respond delivers the reply to the request waiting under id. It returns
+// false if no request is waiting, e.g. because it was already answered or
+// timed out
+func (r *Registry) Respond(id string, response Response) bool {
+	r.mu.Lock()
+	ch, ok := r.pending[id]
+	delete(r.pending, id)
+	r.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+	ch <- response
+	return true
+}
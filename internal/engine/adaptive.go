@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"block-flow/internal/blocks"
+)
+
+// Adaptive input tuning: pressure is the smoothed fill ratio of the fullest
+// node input buffer. Above adaptiveHigh the input rate halves, below
+// adaptiveLow it doubles again, down to 1/2^adaptiveMaxLevel of normal
+const (
+	adaptiveHigh      = 0.75
+	adaptiveLow       = 0.25
+	adaptiveSmoothing = 0.3 // Weight of the newest observation
+	adaptiveMaxLevel  = 5
+)
+
+// errShed fails the delivery of streaming input messages shed at the source
+var errShed = errors.New("input shed by adaptive rate control")
+
+// AdaptiveInput reports the state of a flow's adaptive input rate control
+type AdaptiveInput struct {
+	Pressure   float64 `json:"pressure"`    // Smoothed fill ratio of the fullest node input buffer
+	RateFactor float64 `json:"rate_factor"` // Fraction of input fires let through
+	Shed       int64   `json:"shed"`        // Input fires skipped or messages shed
+}
+
+// adaptiveInput slows a flow's inputs while downstream buffers stay full, so
+// overload is shed at the source instead of by dropping arbitrary messages
+// mid-pipeline. Interval inputs skip fires; streaming inputs sample messages
+type adaptiveInput struct {
+	enabled  bool
+	pressure float64
+	level    int    // Inputs let 1 in 2^level fires through
+	fires    uint64 // Fires seen, for sampling
+	shed     atomic.Int64
+	mu       sync.Mutex
+}
+
+// parseAdaptiveInput reads the flow's adaptive_input property (default false)
+func parseAdaptiveInput(properties map[string]string) (bool, error) {
+	value, ok := properties["adaptive_input"]
+	if !ok || value == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid adaptive_input '%s': must be true or false", value)
+	}
+	return enabled, nil
+}
+
+// inputPressure returns the fill ratio of the fullest input buffer among the
+// flow's non-input nodes
+func (f *RuntimeFlow) inputPressure() float64 {
+	pressure := 0.0
+	for _, node := range f.Nodes {
+		if node.Group == blocks.InputGroup || cap(node.InputChan) == 0 {
+			continue
+		}
+		if ratio := float64(len(node.InputChan)) / float64(cap(node.InputChan)); ratio > pressure {
+			pressure = ratio
+		}
+	}
+	return pressure
+}
+
+// admit feeds an observed pressure into the controller and reports whether
+// an input fire may go ahead. It always admits when adaptive input is off.
+// changed is true when the rate level moved
+func (a *adaptiveInput) admit(observed float64) (admitted, changed bool) {
+	if !a.enabled {
+		return true, false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.pressure = adaptiveSmoothing*observed + (1-adaptiveSmoothing)*a.pressure
+	switch {
+	case a.pressure >= adaptiveHigh && a.level < adaptiveMaxLevel:
+		a.level++
+		changed = true
+	case a.pressure <= adaptiveLow && a.level > 0:
+		a.level--
+		changed = true
+	}
+
+	a.fires++
+	if a.fires%(1<<a.level) != 0 {
+		a.shed.Add(1)
+		return false, changed
+	}
+	return true, changed
+}
+
+// status returns the controller state, nil when adaptive input is off
+func (a *adaptiveInput) status() *AdaptiveInput {
+	if !a.enabled {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return &AdaptiveInput{
+		Pressure:   a.pressure,
+		RateFactor: 1 / float64(uint(1)<<a.level),
+		Shed:       a.shed.Load(),
+	}
+}
+
+// admitInput applies the flow's adaptive input control to one input fire
+func (fe *FlowExecutor) admitInput(node *RuntimeNode, flow *RuntimeFlow) bool {
+	admitted, changed := flow.adaptive.admit(flow.inputPressure())
+	if changed {
+		status := flow.adaptive.status()
+		fe.logger.Info("Adaptive input rate changed", map[string]interface{}{
+			"flow_id":     flow.ID,
+			"node_id":     node.ID,
+			"pressure":    status.Pressure,
+			"rate_factor": status.RateFactor,
+		})
+	}
+	return admitted
+}
@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"testing"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestAdaptiveInput(t *testing.T) {
+	t.Run("overload lowers the input rate", func(t *testing.T) {
+		// A flow whose only downstream buffer is full
+		slow := &RuntimeNode{ID: "slow", Group: blocks.PropagationGroup, InputChan: make(chan *models.Message, 4)}
+		for i := 0; i < cap(slow.InputChan); i++ {
+			slow.InputChan <- models.NewMessage(i)
+		}
+		flow := &RuntimeFlow{
+			Nodes: map[string]*RuntimeNode{
+				"in":   {ID: "in", Group: blocks.InputGroup},
+				"slow": slow,
+			},
+			adaptive: adaptiveInput{enabled: true},
+		}
+		if pressure := flow.inputPressure(); pressure != 1 {
+			t.Fatalf("pressure = %v, want 1", pressure)
+		}
+
+		admitted := 0
+		for i := 0; i < 200; i++ {
+			if ok, _ := flow.adaptive.admit(flow.inputPressure()); ok {
+				admitted++
+			}
+		}
+		status := flow.adaptive.status()
+		if want := 1 / float64(uint(1)<<adaptiveMaxLevel); status.RateFactor != want {
+			t.Errorf("rate factor = %v, want %v", status.RateFactor, want)
+		}
+		if admitted >= 20 {
+			t.Errorf("admitted %d of 200 fires under overload, want fewer than 20", admitted)
+		}
+		if status.Shed != int64(200-admitted) {
+			t.Errorf("shed = %d, want %d", status.Shed, 200-admitted)
+		}
+		if len(slow.InputChan) != cap(slow.InputChan) {
+			t.Error("buffered messages were dropped mid-pipeline")
+		}
+
+		// Drained: the rate recovers
+		for len(slow.InputChan) > 0 {
+			<-slow.InputChan
+		}
+		for i := 0; i < 200; i++ {
+			flow.adaptive.admit(flow.inputPressure())
+		}
+		if status := flow.adaptive.status(); status.RateFactor != 1 {
+			t.Errorf("rate factor after draining = %v, want 1", status.RateFactor)
+		}
+	})
+
+	t.Run("disabled always admits", func(t *testing.T) {
+		var adaptive adaptiveInput
+		for i := 0; i < 10; i++ {
+			if ok, changed := adaptive.admit(1); !ok || changed {
+				t.Fatalf("admit = %v, %v; want true, false", ok, changed)
+			}
+		}
+		if adaptive.status() != nil {
+			t.Error("status reported for disabled adaptive input")
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/config"
+	"block-flow/internal/models"
+)
+
+// BackpressureStrategy decides what happens to a message whose target
+// node's input buffer is full
+type BackpressureStrategy string
+
+const (
+	// BackpressureDrop drops the message, failing its delivery
+	BackpressureDrop BackpressureStrategy = "drop"
+	// BackpressureBlock holds the sending node until the target has room,
+	// which in turn slows the flow's inputs
+	BackpressureBlock BackpressureStrategy = "block"
+	// BackpressureBlockWithTimeout holds the sending node like
+	// BackpressureBlock, but drops the message once the flow's backpressure
+	// timeout has passed. Also accepted as "block-with-timeout"
+	BackpressureBlockWithTimeout BackpressureStrategy = "block_with_timeout"
+	// BackpressureDeadLetter drops the message into the flow's dead letters
+	BackpressureDeadLetter BackpressureStrategy = "dead-letter"
+)
+
+// backpressurePolicy maps the block group of a message's target node to the
+// strategy applied when that node's input buffer is full
+type backpressurePolicy map[blocks.BlockGroup]BackpressureStrategy
+
+// defaultBackpressure never drops messages bound for an action (sink) node,
+// and lets propagation chains shed load only after waiting a while
+var defaultBackpressure = backpressurePolicy{
+	blocks.PropagationGroup: BackpressureBlockWithTimeout,
+	blocks.ActionGroup:      BackpressureBlock,
+}
+
+// defaultBackpressureTimeout is how long block_with_timeout waits when
+// neither the engine nor the flow configures it
+const defaultBackpressureTimeout = 5 * time.Second
+
+// parseBackpressure builds a flow's policy from the defaults, then the
+// engine-wide BACKPRESSURE setting, then the flow's backpressure property.
+// Both use "group=strategy" pairs separated by commas, e.g.
+// "propagation=block,action=dead-letter", or a single strategy for all groups
+func parseBackpressure(cfg config.EngineConfig, properties map[string]string) (backpressurePolicy, error) {
+	policy := make(backpressurePolicy, len(defaultBackpressure))
+	for group, strategy := range defaultBackpressure {
+		policy[group] = strategy
+	}
+
+	if err := policy.apply(cfg.Backpressure); err != nil {
+		return nil, fmt.Errorf("invalid BACKPRESSURE setting: %w", err)
+	}
+	if err := policy.apply(properties["backpressure"]); err != nil {
+		return nil, fmt.Errorf("invalid backpressure: %w", err)
+	}
+	return policy, nil
+}
+
+// apply overrides the policy with the given "group=strategy" pairs. A
+// strategy without a group applies to every group
+func (p backpressurePolicy) apply(pairs string) error {
+	for _, pair := range strings.Split(pairs, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			strategy, err := parseStrategy(pair)
+			if err != nil {
+				return err
+			}
+			p[blocks.PropagationGroup] = strategy
+			p[blocks.ActionGroup] = strategy
+			continue
+		}
+
+		group := blocks.BlockGroup(strings.TrimSpace(name))
+		if group != blocks.PropagationGroup && group != blocks.ActionGroup {
+			return fmt.Errorf("unknown group '%s': must be propagation or action", group)
+		}
+		strategy, err := parseStrategy(value)
+		if err != nil {
+			return fmt.Errorf("%w for %s", err, group)
+		}
+		p[group] = strategy
+	}
+	return nil
+}
+
+// backpressureAliases are alternative spellings of strategies
+var backpressureAliases = map[BackpressureStrategy]BackpressureStrategy{
+	"block-with-timeout": BackpressureBlockWithTimeout,
+}
+
+func parseStrategy(value string) (BackpressureStrategy, error) {
+	strategy := BackpressureStrategy(strings.TrimSpace(value))
+	if canonical, ok := backpressureAliases[strategy]; ok {
+		strategy = canonical
+	}
+	switch strategy {
+	case BackpressureDrop, BackpressureBlock, BackpressureBlockWithTimeout, BackpressureDeadLetter:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown strategy '%s': must be drop, block, block_with_timeout or dead-letter", strategy)
+}
+
+// parseBackpressureTimeout reads how long block_with_timeout waits: the
+// flow's backpressure_timeout property (a duration such as "2s"), else the
+// engine's BACKPRESSURE_TIMEOUT
+func parseBackpressureTimeout(cfg config.EngineConfig, properties map[string]string) (time.Duration, error) {
+	timeout := cfg.BackpressureTimeout
+	if value, ok := properties["backpressure_timeout"]; ok && value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return 0, fmt.Errorf("invalid backpressure_timeout '%s': must be a positive duration", value)
+		}
+		timeout = parsed
+	}
+	if timeout <= 0 {
+		timeout = defaultBackpressureTimeout
+	}
+	return timeout, nil
+}
+
+// deliver hands msg to target's input channel, applying the flow's
+// backpressure strategy for the target's group when the channel is full.
+// It reports whether the message was delivered
+func (fe *FlowExecutor) deliver(source, target *RuntimeNode, msg *models.Message, flow *RuntimeFlow) bool {
+	flow.messageEnqueued(msg)
+	select {
+	case target.InputChan <- msg:
+		return true
+	default:
+	}
+
+	strategy := flow.backpressure[target.Group]
+	switch strategy {
+	case BackpressureBlock:
+		select {
+		case target.InputChan <- msg:
+			return true
+		case <-flow.StopChan:
+		case <-source.StopChan:
+		}
+		flow.messageDropped(msg)
+		return false
+	case BackpressureBlockWithTimeout:
+		timer := time.NewTimer(flow.backpressureTimeout)
+		defer timer.Stop()
+		select {
+		case target.InputChan <- msg:
+			return true
+		case <-flow.StopChan:
+			flow.messageDropped(msg)
+			return false
+		case <-source.StopChan:
+			flow.messageDropped(msg)
+			return false
+		case <-timer.C:
+			// Given up waiting: dropped and logged below
+		}
+	}
+
+	flow.messageDropped(msg)
+	if strategy == BackpressureDeadLetter {
+		flow.addDeadLetter(target.ID, msg, errDropped)
+	}
+	fe.flowLog(flow).Warn("Target node input channel full, dropping message", map[string]interface{}{
+		"flow_id":     flow.ID,
+		"source_node": source.ID,
+		"target_node": target.ID,
+		"strategy":    string(strategy),
+	})
+	return false
+}
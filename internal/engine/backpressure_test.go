@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/config"
+	"block-flow/internal/models"
+)
+
+func TestParseBackpressure(t *testing.T) {
+	tests := []struct {
+		name     string
+		engine   string // BACKPRESSURE setting
+		property string // Flow's backpressure property
+		want     backpressurePolicy
+		wantErr  bool
+	}{
+		{name: "defaults", want: defaultBackpressure},
+		{
+			name:     "block_with_timeout property",
+			property: "block_with_timeout",
+			want:     backpressurePolicy{blocks.PropagationGroup: BackpressureBlockWithTimeout, blocks.ActionGroup: BackpressureBlockWithTimeout},
+		},
+		{
+			name:     "block-with-timeout alias",
+			property: "action=block-with-timeout",
+			want:     backpressurePolicy{blocks.PropagationGroup: BackpressureBlockWithTimeout, blocks.ActionGroup: BackpressureBlockWithTimeout},
+		},
+		{
+			name:   "engine setting",
+			engine: "propagation=drop, action=block_with_timeout",
+			want:   backpressurePolicy{blocks.PropagationGroup: BackpressureDrop, blocks.ActionGroup: BackpressureBlockWithTimeout},
+		},
+		{
+			name:     "property overrides engine setting",
+			engine:   "dead-letter",
+			property: "propagation=block",
+			want:     backpressurePolicy{blocks.PropagationGroup: BackpressureBlock, blocks.ActionGroup: BackpressureDeadLetter},
+		},
+		{name: "unknown strategy", property: "block_with_delay", wantErr: true},
+		{name: "unknown group", property: "input=block", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.EngineConfig{Backpressure: tt.engine}
+			got, err := parseBackpressure(cfg, map[string]string{"backpressure": tt.property})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseBackpressure error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseBackpressure = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeliverFullChannel(t *testing.T) {
+	e, _ := newTestEngine(t, testConfig())
+	flow := &RuntimeFlow{
+		ID:           "backpressure",
+		StopChan:     make(chan struct{}),
+		backpressure: backpressurePolicy{blocks.PropagationGroup: BackpressureDrop, blocks.ActionGroup: BackpressureBlock},
+	}
+	source := &RuntimeNode{ID: "source", StopChan: make(chan struct{})}
+
+	// full returns a node whose one-message input buffer is already taken
+	full := func(group blocks.BlockGroup) *RuntimeNode {
+		node := &RuntimeNode{ID: string(group), Group: group, InputChan: make(chan *models.Message, 1)}
+		node.InputChan <- models.NewMessage("queued")
+		return node
+	}
+
+	t.Run("propagation-bound delivery drops", func(t *testing.T) {
+		target := full(blocks.PropagationGroup)
+		if e.executor.deliver(source, target, models.NewMessage(1.0), flow) {
+			t.Fatal("delivered to a full propagation node")
+		}
+		if got := flow.idle.inFlight.Load(); got != 0 {
+			t.Errorf("in-flight after drop = %d, want 0", got)
+		}
+	})
+
+	t.Run("action-bound delivery blocks until there is room", func(t *testing.T) {
+		target := full(blocks.ActionGroup)
+		delivered := make(chan bool, 1)
+		go func() { delivered <- e.executor.deliver(source, target, models.NewMessage(2.0), flow) }()
+
+		select {
+		case <-delivered:
+			t.Fatal("deliver returned while the action node's buffer was full")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		<-target.InputChan
+		select {
+		case ok := <-delivered:
+			if !ok {
+				t.Fatal("blocked delivery failed once there was room")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("blocked delivery never completed")
+		}
+		if msg := <-target.InputChan; msg.Payload != 2.0 {
+			t.Errorf("delivered payload = %v, want 2", msg.Payload)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// ErrNotAtBreakpoint is returned when stepping a node that holds no message
+var ErrNotAtBreakpoint = errors.New("no message held at breakpoint")
+
+// breakpoint pauses a node before it executes a message until the message is
+// released by a step, or the breakpoint is removed
+type breakpoint struct {
+	enabled  bool
+	held     *models.Message // Message waiting at the breakpoint
+	released chan struct{}   // Closed to let the held message through
+	mu       sync.Mutex
+}
+
+// wait holds msg while the breakpoint is set. It returns false if the flow or
+// node stopped before the message was released
+func (b *breakpoint) wait(msg *models.Message, flowStop, nodeStop <-chan struct{}) bool {
+	b.mu.Lock()
+	if !b.enabled {
+		b.mu.Unlock()
+		return true
+	}
+	released := make(chan struct{})
+	b.held, b.released = msg, released
+	b.mu.Unlock()
+
+	select {
+	case <-flowStop:
+	case <-nodeStop:
+	case <-released:
+		return true
+	}
+
+	b.mu.Lock()
+	if b.released == released {
+		b.held, b.released = nil, nil
+	}
+	b.mu.Unlock()
+	return false
+}
+
+// release lets the held message through. Callers must hold b.mu
+func (b *breakpoint) release() {
+	if b.released != nil {
+		close(b.released)
+	}
+	b.held, b.released = nil, nil
+}
+
+// set enables or disables the breakpoint; disabling releases a held message
+func (b *breakpoint) set(enabled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.enabled = enabled
+	if !enabled {
+		b.release()
+	}
+}
+
+// toggle flips the breakpoint and reports whether it is now set
+func (b *breakpoint) toggle() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.enabled = !b.enabled
+	if !b.enabled {
+		b.release()
+	}
+	return b.enabled
+}
+
+// step releases the held message and returns a copy of it
+func (b *breakpoint) step() (*models.Message, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.held == nil {
+		return nil, ErrNotAtBreakpoint
+	}
+	// Copy before release; the node modifies the message while executing
+	held := b.held.Clone()
+	b.release()
+	return held, nil
+}
+
+// state describes the breakpoint for status reports: "" when unset, "set"
+// while waiting for a message and "paused" while one is held
+func (b *breakpoint) state() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case b.held != nil:
+		return "paused"
+	case b.enabled:
+		return "set"
+	}
+	return ""
+}
+
+// breakpointNode returns a node of a running flow that can take a breakpoint.
+// Input nodes have no incoming messages to hold
+func (fe *FlowExecutor) breakpointNode(flowID, nodeID string) (*RuntimeNode, error) {
+	runtimeFlow, err := fe.runningFlow(flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	node, exists := runtimeFlow.Nodes[nodeID]
+	if !exists {
+		return nil, fmt.Errorf("node '%s' not found in flow '%s'", nodeID, flowID)
+	}
+	if node.Group == blocks.InputGroup {
+		return nil, fmt.Errorf("node '%s' is an input node and receives no messages", nodeID)
+	}
+	return node, nil
+}
+
+// SetBreakpoint sets or removes a breakpoint on a node of a running flow; a
+// nil enabled toggles it. Removing it lets a held message continue.
+// Breakpoints last until the flow stops. It returns whether the breakpoint is
+// now set
+func (fe *FlowExecutor) SetBreakpoint(flowID, nodeID string, enabled *bool) (bool, error) {
+	node, err := fe.breakpointNode(flowID, nodeID)
+	if err != nil {
+		return false, err
+	}
+
+	var set bool
+	if enabled == nil {
+		set = node.breakpoint.toggle()
+	} else {
+		set = *enabled
+		node.breakpoint.set(set)
+	}
+
+	fe.logger.Info("Breakpoint changed", map[string]interface{}{
+		"flow_id": flowID,
+		"node_id": nodeID,
+		"enabled": set,
+	})
+	return set, nil
+}
+
+// StepBreakpoint releases the message held at a node's breakpoint and returns
+// it. The breakpoint stays set, so the node pauses again on its next message
+func (fe *FlowExecutor) StepBreakpoint(flowID, nodeID string) (*models.Message, error) {
+	node, err := fe.breakpointNode(flowID, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := node.breakpoint.step()
+	if err != nil {
+		return nil, fmt.Errorf("node '%s': %w", nodeID, err)
+	}
+	return msg, nil
+}
+
+// PausedAtBreakpoint lists the nodes of a flow holding a message at a breakpoint
+func (fe *FlowExecutor) PausedAtBreakpoint(flowID string) ([]string, error) {
+	fe.mutex.RLock()
+	runtimeFlow, exists := fe.flows[flowID]
+	fe.mutex.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("flow '%s' not found", flowID)
+	}
+
+	paused := make([]string, 0)
+	for nodeID, node := range runtimeFlow.Nodes {
+		if node.breakpoint.state() == "paused" {
+			paused = append(paused, nodeID)
+		}
+	}
+	sort.Strings(paused)
+	return paused, nil
+}
@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestBreakpointStep(t *testing.T) {
+	received := make(chan interface{}, 4)
+	input := &testBlock{typ: "test-input", group: blocks.InputGroup}
+	held := &testBlock{typ: "test-pass", group: blocks.PropagationGroup}
+	sink := sinkBlock(func(msg *models.Message) { received <- msg.Payload })
+	e, store := newTestEngine(t, testConfig(), input, held, sink)
+
+	flow := chain("breakpoint",
+		models.Node{ID: "in", Type: "test-input"},
+		models.Node{ID: "held", Type: "test-pass"},
+		models.Node{ID: "out", Type: "test-sink"},
+	)
+	startTestFlow(t, e, store, flow)
+
+	enabled := true
+	if set, err := e.executor.SetBreakpoint(flow.ID, "held", &enabled); err != nil || !set {
+		t.Fatalf("SetBreakpoint = %v, %v; want true, nil", set, err)
+	}
+	if _, err := e.executor.SetBreakpoint(flow.ID, "in", &enabled); err == nil {
+		t.Error("breakpoint on an input node accepted")
+	}
+
+	paused := func() bool {
+		nodes, err := e.executor.PausedAtBreakpoint(flow.ID)
+		return err == nil && reflect.DeepEqual(nodes, []string{"held"})
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+			t.Fatalf("trigger: %v", err)
+		}
+		if !eventually(t, time.Second, paused) {
+			t.Fatalf("message %d not held at the breakpoint", i+1)
+		}
+		select {
+		case got := <-received:
+			t.Fatalf("message %v passed the breakpoint before stepping", got)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		msg, err := e.executor.StepBreakpoint(flow.ID, "held")
+		if err != nil {
+			t.Fatalf("step: %v", err)
+		}
+		if msg.Payload != 1.0 {
+			t.Errorf("stepped message payload = %v, want 1", msg.Payload)
+		}
+		select {
+		case <-received:
+		case <-time.After(time.Second):
+			t.Fatalf("message %d not delivered after stepping", i+1)
+		}
+	}
+
+	if _, err := e.executor.StepBreakpoint(flow.ID, "held"); !errors.Is(err, ErrNotAtBreakpoint) {
+		t.Errorf("step with nothing held = %v, want ErrNotAtBreakpoint", err)
+	}
+}
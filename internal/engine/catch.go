@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"sort"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// caughtErrorKey is the message context entry marking messages derived from
+// a caught error. Errors while handling them aren't caught again, so a
+// failing error handler can't loop
+const caughtErrorKey = "caught_error"
+
+// catchError passes a node's execution error to the catch nodes of its flow
+// whose scope covers the node, in node ID order. Their messages inherit the
+// delivery of the failed message
+func (fe *FlowExecutor) catchError(node *RuntimeNode, flow *RuntimeFlow, msg *models.Message, err error, category blocks.ErrorCategory) {
+	if msg != nil {
+		if _, caught := msg.Context[caughtErrorKey]; caught {
+			return
+		}
+	}
+
+	catchers := make([]*RuntimeNode, 0)
+	for _, candidate := range flow.Nodes {
+		catcher, ok := candidate.Block.(blocks.CatchBlock)
+		if ok && candidate.ID != node.ID && catcher.Catches(node.ID, candidate.Properties) {
+			catchers = append(catchers, candidate)
+		}
+	}
+	if len(catchers) == 0 {
+		return
+	}
+	sort.Slice(catchers, func(i, j int) bool { return catchers[i].ID < catchers[j].ID })
+
+	failure := map[string]interface{}{
+		"node_id":   node.ID,
+		"node_type": node.Type,
+		"error":     err.Error(),
+		"category":  string(category),
+	}
+	if msg != nil {
+		failure["message"] = map[string]interface{}{
+			"id":      msg.ID,
+			"payload": msg.Payload,
+			"topic":   msg.Topic,
+			"headers": msg.Headers,
+			"source":  msg.Source,
+		}
+	}
+
+	for _, catcher := range catchers {
+		caught := models.NewMessage(failure)
+		caught.Source = node.ID
+		caught.Context[caughtErrorKey] = node.ID
+
+		messages, execErr := fe.executeBlock(catcher, flow, caught)
+		if execErr != nil {
+			fe.handleExecutionError(catcher, flow, caught, execErr)
+			continue
+		}
+		if msg != nil {
+			for _, out := range messages {
+				if out.Delivery == nil {
+					out.Delivery = msg.Delivery
+				}
+			}
+		}
+		fe.emitInput(catcher, flow, messages)
+	}
+}
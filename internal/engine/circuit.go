@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"block-flow/internal/blocks"
+)
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+// errCircuitOpen is returned for messages rejected while a breaker is open
+var errCircuitOpen = blocks.Transient(fmt.Errorf("circuit breaker open"))
+
+// circuitBreaker stops calling a failing dependency after a number of
+// consecutive failures. Once the cooldown has passed a single probe is let
+// through (half-open); its outcome closes or re-opens the circuit
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+	mu       sync.Mutex
+}
+
+// newCircuitBreaker builds a breaker from the node's circuitThreshold and
+// circuitCooldown (ms) properties. It returns nil when no threshold is set
+func newCircuitBreaker(properties map[string]interface{}) (*circuitBreaker, error) {
+	threshold, ok, err := numberProperty(properties, "circuitThreshold")
+	if err != nil || !ok || threshold <= 0 {
+		return nil, err
+	}
+
+	cooldown := 30 * time.Second
+	if ms, ok, err := numberProperty(properties, "circuitCooldown"); err != nil {
+		return nil, err
+	} else if ok {
+		cooldown = time.Duration(ms) * time.Millisecond
+	}
+
+	return &circuitBreaker{
+		threshold: int(threshold),
+		cooldown:  cooldown,
+		state:     CircuitClosed,
+	}, nil
+}
+
+// allow reports whether a call may proceed, moving an open circuit to
+// half-open once the cooldown has elapsed
+func (cb *circuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if time.Since(cb.openedAt) < cb.cooldown {
+			return false
+		}
+		cb.state = CircuitHalfOpen
+		cb.probing = true
+		return true
+	case CircuitHalfOpen:
+		// Only one probe at a time
+		if cb.probing {
+			return false
+		}
+		cb.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record updates the breaker with the outcome of a call. Invalid errors are
+// caused by the message, not the dependency, and don't count as failures;
+// neither do unclassified ones
+func (cb *circuitBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probing = false
+	if category := blocks.CategoryOf(err); err == nil || category == blocks.ErrorInvalid || category == blocks.ErrorUnknown {
+		cb.state = CircuitClosed
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	if cb.state == CircuitHalfOpen || cb.failures >= cb.threshold {
+		cb.state = CircuitOpen
+		cb.openedAt = time.Now()
+	}
+}
+
+// currentState returns the breaker state for status reporting
+func (cb *circuitBreaker) currentState() string {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// numberProperty reads an optional numeric property
+func numberProperty(properties map[string]interface{}, name string) (float64, bool, error) {
+	value, exists := properties[name]
+	if !exists || value == nil {
+		return 0, false, nil
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return v, true, nil
+	case float32:
+		return float64(v), true, nil
+	case int:
+		return float64(v), true, nil
+	case int64:
+		return float64(v), true, nil
+	case json.Number:
+		number, err := v.Float64()
+		if err != nil {
+			return 0, false, fmt.Errorf("property '%s' must be a number: %w", name, err)
+		}
+		return number, true, nil
+	default:
+		return 0, false, fmt.Errorf("property '%s' must be a number, got %T", name, value)
+	}
+}
@@ -0,0 +1,130 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	const cooldown = 20 * time.Millisecond
+	down := blocks.Transient(errors.New("dependency down"))
+	invalid := blocks.Invalid(errors.New("bad message"))
+
+	// step is one call through the breaker; a call it rejects records nothing
+	type step struct {
+		wait      bool  // Let the cooldown pass first
+		wantAllow bool  // The call is let through
+		outcome   error // Result of a call let through
+		wantState string
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "opens after consecutive failures",
+			steps: []step{
+				{wantAllow: true, outcome: down, wantState: CircuitClosed},
+				{wantAllow: true, outcome: down, wantState: CircuitOpen},
+				{wantAllow: false, wantState: CircuitOpen},
+			},
+		},
+		{
+			name: "success resets the failure count",
+			steps: []step{
+				{wantAllow: true, outcome: down, wantState: CircuitClosed},
+				{wantAllow: true, outcome: nil, wantState: CircuitClosed},
+				{wantAllow: true, outcome: down, wantState: CircuitClosed},
+			},
+		},
+		{
+			name: "invalid errors don't count",
+			steps: []step{
+				{wantAllow: true, outcome: invalid, wantState: CircuitClosed},
+				{wantAllow: true, outcome: invalid, wantState: CircuitClosed},
+				{wantAllow: true, outcome: down, wantState: CircuitClosed},
+			},
+		},
+		{
+			name: "flapping dependency",
+			steps: []step{
+				{wantAllow: true, outcome: down, wantState: CircuitClosed},
+				{wantAllow: true, outcome: down, wantState: CircuitOpen},
+				{wantAllow: false, wantState: CircuitOpen},
+				// Probe fails: open again for another cooldown
+				{wait: true, wantAllow: true, outcome: down, wantState: CircuitOpen},
+				{wantAllow: false, wantState: CircuitOpen},
+				// Probe succeeds: closed
+				{wait: true, wantAllow: true, outcome: nil, wantState: CircuitClosed},
+				{wantAllow: true, outcome: nil, wantState: CircuitClosed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb, err := newCircuitBreaker(map[string]interface{}{
+				"circuitThreshold": 2.0,
+				"circuitCooldown":  float64(cooldown / time.Millisecond),
+			})
+			if err != nil || cb == nil {
+				t.Fatalf("newCircuitBreaker = (%v, %v)", cb, err)
+			}
+
+			for i, s := range tt.steps {
+				if s.wait {
+					time.Sleep(cooldown + 5*time.Millisecond)
+				}
+				allowed := cb.allow()
+				if allowed != s.wantAllow {
+					t.Fatalf("step %d: allow = %v, want %v", i, allowed, s.wantAllow)
+				}
+				if allowed {
+					if cb.currentState() == CircuitHalfOpen && cb.allow() {
+						t.Fatalf("step %d: second probe let through while half-open", i)
+					}
+					cb.record(s.outcome)
+				}
+				if state := cb.currentState(); state != s.wantState {
+					t.Fatalf("step %d: state = %s, want %s", i, state, s.wantState)
+				}
+			}
+		})
+	}
+}
+
+func TestNewCircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name         string
+		properties   map[string]interface{}
+		wantBreaker  bool
+		wantCooldown time.Duration
+		wantErr      bool
+	}{
+		{name: "no threshold", properties: map[string]interface{}{}},
+		{name: "zero threshold", properties: map[string]interface{}{"circuitThreshold": 0.0}},
+		{name: "default cooldown", properties: map[string]interface{}{"circuitThreshold": 3.0}, wantBreaker: true, wantCooldown: 30 * time.Second},
+		{name: "cooldown", properties: map[string]interface{}{"circuitThreshold": 3.0, "circuitCooldown": 500.0}, wantBreaker: true, wantCooldown: 500 * time.Millisecond},
+		{name: "invalid threshold", properties: map[string]interface{}{"circuitThreshold": "3"}, wantErr: true},
+		{name: "invalid cooldown", properties: map[string]interface{}{"circuitThreshold": 3.0, "circuitCooldown": "soon"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb, err := newCircuitBreaker(tt.properties)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if (cb != nil) != tt.wantBreaker {
+				t.Fatalf("breaker = %v, want one %v", cb, tt.wantBreaker)
+			}
+			if cb != nil && cb.cooldown != tt.wantCooldown {
+				t.Errorf("cooldown = %s, want %s", cb.cooldown, tt.wantCooldown)
+			}
+		})
+	}
+}
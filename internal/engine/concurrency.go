@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"errors"
+
+	"block-flow/internal/blocks"
+)
+
+// errStopping is returned when a node gives up waiting for a concurrency slot
+// because its flow or the node itself is stopping
+var errStopping = blocks.Transient(errors.New("node is stopping"))
+
+// BlockConcurrency reports the usage of a block type's concurrency cap
+type BlockConcurrency struct {
+	Limit int `json:"limit"`
+	InUse int `json:"in_use"`
+}
+
+// blockLimiter caps concurrent Execute calls per block type across all flows
+type blockLimiter struct {
+	slots map[string]chan struct{}
+}
+
+// newBlockLimiter creates a semaphore for every block type with a limit
+func newBlockLimiter(limits map[string]int) *blockLimiter {
+	limiter := &blockLimiter{slots: make(map[string]chan struct{})}
+	for blockType, limit := range limits {
+		if limit > 0 {
+			limiter.slots[blockType] = make(chan struct{}, limit)
+		}
+	}
+	return limiter
+}
+
+// acquire blocks until a slot for blockType is free. It returns false if the
+// flow or node stops first. Uncapped types never block
+func (l *blockLimiter) acquire(blockType string, flowStop, nodeStop <-chan struct{}) bool {
+	slots, ok := l.slots[blockType]
+	if !ok {
+		return true
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-flowStop:
+		return false
+	case <-nodeStop:
+		return false
+	}
+}
+
+// release frees a slot acquired for blockType
+func (l *blockLimiter) release(blockType string) {
+	if slots, ok := l.slots[blockType]; ok {
+		<-slots
+	}
+}
+
+// usage returns the limit and current in-use count per capped block type
+func (l *blockLimiter) usage() map[string]BlockConcurrency {
+	usage := make(map[string]BlockConcurrency, len(l.slots))
+	for blockType, slots := range l.slots {
+		usage[blockType] = BlockConcurrency{Limit: cap(slots), InUse: len(slots)}
+	}
+	return usage
+}
+
+// GetBlockConcurrency returns the usage of every configured block type cap
+func (fe *FlowExecutor) GetBlockConcurrency() map[string]BlockConcurrency {
+	return fe.concurrency.usage()
+}
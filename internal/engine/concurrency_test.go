@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestBlockConcurrencyCap(t *testing.T) {
+	var mu sync.Mutex
+	running, peak, done := 0, 0, 0
+	slow := &testBlock{
+		typ:   "test-slow",
+		group: blocks.ActionGroup,
+		execute: func(*models.BlockExecutionContext) ([]*models.Message, error) {
+			mu.Lock()
+			running++
+			if running > peak {
+				peak = running
+			}
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			done++
+			mu.Unlock()
+			return nil, nil
+		},
+	}
+	input := &testBlock{typ: "test-input", group: blocks.InputGroup}
+
+	cfg := testConfig()
+	cfg.BlockConcurrency = map[string]int{"test-slow": 2}
+	e, store := newTestEngine(t, cfg, input, slow)
+
+	// Six flows share the capped block type
+	const flows = 6
+	for i := 0; i < flows; i++ {
+		flow := chain(fmt.Sprintf("flow-%d", i), models.Node{ID: "in", Type: "test-input"}, models.Node{ID: "slow", Type: "test-slow"})
+		startTestFlow(t, e, store, flow)
+		if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+			t.Fatalf("trigger %s: %v", flow.ID, err)
+		}
+	}
+
+	if !eventually(t, 2*time.Second, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return done == flows
+	}) {
+		t.Fatal("capped executions did not all finish")
+	}
+	if peak != 2 {
+		t.Errorf("peak concurrent executions = %d, want the cap of 2", peak)
+	}
+
+	// The slot is released just after Execute returns
+	if !eventually(t, time.Second, func() bool { return e.executor.GetBlockConcurrency()["test-slow"].InUse == 0 }) {
+		t.Error("slots still held after every execution finished")
+	}
+	usage := e.executor.GetBlockConcurrency()
+	if got := usage["test-slow"]; got.Limit != 2 {
+		t.Errorf("limit = %d, want 2", got.Limit)
+	}
+	if _, ok := usage["test-input"]; ok {
+		t.Error("uncapped block type reported")
+	}
+}
@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"block-flow/internal/models"
+)
+
+// findCycle returns the nodes of a cycle in the connection graph, starting
+// and ending with the same node, or nil if the flow is acyclic. Nodes and
+// connections are visited in flow order so the same cycle is always reported
+func findCycle(flow *models.Flow) []string {
+	targets := make(map[string][]string, len(flow.Nodes))
+	for _, conn := range flow.Connections {
+		targets[conn.Source] = append(targets[conn.Source], conn.Target)
+	}
+
+	const (
+		unvisited = iota
+		visiting  // On the current DFS path
+		done
+	)
+	state := make(map[string]int, len(flow.Nodes))
+	var path []string
+
+	var visit func(nodeID string) []string
+	visit = func(nodeID string) []string {
+		state[nodeID] = visiting
+		path = append(path, nodeID)
+		for _, target := range targets[nodeID] {
+			switch state[target] {
+			case visiting:
+				// The cycle is the path from the target's first visit on
+				for i, id := range path {
+					if id == target {
+						return append(append([]string(nil), path[i:]...), target)
+					}
+				}
+			case unvisited:
+				if cycle := visit(target); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[nodeID] = done
+		return nil
+	}
+
+	for _, node := range flow.Nodes {
+		if state[node.ID] == unvisited {
+			if cycle := visit(node.ID); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// checkCycles rejects flows whose connections loop back on themselves, or
+// only warns about them when the deployment allows feedback loops
+func (fe *FlowExecutor) checkCycles(flow *models.Flow) error {
+	cycle := findCycle(flow)
+	if cycle == nil {
+		return nil
+	}
+
+	path := strings.Join(cycle, " -> ")
+	if fe.config.AllowCycles {
+		fe.logger.Warn("Flow contains a cycle", map[string]interface{}{
+			"flow_id": flow.ID,
+			"cycle":   path,
+		})
+		return nil
+	}
+	return fmt.Errorf("flow contains a cycle: %s (set ALLOW_CYCLES=true to allow feedback loops)", path)
+}
@@ -0,0 +1,44 @@
+package engine
+
+import (
+	"fmt"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// maxDeadLetters bounds the number of failed messages retained per flow
+const maxDeadLetters = 100
+
+// addDeadLetter records a message that failed permanently, evicting the
+// oldest entry once the flow's dead-letter queue is full
+func (rf *RuntimeFlow) addDeadLetter(nodeID string, msg *models.Message, err error) {
+	rf.deadMu.Lock()
+	defer rf.deadMu.Unlock()
+
+	if len(rf.deadLetters) >= maxDeadLetters {
+		rf.deadLetters = rf.deadLetters[1:]
+	}
+
+	entry := models.NewExecutionMessage(nodeID, "error", msg)
+	entry.Error = err.Error()
+	entry.Debug = string(blocks.CategoryOf(err))
+	rf.deadLetters = append(rf.deadLetters, entry)
+}
+
+// GetDeadLetters returns the messages that failed permanently in a flow
+func (fe *FlowExecutor) GetDeadLetters(flowID string) ([]models.ExecutionMessage, error) {
+	fe.mutex.RLock()
+	runtimeFlow, exists := fe.flows[flowID]
+	fe.mutex.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("flow '%s' not found", flowID)
+	}
+
+	runtimeFlow.deadMu.Lock()
+	defer runtimeFlow.deadMu.Unlock()
+
+	deadLetters := make([]models.ExecutionMessage, len(runtimeFlow.deadLetters))
+	copy(deadLetters, runtimeFlow.deadLetters)
+	return deadLetters, nil
+}
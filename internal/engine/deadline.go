@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+// errDeadlineExceeded fails the delivery of messages whose processing
+// deadline passed before they reached a node
+var errDeadlineExceeded = errors.New("message deadline exceeded")
+
+// parseDeadline reads an input node's deadline (ms) property: the latency
+// budget of each message it emits, covering everything derived from it.
+// Zero or unset means no deadline
+func parseDeadline(properties map[string]interface{}) (time.Duration, error) {
+	ms, _, err := numberProperty(properties, "deadline")
+	if err != nil {
+		return 0, err
+	}
+	if ms < 0 {
+		return 0, fmt.Errorf("deadline must be non-negative, got %v", ms)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// stampDeadline gives messages emitted by node a deadline: derived messages
+// inherit their input's, new messages from input nodes get the node's budget.
+// Deadlines already set by the block are kept
+func stampDeadline(node *RuntimeNode, input *models.Message, messages []*models.Message) {
+	var deadline *time.Time
+	switch {
+	case input != nil:
+		deadline = input.Deadline
+	case node.Deadline > 0:
+		at := time.Now().Add(node.Deadline)
+		deadline = &at
+	}
+	if deadline == nil {
+		return
+	}
+
+	for _, msg := range messages {
+		if msg.Deadline == nil {
+			at := *deadline
+			msg.Deadline = &at
+		}
+	}
+}
+
+// withinDeadline reports whether msg may still be processed by node. A
+// message past its deadline is dead-lettered instead of executed
+func (fe *FlowExecutor) withinDeadline(node *RuntimeNode, flow *RuntimeFlow, msg *models.Message) bool {
+	if msg.Deadline == nil || time.Now().Before(*msg.Deadline) {
+		return true
+	}
+
+	msg.Delivery.Fail(errDeadlineExceeded)
+	node.stateMu.Lock()
+	node.State.DeadlineExceeded++
+	node.stateMu.Unlock()
+	flow.addDeadLetter(node.ID, msg, errDeadlineExceeded)
+
+	fe.flowLog(flow).Warn("Message deadline exceeded, dropping message", map[string]interface{}{
+		"flow_id":    flow.ID,
+		"node_id":    node.ID,
+		"message_id": msg.ID,
+		"late_by":    time.Since(*msg.Deadline).String(),
+	})
+	return false
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestDeadlineExceeded(t *testing.T) {
+	received := make(chan *models.Message, 1)
+	input := &testBlock{typ: "test-input", group: blocks.InputGroup}
+	fast := &testBlock{typ: "test-pass", group: blocks.PropagationGroup}
+	slow := &testBlock{
+		typ:   "test-slow",
+		group: blocks.PropagationGroup,
+		execute: func(ctx *models.BlockExecutionContext) ([]*models.Message, error) {
+			time.Sleep(100 * time.Millisecond)
+			return []*models.Message{ctx.Message.Clone()}, nil
+		},
+	}
+	sink := sinkBlock(func(msg *models.Message) { received <- msg })
+	e, store := newTestEngine(t, testConfig(), input, fast, slow, sink)
+
+	flow := chain("deadline",
+		models.Node{ID: "in", Type: "test-input", Properties: map[string]interface{}{"deadline": 50.0}},
+		models.Node{ID: "first", Type: "test-pass"},
+		models.Node{ID: "slow", Type: "test-slow"},
+		models.Node{ID: "late", Type: "test-pass"},
+		models.Node{ID: "out", Type: "test-sink"},
+	)
+	startTestFlow(t, e, store, flow)
+
+	if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+
+	var deadLetters []models.ExecutionMessage
+	if !eventually(t, time.Second, func() bool {
+		deadLetters, _ = e.GetDeadLetters(flow.ID)
+		return len(deadLetters) > 0
+	}) {
+		t.Fatal("late message not dead-lettered")
+	}
+	if got := deadLetters[0]; got.NodeID != "late" || got.Error != errDeadlineExceeded.Error() {
+		t.Errorf("dead letter at %q with error %q, want at late with %q", got.NodeID, got.Error, errDeadlineExceeded)
+	}
+
+	states, err := e.executor.GetNodeStates(flow.ID)
+	if err != nil {
+		t.Fatalf("node states: %v", err)
+	}
+	for nodeID, want := range map[string]int{"first": 0, "slow": 0, "late": 1} {
+		if got := states[nodeID].DeadlineExceeded; got != want {
+			t.Errorf("%s deadline_exceeded = %d, want %d", nodeID, got, want)
+		}
+	}
+
+	select {
+	case msg := <-received:
+		t.Errorf("message %v delivered past its deadline", msg.Payload)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
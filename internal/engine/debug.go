@@ -0,0 +1,202 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+// DebugRecording describes the message recording state of a flow
+type DebugRecording struct {
+	Enabled       bool       `json:"enabled"`
+	Until         *time.Time `json:"until,omitempty"`
+	MaxMessages   int        `json:"max_messages"`
+	MaxBytes      int        `json:"max_bytes"`
+	Recorded      int        `json:"recorded"`
+	RecordedBytes int        `json:"recorded_bytes"`
+}
+
+// debugRecorder controls capturing every message entering and leaving the
+// nodes of a flow into its execution record, for a bounded window of time,
+// number of messages and JSON-serialized bytes
+type debugRecorder struct {
+	enabled     bool
+	until       time.Time
+	maxMessages int
+	maxBytes    int
+	recorded    int
+	bytes       int
+	mu          sync.Mutex
+}
+
+// start enables recording for the given duration, resetting the caps
+func (r *debugRecorder) start(duration time.Duration, maxMessages, maxBytes int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.enabled = true
+	r.until = time.Now().Add(duration)
+	r.maxMessages = maxMessages
+	r.maxBytes = maxBytes
+	r.recorded = 0
+	r.bytes = 0
+}
+
+// stop disables recording while keeping captured messages for retrieval
+func (r *debugRecorder) stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.enabled = false
+}
+
+// active reports whether recording is enabled, before paying for a snapshot
+func (r *debugRecorder) active() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.enabled
+}
+
+// admit accounts for an entry of the given size. Recording turns itself off
+// once its window expires or an entry would exceed the message or byte cap
+func (r *debugRecorder) admit(size int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.enabled {
+		return false
+	}
+	if time.Now().After(r.until) || r.recorded >= r.maxMessages ||
+		(r.maxBytes > 0 && r.bytes+size > r.maxBytes) {
+		r.enabled = false
+		return false
+	}
+	r.recorded++
+	r.bytes += size
+	return true
+}
+
+// status returns the current recording state
+func (r *debugRecorder) status() DebugRecording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.enabled && time.Now().After(r.until) {
+		r.enabled = false
+	}
+
+	status := DebugRecording{
+		Enabled:       r.enabled,
+		MaxMessages:   r.maxMessages,
+		MaxBytes:      r.maxBytes,
+		Recorded:      r.recorded,
+		RecordedBytes: r.bytes,
+	}
+	if r.enabled {
+		until := r.until
+		status.Until = &until
+	}
+	return status
+}
+
+// recordDebug appends a message to the Messages trace of the flow's
+// execution record while debug recording is active
+func (f *RuntimeFlow) recordDebug(nodeID, messageType string, msg *models.Message) {
+	if !f.debug.active() {
+		return
+	}
+
+	var snapshot *models.Message
+	if msg != nil {
+		snapshot = msg.Clone()
+		snapshot.ID = msg.ID
+		snapshot.Timestamp = msg.Timestamp
+	}
+	entry := models.NewExecutionMessage(nodeID, messageType, snapshot)
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if !f.debug.admit(len(data)) {
+		return
+	}
+
+	f.executionMu.Lock()
+	defer f.executionMu.Unlock()
+	if f.execution != nil {
+		f.execution.Messages = append(f.execution.Messages, entry)
+	}
+}
+
+// clearDebugMessages discards the trace of the flow's execution record
+func (f *RuntimeFlow) clearDebugMessages() {
+	f.executionMu.Lock()
+	defer f.executionMu.Unlock()
+	if f.execution != nil {
+		f.execution.Messages = make([]models.ExecutionMessage, 0)
+	}
+}
+
+// debugMessages returns a copy of the trace of the flow's execution record
+func (f *RuntimeFlow) debugMessages() []models.ExecutionMessage {
+	f.executionMu.Lock()
+	defer f.executionMu.Unlock()
+	if f.execution == nil {
+		return []models.ExecutionMessage{}
+	}
+	messages := make([]models.ExecutionMessage, len(f.execution.Messages))
+	copy(messages, f.execution.Messages)
+	return messages
+}
+
+// SetDebugRecording enables or disables message recording for a running flow.
+// Enabling it discards the previous trace. A zero duration, maxMessages or
+// maxBytes falls back to the engine configuration
+func (fe *FlowExecutor) SetDebugRecording(flowID string, enabled bool, duration time.Duration, maxMessages, maxBytes int) (DebugRecording, error) {
+	fe.mutex.RLock()
+	runtimeFlow, exists := fe.flows[flowID]
+	fe.mutex.RUnlock()
+	if !exists {
+		return DebugRecording{}, fmt.Errorf("flow '%s' not found", flowID)
+	}
+
+	if !enabled {
+		runtimeFlow.debug.stop()
+		return runtimeFlow.debug.status(), nil
+	}
+
+	if duration <= 0 {
+		duration = fe.config.DebugRecordDuration
+	}
+	if maxMessages <= 0 {
+		maxMessages = fe.config.DebugRecordMaxMessages
+	}
+	if maxBytes <= 0 {
+		maxBytes = fe.config.DebugRecordMaxBytes
+	}
+	runtimeFlow.clearDebugMessages()
+	runtimeFlow.debug.start(duration, maxMessages, maxBytes)
+
+	fe.logger.Info("Debug recording enabled", map[string]interface{}{
+		"flow_id":      flowID,
+		"duration":     duration.String(),
+		"max_messages": maxMessages,
+		"max_bytes":    maxBytes,
+	})
+
+	return runtimeFlow.debug.status(), nil
+}
+
+// GetDebugMessages returns the recording state and the messages captured for a flow
+func (fe *FlowExecutor) GetDebugMessages(flowID string) (DebugRecording, []models.ExecutionMessage, error) {
+	fe.mutex.RLock()
+	runtimeFlow, exists := fe.flows[flowID]
+	fe.mutex.RUnlock()
+	if !exists {
+		return DebugRecording{}, nil, fmt.Errorf("flow '%s' not found", flowID)
+	}
+
+	return runtimeFlow.debug.status(), runtimeFlow.debugMessages(), nil
+}
@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestDebugRecordingCaps(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxMessages int
+		maxBytes    int
+		wantEntries int
+		wantEnabled bool
+	}{
+		{name: "under caps", maxMessages: 100, maxBytes: 1 << 20, wantEntries: 10, wantEnabled: true},
+		{name: "message cap", maxMessages: 3, maxBytes: 1 << 20, wantEntries: 3, wantEnabled: false},
+		{name: "byte cap", maxMessages: 100, maxBytes: 1, wantEntries: 0, wantEnabled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.DebugRecordDuration = time.Minute
+			e, store := newTestEngine(t, cfg,
+				&testBlock{typ: "test-input", group: blocks.InputGroup},
+				sinkBlock(nil),
+			)
+			flow := chain("debug", models.Node{ID: "in", Type: "test-input"}, models.Node{ID: "out", Type: "test-sink"})
+			startTestFlow(t, e, store, flow)
+
+			if _, err := e.SetDebugRecording(flow.ID, true, 0, tt.maxMessages, tt.maxBytes); err != nil {
+				t.Fatalf("enable recording: %v", err)
+			}
+			// Each trigger records the input node's output and the sink's input
+			for i := 0; i < 5; i++ {
+				if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+					t.Fatalf("trigger: %v", err)
+				}
+			}
+			var messages []models.ExecutionMessage
+			eventually(t, time.Second, func() bool {
+				_, messages, _ = e.GetDebugMessages(flow.ID)
+				return len(messages) >= tt.wantEntries
+			})
+
+			status, messages, err := e.GetDebugMessages(flow.ID)
+			if err != nil {
+				t.Fatalf("get messages: %v", err)
+			}
+			if len(messages) != tt.wantEntries || status.Recorded != tt.wantEntries {
+				t.Errorf("recorded %d entries (status %d), want %d", len(messages), status.Recorded, tt.wantEntries)
+			}
+			if tt.maxBytes > 0 && status.RecordedBytes > tt.maxBytes {
+				t.Errorf("recorded %d bytes past the %d byte cap", status.RecordedBytes, tt.maxBytes)
+			}
+			if status.Enabled != tt.wantEnabled {
+				t.Errorf("enabled = %v, want %v", status.Enabled, tt.wantEnabled)
+			}
+
+			runtimeFlow := e.executor.flows[flow.ID]
+			e.executor.saveExecution(runtimeFlow, false)
+			execution, err := store.LoadFlowExecution(context.Background(), runtimeFlow.execution.ID)
+			if err != nil {
+				t.Fatalf("load execution: %v", err)
+			}
+			if len(execution.Messages) != tt.wantEntries {
+				t.Errorf("execution record holds %d messages, want %d", len(execution.Messages), tt.wantEntries)
+			}
+		})
+	}
+}
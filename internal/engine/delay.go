@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// heldMessage is a message a delaying node holds until its release time
+type heldMessage struct {
+	msg     *models.Message
+	release time.Time
+}
+
+// runDelayingNode runs a propagation node whose block holds messages before
+// processing them. Held messages are kept in arrival order; once as many are
+// held as the input buffer holds, the node stops taking input so backpressure
+// reaches upstream nodes. Messages still held when the node stops are
+// rejected
+func (fe *FlowExecutor) runDelayingNode(node *RuntimeNode, block blocks.DelayingBlock, flow *RuntimeFlow) {
+	var held []heldMessage
+	defer func() {
+		for _, h := range held {
+			rejectMessage(h.msg, errStopping)
+			flow.messageProcessed(h.msg)
+		}
+	}()
+
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		input := node.InputChan
+		if len(held) >= cap(node.InputChan) {
+			input = nil
+		}
+		var due <-chan time.Time
+		if len(held) > 0 {
+			timer.Reset(time.Until(held[0].release))
+			due = timer.C
+		}
+
+		select {
+		case <-flow.StopChan:
+			return
+		case <-node.StopChan:
+			return
+		case msg := <-input:
+			node.recordInput(msg)
+			flow.recordDebug(node.ID, "input", msg)
+			if !node.breakpoint.wait(msg, flow.StopChan, node.StopChan) {
+				rejectMessage(msg, errStopping)
+				flow.messageProcessed(msg)
+				continue
+			}
+			held = append(held, heldMessage{msg: msg, release: block.ReleaseAt(time.Now(), node.Properties)})
+		case <-due:
+			now := time.Now()
+			for len(held) > 0 && !held[0].release.After(now) {
+				msg := held[0].msg
+				held[0] = heldMessage{}
+				held = held[1:]
+				fe.releaseHeld(node, flow, msg)
+			}
+		}
+		timer.Stop()
+	}
+}
+
+// releaseHeld processes a message whose hold has ended
+func (fe *FlowExecutor) releaseHeld(node *RuntimeNode, flow *RuntimeFlow, msg *models.Message) {
+	defer flow.messageProcessed(msg)
+
+	if !fe.withinDeadline(node, flow, msg) {
+		return
+	}
+	messages, err := fe.executeBlock(node, flow, msg)
+	if err != nil {
+		fe.handleExecutionError(node, flow, msg, err)
+		return
+	}
+	fe.emitMessages(node, flow, msg, messages)
+}
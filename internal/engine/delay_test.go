@@ -0,0 +1,130 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestDelayNode(t *testing.T) {
+	const messages = 5
+
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+		minElapsed time.Duration // Before the last message arrives
+		maxElapsed time.Duration
+	}{
+		{
+			name:       "delay",
+			properties: map[string]interface{}{"mode": "delay", "delay": 30},
+			minElapsed: 30 * time.Millisecond,
+			maxElapsed: time.Second,
+		},
+		{
+			name:       "rate limit",
+			properties: map[string]interface{}{"mode": "rateLimit", "interval": 20},
+			minElapsed: (messages - 1) * 20 * time.Millisecond,
+			maxElapsed: time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seq atomic.Int32
+			input := &testBlock{
+				typ:   "test-input",
+				group: blocks.InputGroup,
+				execute: func(*models.BlockExecutionContext) ([]*models.Message, error) {
+					return []*models.Message{models.NewMessage(float64(seq.Add(1)))}, nil
+				},
+			}
+			var mu sync.Mutex
+			var received []float64
+			e, store := newTestEngine(t, testConfig(), input, sinkBlock(func(msg *models.Message) {
+				mu.Lock()
+				defer mu.Unlock()
+				received = append(received, msg.Payload.(float64))
+			}))
+
+			flow := chain("delay",
+				models.Node{ID: "in", Type: "test-input"},
+				models.Node{ID: "delay", Type: "delay", Properties: tt.properties},
+				models.Node{ID: "out", Type: "test-sink"},
+			)
+			startTestFlow(t, e, store, flow)
+
+			started := time.Now()
+			for i := 0; i < messages; i++ {
+				if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+					t.Fatalf("trigger: %v", err)
+				}
+			}
+			done := eventually(t, tt.maxElapsed, func() bool {
+				mu.Lock()
+				defer mu.Unlock()
+				return len(received) == messages
+			})
+			elapsed := time.Since(started)
+			if !done {
+				t.Fatalf("messages not released within %s", tt.maxElapsed)
+			}
+			if elapsed < tt.minElapsed {
+				t.Errorf("messages released after %s, want at least %s", elapsed, tt.minElapsed)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for i, payload := range received {
+				if payload != float64(i+1) {
+					t.Fatalf("messages reordered: %v", received)
+				}
+			}
+		})
+	}
+}
+
+func TestDelayNodeRejectsHeldOnStop(t *testing.T) {
+	const messages = 3
+	deliveries := &deliveryResults{}
+	var handled atomic.Int32
+	e, store := newTestEngine(t, testConfig(), deliveries.input(), sinkBlock(func(*models.Message) { handled.Add(1) }))
+
+	flow := chain("delay-stop",
+		models.Node{ID: "in", Type: "test-input"},
+		models.Node{ID: "delay", Type: "delay", Properties: map[string]interface{}{"delay": 10000}},
+		models.Node{ID: "out", Type: "test-sink"},
+	)
+	startTestFlow(t, e, store, flow)
+
+	for i := 0; i < messages; i++ {
+		if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+			t.Fatalf("trigger: %v", err)
+		}
+	}
+	started := time.Now()
+	if err := e.StopFlow(context.Background(), flow.ID); err != nil {
+		t.Fatalf("stop flow: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("stop waited %s for held messages", elapsed)
+	}
+
+	results := deliveries.snapshot()
+	if len(results) != messages {
+		t.Fatalf("%d of %d deliveries resolved", len(results), messages)
+	}
+	for i, err := range results {
+		if err == nil {
+			t.Errorf("held message %d acked", i)
+		}
+	}
+	if handled.Load() != 0 {
+		t.Errorf("%d held messages reached the sink", handled.Load())
+	}
+}
@@ -0,0 +1,133 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// deliveryResults records how the deliveries of input messages resolved
+type deliveryResults struct {
+	mu      sync.Mutex
+	results []error
+}
+
+// input is an input block attaching a tracked delivery to every message
+func (r *deliveryResults) input() *testBlock {
+	return &testBlock{
+		typ:   "test-input",
+		group: blocks.InputGroup,
+		execute: func(ctx *models.BlockExecutionContext) ([]*models.Message, error) {
+			msg := models.NewMessage(1.0)
+			msg.Delivery = models.NewDelivery(func(err error) {
+				r.mu.Lock()
+				defer r.mu.Unlock()
+				r.results = append(r.results, err)
+			})
+			return []*models.Message{msg}, nil
+		},
+	}
+}
+
+func (r *deliveryResults) snapshot() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]error(nil), r.results...)
+}
+
+func TestDeliveryAcrossBranches(t *testing.T) {
+	tests := []struct {
+		name    string
+		fail    bool // The second branch fails
+		wantErr bool
+	}{
+		{name: "both branches succeed", wantErr: false},
+		{name: "one branch fails", fail: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveries := &deliveryResults{}
+			branch := &testBlock{
+				typ:   "test-branch",
+				group: blocks.ActionGroup,
+				execute: func(*models.BlockExecutionContext) ([]*models.Message, error) {
+					if tt.fail {
+						return nil, blocks.Fatal(errors.New("branch failed"))
+					}
+					return nil, nil
+				},
+			}
+			e, store := newTestEngine(t, testConfig(), deliveries.input(), sinkBlock(nil), branch)
+
+			flow := &models.Flow{
+				ID:   "branches",
+				Name: "branches",
+				Nodes: []models.Node{
+					{ID: "in", Type: "test-input"},
+					{ID: "a", Type: "test-sink"},
+					{ID: "b", Type: "test-branch"},
+				},
+				Connections: []models.Connection{
+					{ID: "c1", Source: "in", Target: "a"},
+					{ID: "c2", Source: "in", Target: "b"},
+				},
+			}
+			startTestFlow(t, e, store, flow)
+
+			if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+				t.Fatalf("trigger: %v", err)
+			}
+			if !eventually(t, time.Second, func() bool { return len(deliveries.snapshot()) > 0 }) {
+				t.Fatal("delivery never resolved")
+			}
+			results := deliveries.snapshot()
+			if len(results) != 1 {
+				t.Fatalf("delivery resolved %d times", len(results))
+			}
+			if (results[0] != nil) != tt.wantErr {
+				t.Errorf("delivery error = %v, want error %v", results[0], tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQueuedDeliveriesRejectedOnStop(t *testing.T) {
+	const messages = 10
+	deliveries := &deliveryResults{}
+	e, store := newTestEngine(t, testConfig(),
+		deliveries.input(),
+		sinkBlock(func(*models.Message) { time.Sleep(20 * time.Millisecond) }),
+	)
+
+	flow := chain("queued-stop", models.Node{ID: "in", Type: "test-input"}, models.Node{ID: "out", Type: "test-sink"})
+	startTestFlow(t, e, store, flow)
+
+	for i := 0; i < messages; i++ {
+		if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+			t.Fatalf("trigger: %v", err)
+		}
+	}
+	if err := e.StopFlow(context.Background(), flow.ID); err != nil {
+		t.Fatalf("stop flow: %v", err)
+	}
+
+	results := deliveries.snapshot()
+	if len(results) != messages {
+		t.Fatalf("%d of %d deliveries resolved after the flow stopped", len(results), messages)
+	}
+	rejected := 0
+	for _, err := range results {
+		if err != nil {
+			rejected++
+		}
+	}
+	if rejected == 0 {
+		t.Error("no message still queued at stop was rejected")
+	}
+}
@@ -2,12 +2,20 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"block-flow/internal/blocks"
 	"block-flow/internal/blocks/builtin"
+	"block-flow/internal/config"
+	"block-flow/internal/endpoints"
+	"block-flow/internal/events"
 	"block-flow/internal/models"
+	"block-flow/internal/plugins"
 	"block-flow/internal/storage"
 )
 
@@ -44,25 +52,44 @@ func (l *SimpleLogger) Error(message string, fields map[string]interface{}) {
 
 // Engine manages flow execution using the new FlowExecutor
 type Engine struct {
-	storage  storage.Storage
-	registry *blocks.Registry
-	executor *FlowExecutor
-	logger   Logger
-	mu       sync.RWMutex
+	storage   storage.Storage
+	registry  *blocks.Registry
+	executor  *FlowExecutor
+	events    *events.Bus
+	endpoints *endpoints.Registry // HTTP endpoints served by flows
+	logger    Logger
+	mu        sync.RWMutex
+
+	pluginsDir   string
+	pluginBlocks map[string]bool // Block types registered by plugins
+
+	quarantined map[string][]UnavailableNode // Flows held back by missing block types
+
+	ready atomic.Bool // Set once the startup flows have been loaded
+
+	janitor *janitor // Purges expired trash; nil until started
 }
 
 // New creates a new flow engine
-func New(storage storage.Storage, logger Logger) *Engine {
+func New(storage storage.Storage, cfg config.EngineConfig, logger Logger) *Engine {
 	registry := blocks.NewRegistry()
+	bus := events.NewBus()
+	flowEndpoints := endpoints.NewRegistry()
 
 	// Register built-in blocks
-	builtin.RegisterBuiltinBlocks(registry)
+	builtin.RegisterBuiltinBlocks(registry, builtin.Services{
+		Events:    bus,
+		Storage:   storage,
+		Endpoints: flowEndpoints,
+	})
 
 	engine := &Engine{
-		storage:  storage,
-		registry: registry,
-		executor: NewFlowExecutor(registry, logger),
-		logger:   logger,
+		storage:   storage,
+		registry:  registry,
+		executor:  NewFlowExecutor(registry, storage, cfg, bus, logger),
+		events:    bus,
+		endpoints: flowEndpoints,
+		logger:    logger,
 	}
 
 	return engine
@@ -70,14 +97,32 @@ func New(storage storage.Storage, logger Logger) *Engine {
 
 // LoadAndStartFlows loads all flows from storage and starts active ones
 func (e *Engine) LoadAndStartFlows(ctx context.Context) error {
+	// Startup is over even when loading failed; the API reports storage errors
+	defer e.ready.Store(true)
+
 	flows, err := e.storage.LoadAllFlows(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to load flows: %w", err)
+		// Fall back to loading flows one by one, starting whatever is readable
+		e.logger.Warn("Failed to load flows, loading individually", map[string]interface{}{
+			"error": err.Error(),
+		})
+		flows, err = e.loadFlowsIndividually(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load flows: %w", err)
+		}
 	}
 
 	for _, flow := range flows {
 		if flow.Active {
 			if err := e.StartFlow(ctx, flow.ID); err != nil {
+				var unavailable *UnavailableBlocksError
+				if errors.As(err, &unavailable) {
+					e.logger.Warn("Flow quarantined", map[string]interface{}{
+						"flow_id": flow.ID,
+						"nodes":   unavailable.Nodes,
+					})
+					continue
+				}
 				e.logger.Error("Failed to start flow", map[string]interface{}{
 					"flow_id": flow.ID,
 					"error":   err.Error(),
@@ -91,8 +136,47 @@ func (e *Engine) LoadAndStartFlows(ctx context.Context) error {
 	return nil
 }
 
-// StartFlow starts execution of a flow
+// Ready reports whether startup has finished loading and starting flows
+func (e *Engine) Ready() bool {
+	return e.ready.Load()
+}
+
+// loadFlowsIndividually loads every listed flow on its own, skipping the
+// ones that can't be loaded
+func (e *Engine) loadFlowsIndividually(ctx context.Context) ([]*models.Flow, error) {
+	flowIDs, err := e.storage.ListFlowIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	flows := make([]*models.Flow, 0, len(flowIDs))
+	for _, flowID := range flowIDs {
+		flow, err := e.storage.LoadFlow(ctx, flowID)
+		if err != nil {
+			e.logger.Error("Failed to load flow", map[string]interface{}{
+				"flow_id": flowID,
+				"error":   err.Error(),
+			})
+			continue
+		}
+		flows = append(flows, flow)
+	}
+	return flows, nil
+}
+
+// StartFlow starts execution of a flow. A flow with the start_paused
+// property starts with its inputs paused
 func (e *Engine) StartFlow(ctx context.Context, flowID string) error {
+	return e.startFlow(ctx, flowID, false)
+}
+
+// StartFlowPaused starts a flow with its inputs paused: every node is running
+// but input nodes emit nothing until ResumeInputs
+func (e *Engine) StartFlowPaused(ctx context.Context, flowID string) error {
+	return e.startFlow(ctx, flowID, true)
+}
+
+func (e *Engine) startFlow(ctx context.Context, flowID string, paused bool) error {
 	// Load flow from storage
 	flow, err := e.storage.LoadFlow(ctx, flowID)
 	if err != nil {
@@ -104,8 +188,52 @@ func (e *Engine) StartFlow(ctx context.Context, flowID string) error {
 		return fmt.Errorf("flow validation failed: %w", err)
 	}
 
-	// Use the new executor to prepare and start the flow
-	return e.executor.PrepareAndStartFlow(flow)
+	// Use the new executor to prepare and start the flow. A flow referencing
+	// unregistered block types is quarantined until it starts successfully
+	startPaused, err := parseStartPaused(flow.Properties)
+	if err != nil {
+		return err
+	}
+
+	if err := e.executor.PrepareAndStartFlow(flow, paused || startPaused); err != nil {
+		var unavailable *UnavailableBlocksError
+		if errors.As(err, &unavailable) {
+			e.quarantineFlow(unavailable)
+		}
+		return err
+	}
+	e.ReleaseFlow(flowID)
+	return nil
+}
+
+// ValidateConnection checks whether a connection could be added to a stored
+// flow, returning the reasons it would be rejected
+func (e *Engine) ValidateConnection(ctx context.Context, flowID string, conn models.Connection) ([]string, error) {
+	flow, err := e.storage.LoadFlow(ctx, flowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load flow: %w", err)
+	}
+	return e.executor.ValidateConnection(flow, conn), nil
+}
+
+// GetEffectiveProperties returns the defaults-merged properties a stored
+// node would be executed with
+func (e *Engine) GetEffectiveProperties(ctx context.Context, flowID, nodeID string) (map[string]interface{}, error) {
+	flow, err := e.storage.LoadFlow(ctx, flowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load flow: %w", err)
+	}
+	node, exists := flow.GetNode(nodeID)
+	if !exists {
+		return nil, fmt.Errorf("node '%s' not found in flow '%s'", nodeID, flowID)
+	}
+	return e.executor.EffectiveProperties(node)
+}
+
+// CheckFlowSize reports whether a flow fits within the configured node and
+// connection limits
+func (e *Engine) CheckFlowSize(flow *models.Flow) error {
+	return e.executor.CheckFlowSize(flow)
 }
 
 // StopFlow stops execution of a flow
@@ -113,11 +241,108 @@ func (e *Engine) StopFlow(ctx context.Context, flowID string) error {
 	return e.executor.StopFlow(flowID)
 }
 
-// TriggerFlow triggers a flow with an input message (manual trigger)
-func (e *Engine) TriggerFlow(ctx context.Context, flowID string, input *models.Message) error {
-	// For now, we'll implement this as a start operation
-	// In the future, this could send a message to a running flow
-	return e.StartFlow(ctx, flowID)
+// EnableFlow marks a stored flow active, so it starts with the server, and
+// starts it unless it is running already. The flow stays inactive if it
+// can't be started
+func (e *Engine) EnableFlow(ctx context.Context, flowID string) (*models.Flow, error) {
+	if running, _ := e.executor.GetFlowStatus(flowID); !running {
+		if err := e.StartFlow(ctx, flowID); err != nil {
+			return nil, err
+		}
+	}
+	return e.setFlowActive(ctx, flowID, true)
+}
+
+// DisableFlow marks a stored flow inactive and stops it if it is running
+func (e *Engine) DisableFlow(ctx context.Context, flowID string) (*models.Flow, error) {
+	flow, err := e.setFlowActive(ctx, flowID, false)
+	if err != nil {
+		return nil, err
+	}
+	if running, _ := e.executor.GetFlowStatus(flowID); running {
+		if err := e.StopFlow(ctx, flowID); err != nil {
+			return nil, err
+		}
+	}
+	return flow, nil
+}
+
+// setFlowActive saves a stored flow's active flag
+func (e *Engine) setFlowActive(ctx context.Context, flowID string, active bool) (*models.Flow, error) {
+	flow, err := e.storage.LoadFlow(ctx, flowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load flow: %w", err)
+	}
+	if flow.Active == active {
+		return flow, nil
+	}
+
+	flow.Active = active
+	flow.UpdatedAt = time.Now()
+	if err := e.storage.SaveFlow(ctx, flow); err != nil {
+		return nil, fmt.Errorf("failed to save flow: %w", err)
+	}
+	return flow, nil
+}
+
+// TriggerFlow triggers a flow with input messages (manual trigger). Each
+// input is emitted once from every input node of the flow, in priority
+// order; a nil input fires the input nodes themselves. For a running flow
+// one trigger per input is queued, and ErrTriggerQueueFull is returned, with
+// nothing queued, when the flow's trigger queue can't take all inputs. A
+// stopped flow runs once: it is started with its inputs paused, given the
+// inputs, and stopped again once their messages have been handled
+func (e *Engine) TriggerFlow(ctx context.Context, flowID string, inputs ...*models.Message) error {
+	if running, _ := e.executor.GetFlowStatus(flowID); running {
+		return e.executor.EnqueueTrigger(flowID, inputs...)
+	}
+
+	if err := e.StartFlowPaused(ctx, flowID); err != nil {
+		return err
+	}
+	return e.executor.RunOnce(flowID, inputs...)
+}
+
+// PauseInputs stops the input nodes of a running flow, letting the rest of
+// the flow drain
+func (e *Engine) PauseInputs(ctx context.Context, flowID string) error {
+	return e.executor.PauseInputs(flowID)
+}
+
+// ResumeInputs lets the input nodes of a running flow emit again
+func (e *Engine) ResumeInputs(ctx context.Context, flowID string) error {
+	return e.executor.ResumeInputs(flowID)
+}
+
+// TriggerNode fires a single input node of a running flow and returns the emitted messages
+func (e *Engine) TriggerNode(ctx context.Context, flowID, nodeID string) ([]*models.Message, error) {
+	return e.executor.TriggerNode(flowID, nodeID)
+}
+
+// SetBreakpoint sets, removes (or with a nil enabled, toggles) a debug
+// breakpoint on a node of a running flow and returns whether it is now set
+func (e *Engine) SetBreakpoint(flowID, nodeID string, enabled *bool) (bool, error) {
+	return e.executor.SetBreakpoint(flowID, nodeID, enabled)
+}
+
+// StepBreakpoint releases the message held at a node's breakpoint
+func (e *Engine) StepBreakpoint(flowID, nodeID string) (*models.Message, error) {
+	return e.executor.StepBreakpoint(flowID, nodeID)
+}
+
+// GetDeadLetters returns the messages that failed permanently in a flow
+func (e *Engine) GetDeadLetters(flowID string) ([]models.ExecutionMessage, error) {
+	return e.executor.GetDeadLetters(flowID)
+}
+
+// SetDebugRecording toggles recording of every message passing through a running flow
+func (e *Engine) SetDebugRecording(flowID string, enabled bool, duration time.Duration, maxMessages, maxBytes int) (DebugRecording, error) {
+	return e.executor.SetDebugRecording(flowID, enabled, duration, maxMessages, maxBytes)
+}
+
+// GetDebugMessages returns the messages recorded while debug recording was enabled
+func (e *Engine) GetDebugMessages(flowID string) (DebugRecording, []models.ExecutionMessage, error) {
+	return e.executor.GetDebugMessages(flowID)
 }
 
 // GetFlowStatus returns the status of a flow
@@ -127,10 +352,203 @@ func (e *Engine) GetFlowStatus(flowID string) (map[string]interface{}, error) {
 		return nil, err
 	}
 
+	nodes, err := e.executor.GetNodeStates(flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	restarts, unhealthy, err := e.executor.GetFlowHealth(flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := map[string]interface{}{
+		"running":   running,
+		"flow_id":   flowID,
+		"nodes":     nodes,
+		"restarts":  restarts,
+		"unhealthy": unhealthy,
+	}
+
+	resources, err := e.executor.GetResourceUsage(flowID)
+	if err != nil {
+		return nil, err
+	}
+	status["resources"] = resources
+
+	inputPaused, err := e.executor.InputsPaused(flowID)
+	if err != nil {
+		return nil, err
+	}
+	status["input_paused"] = inputPaused
+
+	pausedAt, err := e.executor.PausedAtBreakpoint(flowID)
+	if err != nil {
+		return nil, err
+	}
+	status["paused_at_breakpoint"] = pausedAt
+
+	queued, capacity, err := e.executor.GetTriggerQueue(flowID)
+	if err != nil {
+		return nil, err
+	}
+	status["trigger_queue"] = map[string]int{"queued": queued, "capacity": capacity}
+
+	idleRemaining, ok, err := e.executor.GetIdleRemaining(flowID)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		status["idle_remaining"] = idleRemaining
+	}
+
+	return status, nil
+}
+
+// GetFlowSummary returns a compact overview of a stored flow: node counts by
+// block group, connection count, run state, validity and execution history
+func (e *Engine) GetFlowSummary(ctx context.Context, flowID string) (map[string]interface{}, error) {
+	flow, err := e.storage.LoadFlow(ctx, flowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load flow: %w", err)
+	}
+
+	groups := make(map[string]int)
+	for _, node := range flow.Nodes {
+		info, err := e.registry.GetBlockInfoByType(node.Type)
+		if err != nil {
+			groups["unknown"]++
+			continue
+		}
+		groups[string(info.BlockGroup)]++
+	}
+
+	running, _ := e.executor.GetFlowStatus(flowID) // Not prepared means not running
+
+	validationError := ""
+	var unavailable *UnavailableBlocksError
+	if err := flow.Validate(); err != nil {
+		validationError = err.Error()
+	} else if err := e.executor.ValidateFlow(flow); err != nil {
+		validationError = err.Error()
+		errors.As(err, &unavailable)
+	}
+
+	executions, err := e.storage.SummarizeFlowExecutions(ctx, flowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize executions: %w", err)
+	}
+
+	summary := map[string]interface{}{
+		"flow_id":     flow.ID,
+		"name":        flow.Name,
+		"nodes":       len(flow.Nodes),
+		"node_groups": groups,
+		"connections": len(flow.Connections),
+		"running":     running,
+		"valid":       validationError == "",
+		"executions":  executions,
+	}
+	if validationError != "" {
+		summary["validation_error"] = validationError
+	}
+	if unavailable != nil {
+		summary["unavailable_nodes"] = unavailable.Nodes
+	}
+	if e.isQuarantined(flowID) {
+		summary["quarantined"] = true
+	}
+
+	return summary, nil
+}
+
+// GetMetrics returns engine-wide runtime metrics
+func (e *Engine) GetMetrics() map[string]interface{} {
+	bus := e.events.Stats()
 	return map[string]interface{}{
-		"running": running,
-		"flow_id": flowID,
-	}, nil
+		"block_concurrency": e.executor.GetBlockConcurrency(),
+		"quarantined_flows": e.QuarantinedFlows(),
+		"event_bus": map[string]interface{}{
+			"subscribers":          bus.Subscribers,
+			"published":            bus.Published,
+			"published_per_second": bus.PublishedPerSecond,
+			"dropped":              bus.Dropped,
+		},
+	}
+}
+
+// LoadPlugins registers plugin blocks found in dir and remembers it for
+// later reloads
+func (e *Engine) LoadPlugins(dir string) ([]plugins.LoadResult, error) {
+	e.mu.Lock()
+	e.pluginsDir = dir
+	e.mu.Unlock()
+
+	results, _, err := e.ReloadPlugins()
+	return results, err
+}
+
+// ReloadPlugins rescans the plugins directory and registers any blocks found.
+// Blocks already registered by a plugin are replaced with the reloaded factory
+// and blocks of plugins that are gone are unregistered. Plugins colliding with
+// a built-in or another plugin's block are reported as failed. Reloads are
+// serialized, so the block types reported as added are exactly the ones this
+// reload registered
+func (e *Engine) ReloadPlugins() ([]plugins.LoadResult, []string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.pluginsDir == "" {
+		return []plugins.LoadResult{}, []string{}, nil
+	}
+
+	results, err := plugins.Load(e.pluginsDir, e.registry, e.pluginBlocks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loaded := make(map[string]bool)
+	added := make([]string, 0)
+	for _, result := range results {
+		for _, blockType := range result.Blocks {
+			loaded[blockType] = true
+			if !e.pluginBlocks[blockType] {
+				added = append(added, blockType)
+			}
+		}
+	}
+	for blockType := range e.pluginBlocks {
+		if !loaded[blockType] {
+			e.registry.Unregister(blockType)
+		}
+	}
+	e.pluginBlocks = loaded
+
+	for _, result := range results {
+		if result.Error != "" {
+			e.logger.Warn("Failed to load plugin", map[string]interface{}{
+				"source": result.Source,
+				"error":  result.Error,
+			})
+			continue
+		}
+		e.logger.Info("Loaded plugin", map[string]interface{}{
+			"source": result.Source,
+			"blocks": result.Blocks,
+		})
+	}
+
+	return results, added, nil
+}
+
+// GetEventBus returns the engine's event bus
+func (e *Engine) GetEventBus() *events.Bus {
+	return e.events
+}
+
+// GetEndpoints returns the registry of HTTP endpoints served by flows
+func (e *Engine) GetEndpoints() *endpoints.Registry {
+	return e.endpoints
 }
 
 // GetRegistry returns the block registry
@@ -138,8 +556,21 @@ func (e *Engine) GetRegistry() *blocks.Registry {
 	return e.registry
 }
 
-// Shutdown gracefully shuts down the engine
-func (e *Engine) Shutdown(ctx context.Context) {
-	// For now, just log the shutdown
+// Shutdown stops all running flows, waiting until ctx expires. Input nodes
+// stop emitting right away, and pending automatic restarts are dropped.
+// Flows whose nodes don't stop in time are abandoned and reported in the
+// returned error
+func (e *Engine) Shutdown(ctx context.Context) error {
 	e.logger.Info("Engine shutting down", map[string]interface{}{})
+	e.stopJanitor()
+
+	stuck := e.executor.StopAllFlows(ctx)
+	if len(stuck) > 0 {
+		e.logger.Error("Flows did not stop before the shutdown timeout", map[string]interface{}{
+			"flows": stuck,
+		})
+		return fmt.Errorf("flows did not stop in time: %s", strings.Join(stuck, ", "))
+	}
+
+	return nil
 }
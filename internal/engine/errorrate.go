@@ -0,0 +1,153 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"block-flow/internal/events"
+	"block-flow/internal/models"
+)
+
+// errorRateBuckets is the resolution of the sliding error window
+const errorRateBuckets = 20
+
+// errorRateTracker counts block executions and failures across a flow over a
+// sliding window, split into buckets so old outcomes expire gradually
+type errorRateTracker struct {
+	threshold     float64 // Failed fraction of executions that trips the stop
+	window        time.Duration
+	minExecutions int // Executions needed in the window before tripping
+
+	buckets [errorRateBuckets]struct {
+		slot          int64
+		total, failed int
+	}
+	tripped bool
+	mu      sync.Mutex
+}
+
+// parseErrorRate reads the flow's error_rate_threshold (0-1),
+// error_rate_window (default 1m) and error_rate_min_executions (default 10)
+// properties. It returns nil when no threshold is set
+func parseErrorRate(properties map[string]string) (*errorRateTracker, error) {
+	value, ok := properties["error_rate_threshold"]
+	if !ok || value == "" {
+		return nil, nil
+	}
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold <= 0 || threshold > 1 {
+		return nil, fmt.Errorf("invalid error_rate_threshold '%s': must be a number in (0, 1]", value)
+	}
+
+	tracker := &errorRateTracker{
+		threshold:     threshold,
+		window:        time.Minute,
+		minExecutions: 10,
+	}
+
+	if value, ok := properties["error_rate_window"]; ok && value != "" {
+		window, err := time.ParseDuration(value)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid error_rate_window '%s': must be a positive duration", value)
+		}
+		tracker.window = window
+	}
+
+	if value, ok := properties["error_rate_min_executions"]; ok && value != "" {
+		minExecutions, err := strconv.Atoi(value)
+		if err != nil || minExecutions < 1 {
+			return nil, fmt.Errorf("invalid error_rate_min_executions '%s': must be a positive integer", value)
+		}
+		tracker.minExecutions = minExecutions
+	}
+
+	return tracker, nil
+}
+
+// record adds an execution outcome and reports the current error rate and
+// execution count. tripped is true only for the outcome that first pushes the
+// rate over the threshold
+func (t *errorRateTracker) record(failed bool, now time.Time) (rate float64, total int, tripped bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	width := int64(t.window / errorRateBuckets)
+	if width <= 0 {
+		width = 1
+	}
+	slot := now.UnixNano() / width
+
+	bucket := &t.buckets[slot%errorRateBuckets]
+	if bucket.slot != slot {
+		bucket.slot, bucket.total, bucket.failed = slot, 0, 0
+	}
+	bucket.total++
+	if failed {
+		bucket.failed++
+	}
+
+	failures := 0
+	for _, b := range t.buckets {
+		if b.slot > slot-errorRateBuckets {
+			total += b.total
+			failures += b.failed
+		}
+	}
+	rate = float64(failures) / float64(total)
+
+	if !t.tripped && total >= t.minExecutions && rate >= t.threshold {
+		t.tripped = true
+		return rate, total, true
+	}
+	return rate, total, false
+}
+
+// recordOutcome feeds a block execution result into the flow's error rate and
+// stops the flow once the rate exceeds its threshold
+func (fe *FlowExecutor) recordOutcome(flow *RuntimeFlow, err error) {
+	if flow.errorRate == nil || errors.Is(err, errStopping) {
+		return
+	}
+
+	rate, total, tripped := flow.errorRate.record(err != nil, time.Now())
+	if !tripped {
+		return
+	}
+
+	reason := fmt.Sprintf("error rate %.0f%% over %d executions in %s reached threshold of %.0f%%",
+		rate*100, total, flow.errorRate.window, flow.errorRate.threshold*100)
+
+	fe.logger.Error("Flow error rate exceeded, stopping", map[string]interface{}{
+		"flow_id":    flow.ID,
+		"error_rate": rate,
+		"executions": total,
+		"threshold":  flow.errorRate.threshold,
+		"window":     flow.errorRate.window.String(),
+	})
+
+	fe.events.Publish(events.Event{
+		Type:   events.FlowErrorRateExceeded,
+		FlowID: flow.ID,
+		Data: map[string]interface{}{
+			"error_rate": rate,
+			"executions": total,
+			"threshold":  flow.errorRate.threshold,
+			"window":     flow.errorRate.window.String(),
+		},
+	})
+
+	fe.recordExecution(flow.ID, models.ExecutionStatusFailed, "stopped: "+reason)
+
+	// StopFlow waits for every node goroutine, including the calling one
+	go func() {
+		if stopErr := fe.StopFlow(flow.ID); stopErr != nil {
+			fe.logger.Warn("Failed to stop flow after error rate exceeded", map[string]interface{}{
+				"flow_id": flow.ID,
+				"error":   stopErr.Error(),
+			})
+		}
+	}()
+}
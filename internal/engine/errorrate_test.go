@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/events"
+	"block-flow/internal/models"
+)
+
+func TestErrorRateStopsFlow(t *testing.T) {
+	failing := &testBlock{
+		typ:   "test-failing",
+		group: blocks.ActionGroup,
+		execute: func(*models.BlockExecutionContext) ([]*models.Message, error) {
+			return nil, blocks.Invalid(errors.New("always fails"))
+		},
+	}
+	e, store := newTestEngine(t, testConfig(), &testBlock{typ: "test-input", group: blocks.InputGroup}, failing)
+	received, unsubscribe := e.events.Subscribe("test", 16)
+	defer unsubscribe()
+
+	const minExecutions = 5
+	flow := chain("failing", models.Node{ID: "in", Type: "test-input"}, models.Node{ID: "fail", Type: "test-failing"})
+	flow.Properties = map[string]string{
+		"error_rate_threshold":      "0.5",
+		"error_rate_window":         "1m",
+		"error_rate_min_executions": "5",
+	}
+	startTestFlow(t, e, store, flow)
+	ctx := context.Background()
+	runtimeFlow := e.executor.flows[flow.ID]
+
+	// The input's own executions succeed, so each trigger adds one success
+	// and one failure: the window fills after the third trigger
+	for i := 1; i < minExecutions/2+1; i++ {
+		if _, err := e.TriggerNode(ctx, flow.ID, "in"); err != nil {
+			t.Fatalf("trigger %d: %v", i, err)
+		}
+		if !eventually(t, time.Second, func() bool { return runtimeFlow.idle.inFlight.Load() == 0 }) {
+			t.Fatalf("trigger %d never processed", i)
+		}
+		if running, _ := e.executor.GetFlowStatus(flow.ID); !running {
+			t.Fatalf("flow stopped after %d triggers, before the window filled", i)
+		}
+	}
+
+	if _, err := e.TriggerNode(ctx, flow.ID, "in"); err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	stopped := eventually(t, 2*time.Second, func() bool {
+		running, _ := e.executor.GetFlowStatus(flow.ID)
+		return !running
+	})
+	if !stopped {
+		t.Fatal("flow still running once the error rate exceeded the threshold")
+	}
+
+	alerted := false
+	for !alerted {
+		select {
+		case event := <-received:
+			alerted = event.Type == events.FlowErrorRateExceeded && event.FlowID == flow.ID
+		case <-time.After(time.Second):
+			t.Fatal("no error rate event published")
+		}
+	}
+
+	executions, err := store.LoadFlowExecutions(ctx, flow.ID)
+	if err != nil {
+		t.Fatalf("load executions: %v", err)
+	}
+	recorded := false
+	for _, execution := range executions {
+		recorded = recorded || strings.Contains(execution.Error, "error rate")
+	}
+	if !recorded {
+		t.Error("stop reason not recorded in the flow's executions")
+	}
+}
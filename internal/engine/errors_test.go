@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestExecutionErrorRouting(t *testing.T) {
+	base := errors.New("boom")
+
+	tests := []struct {
+		name        string
+		err         error
+		attempts    int32 // Executions per message
+		deadLetters bool
+		stopsFlow   bool
+	}{
+		{"transient is retried then dead-lettered", blocks.Transient(base), 3, true, false},
+		{"invalid is dead-lettered at once", blocks.Invalid(base), 1, true, false},
+		{"unknown is handled like invalid", base, 1, true, false},
+		{"fatal stops the flow", blocks.Fatal(base), 1, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			failing := &testBlock{
+				typ:   "test-failing",
+				group: blocks.PropagationGroup,
+				execute: func(ctx *models.BlockExecutionContext) ([]*models.Message, error) {
+					attempts.Add(1)
+					return nil, tt.err
+				},
+			}
+			e, store := newTestEngine(t, testConfig(), failing, sinkBlock(nil))
+
+			flow := chain("routing",
+				models.Node{ID: "in", Type: "inject", Properties: map[string]interface{}{"payload": "1", "interval": 0}},
+				models.Node{ID: "fail", Type: "test-failing"},
+				models.Node{ID: "out", Type: "test-sink"},
+			)
+			startTestFlow(t, e, store, flow)
+			if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+				t.Fatalf("trigger: %v", err)
+			}
+
+			if tt.stopsFlow {
+				stopped := eventually(t, 2*time.Second, func() bool {
+					running, _ := e.executor.GetFlowStatus(flow.ID)
+					return !running
+				})
+				if !stopped {
+					t.Fatal("flow still running after a fatal error")
+				}
+			} else {
+				eventually(t, 2*time.Second, func() bool {
+					letters, _ := e.GetDeadLetters(flow.ID)
+					return len(letters) > 0
+				})
+				if running, _ := e.executor.GetFlowStatus(flow.ID); !running {
+					t.Error("flow stopped after a non-fatal error")
+				}
+			}
+
+			if got := attempts.Load(); got != tt.attempts {
+				t.Errorf("executions = %d, want %d", got, tt.attempts)
+			}
+			letters, _ := e.GetDeadLetters(flow.ID)
+			if got := len(letters) > 0; got != tt.deadLetters {
+				t.Errorf("dead-lettered = %v, want %v", got, tt.deadLetters)
+			}
+			if tt.deadLetters && len(letters) > 0 && letters[0].Debug != string(blocks.CategoryOf(tt.err)) {
+				t.Errorf("dead letter category = %q, want %q", letters[0].Debug, blocks.CategoryOf(tt.err))
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+// startExecution opens the execution record of a flow run
+func (f *RuntimeFlow) startExecution() {
+	f.execution = models.NewFlowExecution(f.ID)
+	f.execution.Status = models.ExecutionStatusRunning
+}
+
+// recordInput counts a message taken from the node's input channel
+func (n *RuntimeNode) recordInput(msg *models.Message) {
+	n.stateMu.Lock()
+	defer n.stateMu.Unlock()
+	n.State.InputCount++
+	n.State.LastMessage = msg
+}
+
+// recordOutputs counts the messages the node emitted. Input nodes have no
+// input message, so their last output is kept as the last message
+func (n *RuntimeNode) recordOutputs(messages []*models.Message) {
+	if len(messages) == 0 {
+		return
+	}
+	n.stateMu.Lock()
+	defer n.stateMu.Unlock()
+	n.State.OutputCount += len(messages)
+	if n.Inputs == 0 {
+		n.State.LastMessage = messages[len(messages)-1]
+	}
+}
+
+// startRun marks the node as executing and returns the start time for
+// finishRun
+func (n *RuntimeNode) startRun() time.Time {
+	now := time.Now()
+	n.stateMu.Lock()
+	defer n.stateMu.Unlock()
+	n.State.Status = models.NodeStatusRunning
+	n.State.ExecutedAt = &now
+	return now
+}
+
+// finishRun records the outcome and duration of an execution. Executions
+// abandoned on shutdown leave the node idle; a success clears the error of
+// an earlier failure
+func (n *RuntimeNode) finishRun(started time.Time, err error) {
+	n.stateMu.Lock()
+	defer n.stateMu.Unlock()
+	n.State.Duration = time.Since(started)
+	switch {
+	case errors.Is(err, errStopping):
+		n.State.Status = models.NodeStatusIdle
+		return
+	case err != nil:
+		n.State.Status = models.NodeStatusError
+		n.State.Error = err.Error()
+		return
+	}
+	n.State.Status = models.NodeStatusSuccess
+	n.State.Error = ""
+}
+
+// runExecutionSaver persists the flow's execution record every
+// ExecutionSaveInterval until the flow stops
+func (fe *FlowExecutor) runExecutionSaver(flow *RuntimeFlow) {
+	defer flow.WaitGroup.Done()
+
+	ticker := time.NewTicker(fe.config.ExecutionSaveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-flow.StopChan:
+			return
+		case <-ticker.C:
+			fe.saveExecution(flow, false)
+		}
+	}
+}
+
+// saveExecution snapshots the node states into the flow's execution record
+// and persists it. The final save marks the run as stopped
+func (fe *FlowExecutor) saveExecution(flow *RuntimeFlow, final bool) {
+	if fe.storage == nil || flow.execution == nil {
+		return
+	}
+
+	flow.executionMu.Lock()
+	defer flow.executionMu.Unlock()
+
+	execution := flow.execution
+	for nodeID, node := range flow.Nodes {
+		node.stateMu.Lock()
+		state := *node.State
+		node.stateMu.Unlock()
+		execution.Nodes[nodeID] = &state
+	}
+	if final {
+		now := time.Now()
+		execution.Status = models.ExecutionStatusStopped
+		execution.EndedAt = &now
+	}
+
+	if err := fe.storage.SaveFlowExecution(context.Background(), execution); err != nil {
+		fe.flowLog(flow).Warn("Failed to save flow execution", map[string]interface{}{
+			"flow_id":      flow.ID,
+			"execution_id": execution.ID,
+			"error":        err.Error(),
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"block-flow/internal/models"
+)
+
+func TestFinishRunRecordsOutcome(t *testing.T) {
+	boom := errors.New("boom")
+
+	tests := []struct {
+		name       string
+		outcomes   []error // Results of consecutive executions
+		wantStatus models.NodeStatus
+		wantError  string
+	}{
+		{name: "success", outcomes: []error{nil}, wantStatus: models.NodeStatusSuccess},
+		{name: "failure", outcomes: []error{boom}, wantStatus: models.NodeStatusError, wantError: "boom"},
+		{name: "success after failure", outcomes: []error{boom, nil}, wantStatus: models.NodeStatusSuccess},
+		{name: "failure after success", outcomes: []error{nil, boom}, wantStatus: models.NodeStatusError, wantError: "boom"},
+		{name: "stopped after failure", outcomes: []error{boom, errStopping}, wantStatus: models.NodeStatusIdle, wantError: "boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &RuntimeNode{ID: "n", State: &models.NodeState{}}
+			for _, err := range tt.outcomes {
+				node.finishRun(node.startRun(), err)
+			}
+			if node.State.Status != tt.wantStatus || node.State.Error != tt.wantError {
+				t.Errorf("state = (%s, %q), want (%s, %q)", node.State.Status, node.State.Error, tt.wantStatus, tt.wantError)
+			}
+			if node.State.ExecutedAt == nil {
+				t.Error("execution time not recorded")
+			}
+		})
+	}
+}
@@ -1,12 +1,21 @@
 package engine
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"block-flow/internal/blocks"
+	"block-flow/internal/config"
+	"block-flow/internal/events"
 	"block-flow/internal/models"
+	"block-flow/internal/storage"
 )
 
 // LoggerAdapter adapts our Logger interface to models.BlockLogger
@@ -40,6 +49,7 @@ type RuntimeNode struct {
 	ID         string
 	Type       string
 	Name       string
+	Group      blocks.BlockGroup
 	Block      blocks.Block
 	Properties map[string]interface{}
 
@@ -48,11 +58,45 @@ type RuntimeNode struct {
 	OutputChan chan *models.Message
 
 	// Connection management
-	OutputConnections []string // Target node IDs
+	Inputs            int
+	Outputs           int
+	OutputConnections []string                      // Target node IDs
+	OutputPorts       [][]models.Connection         // Outgoing connections per output port
+	limiters          map[string]*connectionLimiter // Outgoing connections with a min_interval
 
 	// Execution control
 	StopChan  chan struct{}
 	WaitGroup *sync.WaitGroup
+
+	// Resilience policies
+	breaker *circuitBreaker // Optional, action nodes only
+	retry   *retryPolicy    // Optional, propagation and action nodes only
+	Timeout time.Duration   // Max duration of a single Execute (0 = unlimited)
+
+	retryTimeouts bool // Timeouts are retried like other errors (opt-in)
+
+	// Processing budget of messages emitted by an input node (0 = none)
+	Deadline time.Duration
+
+	// Debugging
+	breakpoint breakpoint // Holds incoming messages until stepped
+
+	// Firing order of input nodes triggered together; higher fires first
+	Priority int
+
+	// Runtime state
+	State             *models.NodeState
+	blockState        map[string]interface{} // Persists across executions, exposed as BlockExecutionContext.State
+	consecutivePanics int
+	stateMu           sync.Mutex
+
+	// Serializes executions of the block: input nodes run from their ticker,
+	// flow triggers and the node trigger API at once
+	execMu sync.Mutex
+
+	// Closed once the execution last abandoned on timeout returns; nil when
+	// there is none. Guarded by execMu
+	abandoned chan struct{}
 }
 
 // RuntimeFlow represents a flow during execution
@@ -62,30 +106,112 @@ type RuntimeFlow struct {
 	Nodes       map[string]*RuntimeNode
 	Connections []models.Connection
 
+	// Definition the flow was prepared from, used for restarts
+	Definition *models.Flow
+
+	// Limits
+	MaxPayloadSize int           // Max JSON-serialized payload size in bytes (0 = unlimited)
+	IdleTimeout    time.Duration // Stop the flow after being idle this long (0 = never)
+	Limits         ResourceLimits
+	idle           idleTracker
+	errorRate      *errorRateTracker // Stops the flow on too many failures (nil = off)
+	resources      resourceTracker
+	inputs         inputGate     // Pauses input nodes only
+	adaptive       adaptiveInput // Slows inputs while downstream buffers stay full (opt-in)
+	triggers       triggerQueue
+	backpressure   backpressurePolicy // Strategy per target block group when an input buffer is full
+
+	backpressureTimeout time.Duration // How long block_with_timeout waits for room
+
+	// Execution record of the current run, persisted periodically and on stop
+	execution   *models.FlowExecution
+	executionMu sync.Mutex
+
+	// Restart handling
+	RestartPolicy RestartPolicy
+	Restarts      int  // Automatic restarts performed so far
+	Unhealthy     bool // Restart limit reached
+	restarting    bool
+
 	// Flow control
+	Context   context.Context // Cancelled when the flow stops
+	cancel    context.CancelFunc
 	StopChan  chan struct{}
 	WaitGroup sync.WaitGroup
 	Running   bool
+	stopping  bool
 	mutex     sync.RWMutex
+
+	// Messages that failed permanently
+	deadLetters []models.ExecutionMessage
+	deadMu      sync.Mutex
+
+	// Runtime-toggleable message trace
+	debug debugRecorder
+
+	// Optional per-flow log file, opened while the flow runs
+	logToFile bool
+	log       Logger // Central logger plus the flow's file; nil without a file
 }
 
+// transientRetryBackoff is the base delay between retries of transient errors
+const transientRetryBackoff = 100 * time.Millisecond
+
 // FlowExecutor manages the execution of flows
 type FlowExecutor struct {
 	registry *blocks.Registry
+	storage  storage.Storage
+	config   config.EngineConfig
+	events   *events.Bus
 	logger   Logger
 	flows    map[string]*RuntimeFlow
 	mutex    sync.RWMutex
+
+	// Set, and shutdown closed, once StopAllFlows ran, so pending restarts
+	// don't revive flows
+	shuttingDown bool
+	shutdown     chan struct{}
+
+	// Per-block-type concurrency caps shared by all flows
+	concurrency *blockLimiter
 }
 
 // NewFlowExecutor creates a new flow executor
-func NewFlowExecutor(registry *blocks.Registry, logger Logger) *FlowExecutor {
+func NewFlowExecutor(registry *blocks.Registry, storage storage.Storage, cfg config.EngineConfig, bus *events.Bus, logger Logger) *FlowExecutor {
 	return &FlowExecutor{
 		registry: registry,
+		storage:  storage,
+		config:   cfg,
+		events:   bus,
 		logger:   logger,
 		flows:    make(map[string]*RuntimeFlow),
+		shutdown: make(chan struct{}),
+
+		concurrency: newBlockLimiter(cfg.BlockConcurrency),
 	}
 }
 
+// InvalidNode is a node whose block rejects its properties
+type InvalidNode struct {
+	NodeID string `json:"node_id"`
+	Type   string `json:"type"`
+	Error  string `json:"error"`
+}
+
+// InvalidNodesError reports every node of a flow whose properties fail its
+// block's validation
+type InvalidNodesError struct {
+	Nodes []InvalidNode
+}
+
+func (e *InvalidNodesError) Error() string {
+	nodes := make([]string, len(e.Nodes))
+	for i, node := range e.Nodes {
+		nodes[i] = fmt.Sprintf("node '%s' (type '%s'): %s", node.NodeID, node.Type, node.Error)
+	}
+	return "invalid node properties: " + strings.Join(nodes, "; ")
+}
+
 // ValidateFlow validates a flow before execution
 func (fe *FlowExecutor) ValidateFlow(flow *models.Flow) error {
 	if flow == nil {
@@ -96,48 +222,176 @@ func (fe *FlowExecutor) ValidateFlow(flow *models.Flow) error {
 		return fmt.Errorf("flow must contain at least one block")
 	}
 
-	// Validate all nodes have valid block types
+	if err := fe.CheckFlowSize(flow); err != nil {
+		return err
+	}
+
+	// Report every node whose block type is missing at once
+	if err := fe.unavailableBlocks(flow); err != nil {
+		return err
+	}
+
+	// Resolve the port counts of all nodes, reporting every node whose block
+	// rejects its properties at once. Deployment overrides count as set, block
+	// defaults do not, so required properties must be given
+	ports := make(map[string]nodePorts, len(flow.Nodes))
+	var invalid []InvalidNode
 	for _, node := range flow.Nodes {
-		_, err := fe.registry.GetBlockInfoByType(node.Type)
+		block, err := fe.registry.CreateBlock(node.Type)
 		if err != nil {
 			return fmt.Errorf("unknown block type '%s' in node '%s'", node.Type, node.ID)
 		}
-	}
 
-	// Validate connections
-	nodeMap := make(map[string]*models.Node)
-	for _, node := range flow.Nodes {
-		nodeMap[node.ID] = &node
+		properties := blocks.EffectiveProperties(nil, fe.config.BlockPropertyOverrides[node.Type], node.Properties)
+		err = blocks.ValidateProperties(block.GetProperties(), properties)
+		if err == nil {
+			err = block.Validate(properties)
+		}
+		if err != nil {
+			invalid = append(invalid, InvalidNode{NodeID: node.ID, Type: node.Type, Error: err.Error()})
+		}
+
+		inputs, outputs := blocks.PortCounts(block, node.Inputs, node.Outputs, node.Properties)
+		ports[node.ID] = nodePorts{inputs: inputs, outputs: outputs}
+	}
+	if len(invalid) > 0 {
+		return &InvalidNodesError{Nodes: invalid}
 	}
 
+	// Validate connections against the effective port counts
 	for _, conn := range flow.Connections {
-		sourceNode, exists := nodeMap[conn.Source]
+		sourcePorts, exists := ports[conn.Source]
 		if !exists {
 			return fmt.Errorf("connection references non-existent source node '%s'", conn.Source)
 		}
 
-		targetNode, exists := nodeMap[conn.Target]
+		targetPorts, exists := ports[conn.Target]
 		if !exists {
 			return fmt.Errorf("connection references non-existent target node '%s'", conn.Target)
 		}
 
 		// Validate port ranges
-		if conn.SourcePort >= sourceNode.Outputs {
+		if conn.SourcePort < 0 || conn.SourcePort >= sourcePorts.outputs {
 			return fmt.Errorf("connection references invalid source port %d (node '%s' has %d outputs)",
-				conn.SourcePort, conn.Source, sourceNode.Outputs)
+				conn.SourcePort, conn.Source, sourcePorts.outputs)
 		}
 
-		if conn.TargetPort >= targetNode.Inputs {
+		if conn.TargetPort < 0 || conn.TargetPort >= targetPorts.inputs {
 			return fmt.Errorf("connection references invalid target port %d (node '%s' has %d inputs)",
-				conn.TargetPort, conn.Target, targetNode.Inputs)
+				conn.TargetPort, conn.Target, targetPorts.inputs)
 		}
 	}
 
+	return fe.checkCycles(flow)
+}
+
+// EffectiveProperties returns the properties a node's block is executed with:
+// its stored properties over any deployment overrides over the block defaults
+func (fe *FlowExecutor) EffectiveProperties(node *models.Node) (map[string]interface{}, error) {
+	block, err := fe.registry.CreateBlock(node.Type)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create block for node '%s': %w", node.ID, err)
+	}
+	return fe.effectiveProperties(block, node), nil
+}
+
+func (fe *FlowExecutor) effectiveProperties(block blocks.Block, node *models.Node) map[string]interface{} {
+	return blocks.EffectiveProperties(block.GetProperties(), fe.config.BlockPropertyOverrides[node.Type], node.Properties)
+}
+
+// ValidateConnection checks a candidate connection against a flow without
+// adding it, returning the reasons it would be rejected (none if it is valid)
+func (fe *FlowExecutor) ValidateConnection(flow *models.Flow, conn models.Connection) []string {
+	reasons := make([]string, 0)
+
+	source, sourceExists := flow.GetNode(conn.Source)
+	if !sourceExists {
+		reasons = append(reasons, fmt.Sprintf("source node '%s' does not exist", conn.Source))
+	}
+	target, targetExists := flow.GetNode(conn.Target)
+	if !targetExists {
+		reasons = append(reasons, fmt.Sprintf("target node '%s' does not exist", conn.Target))
+	}
+
+	if sourceExists {
+		reasons = append(reasons, fe.validateEndpoint(source, conn.SourcePort, false)...)
+	}
+	if targetExists {
+		reasons = append(reasons, fe.validateEndpoint(target, conn.TargetPort, true)...)
+	}
+
+	if err := conn.Transform.Validate(); err != nil {
+		reasons = append(reasons, "invalid transform: "+err.Error())
+	}
+
+	if sourceExists && targetExists {
+		for _, existing := range flow.Connections {
+			if existing.Source == conn.Source && existing.SourcePort == conn.SourcePort &&
+				existing.Target == conn.Target && existing.TargetPort == conn.TargetPort {
+				reasons = append(reasons, "connection already exists")
+				break
+			}
+		}
+		if !fe.config.AllowCycles && (conn.Source == conn.Target || flow.HasPath(conn.Target, conn.Source)) {
+			reasons = append(reasons, fmt.Sprintf("connection would create a cycle: '%s' already leads to '%s'", conn.Target, conn.Source))
+		}
+	}
+
+	return reasons
+}
+
+// validateEndpoint checks one end of a candidate connection: the node's block
+// group must allow the direction and the port must be in range
+func (fe *FlowExecutor) validateEndpoint(node *models.Node, port int, input bool) []string {
+	block, err := fe.registry.CreateBlock(node.Type)
+	if err != nil {
+		return []string{fmt.Sprintf("unknown block type '%s' in node '%s'", node.Type, node.ID)}
+	}
+	inputs, outputs := blocks.PortCounts(block, node.Inputs, node.Outputs, node.Properties)
+
+	if input {
+		if block.GetBlockGroup() == blocks.InputGroup {
+			return []string{fmt.Sprintf("node '%s' is an input block and cannot receive messages", node.ID)}
+		}
+		if port < 0 || port >= inputs {
+			return []string{fmt.Sprintf("target port %d is out of range (node '%s' has %d inputs)", port, node.ID, inputs)}
+		}
+		return nil
+	}
+
+	if block.GetBlockGroup() == blocks.ActionGroup {
+		return []string{fmt.Sprintf("node '%s' is an action block and cannot send messages", node.ID)}
+	}
+	if port < 0 || port >= outputs {
+		return []string{fmt.Sprintf("source port %d is out of range (node '%s' has %d outputs)", port, node.ID, outputs)}
+	}
+	return nil
+}
+
+// CheckFlowSize rejects flows with more nodes or connections than the
+// engine is configured to accept
+func (fe *FlowExecutor) CheckFlowSize(flow *models.Flow) error {
+	if limit := fe.config.MaxFlowNodes; limit > 0 && len(flow.Nodes) > limit {
+		return fmt.Errorf("flow has %d nodes, exceeding the limit of %d", len(flow.Nodes), limit)
+	}
+	if limit := fe.config.MaxFlowConnections; limit > 0 && len(flow.Connections) > limit {
+		return fmt.Errorf("flow has %d connections, exceeding the limit of %d", len(flow.Connections), limit)
+	}
 	return nil
 }
 
+// nodePorts holds the effective port counts of a node
+type nodePorts struct {
+	inputs  int
+	outputs int
+}
+
 // PrepareFlow prepares a flow for execution by creating runtime structures
 func (fe *FlowExecutor) PrepareFlow(flow *models.Flow) (*RuntimeFlow, error) {
+	if flow != nil {
+		flow.Normalize()
+	}
+
 	err := fe.ValidateFlow(flow)
 	if err != nil {
 		return nil, fmt.Errorf("flow validation failed: %w", err)
@@ -148,9 +402,83 @@ func (fe *FlowExecutor) PrepareFlow(flow *models.Flow) (*RuntimeFlow, error) {
 		Name:        flow.Name,
 		Nodes:       make(map[string]*RuntimeNode),
 		Connections: flow.Connections,
+		Definition:  flow,
 		StopChan:    make(chan struct{}),
 		Running:     false,
 	}
+	runtimeFlow.Context, runtimeFlow.cancel = context.WithCancel(context.Background())
+
+	// Flow properties take precedence over the engine-wide payload limit
+	runtimeFlow.MaxPayloadSize = fe.config.MaxPayloadSize
+	if value, ok := flow.Properties["max_payload_size"]; ok {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid max_payload_size '%s': must be a non-negative integer", value)
+		}
+		runtimeFlow.MaxPayloadSize = limit
+	}
+
+	runtimeFlow.RestartPolicy, err = parseRestartPolicy(flow.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeFlow.IdleTimeout, err = parseIdleTimeout(flow.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeFlow.errorRate, err = parseErrorRate(flow.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeFlow.triggers, err = parseTriggerQueue(flow.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeFlow.adaptive.enabled, err = parseAdaptiveInput(flow.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeFlow.backpressure, err = parseBackpressure(fe.config, flow.Properties)
+	if err != nil {
+		return nil, err
+	}
+	runtimeFlow.backpressureTimeout, err = parseBackpressureTimeout(fe.config, flow.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeFlow.logToFile, err = parseFlowLogFile(fe.config, flow.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeFlow.Limits, err = parseResourceLimits(fe.config, flow.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	// One goroutine per node, trigger worker and delaying connection, plus
+	// the idle watcher and execution saver
+	runtimeFlow.resources.goroutines = len(flow.Nodes) + runtimeFlow.triggers.concurrency
+	for _, conn := range flow.Connections {
+		if conn.MinInterval > 0 && conn.RatePolicy != models.RatePolicyDrop {
+			runtimeFlow.resources.goroutines++
+		}
+	}
+	if runtimeFlow.IdleTimeout > 0 {
+		runtimeFlow.resources.goroutines++
+	}
+	if fe.savesExecutions() {
+		runtimeFlow.resources.goroutines++
+	}
+	if limit := runtimeFlow.Limits.MaxGoroutines; limit > 0 && runtimeFlow.resources.goroutines > limit {
+		return nil, fmt.Errorf("flow needs %d goroutines, exceeding max_goroutines of %d", runtimeFlow.resources.goroutines, limit)
+	}
 
 	// Create runtime nodes
 	for _, node := range flow.Nodes {
@@ -164,22 +492,74 @@ func (fe *FlowExecutor) PrepareFlow(flow *models.Flow) (*RuntimeFlow, error) {
 			return nil, fmt.Errorf("failed to get block info for node '%s': %w", node.ID, err)
 		}
 
+		inputs, outputs := blocks.PortCounts(block, node.Inputs, node.Outputs, node.Properties)
+
 		runtimeNode := &RuntimeNode{
 			ID:         node.ID,
 			Type:       node.Type,
 			Name:       node.Name,
+			Group:      blockInfo.BlockGroup,
 			Block:      block,
-			Properties: node.Properties,
+			Properties: fe.effectiveProperties(block, &node),
+			Inputs:     inputs,
+			Outputs:    outputs,
 			InputChan:  make(chan *models.Message, 100), // Buffered channel
 			OutputChan: make(chan *models.Message, 100), // Buffered channel
 			StopChan:   make(chan struct{}),
 			WaitGroup:  &runtimeFlow.WaitGroup,
+			State: &models.NodeState{
+				NodeID: node.ID,
+				Status: models.NodeStatusIdle,
+			},
+			blockState: make(map[string]interface{}),
+		}
+
+		runtimeNode.Timeout, err = parseExecuteTimeout(runtimeNode.Properties, fe.config.DefaultTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout for node '%s': %w", node.ID, err)
+		}
+		runtimeNode.retryTimeouts, err = parseRetryTimeouts(runtimeNode.Properties)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry policy for node '%s': %w", node.ID, err)
+		}
+
+		if runtimeNode.Group == blocks.InputGroup {
+			priority, _, err := numberProperty(runtimeNode.Properties, "priority")
+			if err != nil {
+				return nil, fmt.Errorf("invalid priority for node '%s': %w", node.ID, err)
+			}
+			runtimeNode.Priority = int(priority)
+
+			runtimeNode.Deadline, err = parseDeadline(runtimeNode.Properties)
+			if err != nil {
+				return nil, fmt.Errorf("invalid deadline for node '%s': %w", node.ID, err)
+			}
+		}
+
+		if runtimeNode.Group != blocks.InputGroup {
+			runtimeNode.retry, err = parseRetryPolicy(runtimeNode.Properties)
+			if err != nil {
+				return nil, fmt.Errorf("invalid retry policy for node '%s': %w", node.ID, err)
+			}
+		}
+
+		if runtimeNode.Group == blocks.ActionGroup {
+			runtimeNode.breaker, err = newCircuitBreaker(runtimeNode.Properties)
+			if err != nil {
+				return nil, fmt.Errorf("invalid circuit breaker policy for node '%s': %w", node.ID, err)
+			}
 		}
 
 		// Determine output connections for this node
+		runtimeNode.OutputPorts = make([][]models.Connection, outputs)
+		runtimeNode.limiters = make(map[string]*connectionLimiter)
 		for _, conn := range flow.Connections {
 			if conn.Source == node.ID {
 				runtimeNode.OutputConnections = append(runtimeNode.OutputConnections, conn.Target)
+				runtimeNode.OutputPorts[conn.SourcePort] = append(runtimeNode.OutputPorts[conn.SourcePort], conn)
+				if limiter := newConnectionLimiter(conn); limiter != nil {
+					runtimeNode.limiters[connectionKey(conn)] = limiter
+				}
 			}
 		}
 
@@ -201,6 +581,11 @@ func (fe *FlowExecutor) StartFlow(flowID string) error {
 	fe.mutex.Lock()
 	defer fe.mutex.Unlock()
 
+	return fe.startFlowLocked(flowID)
+}
+
+// startFlowLocked starts a prepared flow. The caller holds fe.mutex
+func (fe *FlowExecutor) startFlowLocked(flowID string) error {
 	runtimeFlow, exists := fe.flows[flowID]
 	if !exists {
 		return fmt.Errorf("flow '%s' is not prepared for execution", flowID)
@@ -210,96 +595,242 @@ func (fe *FlowExecutor) StartFlow(flowID string) error {
 		return fmt.Errorf("flow '%s' is already running", flowID)
 	}
 
+	// Opened before the flow is marked running, so readers never see it change
+	if runtimeFlow.logToFile {
+		fe.openFlowLog(runtimeFlow)
+	}
+
+	runtimeFlow.startExecution()
+
 	runtimeFlow.mutex.Lock()
 	runtimeFlow.Running = true
 	runtimeFlow.mutex.Unlock()
 
-	// Start all nodes
+	// Start all nodes, and the queues of their delaying connections
 	for _, node := range runtimeFlow.Nodes {
 		runtimeFlow.WaitGroup.Add(1)
 		go fe.runNode(node, runtimeFlow)
+
+		for _, limiter := range node.limiters {
+			target, exists := runtimeFlow.Nodes[limiter.conn.Target]
+			if limiter.dropping || !exists {
+				continue
+			}
+			runtimeFlow.WaitGroup.Add(1)
+			go fe.runConnectionQueue(node, target, limiter, runtimeFlow)
+		}
+	}
+
+	// Start the workers processing API triggers
+	for i := 0; i < runtimeFlow.triggers.concurrency; i++ {
+		runtimeFlow.WaitGroup.Add(1)
+		go fe.runTriggerWorker(runtimeFlow)
 	}
 
-	fe.logger.Info("Flow started", map[string]interface{}{
+	if fe.savesExecutions() {
+		runtimeFlow.WaitGroup.Add(1)
+		go fe.runExecutionSaver(runtimeFlow)
+	}
+
+	// Not part of the WaitGroup: the watcher itself stops the flow
+	if runtimeFlow.IdleTimeout > 0 {
+		runtimeFlow.idle.touch()
+		go fe.watchIdle(runtimeFlow)
+	}
+
+	fe.flowLog(runtimeFlow).Info("Flow started", map[string]interface{}{
 		"flow_id":   flowID,
 		"flow_name": runtimeFlow.Name,
 		"nodes":     len(runtimeFlow.Nodes),
 	})
+	fe.events.Publish(events.Event{Type: events.FlowStarted, FlowID: flowID})
 
 	return nil
 }
 
-// StopFlow stops the execution of a flow
+// StopFlow stops the execution of a flow and waits for its nodes to finish.
+// The executor lock is released while waiting so a slow flow doesn't block
+// other flows from stopping
 func (fe *FlowExecutor) StopFlow(flowID string) error {
 	fe.mutex.Lock()
-	defer fe.mutex.Unlock()
-
 	runtimeFlow, exists := fe.flows[flowID]
 	if !exists {
+		fe.mutex.Unlock()
 		return fmt.Errorf("flow '%s' is not running", flowID)
 	}
 
-	if !runtimeFlow.Running {
+	runtimeFlow.mutex.Lock()
+	if !runtimeFlow.Running || runtimeFlow.stopping {
+		runtimeFlow.mutex.Unlock()
+		fe.mutex.Unlock()
 		return fmt.Errorf("flow '%s' is not running", flowID)
 	}
+	runtimeFlow.stopping = true
+	runtimeFlow.mutex.Unlock()
+	fe.mutex.Unlock()
+
+	// Let on-stop hooks run through the still running flow first
+	fe.runStopHooks(runtimeFlow)
 
 	// Signal all nodes to stop
 	close(runtimeFlow.StopChan)
+	runtimeFlow.cancel()
 
-	// Wait for all nodes to finish
+	// Wait for all nodes to finish, then reject what they left queued
 	runtimeFlow.WaitGroup.Wait()
+	drainInputs(runtimeFlow)
+	fe.saveExecution(runtimeFlow, true)
 
 	runtimeFlow.mutex.Lock()
 	runtimeFlow.Running = false
+	runtimeFlow.stopping = false
 	runtimeFlow.mutex.Unlock()
 
-	fe.logger.Info("Flow stopped", map[string]interface{}{
+	fe.flowLog(runtimeFlow).Info("Flow stopped", map[string]interface{}{
 		"flow_id": flowID,
 	})
+	fe.closeFlowLog(runtimeFlow)
+	fe.events.Publish(events.Event{Type: events.FlowStopped, FlowID: flowID})
 
 	return nil
 }
 
-// runNode runs a single node in the flow
-func (fe *FlowExecutor) runNode(node *RuntimeNode, flow *RuntimeFlow) {
-	defer node.WaitGroup.Done()
+// drainInputs rejects the messages still buffered in the input channels of a
+// stopped flow's nodes, so their deliveries are nacked instead of never
+// resolving. Called once no node runs anymore
+func drainInputs(flow *RuntimeFlow) {
+	for _, node := range flow.Nodes {
+		for len(node.InputChan) > 0 {
+			msg := <-node.InputChan
+			rejectMessage(msg, errStopping)
+			flow.messageProcessed(msg)
+		}
+	}
+}
+
+// savesExecutions reports whether running flows persist their execution
+// record periodically
+func (fe *FlowExecutor) savesExecutions() bool {
+	return fe.storage != nil && fe.config.ExecutionSaveInterval > 0
+}
+
+// StopAllFlows stops every running flow concurrently. Inputs of all flows
+// are paused first, so no flow takes in new messages while others are still
+// stopping. It returns the IDs of flows whose nodes didn't finish before ctx
+// expired; those are abandoned
+func (fe *FlowExecutor) StopAllFlows(ctx context.Context) []string {
+	fe.mutex.Lock()
+	if !fe.shuttingDown {
+		fe.shuttingDown = true
+		close(fe.shutdown)
+	}
+	done := make(map[string]chan struct{})
+	for flowID, runtimeFlow := range fe.flows {
+		runtimeFlow.mutex.RLock()
+		running := runtimeFlow.Running
+		runtimeFlow.mutex.RUnlock()
+		if running {
+			runtimeFlow.inputs.pause()
+			done[flowID] = make(chan struct{})
+		}
+	}
+	fe.mutex.Unlock()
 
-	blockInfo := fe.registry.GetBlockInfo()[0] // Get block info for logging
-	for _, info := range fe.registry.GetBlockInfo() {
-		if info.Type == node.Type {
-			blockInfo = info
-			break
+	for flowID, ch := range done {
+		go func(flowID string, ch chan struct{}) {
+			defer close(ch)
+			if err := fe.StopFlow(flowID); err != nil {
+				fe.logger.Debug("Flow already stopped", map[string]interface{}{
+					"flow_id": flowID,
+				})
+			}
+		}(flowID, ch)
+	}
+
+	var stuck []string
+	for flowID, ch := range done {
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			select {
+			case <-ch:
+			default:
+				stuck = append(stuck, flowID)
+			}
 		}
 	}
+	sort.Strings(stuck)
 
-	fe.logger.Debug("Starting node", map[string]interface{}{
+	return stuck
+}
+
+// runNode runs a single node in the flow
+func (fe *FlowExecutor) runNode(node *RuntimeNode, flow *RuntimeFlow) {
+	defer node.WaitGroup.Done()
+
+	fe.flowLog(flow).Debug("Starting node", map[string]interface{}{
 		"node_id":     node.ID,
 		"node_type":   node.Type,
-		"block_group": blockInfo.BlockGroup,
+		"block_group": node.Group,
 	})
 
 	// Handle different block groups
-	switch blockInfo.BlockGroup {
+	switch node.Group {
 	case blocks.InputGroup:
 		fe.runInputNode(node, flow)
 	case blocks.PropagationGroup:
 		fe.runPropagationNode(node, flow)
 	case blocks.ActionGroup:
 		fe.runActionNode(node, flow)
+	default:
+		err := fmt.Errorf("unknown block group '%s'", node.Group)
+		node.stateMu.Lock()
+		node.State.Status = models.NodeStatusError
+		node.State.Error = err.Error()
+		node.stateMu.Unlock()
+		fe.flowLog(flow).Error("Node not started", map[string]interface{}{
+			"node_id":     node.ID,
+			"node_type":   node.Type,
+			"block_group": node.Group,
+			"error":       err.Error(),
+		})
+		return
 	}
 
-	fe.logger.Debug("Node finished", map[string]interface{}{
+	fe.flowLog(flow).Debug("Node finished", map[string]interface{}{
 		"node_id": node.ID,
 	})
 }
 
 // runInputNode runs an input group node (generates messages)
 func (fe *FlowExecutor) runInputNode(node *RuntimeNode, flow *RuntimeFlow) {
-	// For inject blocks, we can implement interval-based message generation
-	// For now, we'll implement a simple trigger mechanism
+	if streaming, ok := node.Block.(blocks.StreamingBlock); ok {
+		fe.runStreamingNode(node, streaming, flow)
+		return
+	}
+	if _, ok := node.Block.(blocks.CatchBlock); ok {
+		// Fired by other nodes' errors
+		return
+	}
+	if lifecycle, ok := node.Block.(blocks.LifecycleBlock); ok {
+		// Stop hooks are fired by StopFlow. A flow started paused fires
+		// its start hooks on resume
+		if lifecycle.LifecycleEvent() == blocks.LifecycleStart && flow.inputs.wait(flow.StopChan, node.StopChan) {
+			fe.fireInputNode(node, flow)
+		}
+		return
+	}
 
-	ticker := time.NewTicker(1 * time.Second) // Default 1 second interval
+	interval := fe.inputInterval(node, flow)
+	if interval == 0 {
+		// Manual only: the node fires on flow and node triggers
+		return
+	}
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
+	defer node.setNextFire(nil)
+
+	node.setNextFire(&interval)
 
 	for {
 		select {
@@ -308,31 +839,141 @@ func (fe *FlowExecutor) runInputNode(node *RuntimeNode, flow *RuntimeFlow) {
 		case <-node.StopChan:
 			return
 		case <-ticker.C: // Generate message from input block
-			ctx := &models.BlockExecutionContext{
-				NodeID:  node.ID,
-				Logger:  &LoggerAdapter{logger: fe.logger},
-				Message: nil, // Input blocks don't have input messages
+			node.setNextFire(&interval)
+			if flow.inputs.paused() {
+				continue
 			}
-
-			messages, err := node.Block.Execute(ctx, node.Properties)
-			if err != nil {
-				fe.logger.Error("Error executing input node", map[string]interface{}{
-					"node_id": node.ID,
-					"error":   err.Error(),
-				})
+			if flow.overLimit() {
+				// Paused until downstream nodes drain below the flow's limits
+				flow.resources.throttled.Add(1)
 				continue
 			}
-
-			// Send messages to output connections
-			for _, msg := range messages {
-				fe.distributeMessage(node, msg, flow)
+			if !fe.admitInput(node, flow) {
+				continue
 			}
+			fe.fireInputNode(node, flow)
 		}
 	}
 }
 
+// defaultInputInterval is how often an input node without an interval
+// property fires
+const defaultInputInterval = time.Second
+
+// inputInterval reads an input node's interval property (milliseconds, 0 for
+// manual triggers only). Invalid or negative values fall back to the block's
+// default interval
+func (fe *FlowExecutor) inputInterval(node *RuntimeNode, flow *RuntimeFlow) time.Duration {
+	fallback := defaultInputInterval
+	for _, def := range node.Block.GetProperties() {
+		if def.Name != "interval" {
+			continue
+		}
+		if millis, err := models.ToNumber(def.DefaultValue); err == nil && millis >= 0 {
+			fallback = time.Duration(millis * float64(time.Millisecond))
+		}
+		break
+	}
+
+	millis, ok, err := numberProperty(node.Properties, "interval")
+	if err == nil && !ok {
+		return fallback
+	}
+	if err != nil || millis < 0 {
+		fe.flowLog(flow).Warn("Invalid input interval, using the block default", map[string]interface{}{
+			"flow_id":  flow.ID,
+			"node_id":  node.ID,
+			"interval": node.Properties["interval"],
+			"fallback": fallback.String(),
+		})
+		return fallback
+	}
+	return time.Duration(millis * float64(time.Millisecond))
+}
+
+// runStreamingNode runs an input block that produces messages on its own
+// schedule until the flow stops
+func (fe *FlowExecutor) runStreamingNode(node *RuntimeNode, block blocks.StreamingBlock, flow *RuntimeFlow) {
+	ctx := fe.newExecutionContext(node, flow, nil)
+
+	emit := func(msg *models.Message) {
+		if !flow.inputs.wait(flow.StopChan, node.StopChan) || !flow.waitForCapacity() {
+			rejectMessage(msg, errStopping)
+			return
+		}
+		if !fe.admitInput(node, flow) {
+			rejectMessage(msg, errShed)
+			return
+		}
+
+		now := time.Now()
+		node.stateMu.Lock()
+		node.State.LastEmitAt = &now
+		node.stateMu.Unlock()
+		flow.idle.touch()
+
+		fe.emitMessages(node, flow, nil, []*models.Message{msg})
+	}
+
+	if err := fe.safeRun(node, block, ctx, emit); err != nil && flow.Context.Err() == nil {
+		fe.handleExecutionError(node, flow, nil, err)
+	}
+}
+
+// setNextFire publishes when a scheduled input node will fire next;
+// a nil interval clears it (manual-only or stopped nodes)
+func (n *RuntimeNode) setNextFire(interval *time.Duration) {
+	n.stateMu.Lock()
+	defer n.stateMu.Unlock()
+
+	if interval == nil {
+		n.State.NextFireAt = nil
+		return
+	}
+	next := time.Now().Add(*interval)
+	n.State.NextFireAt = &next
+}
+
+// fireInputNode executes an input node once and distributes its output
+func (fe *FlowExecutor) fireInputNode(node *RuntimeNode, flow *RuntimeFlow) ([]*models.Message, error) {
+	messages, err := fe.executeBlock(node, flow, nil) // Input blocks don't have input messages
+	if err != nil {
+		fe.handleExecutionError(node, flow, nil, err)
+		return nil, err
+	}
+	return fe.emitInput(node, flow, messages), nil
+}
+
+// emitInput distributes messages as the output of an input node
+func (fe *FlowExecutor) emitInput(node *RuntimeNode, flow *RuntimeFlow, messages []*models.Message) []*models.Message {
+	now := time.Now()
+	node.stateMu.Lock()
+	node.State.LastEmitAt = &now
+	node.stateMu.Unlock()
+	flow.idle.touch()
+
+	// Send messages to output connections
+	return fe.emitMessages(node, flow, nil, messages)
+}
+
 // runPropagationNode runs a propagation group node (processes messages)
 func (fe *FlowExecutor) runPropagationNode(node *RuntimeNode, flow *RuntimeFlow) {
+	if delaying, ok := node.Block.(blocks.DelayingBlock); ok {
+		fe.runDelayingNode(node, delaying, flow)
+		return
+	}
+
+	// Ticking blocks get a periodic call in this goroutine to flush state
+	var tick <-chan time.Time
+	ticking, isTicking := node.Block.(blocks.TickingBlock)
+	if isTicking {
+		if interval := ticking.TickInterval(node.Properties); interval > 0 {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+	}
+
 	for {
 		select {
 		case <-flow.StopChan:
@@ -340,25 +981,45 @@ func (fe *FlowExecutor) runPropagationNode(node *RuntimeNode, flow *RuntimeFlow)
 		case <-node.StopChan:
 			return
 		case msg := <-node.InputChan: // Process message
-			ctx := &models.BlockExecutionContext{
-				NodeID:  node.ID,
-				Logger:  &LoggerAdapter{logger: fe.logger},
-				Message: msg,
+			node.recordInput(msg)
+			flow.recordDebug(node.ID, "input", msg)
+			if !node.breakpoint.wait(msg, flow.StopChan, node.StopChan) {
+				rejectMessage(msg, errStopping)
+				flow.messageProcessed(msg)
+				continue
 			}
-
-			messages, err := node.Block.Execute(ctx, node.Properties)
+			if !fe.withinDeadline(node, flow, msg) {
+				flow.messageProcessed(msg)
+				continue
+			}
+			messages, err := fe.executeBlock(node, flow, msg)
 			if err != nil {
-				fe.logger.Error("Error executing propagation node", map[string]interface{}{
-					"node_id": node.ID,
-					"error":   err.Error(),
-				})
+				fe.handleExecutionError(node, flow, msg, err)
+				flow.messageProcessed(msg)
 				continue
 			}
 
 			// Send messages to output connections
-			for _, outMsg := range messages {
-				fe.distributeMessage(node, outMsg, flow)
+			fe.emitMessages(node, flow, msg, messages)
+			flow.messageProcessed(msg)
+		case <-tick:
+			ctx := fe.newExecutionContext(node, flow, nil)
+			node.execMu.Lock()
+			if node.abandonedRunning() {
+				node.execMu.Unlock()
+				continue // Retried on the next tick
 			}
+			messages, err := fe.safeTick(node, ticking, ctx)
+			node.execMu.Unlock()
+			if err != nil {
+				fe.handleExecutionError(node, flow, nil, err)
+				continue
+			}
+			node.stateMu.Lock()
+			node.consecutivePanics = 0
+			node.stateMu.Unlock()
+
+			fe.emitMessages(node, flow, nil, messages)
 		}
 	}
 }
@@ -372,30 +1033,286 @@ func (fe *FlowExecutor) runActionNode(node *RuntimeNode, flow *RuntimeFlow) {
 		case <-node.StopChan:
 			return
 		case msg := <-node.InputChan: // Process message (no output)
-			ctx := &models.BlockExecutionContext{
-				NodeID:  node.ID,
-				Logger:  &LoggerAdapter{logger: fe.logger},
-				Message: msg,
+			node.recordInput(msg)
+			flow.recordDebug(node.ID, "input", msg)
+			if !node.breakpoint.wait(msg, flow.StopChan, node.StopChan) {
+				rejectMessage(msg, errStopping)
+				flow.messageProcessed(msg)
+				continue
+			}
+			if !fe.withinDeadline(node, flow, msg) {
+				flow.messageProcessed(msg)
+				continue
+			}
+
+			// Fast-fail while the node's circuit breaker is open
+			if node.breaker != nil && !node.breaker.allow() {
+				fe.handleExecutionError(node, flow, msg, errCircuitOpen)
+				flow.messageProcessed(msg)
+				continue
 			}
 
-			_, err := node.Block.Execute(ctx, node.Properties)
+			_, err := fe.executeBlock(node, flow, msg)
+			if node.breaker != nil {
+				node.breaker.record(err)
+			}
 			if err != nil {
-				fe.logger.Error("Error executing action node", map[string]interface{}{
-					"node_id": node.ID,
-					"error":   err.Error(),
-				})
+				fe.handleExecutionError(node, flow, msg, err)
 			}
+			flow.messageProcessed(msg)
 			// Action blocks don't generate output messages
 		}
 	}
 }
 
-// distributeMessage sends a message to all connected target nodes
+// executeBlock runs the node's block for a single message, one execution of
+// the node at a time, retrying failures with a linear backoff while the flow
+// is running: transient ones by default, any but fatal ones per the node's
+// retry policy
+func (fe *FlowExecutor) executeBlock(node *RuntimeNode, flow *RuntimeFlow, msg *models.Message) (messages []*models.Message, err error) {
+	node.execMu.Lock()
+	defer node.execMu.Unlock()
+
+	started := node.startRun()
+	defer func() { node.finishRun(started, err) }()
+
+	for attempt := 0; ; attempt++ {
+		ctx := fe.newExecutionContext(node, flow, msg)
+
+		if err := fe.awaitAbandoned(node, flow); err != nil {
+			fe.recordOutcome(flow, err)
+			return nil, err
+		}
+		if !fe.concurrency.acquire(node.Type, flow.StopChan, node.StopChan) {
+			return nil, errStopping
+		}
+		messages, err := fe.executeWithTimeout(node, ctx, func() { fe.concurrency.release(node.Type) })
+		if err == nil {
+			node.stateMu.Lock()
+			node.consecutivePanics = 0
+			node.stateMu.Unlock()
+			fe.recordOutcome(flow, nil)
+			return messages, nil
+		}
+
+		delay, retry := fe.retryDelay(node, attempt, err)
+		if !retry {
+			fe.recordOutcome(flow, err)
+			return nil, err
+		}
+
+		fe.flowLog(flow).Warn("Execution failed, retrying", map[string]interface{}{
+			"node_id": node.ID,
+			"attempt": attempt + 1,
+			"delay":   delay.String(),
+			"error":   err.Error(),
+		})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-flow.StopChan:
+			timer.Stop()
+			return nil, err
+		case <-node.StopChan:
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// newExecutionContext builds the context a block executes with: the flow's
+// cancellation context and properties, the node's persistent state and the
+// engine debug flag
+func (fe *FlowExecutor) newExecutionContext(node *RuntimeNode, flow *RuntimeFlow, msg *models.Message) *models.BlockExecutionContext {
+	ctx := models.NewBlockExecutionContext(flow.Context, node.ID, flow.ID, msg, &LoggerAdapter{logger: fe.flowLog(flow)})
+	ctx.State = node.blockState
+	ctx.Debug = fe.config.DebugMode
+	if flow.Definition != nil {
+		ctx.FlowProperties = flow.Definition.Properties
+	}
+	return ctx
+}
+
+// safeExecute calls the block's Execute, converting a panic into a PanicError
+func (fe *FlowExecutor) safeExecute(node *RuntimeNode, ctx *models.BlockExecutionContext) (messages []*models.Message, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			messages = nil
+			err = &PanicError{NodeID: node.ID, Value: r}
+		}
+	}()
+
+	return node.Block.Execute(ctx, node.Properties)
+}
+
+// safeRun calls a streaming block's Run, converting a panic into a PanicError
+func (fe *FlowExecutor) safeRun(node *RuntimeNode, block blocks.StreamingBlock, ctx *models.BlockExecutionContext, emit func(*models.Message)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{NodeID: node.ID, Value: r}
+		}
+	}()
+
+	return block.Run(ctx, node.Properties, emit)
+}
+
+// safeTick calls a ticking block's Tick, converting a panic into a PanicError
+func (fe *FlowExecutor) safeTick(node *RuntimeNode, block blocks.TickingBlock, ctx *models.BlockExecutionContext) (messages []*models.Message, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			messages = nil
+			err = &PanicError{NodeID: node.ID, Value: r}
+		}
+	}()
+
+	return block.Tick(ctx, node.Properties)
+}
+
+// handleExecutionError routes a failed execution according to its error
+// category: invalid (and exhausted transient) errors are dead-lettered,
+// fatal errors stop the whole flow
+func (fe *FlowExecutor) handleExecutionError(node *RuntimeNode, flow *RuntimeFlow, msg *models.Message, err error) {
+	if msg != nil {
+		msg.Delivery.Fail(err)
+	}
+
+	// Messages abandoned on shutdown are dropped like queued ones
+	if errors.Is(err, errStopping) {
+		return
+	}
+
+	category := blocks.CategoryOf(err)
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		fe.recordPanic(node, flow)
+	}
+
+	node.stateMu.Lock()
+	node.State.Error = err.Error()
+	node.stateMu.Unlock()
+
+	fe.flowLog(flow).Error("Error executing node", map[string]interface{}{
+		"flow_id":     flow.ID,
+		"node_id":     node.ID,
+		"block_group": node.Group,
+		"category":    category,
+		"error":       err.Error(),
+	})
+
+	fe.events.Publish(events.Event{
+		Type:   events.NodeError,
+		FlowID: flow.ID,
+		NodeID: node.ID,
+		Data: map[string]interface{}{
+			"error":    err.Error(),
+			"category": string(category),
+		},
+	})
+	fe.catchError(node, flow, msg, err, category)
+
+	if category == blocks.ErrorFatal {
+		// StopFlow waits for every node goroutine, including this one
+		go func() {
+			if stopErr := fe.StopFlow(flow.ID); stopErr != nil {
+				fe.flowLog(flow).Warn("Failed to stop flow after fatal error", map[string]interface{}{
+					"flow_id": flow.ID,
+					"error":   stopErr.Error(),
+				})
+			}
+		}()
+		return
+	}
+
+	flow.addDeadLetter(node.ID, msg, err)
+}
+
+// emitMessages sends a node's output messages to their targets and returns
+// the messages sent. Outputs inherit the delivery of the input they were
+// produced from, so the originating message is only acknowledged once the
+// whole downstream chain has handled it
+func (fe *FlowExecutor) emitMessages(node *RuntimeNode, flow *RuntimeFlow, input *models.Message, messages []*models.Message) []*models.Message {
+	for _, msg := range messages {
+		if msg.Delivery == nil && input != nil {
+			msg.Delivery = input.Delivery
+		}
+	}
+	stampDeadline(node, input, messages)
+
+	allowed := fe.enforcePayloadSize(node, messages, flow)
+	node.recordOutputs(allowed)
+	for _, msg := range allowed {
+		msg.Delivery.Add()
+		fe.distributeMessage(node, msg, flow)
+		msg.Delivery.Done()
+	}
+	return allowed
+}
+
+// rejectMessage fails a message that won't be delivered, nacking its
+// delivery if it has one
+func rejectMessage(msg *models.Message, err error) {
+	msg.Delivery.Add()
+	msg.Delivery.Fail(err)
+	msg.Delivery.Done()
+}
+
+// enforcePayloadSize drops messages whose JSON-serialized payload exceeds the
+// flow's size limit, recording an error on the producing node
+func (fe *FlowExecutor) enforcePayloadSize(node *RuntimeNode, messages []*models.Message, flow *RuntimeFlow) []*models.Message {
+	if flow.MaxPayloadSize <= 0 {
+		return messages
+	}
+
+	allowed := make([]*models.Message, 0, len(messages))
+	for _, msg := range messages {
+		data, err := json.Marshal(msg.Payload)
+		if err != nil || len(data) <= flow.MaxPayloadSize {
+			// Payloads that can't be serialized aren't measurable; let them through
+			allowed = append(allowed, msg)
+			continue
+		}
+
+		errMsg := fmt.Sprintf("payload size %d bytes exceeds limit of %d bytes", len(data), flow.MaxPayloadSize)
+		rejectMessage(msg, errors.New(errMsg))
+		node.stateMu.Lock()
+		node.State.OversizedDropped++
+		node.State.Error = errMsg
+		node.stateMu.Unlock()
+
+		fe.flowLog(flow).Error("Dropping oversized message", map[string]interface{}{
+			"flow_id":    flow.ID,
+			"node_id":    node.ID,
+			"message_id": msg.ID,
+			"size":       len(data),
+			"limit":      flow.MaxPayloadSize,
+		})
+	}
+
+	return allowed
+}
+
+// distributeMessage sends a message to all target nodes connected to the
+// output port the message was emitted on
 func (fe *FlowExecutor) distributeMessage(sourceNode *RuntimeNode, msg *models.Message, flow *RuntimeFlow) {
-	for _, targetNodeID := range sourceNode.OutputConnections {
+	flow.recordDebug(sourceNode.ID, "output", msg)
+
+	if msg.Port < 0 || msg.Port >= len(sourceNode.OutputPorts) {
+		msg.Delivery.Fail(fmt.Errorf("message emitted on invalid output port %d", msg.Port))
+		fe.flowLog(flow).Warn("Message emitted on invalid output port, dropping message", map[string]interface{}{
+			"source_node": sourceNode.ID,
+			"port":        msg.Port,
+			"outputs":     len(sourceNode.OutputPorts),
+		})
+		return
+	}
+
+	for _, conn := range sourceNode.OutputPorts[msg.Port] {
+		targetNodeID := conn.Target
 		targetNode, exists := flow.Nodes[targetNodeID]
 		if !exists {
-			fe.logger.Error("Target node not found", map[string]interface{}{
+			fe.flowLog(flow).Error("Target node not found", map[string]interface{}{
 				"source_node": sourceNode.ID,
 				"target_node": targetNodeID,
 			})
@@ -404,30 +1321,58 @@ func (fe *FlowExecutor) distributeMessage(sourceNode *RuntimeNode, msg *models.M
 
 		// Clone message for each target to avoid shared state issues
 		clonedMsg := msg.Clone()
+		clonedMsg.InputPort = conn.TargetPort
+		clonedMsg.Delivery = msg.Delivery
+		if err := conn.Transform.Apply(clonedMsg); err != nil {
+			msg.Delivery.Fail(fmt.Errorf("transform to '%s' failed: %w", targetNodeID, err))
+			fe.flowLog(flow).Warn("Connection transform failed, dropping message", map[string]interface{}{
+				"source_node": sourceNode.ID,
+				"target_node": targetNodeID,
+				"error":       err.Error(),
+			})
+			continue
+		}
 
-		// Non-blocking send (drop message if channel is full)
-		select {
-		case targetNode.InputChan <- clonedMsg:
-			fe.logger.Debug("Message sent", map[string]interface{}{
+		// Per-connection rate limit, holding neither this node nor its
+		// other connections
+		if limiter := sourceNode.limiters[connectionKey(conn)]; limiter != nil {
+			fe.deliverLimited(sourceNode, targetNode, limiter, clonedMsg, flow)
+			continue
+		}
+
+		if fe.deliver(sourceNode, targetNode, clonedMsg, flow) {
+			fe.flowLog(flow).Debug("Message sent", map[string]interface{}{
 				"from":    sourceNode.ID,
 				"to":      targetNodeID,
 				"payload": clonedMsg.Payload,
 			})
-		default:
-			fe.logger.Warn("Target node input channel full, dropping message", map[string]interface{}{
-				"source_node": sourceNode.ID,
-				"target_node": targetNodeID,
-			})
 		}
 	}
 }
 
-// PrepareAndStartFlow is a convenience method to prepare and start a flow
-func (fe *FlowExecutor) PrepareAndStartFlow(flow *models.Flow) error {
+// PrepareAndStartFlow is a convenience method to prepare and start a flow,
+// optionally with its inputs paused from the start
+func (fe *FlowExecutor) PrepareAndStartFlow(flow *models.Flow, paused bool) error {
+	fe.mutex.RLock()
+	existing, exists := fe.flows[flow.ID]
+	fe.mutex.RUnlock()
+	if exists {
+		existing.mutex.RLock()
+		running := existing.Running
+		existing.mutex.RUnlock()
+		if running {
+			return fmt.Errorf("flow '%s' is already running", flow.ID)
+		}
+	}
+
 	runtimeFlow, err := fe.PrepareFlow(flow)
 	if err != nil {
 		return fmt.Errorf("failed to prepare flow: %w", err)
 	}
+	if paused {
+		// Before any node runs, so not a single message slips out
+		runtimeFlow.inputs.pause()
+	}
 
 	fe.mutex.Lock()
 	fe.flows[flow.ID] = runtimeFlow
@@ -452,3 +1397,168 @@ func (fe *FlowExecutor) GetFlowStatus(flowID string) (bool, error) {
 
 	return running, nil
 }
+
+// GetNodeStates returns a snapshot of the runtime state of every node in a flow
+func (fe *FlowExecutor) GetNodeStates(flowID string) (map[string]models.NodeState, error) {
+	fe.mutex.RLock()
+	defer fe.mutex.RUnlock()
+
+	runtimeFlow, exists := fe.flows[flowID]
+	if !exists {
+		return nil, fmt.Errorf("flow '%s' not found", flowID)
+	}
+
+	states := make(map[string]models.NodeState, len(runtimeFlow.Nodes))
+	for nodeID, node := range runtimeFlow.Nodes {
+		node.stateMu.Lock()
+		state := *node.State
+		node.stateMu.Unlock()
+
+		if node.breaker != nil {
+			state.CircuitState = node.breaker.currentState()
+		}
+		state.Breakpoint = node.breakpoint.state()
+		states[nodeID] = state
+	}
+
+	return states, nil
+}
+
+// TriggerNode manually fires a single input node of a running flow once and
+// returns the messages it emitted
+func (fe *FlowExecutor) TriggerNode(flowID, nodeID string) ([]*models.Message, error) {
+	fe.mutex.RLock()
+	runtimeFlow, exists := fe.flows[flowID]
+	fe.mutex.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("flow '%s' not found", flowID)
+	}
+
+	runtimeFlow.mutex.RLock()
+	running := runtimeFlow.Running
+	runtimeFlow.mutex.RUnlock()
+	if !running {
+		return nil, fmt.Errorf("flow '%s' is not running", flowID)
+	}
+
+	node, exists := runtimeFlow.Nodes[nodeID]
+	if !exists {
+		return nil, fmt.Errorf("node '%s' not found in flow '%s'", nodeID, flowID)
+	}
+
+	if node.Group != blocks.InputGroup {
+		return nil, fmt.Errorf("node '%s' is not an input node", nodeID)
+	}
+
+	if runtimeFlow.inputs.paused() {
+		return nil, fmt.Errorf("flow '%s' has its inputs paused", flowID)
+	}
+
+	if runtimeFlow.overLimit() {
+		runtimeFlow.resources.throttled.Add(1)
+		return nil, fmt.Errorf("flow '%s' is over its in-flight limits, try again later", flowID)
+	}
+
+	return fe.fireInputNode(node, runtimeFlow)
+}
+
+// TriggerInputs fires every input node of a running flow once, except
+// streaming, lifecycle and catch inputs. Nodes fire one after another by
+// descending priority (ties by node ID), and each node's output is queued
+// downstream before the next fires, so shared downstream nodes see a
+// deterministic order
+func (fe *FlowExecutor) TriggerInputs(flowID string) ([]*models.Message, error) {
+	runtimeFlow, err := fe.runningFlow(flowID)
+	if err != nil {
+		return nil, err
+	}
+	return fe.fireInputs(runtimeFlow, nil)
+}
+
+// fireInputs fires the triggerable input nodes of a running flow once, in
+// priority order. With a message each node emits a copy of it instead of
+// running its block
+func (fe *FlowExecutor) fireInputs(runtimeFlow *RuntimeFlow, msg *models.Message) ([]*models.Message, error) {
+	if runtimeFlow.inputs.paused() {
+		return nil, fmt.Errorf("flow '%s' has its inputs paused", runtimeFlow.ID)
+	}
+	return fe.triggerInputs(runtimeFlow, msg, nil)
+}
+
+// triggerInputs is fireInputs regardless of the input gate. Emitted messages
+// are tracked by delivery when set
+func (fe *FlowExecutor) triggerInputs(runtimeFlow *RuntimeFlow, msg *models.Message, delivery *models.Delivery) ([]*models.Message, error) {
+	flowID := runtimeFlow.ID
+	inputs := make([]*RuntimeNode, 0)
+	for _, node := range runtimeFlow.Nodes {
+		if node.Group != blocks.InputGroup {
+			continue
+		}
+		if _, streaming := node.Block.(blocks.StreamingBlock); streaming {
+			continue
+		}
+		if _, lifecycle := node.Block.(blocks.LifecycleBlock); lifecycle {
+			continue
+		}
+		if _, catcher := node.Block.(blocks.CatchBlock); catcher {
+			continue
+		}
+		inputs = append(inputs, node)
+	}
+	sort.Slice(inputs, func(i, j int) bool {
+		if inputs[i].Priority != inputs[j].Priority {
+			return inputs[i].Priority > inputs[j].Priority
+		}
+		return inputs[i].ID < inputs[j].ID
+	})
+
+	emitted := make([]*models.Message, 0)
+	for _, node := range inputs {
+		if runtimeFlow.overLimit() {
+			runtimeFlow.resources.throttled.Add(1)
+			return emitted, fmt.Errorf("flow '%s' is over its in-flight limits, try again later", flowID)
+		}
+
+		var messages []*models.Message
+		if msg != nil {
+			injected := msg.Clone()
+			injected.Source = node.ID
+			if injected.Timestamp.IsZero() {
+				injected.Timestamp = time.Now()
+			}
+			messages = []*models.Message{injected}
+		} else {
+			var err error
+			messages, err = fe.executeBlock(node, runtimeFlow, nil)
+			if err != nil {
+				// Failures are recorded on the node and don't stop the others
+				fe.handleExecutionError(node, runtimeFlow, nil, err)
+				continue
+			}
+		}
+		if delivery != nil {
+			for _, message := range messages {
+				message.Delivery = delivery
+			}
+		}
+		emitted = append(emitted, fe.emitInput(node, runtimeFlow, messages)...)
+	}
+
+	return emitted, nil
+}
+
+// GetFlowHealth returns the number of automatic restarts of a flow and
+// whether it was left stopped after exhausting its restart policy
+func (fe *FlowExecutor) GetFlowHealth(flowID string) (restarts int, unhealthy bool, err error) {
+	fe.mutex.RLock()
+	defer fe.mutex.RUnlock()
+
+	runtimeFlow, exists := fe.flows[flowID]
+	if !exists {
+		return 0, false, fmt.Errorf("flow '%s' not found", flowID)
+	}
+
+	runtimeFlow.mutex.RLock()
+	defer runtimeFlow.mutex.RUnlock()
+	return runtimeFlow.Restarts, runtimeFlow.Unhealthy, nil
+}
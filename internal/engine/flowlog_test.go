@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestFlowLogFile(t *testing.T) {
+	cfg := testConfig()
+	cfg.FlowLogDir = t.TempDir()
+	cfg.FlowLogMaxSize = 1024
+	cfg.FlowLogMaxFiles = 1
+
+	input := &testBlock{typ: "test-input", group: blocks.InputGroup}
+	e, store := newTestEngine(t, cfg, input, sinkBlock(nil))
+
+	logged := chain("logged", models.Node{ID: "in", Type: "test-input"}, models.Node{ID: "out", Type: "test-sink"})
+	logged.Properties = map[string]string{"log_file": "true"}
+	startTestFlow(t, e, store, logged)
+	startTestFlow(t, e, store, chain("central", models.Node{ID: "in", Type: "test-input"}))
+
+	path := filepath.Join(cfg.FlowLogDir, "logged.log")
+	for i := 0; i < 20; i++ {
+		if _, err := e.TriggerNode(context.Background(), logged.ID, "in"); err != nil {
+			t.Fatalf("trigger: %v", err)
+		}
+	}
+	if !eventually(t, time.Second, func() bool {
+		_, err := os.Stat(path + ".1")
+		return err == nil
+	}) {
+		t.Fatal("flow log not rotated at the size cap")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat flow log: %v", err)
+	}
+	if info.Size() > int64(cfg.FlowLogMaxSize) {
+		t.Errorf("flow log is %d bytes, over the %d byte cap", info.Size(), cfg.FlowLogMaxSize)
+	}
+
+	file, err := os.Open(path + ".1")
+	if err != nil {
+		t.Fatalf("open rotated log: %v", err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", scanner.Text(), err)
+		}
+		if entry["flow_id"] != logged.ID {
+			t.Errorf("log line for flow %v in %s's log", entry["flow_id"], logged.ID)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(cfg.FlowLogDir, "central.log")); !os.IsNotExist(err) {
+		t.Errorf("log file created for a flow without log_file (stat: %v)", err)
+	}
+}
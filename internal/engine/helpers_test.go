@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/config"
+	"block-flow/internal/models"
+	"block-flow/internal/storage"
+)
+
+// nopLogger discards engine logs
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, map[string]interface{}) {}
+func (nopLogger) Info(string, map[string]interface{})  {}
+func (nopLogger) Warn(string, map[string]interface{})  {}
+func (nopLogger) Error(string, map[string]interface{}) {}
+
+// testBlock is a block whose behaviour is supplied by the test
+type testBlock struct {
+	typ     string
+	group   blocks.BlockGroup
+	outputs int
+	execute func(ctx *models.BlockExecutionContext) ([]*models.Message, error)
+}
+
+func (b *testBlock) GetType() string                  { return b.typ }
+func (b *testBlock) GetName() string                  { return b.typ }
+func (b *testBlock) GetDescription() string           { return "test block" }
+func (b *testBlock) GetCategory() string              { return "test" }
+func (b *testBlock) GetBlockGroup() blocks.BlockGroup { return b.group }
+func (b *testBlock) GetOutputs() int                  { return b.outputs }
+func (b *testBlock) GetProperties() []blocks.PropertyDefinition {
+	return nil
+}
+func (b *testBlock) Validate(map[string]interface{}) error { return nil }
+
+func (b *testBlock) GetInputs() int {
+	if b.group == blocks.InputGroup {
+		return 0
+	}
+	return 1
+}
+
+func (b *testBlock) Execute(ctx *models.BlockExecutionContext, _ map[string]interface{}) ([]*models.Message, error) {
+	if b.execute == nil {
+		if ctx.Message == nil {
+			return []*models.Message{models.NewMessage(1.0)}, nil
+		}
+		return []*models.Message{ctx.Message.Clone()}, nil
+	}
+	return b.execute(ctx)
+}
+
+// sinkBlock is an action block passing every message it handles to handle
+func sinkBlock(handle func(msg *models.Message)) *testBlock {
+	return &testBlock{
+		typ:   "test-sink",
+		group: blocks.ActionGroup,
+		execute: func(ctx *models.BlockExecutionContext) ([]*models.Message, error) {
+			if handle != nil {
+				handle(ctx.Message)
+			}
+			return nil, nil
+		},
+	}
+}
+
+// blockFactory registers a test block
+type blockFactory struct {
+	block blocks.Block
+}
+
+func (f *blockFactory) CreateBlock() blocks.Block {
+	return f.block
+}
+
+func (f *blockFactory) GetBlockInfo() blocks.BlockInfo {
+	return blocks.BlockInfo{
+		Type:       f.block.GetType(),
+		Name:       f.block.GetName(),
+		Category:   "test",
+		BlockGroup: f.block.GetBlockGroup(),
+		Inputs:     f.block.GetInputs(),
+		Outputs:    f.block.GetOutputs(),
+	}
+}
+
+// testConfig is an engine configuration with short timeouts
+func testConfig() config.EngineConfig {
+	return config.EngineConfig{
+		DefaultTimeout:      5 * time.Second,
+		TransientRetries:    2,
+		MaxFlowNodes:        100,
+		MaxFlowConnections:  100,
+		StopHookTimeout:     time.Second,
+		BackpressureTimeout: time.Second,
+	}
+}
+
+// newTestEngine creates an engine over in-memory storage, registering the
+// given test blocks next to the built-in ones
+func newTestEngine(t *testing.T, cfg config.EngineConfig, testBlocks ...*testBlock) (*Engine, storage.Storage) {
+	t.Helper()
+	store := storage.NewMemoryStorage()
+	e := New(store, cfg, nopLogger{})
+	for _, block := range testBlocks {
+		if block.outputs == 0 && block.group != blocks.ActionGroup {
+			block.outputs = 1
+		}
+		e.registry.MustRegister(&blockFactory{block: block})
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		e.Shutdown(ctx)
+	})
+	return e, store
+}
+
+// chain builds a flow running the given node types one after another
+func chain(id string, nodes ...models.Node) *models.Flow {
+	flow := &models.Flow{ID: id, Name: id, Nodes: nodes}
+	for i := 1; i < len(nodes); i++ {
+		flow.Connections = append(flow.Connections, models.Connection{
+			ID:     nodes[i-1].ID + "-" + nodes[i].ID,
+			Source: nodes[i-1].ID,
+			Target: nodes[i].ID,
+		})
+	}
+	return flow
+}
+
+// startTestFlow saves and starts a flow
+func startTestFlow(t *testing.T, e *Engine, store storage.Storage, flow *models.Flow) {
+	t.Helper()
+	ctx := context.Background()
+	if err := store.SaveFlow(ctx, flow); err != nil {
+		t.Fatalf("save flow: %v", err)
+	}
+	if err := e.StartFlow(ctx, flow.ID); err != nil {
+		t.Fatalf("start flow: %v", err)
+	}
+}
+
+// eventually polls cond until it holds or the timeout passes
+func eventually(t *testing.T, timeout time.Duration, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return cond()
+}
@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// idleTracker counts messages in flight across a flow's nodes and remembers
+// the last time anything happened, so idle flows can be stopped
+type idleTracker struct {
+	inFlight     atomic.Int64
+	lastActivity atomic.Int64 // Unix nanoseconds
+}
+
+// touch records activity without changing the in-flight count
+func (t *idleTracker) touch() {
+	t.lastActivity.Store(time.Now().UnixNano())
+}
+
+// enqueued records a message handed to a node's input channel
+func (t *idleTracker) enqueued() {
+	t.inFlight.Add(1)
+	t.touch()
+}
+
+// dropped reverts enqueued for a message that couldn't be delivered
+func (t *idleTracker) dropped() {
+	t.inFlight.Add(-1)
+}
+
+// processed records that a node finished handling a message
+func (t *idleTracker) processed() {
+	t.inFlight.Add(-1)
+	t.touch()
+}
+
+// remaining returns how long until the flow counts as idle for timeout.
+// Messages in flight keep the full timeout
+func (t *idleTracker) remaining(timeout time.Duration) time.Duration {
+	if t.inFlight.Load() > 0 {
+		return timeout
+	}
+	elapsed := time.Since(time.Unix(0, t.lastActivity.Load()))
+	if elapsed >= timeout {
+		return 0
+	}
+	return timeout - elapsed
+}
+
+// parseIdleTimeout reads the flow's idle_timeout property (0 = run indefinitely)
+func parseIdleTimeout(properties map[string]string) (time.Duration, error) {
+	value, ok := properties["idle_timeout"]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("invalid idle_timeout '%s': must be a non-negative duration", value)
+	}
+	return timeout, nil
+}
+
+// watchIdle stops the flow once it has been idle for its idle timeout
+func (fe *FlowExecutor) watchIdle(flow *RuntimeFlow) {
+	interval := flow.IdleTimeout / 4
+	if interval > time.Second {
+		interval = time.Second
+	}
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-flow.StopChan:
+			return
+		case <-ticker.C:
+			if flow.idle.remaining(flow.IdleTimeout) > 0 {
+				continue
+			}
+
+			fe.logger.Info("Flow idle, stopping", map[string]interface{}{
+				"flow_id":      flow.ID,
+				"idle_timeout": flow.IdleTimeout.String(),
+			})
+			if err := fe.StopFlow(flow.ID); err != nil {
+				fe.logger.Debug("Idle stop skipped", map[string]interface{}{
+					"flow_id": flow.ID,
+					"error":   err.Error(),
+				})
+			}
+			return
+		}
+	}
+}
+
+// GetIdleRemaining returns how long a running flow may stay idle before it is
+// stopped. The boolean result is false when the flow has no idle timeout or
+// isn't running
+func (fe *FlowExecutor) GetIdleRemaining(flowID string) (time.Duration, bool, error) {
+	fe.mutex.RLock()
+	defer fe.mutex.RUnlock()
+
+	runtimeFlow, exists := fe.flows[flowID]
+	if !exists {
+		return 0, false, fmt.Errorf("flow '%s' not found", flowID)
+	}
+
+	runtimeFlow.mutex.RLock()
+	running := runtimeFlow.Running
+	runtimeFlow.mutex.RUnlock()
+	if !running || runtimeFlow.IdleTimeout == 0 {
+		return 0, false, nil
+	}
+
+	return runtimeFlow.idle.remaining(runtimeFlow.IdleTimeout), true, nil
+}
@@ -0,0 +1,42 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestIdleTimeoutStopsFlow(t *testing.T) {
+	handled := make(chan struct{}, 1)
+	input := &testBlock{typ: "test-input", group: blocks.InputGroup}
+	// The sink outlasts the idle timeout: messages in flight keep the flow up
+	sink := sinkBlock(func(*models.Message) {
+		time.Sleep(100 * time.Millisecond)
+		handled <- struct{}{}
+	})
+	e, store := newTestEngine(t, testConfig(), input, sink)
+
+	flow := chain("idle", models.Node{ID: "in", Type: "test-input"}, models.Node{ID: "out", Type: "test-sink"})
+	flow.Properties = map[string]string{"idle_timeout": "50ms"}
+	startTestFlow(t, e, store, flow)
+
+	if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatal("flow stopped before handling its input")
+	}
+
+	running := func() bool {
+		running, _ := e.executor.GetFlowStatus(flow.ID)
+		return running
+	}
+	if !eventually(t, time.Second, func() bool { return !running() }) {
+		t.Fatal("idle flow not stopped")
+	}
+}
@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// streamBlock is a streaming input block emitting a message every interval
+// until its flow stops
+type streamBlock struct {
+	*testBlock
+	interval time.Duration
+}
+
+func (b *streamBlock) Run(ctx *models.BlockExecutionContext, _ map[string]interface{}, emit func(*models.Message)) error {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Context.Done():
+			return nil
+		case <-ticker.C:
+			emit(models.NewMessage(1.0))
+		}
+	}
+}
+
+func TestPauseInputsDrainsPipeline(t *testing.T) {
+	var processed atomic.Int64
+	stream := &streamBlock{testBlock: &testBlock{typ: "test-stream", group: blocks.InputGroup, outputs: 1}, interval: 2 * time.Millisecond}
+	slow := &testBlock{
+		typ:   "test-slow",
+		group: blocks.PropagationGroup,
+		execute: func(ctx *models.BlockExecutionContext) ([]*models.Message, error) {
+			time.Sleep(5 * time.Millisecond)
+			return []*models.Message{ctx.Message.Clone()}, nil
+		},
+	}
+	e, store := newTestEngine(t, testConfig(), slow, sinkBlock(func(*models.Message) { processed.Add(1) }))
+	e.registry.MustRegister(&blockFactory{block: stream})
+
+	flow := chain("pausable",
+		models.Node{ID: "in", Type: "test-stream"},
+		models.Node{ID: "slow", Type: "test-slow"},
+		models.Node{ID: "out", Type: "test-sink"},
+	)
+	startTestFlow(t, e, store, flow)
+	runtimeFlow := e.executor.flows[flow.ID]
+	ctx := context.Background()
+
+	if !eventually(t, time.Second, func() bool { return processed.Load() > 0 }) {
+		t.Fatal("no message processed before pausing")
+	}
+	if err := e.PauseInputs(ctx, flow.ID); err != nil {
+		t.Fatalf("pause inputs: %v", err)
+	}
+
+	// Messages already in flight are still processed
+	if !eventually(t, 2*time.Second, func() bool { return runtimeFlow.idle.inFlight.Load() == 0 }) {
+		t.Fatalf("%d messages still in flight after pausing", runtimeFlow.idle.inFlight.Load())
+	}
+	drained := processed.Load()
+	time.Sleep(50 * time.Millisecond)
+	if processed.Load() != drained {
+		t.Errorf("%d messages processed while inputs were paused", processed.Load()-drained)
+	}
+	if paused, _ := e.executor.InputsPaused(flow.ID); !paused {
+		t.Error("inputs not reported paused")
+	}
+
+	if err := e.ResumeInputs(ctx, flow.ID); err != nil {
+		t.Fatalf("resume inputs: %v", err)
+	}
+	if !eventually(t, time.Second, func() bool { return processed.Load() > drained }) {
+		t.Error("no new message processed after resuming")
+	}
+}
+
+// counterBlock is a streaming input block emitting 0, 1, 2, ... every interval
+type counterBlock struct {
+	*testBlock
+	interval time.Duration
+}
+
+func (b *counterBlock) Run(ctx *models.BlockExecutionContext, _ map[string]interface{}, emit func(*models.Message)) error {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for n := 0.0; ; n++ {
+		select {
+		case <-ctx.Context.Done():
+			return nil
+		case <-ticker.C:
+			emit(models.NewMessage(n))
+		}
+	}
+}
+
+func TestStartPausedWithTap(t *testing.T) {
+	var processed atomic.Int64
+	counter := &counterBlock{testBlock: &testBlock{typ: "test-counter", group: blocks.InputGroup, outputs: 1}, interval: 2 * time.Millisecond}
+	e, store := newTestEngine(t, testConfig(), sinkBlock(func(*models.Message) { processed.Add(1) }))
+	e.registry.MustRegister(&blockFactory{block: counter})
+
+	flow := chain("paused", models.Node{ID: "in", Type: "test-counter"}, models.Node{ID: "out", Type: "test-sink"})
+	ctx := context.Background()
+	if err := store.SaveFlow(ctx, flow); err != nil {
+		t.Fatalf("save flow: %v", err)
+	}
+	if err := e.StartFlowPaused(ctx, flow.ID); err != nil {
+		t.Fatalf("start paused: %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if n := processed.Load(); n != 0 {
+		t.Fatalf("%d messages processed while started paused", n)
+	}
+
+	// Attach the tap before anything flows, then resume
+	if _, err := e.SetDebugRecording(flow.ID, true, time.Minute, 100, 1<<20); err != nil {
+		t.Fatalf("enable debug recording: %v", err)
+	}
+	if err := e.ResumeInputs(ctx, flow.ID); err != nil {
+		t.Fatalf("resume inputs: %v", err)
+	}
+
+	var outputs []models.ExecutionMessage
+	if !eventually(t, time.Second, func() bool {
+		_, recorded, _ := e.GetDebugMessages(flow.ID)
+		outputs = outputs[:0]
+		for _, entry := range recorded {
+			if entry.NodeID == "in" && entry.Type == "output" {
+				outputs = append(outputs, entry)
+			}
+		}
+		return len(outputs) >= 3
+	}) {
+		t.Fatalf("tap recorded %d input messages after resuming", len(outputs))
+	}
+	for i, entry := range outputs[:3] {
+		if entry.Message.Payload != float64(i) {
+			t.Errorf("tapped message %d has payload %v, want %d", i, entry.Message.Payload, i)
+		}
+	}
+}
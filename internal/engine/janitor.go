@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"context"
+	"time"
+)
+
+// janitor periodically purges flows that stayed in the trash longer than
+// the retention
+type janitor struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// StartJanitor starts purging trashed flows older than retention every
+// interval, until Shutdown. A non-positive retention or interval keeps
+// trashed flows until they are restored
+func (e *Engine) StartJanitor(retention, interval time.Duration) {
+	if retention <= 0 || interval <= 0 {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.janitor != nil {
+		return
+	}
+	j := &janitor{stop: make(chan struct{}), done: make(chan struct{})}
+	e.janitor = j
+
+	go func() {
+		defer close(j.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			e.PurgeTrash(context.Background(), retention)
+			select {
+			case <-j.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stopJanitor stops the janitor, if running, and waits for its current pass
+func (e *Engine) stopJanitor() {
+	e.mu.Lock()
+	j := e.janitor
+	e.janitor = nil
+	e.mu.Unlock()
+
+	if j != nil {
+		close(j.stop)
+		<-j.done
+	}
+}
+
+// PurgeTrash permanently deletes the flows trashed more than retention ago,
+// returning their IDs
+func (e *Engine) PurgeTrash(ctx context.Context, retention time.Duration) ([]string, error) {
+	purged, err := e.storage.PurgeTrash(ctx, time.Now().Add(-retention))
+	if err != nil {
+		e.logger.Warn("Failed to purge trash", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	if len(purged) > 0 {
+		e.logger.Info("Purged trashed flows", map[string]interface{}{
+			"flows": purged,
+		})
+	}
+	return purged, err
+}
@@ -0,0 +1,36 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+func TestJanitorPurgesTrash(t *testing.T) {
+	e, store := newTestEngine(t, testConfig())
+	ctx := context.Background()
+	if err := store.SaveFlow(ctx, &models.Flow{ID: "deleted", Name: "deleted"}); err != nil {
+		t.Fatalf("save flow: %v", err)
+	}
+	if err := store.TrashFlow(ctx, "deleted"); err != nil {
+		t.Fatalf("trash flow: %v", err)
+	}
+
+	e.StartJanitor(100*time.Millisecond, 10*time.Millisecond)
+
+	// Kept within the retention
+	time.Sleep(30 * time.Millisecond)
+	if trashed, _ := store.ListTrash(ctx); len(trashed) != 1 {
+		t.Fatalf("trash holds %d flows within the retention, want 1", len(trashed))
+	}
+
+	purged := eventually(t, time.Second, func() bool {
+		trashed, _ := store.ListTrash(ctx)
+		return len(trashed) == 0
+	})
+	if !purged {
+		t.Error("trashed flow not purged after the retention")
+	}
+}
@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// runStopHooks fires the flow's on-stop nodes and waits until their messages
+// have been handled downstream, or StopHookTimeout has passed since they were
+// fired. The flow keeps running meanwhile; StopFlow stops its nodes
+// afterwards, which also ends hooks still executing or blocked delivering
+func (fe *FlowExecutor) runStopHooks(flow *RuntimeFlow) {
+	hooks := make([]*RuntimeNode, 0)
+	for _, node := range flow.Nodes {
+		if lifecycle, ok := node.Block.(blocks.LifecycleBlock); ok && lifecycle.LifecycleEvent() == blocks.LifecycleStop {
+			hooks = append(hooks, node)
+		}
+	}
+	if len(hooks) == 0 {
+		return
+	}
+
+	timer := time.NewTimer(fe.config.StopHookTimeout)
+	defer timer.Stop()
+
+	// A single delivery spans all hooks: done once every derived message is handled
+	done := make(chan struct{})
+	delivery := models.NewDelivery(func(err error) {
+		if err != nil {
+			fe.logger.Warn("Stop hook messages failed", map[string]interface{}{
+				"flow_id": flow.ID,
+				"error":   err.Error(),
+			})
+		}
+		close(done)
+	})
+
+	// Fired in the background so that neither a slow hook nor a full
+	// downstream buffer holds StopFlow past the timeout
+	delivery.Add()
+	flow.WaitGroup.Add(1)
+	go func() {
+		defer flow.WaitGroup.Done()
+		defer delivery.Done()
+		for _, node := range hooks {
+			messages, err := fe.executeBlock(node, flow, nil)
+			if err != nil {
+				fe.handleExecutionError(node, flow, nil, err)
+				continue
+			}
+			for _, msg := range messages {
+				msg.Delivery = delivery
+			}
+			fe.emitMessages(node, flow, nil, messages)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		fe.logger.Warn("Stop hooks did not finish in time", map[string]interface{}{
+			"flow_id": flow.ID,
+			"timeout": fe.config.StopHookTimeout.String(),
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// stopHookBlock is an on-stop input block
+type stopHookBlock struct {
+	*testBlock
+}
+
+func (b *stopHookBlock) LifecycleEvent() blocks.LifecycleEvent { return blocks.LifecycleStop }
+
+func TestStopHooksBoundedByTimeout(t *testing.T) {
+	const hookTimeout = 50 * time.Millisecond
+
+	tests := []struct {
+		name        string
+		hold        time.Duration // The hook's execution time
+		messages    int           // Messages the hook emits
+		handle      time.Duration // The sink's time per message
+		wantHandled bool          // Every message is handled before the nodes stop
+	}{
+		{name: "fast hook", messages: 1, wantHandled: true},
+		{name: "slow hook", hold: time.Second, messages: 1},
+		// More messages than the sink's input buffer holds, blocking delivery
+		{name: "full downstream buffer", messages: 150, handle: 10 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := &stopHookBlock{&testBlock{
+				typ:     "test-stop-hook",
+				group:   blocks.InputGroup,
+				outputs: 1,
+				execute: func(ctx *models.BlockExecutionContext) ([]*models.Message, error) {
+					select {
+					case <-time.After(tt.hold):
+					case <-ctx.Context.Done():
+					}
+					messages := make([]*models.Message, tt.messages)
+					for i := range messages {
+						messages[i] = models.NewMessage(float64(i))
+					}
+					return messages, nil
+				},
+			}}
+			var handled atomic.Int32
+			sink := sinkBlock(func(*models.Message) {
+				time.Sleep(tt.handle)
+				handled.Add(1)
+			})
+
+			cfg := testConfig()
+			cfg.StopHookTimeout = hookTimeout
+			e, store := newTestEngine(t, cfg, sink)
+			e.registry.MustRegister(&blockFactory{block: hook})
+
+			flow := chain("stop-hooks",
+				models.Node{ID: "hook", Type: "test-stop-hook"},
+				models.Node{ID: "out", Type: "test-sink"},
+			)
+			startTestFlow(t, e, store, flow)
+
+			started := time.Now()
+			if err := e.StopFlow(context.Background(), flow.ID); err != nil {
+				t.Fatalf("stop flow: %v", err)
+			}
+			if elapsed := time.Since(started); elapsed > 4*hookTimeout {
+				t.Errorf("stop took %s with a %s stop hook timeout", elapsed, hookTimeout)
+			}
+			if got := int(handled.Load()) == tt.messages; got != tt.wantHandled {
+				t.Errorf("handled %d of %d hook messages", handled.Load(), tt.messages)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// inputState returns the runtime state of a flow's "in" node
+func inputState(t *testing.T, e *Engine, flowID string) models.NodeState {
+	t.Helper()
+	states, err := e.executor.GetNodeStates(flowID)
+	if err != nil {
+		t.Fatalf("node states: %v", err)
+	}
+	return states["in"]
+}
+
+func TestInputNextFire(t *testing.T) {
+	input := &testBlock{typ: "test-input", group: blocks.InputGroup}
+	e, store := newTestEngine(t, testConfig(), input, sinkBlock(nil))
+
+	// Without an interval property the input fires every second
+	flow := chain("next-fire", models.Node{ID: "in", Type: "test-input"}, models.Node{ID: "out", Type: "test-sink"})
+	started := time.Now()
+	startTestFlow(t, e, store, flow)
+
+	var state models.NodeState
+	if !eventually(t, time.Second, func() bool {
+		state = inputState(t, e, flow.ID)
+		return state.NextFireAt != nil
+	}) {
+		t.Fatal("next fire not published for a scheduled input")
+	}
+	if next := *state.NextFireAt; next.Before(started.Add(time.Second)) || next.After(time.Now().Add(time.Second)) {
+		t.Errorf("next fire at %v, want a second after the start at %v", next, started)
+	}
+	if state.LastEmitAt != nil {
+		t.Errorf("last emit at %v before the input fired", state.LastEmitAt)
+	}
+
+	triggered := time.Now()
+	if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	state = inputState(t, e, flow.ID)
+	if state.LastEmitAt == nil || state.LastEmitAt.Before(triggered) {
+		t.Errorf("last emit at %v, want the trigger at %v", state.LastEmitAt, triggered)
+	}
+}
+
+func TestInputNextFireManual(t *testing.T) {
+	input := &testBlock{typ: "test-input", group: blocks.InputGroup}
+	e, store := newTestEngine(t, testConfig(), input, sinkBlock(nil))
+
+	scheduled := chain("scheduled", models.Node{ID: "in", Type: "test-input", Properties: map[string]interface{}{"interval": 20}}, models.Node{ID: "out", Type: "test-sink"})
+	manual := chain("manual", models.Node{ID: "in", Type: "test-input", Properties: map[string]interface{}{"interval": 0}}, models.Node{ID: "out", Type: "test-sink"})
+	startTestFlow(t, e, store, scheduled)
+	startTestFlow(t, e, store, manual)
+
+	// The scheduled input fires on its own, publishing the next fire each time
+	if !eventually(t, time.Second, func() bool { return inputState(t, e, scheduled.ID).LastEmitAt != nil }) {
+		t.Fatal("scheduled input never fired")
+	}
+	if state := inputState(t, e, scheduled.ID); state.NextFireAt == nil || state.NextFireAt.After(time.Now().Add(20*time.Millisecond)) {
+		t.Errorf("next fire at %v, want within the 20ms interval", state.NextFireAt)
+	}
+
+	// A manual-only input has no next fire, before or after a trigger
+	time.Sleep(50 * time.Millisecond)
+	if state := inputState(t, e, manual.ID); state.NextFireAt != nil || state.LastEmitAt != nil {
+		t.Errorf("untriggered manual input has next fire %v and last emit %v, want neither", state.NextFireAt, state.LastEmitAt)
+	}
+	if _, err := e.TriggerNode(context.Background(), manual.ID, "in"); err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	if state := inputState(t, e, manual.ID); state.NextFireAt != nil || state.LastEmitAt == nil {
+		t.Errorf("triggered manual input has next fire %v and last emit %v, want only a last emit", state.NextFireAt, state.LastEmitAt)
+	}
+}
@@ -0,0 +1,197 @@
+package engine
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// panicStreamBlock is a streaming input block whose Run panics
+type panicStreamBlock struct {
+	*testBlock
+}
+
+func (b *panicStreamBlock) Run(*models.BlockExecutionContext, map[string]interface{}, func(*models.Message)) error {
+	panic("run failed")
+}
+
+// panicTickBlock is a propagation block whose Tick panics
+type panicTickBlock struct {
+	*testBlock
+}
+
+func (b *panicTickBlock) TickInterval(map[string]interface{}) time.Duration {
+	return 5 * time.Millisecond
+}
+
+func (b *panicTickBlock) Tick(*models.BlockExecutionContext, map[string]interface{}) ([]*models.Message, error) {
+	panic("tick failed")
+}
+
+func TestPanicsAreRecovered(t *testing.T) {
+	panicking := func(*models.BlockExecutionContext) ([]*models.Message, error) {
+		panic("execute failed")
+	}
+
+	tests := []struct {
+		name    string
+		block   blocks.Block
+		input   string // Type of the input node feeding the panicking node, if any
+		panics  int    // Panics that exhaust the restart policy
+		trigger bool
+	}{
+		{
+			name:    "execute panics on every message",
+			block:   &testBlock{typ: "test-panic", group: blocks.PropagationGroup, outputs: 1, execute: panicking},
+			input:   "test-input",
+			panics:  3,
+			trigger: true,
+		},
+		{
+			name:   "tick panics",
+			block:  &panicTickBlock{&testBlock{typ: "test-panic", group: blocks.PropagationGroup, outputs: 1}},
+			input:  "test-input",
+			panics: 3,
+		},
+		{
+			name:   "run panics",
+			block:  &panicStreamBlock{&testBlock{typ: "test-panic", group: blocks.InputGroup, outputs: 1}},
+			input:  "",
+			panics: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, store := newTestEngine(t, testConfig(),
+				&testBlock{typ: "test-input", group: blocks.InputGroup},
+				sinkBlock(nil),
+			)
+			e.registry.MustRegister(&blockFactory{block: tt.block})
+
+			nodes := []models.Node{{ID: "panic", Type: "test-panic"}, {ID: "out", Type: "test-sink"}}
+			if tt.input != "" {
+				nodes = append([]models.Node{{ID: "in", Type: tt.input}}, nodes...)
+			}
+			flow := chain("panics", nodes...)
+			flow.Properties = map[string]string{
+				"restart_policy":       RestartOnPanic,
+				"restart_after_panics": strconv.Itoa(tt.panics),
+				"max_restarts":         "0",
+			}
+			startTestFlow(t, e, store, flow)
+			runtimeFlow := e.executor.flows[flow.ID]
+
+			if tt.trigger {
+				for i := 0; i < tt.panics; i++ {
+					e.TriggerNode(context.Background(), flow.ID, "in")
+				}
+			}
+
+			unhealthy := eventually(t, 2*time.Second, func() bool {
+				_, unhealthy, _ := e.executor.GetFlowHealth(flow.ID)
+				return unhealthy
+			})
+			if !unhealthy {
+				t.Fatal("flow not stopped by its restart policy after repeated panics")
+			}
+
+			node := runtimeFlow.Nodes["panic"]
+			node.stateMu.Lock()
+			panics := node.State.Panics
+			node.stateMu.Unlock()
+			if panics < tt.panics {
+				t.Errorf("recorded %d panics, want at least %d", panics, tt.panics)
+			}
+		})
+	}
+}
+
+// panicOnceBlock is a streaming input block whose first Run panics; later
+// runs wait for the flow to stop
+type panicOnceBlock struct {
+	*testBlock
+	runs atomic.Int32
+}
+
+func (b *panicOnceBlock) Run(ctx *models.BlockExecutionContext, _ map[string]interface{}, _ func(*models.Message)) error {
+	if b.runs.Add(1) == 1 {
+		panic("run failed")
+	}
+	<-ctx.Context.Done()
+	return nil
+}
+
+func TestRestartAfterBackoff(t *testing.T) {
+	tests := []struct {
+		name         string
+		during       func(e *Engine, flowID string) // Runs while the restart is backing off
+		wantRestarts int
+		wantRunning  bool
+	}{
+		{
+			name:         "restarted once the backoff passed",
+			during:       func(*Engine, string) {},
+			wantRestarts: 1,
+			wantRunning:  true,
+		},
+		{
+			name: "flow started manually is not replaced",
+			during: func(e *Engine, flowID string) {
+				if err := e.StartFlow(context.Background(), flowID); err != nil {
+					t.Errorf("start flow: %v", err)
+				}
+			},
+			wantRestarts: 0,
+			wantRunning:  true,
+		},
+		{
+			name: "shutdown cancels the restart",
+			during: func(e *Engine, _ string) {
+				e.executor.StopAllFlows(context.Background())
+			},
+			wantRestarts: 0,
+			wantRunning:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := &panicOnceBlock{testBlock: &testBlock{typ: "test-panic", group: blocks.InputGroup, outputs: 1}}
+			e, store := newTestEngine(t, testConfig(), sinkBlock(nil))
+			e.registry.MustRegister(&blockFactory{block: block})
+
+			flow := chain("restart", models.Node{ID: "panic", Type: "test-panic"}, models.Node{ID: "out", Type: "test-sink"})
+			flow.Properties = map[string]string{
+				"restart_policy":       RestartOnPanic,
+				"restart_after_panics": "1",
+				"restart_backoff":      "200ms",
+			}
+			startTestFlow(t, e, store, flow)
+
+			stopped := eventually(t, time.Second, func() bool {
+				running, _ := e.executor.GetFlowStatus(flow.ID)
+				return !running
+			})
+			if !stopped {
+				t.Fatal("flow not stopped for a restart after the panic")
+			}
+			tt.during(e, flow.ID)
+
+			time.Sleep(400 * time.Millisecond)
+			restarts, _, _ := e.executor.GetFlowHealth(flow.ID)
+			if restarts != tt.wantRestarts {
+				t.Errorf("restarts = %d, want %d", restarts, tt.wantRestarts)
+			}
+			running, _ := e.executor.GetFlowStatus(flow.ID)
+			if running != tt.wantRunning {
+				t.Errorf("running = %v, want %v", running, tt.wantRunning)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestPayloadSizeGuard(t *testing.T) {
+	tests := []struct {
+		name          string
+		engineLimit   int
+		flowLimit     string // Flow's max_payload_size property
+		payload       int    // Characters in the emitted string payload
+		wantDelivered bool
+		wantErr       bool // Invalid flow property
+	}{
+		{name: "unlimited", payload: 10000, wantDelivered: true},
+		{name: "under the engine limit", engineLimit: 100, payload: 50, wantDelivered: true},
+		{name: "over the engine limit", engineLimit: 100, payload: 200},
+		{name: "flow raises the limit", engineLimit: 100, flowLimit: "1000", payload: 200, wantDelivered: true},
+		{name: "flow lowers the limit", flowLimit: "100", payload: 200},
+		{name: "flow lifts the limit", engineLimit: 100, flowLimit: "0", payload: 200, wantDelivered: true},
+		{name: "invalid flow limit", flowLimit: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.MaxPayloadSize = tt.engineLimit
+			var delivered atomic.Int32
+			e, store := newTestEngine(t, cfg,
+				&testBlock{typ: "test-input", group: blocks.InputGroup},
+				&testBlock{
+					typ:   "test-big",
+					group: blocks.PropagationGroup,
+					execute: func(*models.BlockExecutionContext) ([]*models.Message, error) {
+						return []*models.Message{models.NewMessage(strings.Repeat("x", tt.payload))}, nil
+					},
+				},
+				sinkBlock(func(*models.Message) { delivered.Add(1) }),
+			)
+
+			flow := chain("payload-size",
+				models.Node{ID: "in", Type: "test-input"},
+				models.Node{ID: "big", Type: "test-big"},
+				models.Node{ID: "out", Type: "test-sink"},
+			)
+			if tt.flowLimit != "" {
+				flow.Properties = map[string]string{"max_payload_size": tt.flowLimit}
+			}
+			if tt.wantErr {
+				if _, err := e.executor.PrepareFlow(flow); err == nil {
+					t.Error("flow with an invalid max_payload_size prepared")
+				}
+				return
+			}
+			startTestFlow(t, e, store, flow)
+
+			if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+				t.Fatalf("trigger: %v", err)
+			}
+			node := e.executor.flows[flow.ID].Nodes["big"]
+			oversized := func() int {
+				node.stateMu.Lock()
+				defer node.stateMu.Unlock()
+				return node.State.OversizedDropped
+			}
+			eventually(t, time.Second, func() bool { return delivered.Load() > 0 || oversized() > 0 })
+
+			if got := delivered.Load() == 1; got != tt.wantDelivered {
+				t.Errorf("delivered = %v, want %v", got, tt.wantDelivered)
+			}
+			wantOversized := 0
+			if !tt.wantDelivered {
+				wantOversized = 1
+			}
+			if got := oversized(); got != wantOversized {
+				t.Errorf("oversized dropped = %d, want %d", got, wantOversized)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// writeManifest writes a remote block manifest into the plugins directory
+func writeManifest(t *testing.T, dir, blockType string) {
+	t.Helper()
+	manifest := fmt.Sprintf(`{"type":%q,"endpoint":"http://127.0.0.1:1/execute"}`, blockType)
+	if err := os.WriteFile(filepath.Join(dir, blockType+".block.json"), []byte(manifest), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+}
+
+func TestReloadPlugins(t *testing.T) {
+	tests := []struct {
+		name       string
+		before     []string // Manifests present at the initial load
+		after      []string // Manifests present at the reload
+		wantAdded  []string
+		wantFailed int
+		wantTypes  map[string]bool // Registration state of block types after the reload
+	}{
+		{
+			name:      "new plugin",
+			after:     []string{"remote-a"},
+			wantAdded: []string{"remote-a"},
+			wantTypes: map[string]bool{"remote-a": true},
+		},
+		{
+			name:      "unchanged plugin",
+			before:    []string{"remote-a"},
+			after:     []string{"remote-a"},
+			wantAdded: []string{},
+			wantTypes: map[string]bool{"remote-a": true},
+		},
+		{
+			name:      "removed plugin",
+			before:    []string{"remote-a", "remote-b"},
+			after:     []string{"remote-b"},
+			wantAdded: []string{},
+			wantTypes: map[string]bool{"remote-a": false, "remote-b": true},
+		},
+		{
+			name:       "collision with a built-in",
+			after:      []string{"debug"},
+			wantAdded:  []string{},
+			wantFailed: 1,
+			wantTypes:  map[string]bool{"debug": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _ := newTestEngine(t, testConfig())
+			dir := t.TempDir()
+			for _, blockType := range tt.before {
+				writeManifest(t, dir, blockType)
+			}
+			if _, err := e.LoadPlugins(dir); err != nil {
+				t.Fatalf("load plugins: %v", err)
+			}
+
+			entries, _ := os.ReadDir(dir)
+			for _, entry := range entries {
+				os.Remove(filepath.Join(dir, entry.Name()))
+			}
+			for _, blockType := range tt.after {
+				writeManifest(t, dir, blockType)
+			}
+
+			results, added, err := e.ReloadPlugins()
+			if err != nil {
+				t.Fatalf("reload plugins: %v", err)
+			}
+			if !reflect.DeepEqual(added, tt.wantAdded) {
+				t.Errorf("added = %v, want %v", added, tt.wantAdded)
+			}
+			failed := 0
+			for _, result := range results {
+				if result.Error != "" {
+					failed++
+				}
+			}
+			if failed != tt.wantFailed {
+				t.Errorf("failed = %d, want %d", failed, tt.wantFailed)
+			}
+			for blockType, want := range tt.wantTypes {
+				if _, err := e.registry.GetBlockInfoByType(blockType); (err == nil) != want {
+					t.Errorf("block type %s registered = %v, want %v", blockType, err == nil, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReloadPluginsConcurrently(t *testing.T) {
+	e, _ := newTestEngine(t, testConfig())
+	dir := t.TempDir()
+	writeManifest(t, dir, "remote-a")
+	if _, err := e.LoadPlugins(dir); err != nil {
+		t.Fatalf("load plugins: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if _, _, err := e.ReloadPlugins(); err != nil {
+					t.Errorf("reload plugins: %v", err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				e.registry.CreateBlock("remote-a")
+				e.registry.GetBlockInfo()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, err := e.registry.CreateBlock("remote-a"); err != nil {
+		t.Errorf("plugin block lost after concurrent reloads: %v", err)
+	}
+}
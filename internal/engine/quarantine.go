@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"block-flow/internal/models"
+)
+
+// UnavailableNode is a node whose block type is not registered
+type UnavailableNode struct {
+	NodeID string `json:"node_id"`
+	Type   string `json:"type"`
+}
+
+// UnavailableBlocksError reports the nodes of a flow whose block types are no
+// longer registered, e.g. after their plugin was unloaded
+type UnavailableBlocksError struct {
+	FlowID string
+	Nodes  []UnavailableNode
+}
+
+func (e *UnavailableBlocksError) Error() string {
+	nodes := make([]string, len(e.Nodes))
+	for i, node := range e.Nodes {
+		nodes[i] = fmt.Sprintf("node '%s' (type '%s')", node.NodeID, node.Type)
+	}
+	return "block type no longer available: " + strings.Join(nodes, ", ")
+}
+
+// unavailableBlocks lists every node of a flow with an unregistered block
+// type, or returns nil if all types are available
+func (fe *FlowExecutor) unavailableBlocks(flow *models.Flow) *UnavailableBlocksError {
+	var nodes []UnavailableNode
+	for _, node := range flow.Nodes {
+		if _, err := fe.registry.GetBlockInfoByType(node.Type); err != nil {
+			nodes = append(nodes, UnavailableNode{NodeID: node.ID, Type: node.Type})
+		}
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	return &UnavailableBlocksError{FlowID: flow.ID, Nodes: nodes}
+}
+
+// quarantineFlow marks a stored flow as unable to start until its missing
+// block types are available again. Quarantined flows stay readable and
+// editable so they can be fixed
+func (e *Engine) quarantineFlow(err *UnavailableBlocksError) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.quarantined == nil {
+		e.quarantined = make(map[string][]UnavailableNode)
+	}
+	e.quarantined[err.FlowID] = err.Nodes
+}
+
+// ReleaseFlow lifts the quarantine of a flow, e.g. once it is deleted
+func (e *Engine) ReleaseFlow(flowID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.quarantined, flowID)
+}
+
+// QuarantinedFlows returns the IDs of quarantined flows, sorted
+func (e *Engine) QuarantinedFlows() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	ids := make([]string, 0, len(e.quarantined))
+	for id := range e.quarantined {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// isQuarantined reports whether a flow is quarantined
+func (e *Engine) isQuarantined(flowID string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	_, quarantined := e.quarantined[flowID]
+	return quarantined
+}
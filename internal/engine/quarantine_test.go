@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestQuarantineUnknownBlockType(t *testing.T) {
+	ctx := context.Background()
+	input := &testBlock{typ: "test-input", group: blocks.InputGroup}
+	e, store := newTestEngine(t, testConfig(), input, sinkBlock(nil))
+
+	healthy := chain("healthy", models.Node{ID: "in", Type: "test-input"}, models.Node{ID: "out", Type: "test-sink"})
+	broken := chain("broken", models.Node{ID: "in", Type: "test-input"}, models.Node{ID: "gone", Type: "test-gone"})
+	for _, flow := range []*models.Flow{broken, healthy} {
+		flow.Active = true
+		if err := store.SaveFlow(ctx, flow); err != nil {
+			t.Fatalf("save flow: %v", err)
+		}
+	}
+
+	if err := e.LoadAndStartFlows(ctx); err != nil {
+		t.Fatalf("LoadAndStartFlows: %v", err)
+	}
+	if running, _ := e.executor.GetFlowStatus(healthy.ID); !running {
+		t.Error("healthy flow not started next to the broken one")
+	}
+	if running, _ := e.executor.GetFlowStatus(broken.ID); running {
+		t.Error("flow with an unknown block type started")
+	}
+	if got := e.QuarantinedFlows(); !reflect.DeepEqual(got, []string{broken.ID}) {
+		t.Errorf("quarantined flows = %v, want [%s]", got, broken.ID)
+	}
+
+	err := e.StartFlow(ctx, broken.ID)
+	var unavailable *UnavailableBlocksError
+	if !errors.As(err, &unavailable) {
+		t.Fatalf("StartFlow = %v, want an UnavailableBlocksError", err)
+	}
+	if want := []UnavailableNode{{NodeID: "gone", Type: "test-gone"}}; !reflect.DeepEqual(unavailable.Nodes, want) {
+		t.Errorf("unavailable nodes = %v, want %v", unavailable.Nodes, want)
+	}
+
+	// Registering the missing type lifts the quarantine on the next start
+	e.registry.MustRegister(&blockFactory{block: &testBlock{typ: "test-gone", group: blocks.ActionGroup}})
+	if err := e.StartFlow(ctx, broken.ID); err != nil {
+		t.Fatalf("start once the block type is available: %v", err)
+	}
+	if got := e.QuarantinedFlows(); len(got) != 0 {
+		t.Errorf("quarantined flows after a successful start = %v, want none", got)
+	}
+}
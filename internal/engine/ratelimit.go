@@ -0,0 +1,176 @@
+package engine
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+// connectionQueueSize is how many messages a delaying connection holds for
+// their slot before its source node waits, like a node's input buffer
+const connectionQueueSize = 100
+
+// connectionLimiter spaces deliveries along a connection at least interval
+// apart. With the delay policy messages wait for their slot in the
+// connection's queue, delivered by runConnectionQueue, so a rate-limited
+// connection holds neither its source node nor its other connections
+type connectionLimiter struct {
+	conn     models.Connection
+	interval time.Duration
+	dropping bool
+
+	next time.Time // Earliest time of the next delivery
+	mu   sync.Mutex
+
+	queue   chan *models.Message // Delay policy only
+	closed  bool                 // Set once the queue stopped; guarded by queueMu
+	queueMu sync.RWMutex
+}
+
+// newConnectionLimiter returns a limiter for conn, or nil if it has no
+// minimum interval
+func newConnectionLimiter(conn models.Connection) *connectionLimiter {
+	if conn.MinInterval <= 0 {
+		return nil
+	}
+	limiter := &connectionLimiter{
+		conn:     conn,
+		interval: time.Duration(conn.MinInterval) * time.Millisecond,
+		dropping: conn.RatePolicy == models.RatePolicyDrop,
+	}
+	if !limiter.dropping {
+		limiter.queue = make(chan *models.Message, connectionQueueSize)
+	}
+	return limiter
+}
+
+// connectionKey identifies a connection among its source node's outputs
+func connectionKey(conn models.Connection) string {
+	return fmt.Sprintf("%d>%s:%d", conn.SourcePort, conn.Target, conn.TargetPort)
+}
+
+// reserve claims the next delivery slot. It returns how long the caller must
+// wait before delivering, or false if the message should be dropped
+func (l *connectionLimiter) reserve() (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(l.next) {
+		if l.dropping {
+			return 0, false
+		}
+		wait := l.next.Sub(now)
+		l.next = l.next.Add(l.interval)
+		return wait, true
+	}
+
+	l.next = now.Add(l.interval)
+	return 0, true
+}
+
+// enqueue hands a message to the connection's queue, waiting while it is
+// full. It reports false if the queue or the flow stopped first
+func (l *connectionLimiter) enqueue(msg *models.Message, stop, sourceStop <-chan struct{}) bool {
+	l.queueMu.RLock()
+	defer l.queueMu.RUnlock()
+
+	if l.closed {
+		return false
+	}
+	select {
+	case l.queue <- msg:
+		return true
+	case <-stop:
+		return false
+	case <-sourceStop:
+		return false
+	}
+}
+
+// close stops the queue and returns the messages still waiting in it
+func (l *connectionLimiter) close() []*models.Message {
+	l.queueMu.Lock()
+	l.closed = true
+	l.queueMu.Unlock()
+
+	remaining := make([]*models.Message, 0, len(l.queue))
+	for {
+		select {
+		case msg := <-l.queue:
+			remaining = append(remaining, msg)
+		default:
+			return remaining
+		}
+	}
+}
+
+// deliverLimited sends a message along a rate-limited connection: the drop
+// policy drops it if the connection's slot isn't free yet, the delay policy
+// queues it for runConnectionQueue. Queued messages count as in flight
+func (fe *FlowExecutor) deliverLimited(sourceNode, targetNode *RuntimeNode, limiter *connectionLimiter, msg *models.Message, flow *RuntimeFlow) {
+	if limiter.dropping {
+		if _, ok := limiter.reserve(); !ok {
+			fe.logger.Debug("Connection rate limit reached, dropping message", map[string]interface{}{
+				"source_node": sourceNode.ID,
+				"target_node": targetNode.ID,
+			})
+			return
+		}
+		fe.deliver(sourceNode, targetNode, msg, flow)
+		return
+	}
+
+	flow.messageEnqueued(msg)
+	if !limiter.enqueue(msg, flow.StopChan, sourceNode.StopChan) {
+		flow.messageDropped(msg)
+	}
+}
+
+// runConnectionQueue delivers the messages queued on a delaying connection,
+// each once the connection's next slot comes. Messages still queued when the
+// flow stops are rejected
+func (fe *FlowExecutor) runConnectionQueue(sourceNode, targetNode *RuntimeNode, limiter *connectionLimiter, flow *RuntimeFlow) {
+	defer flow.WaitGroup.Done()
+	defer func() {
+		for _, msg := range limiter.close() {
+			rejectMessage(msg, errStopping)
+			flow.messageProcessed(msg)
+		}
+	}()
+
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		var msg *models.Message
+		select {
+		case <-flow.StopChan:
+			return
+		case msg = <-limiter.queue:
+		}
+
+		if wait, _ := limiter.reserve(); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-flow.StopChan:
+				rejectMessage(msg, errStopping)
+				flow.messageProcessed(msg)
+				return
+			case <-timer.C:
+			}
+		}
+
+		if fe.deliver(sourceNode, targetNode, msg, flow) {
+			fe.flowLog(flow).Debug("Message sent", map[string]interface{}{
+				"from":    sourceNode.ID,
+				"to":      targetNode.ID,
+				"payload": msg.Payload,
+			})
+		}
+		flow.messageProcessed(msg)
+	}
+}
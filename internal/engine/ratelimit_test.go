@@ -0,0 +1,159 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// arrivalSink is an action block recording when each node received messages
+type arrivalSink struct {
+	arrivals map[string][]time.Time
+	mu       sync.Mutex
+}
+
+func (s *arrivalSink) block() *testBlock {
+	return &testBlock{
+		typ:   "test-sink",
+		group: blocks.ActionGroup,
+		execute: func(ctx *models.BlockExecutionContext) ([]*models.Message, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.arrivals[ctx.NodeID] = append(s.arrivals[ctx.NodeID], time.Now())
+			return nil, nil
+		},
+	}
+}
+
+func (s *arrivalSink) count(nodeID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.arrivals[nodeID])
+}
+
+func TestConnectionRateLimit(t *testing.T) {
+	const (
+		messages = 5
+		interval = 40 * time.Millisecond
+	)
+
+	tests := []struct {
+		name        string
+		policy      string
+		wantLimited int
+	}{
+		{name: "delay queues on the connection", policy: models.RatePolicyDelay, wantLimited: messages},
+		{name: "default policy delays", policy: "", wantLimited: messages},
+		{name: "drop", policy: models.RatePolicyDrop, wantLimited: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &arrivalSink{arrivals: make(map[string][]time.Time)}
+			e, store := newTestEngine(t, testConfig(),
+				&testBlock{typ: "test-input", group: blocks.InputGroup},
+				sink.block(),
+			)
+
+			flow := &models.Flow{
+				ID:   "rate-limit",
+				Name: "rate-limit",
+				Nodes: []models.Node{
+					{ID: "in", Type: "test-input"},
+					{ID: "limited", Type: "test-sink"},
+					{ID: "free", Type: "test-sink"},
+				},
+				Connections: []models.Connection{
+					{ID: "c1", Source: "in", Target: "limited", MinInterval: int(interval / time.Millisecond), RatePolicy: tt.policy},
+					{ID: "c2", Source: "in", Target: "free"},
+				},
+			}
+			startTestFlow(t, e, store, flow)
+
+			started := time.Now()
+			for i := 0; i < messages; i++ {
+				if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+					t.Fatalf("trigger: %v", err)
+				}
+			}
+			if elapsed := time.Since(started); elapsed >= interval {
+				t.Errorf("source node held for %s by a rate-limited connection", elapsed)
+			}
+			if !eventually(t, interval/2, func() bool { return sink.count("free") == messages }) {
+				t.Errorf("unlimited connection delivered %d of %d messages before the next slot", sink.count("free"), messages)
+			}
+
+			eventually(t, time.Duration(messages+2)*interval, func() bool { return sink.count("limited") == tt.wantLimited })
+			time.Sleep(interval)
+			if got := sink.count("limited"); got != tt.wantLimited {
+				t.Fatalf("limited connection delivered %d messages, want %d", got, tt.wantLimited)
+			}
+
+			sink.mu.Lock()
+			defer sink.mu.Unlock()
+			arrivals := sink.arrivals["limited"]
+			for i := 1; i < len(arrivals); i++ {
+				// Allow for timer jitter
+				if gap := arrivals[i].Sub(arrivals[i-1]); gap < interval*3/4 {
+					t.Errorf("deliveries %d and %d only %s apart", i-1, i, gap)
+				}
+			}
+		})
+	}
+}
+
+func TestConnectionQueueRejectedOnStop(t *testing.T) {
+	var mu sync.Mutex
+	results := make([]error, 0)
+	input := &testBlock{
+		typ:   "test-input",
+		group: blocks.InputGroup,
+		execute: func(ctx *models.BlockExecutionContext) ([]*models.Message, error) {
+			msg := models.NewMessage(1.0)
+			msg.Delivery = models.NewDelivery(func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				results = append(results, err)
+			})
+			return []*models.Message{msg}, nil
+		},
+	}
+	var handled atomic.Int32
+	e, store := newTestEngine(t, testConfig(), input, sinkBlock(func(*models.Message) { handled.Add(1) }))
+
+	flow := chain("rate-limit-stop", models.Node{ID: "in", Type: "test-input"}, models.Node{ID: "out", Type: "test-sink"})
+	flow.Connections[0].MinInterval = 10000
+	startTestFlow(t, e, store, flow)
+
+	for i := 0; i < 3; i++ {
+		e.TriggerNode(context.Background(), flow.ID, "in")
+	}
+	// The first message takes the free slot; the others wait for the next
+	eventually(t, time.Second, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(results) > 0
+	})
+	if err := e.StopFlow(context.Background(), flow.ID); err != nil {
+		t.Fatalf("stop flow: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(results) != 3 {
+		t.Fatalf("%d of 3 deliveries resolved", len(results))
+	}
+	if handled.Load() != 1 || results[0] != nil {
+		t.Errorf("first message not handled and acked: handled %d, result %v", handled.Load(), results[0])
+	}
+	for i, err := range results[1:] {
+		if err == nil {
+			t.Errorf("queued message %d acked after the flow stopped", i+1)
+		}
+	}
+}
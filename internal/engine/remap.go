@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// RemapFlowIDs gives every flow of an imported set a new ID and rewrites
+// references between them (properties of FlowReferenceBlock nodes) so the
+// relationships survive. It returns the old to new ID mapping and the
+// references that point neither into the set nor at a stored flow
+func (e *Engine) RemapFlowIDs(ctx context.Context, flows []*models.Flow) (map[string]string, []string) {
+	mapping := make(map[string]string, len(flows))
+	for _, flow := range flows {
+		mapping[flow.ID] = models.NewID()
+	}
+	return mapping, e.applyFlowIDs(ctx, flows, mapping)
+}
+
+// RemapConflictingFlowIDs gives a new ID only to the flows of an imported set
+// whose ID is already stored, rewriting references to them like RemapFlowIDs.
+// The set must not repeat an ID
+func (e *Engine) RemapConflictingFlowIDs(ctx context.Context, flows []*models.Flow) (map[string]string, []string) {
+	mapping := make(map[string]string)
+	for _, flow := range flows {
+		if e.storage.FlowExists(ctx, flow.ID) {
+			mapping[flow.ID] = models.NewID()
+		}
+	}
+	return mapping, e.applyFlowIDs(ctx, flows, mapping)
+}
+
+// applyFlowIDs moves the flows of a set to their IDs in mapping (flows not in
+// it keep theirs) and rewrites the references of their nodes to match
+func (e *Engine) applyFlowIDs(ctx context.Context, flows []*models.Flow, mapping map[string]string) []string {
+	inSet := make(map[string]bool, len(flows))
+	for _, flow := range flows {
+		inSet[flow.ID] = true
+	}
+
+	unresolved := make([]string, 0)
+	for _, flow := range flows {
+		oldID := flow.ID
+		if newID, remapped := mapping[oldID]; remapped {
+			flow.ID = newID
+		}
+
+		for i := range flow.Nodes {
+			node := &flow.Nodes[i]
+			block, err := e.registry.CreateBlock(node.Type)
+			if err != nil {
+				continue // Reported when the flow is validated
+			}
+			referencing, ok := block.(blocks.FlowReferenceBlock)
+			if !ok {
+				continue
+			}
+
+			for _, name := range referencing.FlowReferenceProperties() {
+				target, _ := node.Properties[name].(string)
+				if target == "" {
+					continue
+				}
+				if newID, remapped := mapping[target]; remapped {
+					node.Properties[name] = newID
+				} else if !inSet[target] && !e.storage.FlowExists(ctx, target) {
+					unresolved = append(unresolved, fmt.Sprintf("flow '%s' node '%s' property '%s' references unknown flow '%s'",
+						oldID, node.ID, name, target))
+				}
+			}
+		}
+	}
+
+	return unresolved
+}
+
+// RemapNodeReferences rewrites the node IDs held in properties of
+// NodeReferenceBlock nodes after the flow's nodes got new IDs, mapping being
+// the one returned by models.Flow.RemapNodeIDs. IDs not in mapping are kept
+func (e *Engine) RemapNodeReferences(flow *models.Flow, mapping map[string]string) {
+	for i := range flow.Nodes {
+		node := &flow.Nodes[i]
+		block, err := e.registry.CreateBlock(node.Type)
+		if err != nil {
+			continue // Reported when the flow is validated
+		}
+		referencing, ok := block.(blocks.NodeReferenceBlock)
+		if !ok {
+			continue
+		}
+
+		for _, name := range referencing.NodeReferenceProperties() {
+			value, _ := node.Properties[name].(string)
+			if value == "" {
+				continue
+			}
+			ids := strings.Split(value, ",")
+			for j, id := range ids {
+				if newID, remapped := mapping[strings.TrimSpace(id)]; remapped {
+					ids[j] = newID
+				} else {
+					ids[j] = strings.TrimSpace(id)
+				}
+			}
+			node.Properties[name] = strings.Join(ids, ",")
+		}
+	}
+}
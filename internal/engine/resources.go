@@ -0,0 +1,221 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/config"
+	"block-flow/internal/models"
+)
+
+// ResourceLimits caps the resources a single flow may use (0 = unlimited)
+type ResourceLimits struct {
+	MaxGoroutines       int   `json:"max_goroutines,omitempty"`
+	MaxInFlightMessages int64 `json:"max_inflight_messages,omitempty"`
+	MaxInFlightBytes    int64 `json:"max_inflight_bytes,omitempty"`
+}
+
+// ResourceUsage reports a flow's resource accounting
+type ResourceUsage struct {
+	Goroutines       int            `json:"goroutines"`
+	BufferedMessages int            `json:"buffered_messages"`
+	InFlightMessages int64          `json:"inflight_messages"`
+	InFlightBytes    int64          `json:"inflight_bytes"` // Approximate size of in-flight payloads
+	Throttled        int64          `json:"throttled"`      // Input fires skipped or delayed by the limits
+	Paused           bool           `json:"paused"`         // Inputs are held back right now
+	Limits           ResourceLimits `json:"limits"`
+	Adaptive         *AdaptiveInput `json:"adaptive,omitempty"` // Set when adaptive_input is enabled
+}
+
+// resourceTracker accounts the approximate memory of in-flight payloads
+type resourceTracker struct {
+	goroutines int
+	throttled  atomic.Int64
+	bytes      atomic.Int64
+	sizes      map[string]int64 // In-flight message ID → accounted size
+	mu         sync.Mutex
+}
+
+// add accounts an in-flight message
+func (t *resourceTracker) add(msg *models.Message) {
+	size := estimateSize(msg.Payload)
+
+	t.mu.Lock()
+	if t.sizes == nil {
+		t.sizes = make(map[string]int64)
+	}
+	t.sizes[msg.ID] += size
+	t.mu.Unlock()
+
+	t.bytes.Add(size)
+}
+
+// remove releases the size accounted for a message by add
+func (t *resourceTracker) remove(msg *models.Message) {
+	t.mu.Lock()
+	size, ok := t.sizes[msg.ID]
+	delete(t.sizes, msg.ID)
+	t.mu.Unlock()
+
+	if ok {
+		t.bytes.Add(-size)
+	}
+}
+
+// errDropped fails the delivery of messages dropped because their target's
+// input channel was full. Transient: the message may well get through later
+var errDropped = blocks.Transient(errors.New("target node input channel full"))
+
+// messageEnqueued records a message handed to a node's input channel
+func (f *RuntimeFlow) messageEnqueued(msg *models.Message) {
+	f.idle.enqueued()
+	f.resources.add(msg)
+	msg.Delivery.Add()
+}
+
+// messageDropped reverts messageEnqueued for an undelivered message, which
+// fails its delivery
+func (f *RuntimeFlow) messageDropped(msg *models.Message) {
+	f.idle.dropped()
+	f.resources.remove(msg)
+	msg.Delivery.Fail(errDropped)
+	msg.Delivery.Done()
+}
+
+// messageProcessed records that a node finished handling a message
+func (f *RuntimeFlow) messageProcessed(msg *models.Message) {
+	f.idle.processed()
+	f.resources.remove(msg)
+	msg.Delivery.Done()
+}
+
+// overLimit reports whether the flow's in-flight caps are exceeded, in
+// which case its inputs are held back until downstream nodes catch up
+func (f *RuntimeFlow) overLimit() bool {
+	if limit := f.Limits.MaxInFlightMessages; limit > 0 && f.idle.inFlight.Load() >= limit {
+		return true
+	}
+	if limit := f.Limits.MaxInFlightBytes; limit > 0 && f.resources.bytes.Load() >= limit {
+		return true
+	}
+	return false
+}
+
+// waitForCapacity blocks a streaming input until the flow is back under its
+// limits. It returns false if the flow stops while waiting
+func (f *RuntimeFlow) waitForCapacity() bool {
+	if !f.overLimit() {
+		return true
+	}
+	f.resources.throttled.Add(1)
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for f.overLimit() {
+		select {
+		case <-f.StopChan:
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
+
+// parseResourceLimits reads the engine-wide limits, overridden by the flow's
+// max_goroutines, max_inflight_messages and max_inflight_bytes properties
+func parseResourceLimits(cfg config.EngineConfig, properties map[string]string) (ResourceLimits, error) {
+	limits := ResourceLimits{
+		MaxGoroutines:       cfg.MaxFlowGoroutines,
+		MaxInFlightMessages: int64(cfg.MaxInFlightMessages),
+		MaxInFlightBytes:    int64(cfg.MaxInFlightBytes),
+	}
+
+	for key, target := range map[string]*int64{
+		"max_inflight_messages": &limits.MaxInFlightMessages,
+		"max_inflight_bytes":    &limits.MaxInFlightBytes,
+	} {
+		if value, ok := properties[key]; ok {
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || n < 0 {
+				return limits, fmt.Errorf("invalid %s '%s': must be a non-negative integer", key, value)
+			}
+			*target = n
+		}
+	}
+
+	if value, ok := properties["max_goroutines"]; ok {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return limits, fmt.Errorf("invalid max_goroutines '%s': must be a non-negative integer", value)
+		}
+		limits.MaxGoroutines = n
+	}
+
+	return limits, nil
+}
+
+// estimateSize approximates the memory held by a payload without
+// serializing it
+func estimateSize(value interface{}) int64 {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case string:
+		return int64(len(v))
+	case []byte:
+		return int64(len(v))
+	case bool:
+		return 1
+	case map[string]interface{}:
+		var size int64
+		for key, item := range v {
+			size += int64(len(key)) + estimateSize(item)
+		}
+		return size
+	case []interface{}:
+		var size int64
+		for _, item := range v {
+			size += estimateSize(item)
+		}
+		return size
+	default:
+		return 8
+	}
+}
+
+// GetResourceUsage returns the resource accounting of a flow
+func (fe *FlowExecutor) GetResourceUsage(flowID string) (ResourceUsage, error) {
+	fe.mutex.RLock()
+	defer fe.mutex.RUnlock()
+
+	runtimeFlow, exists := fe.flows[flowID]
+	if !exists {
+		return ResourceUsage{}, fmt.Errorf("flow '%s' not found", flowID)
+	}
+
+	usage := ResourceUsage{
+		InFlightMessages: runtimeFlow.idle.inFlight.Load(),
+		InFlightBytes:    runtimeFlow.resources.bytes.Load(),
+		Throttled:        runtimeFlow.resources.throttled.Load(),
+		Paused:           runtimeFlow.overLimit(),
+		Limits:           runtimeFlow.Limits,
+		Adaptive:         runtimeFlow.adaptive.status(),
+	}
+
+	runtimeFlow.mutex.RLock()
+	if runtimeFlow.Running {
+		usage.Goroutines = runtimeFlow.resources.goroutines
+	}
+	runtimeFlow.mutex.RUnlock()
+
+	for _, node := range runtimeFlow.Nodes {
+		usage.BufferedMessages += len(node.InputChan)
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestInFlightCapThrottlesInputs(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]string
+		payload    interface{}
+		wantFires  int // Fires accepted before the flow is over its limits
+	}{
+		{
+			name:       "message cap",
+			properties: map[string]string{"max_inflight_messages": "2"},
+			payload:    1.0,
+			wantFires:  2,
+		},
+		{
+			name:       "byte cap",
+			properties: map[string]string{"max_inflight_bytes": "250"},
+			payload:    strings.Repeat("x", 100),
+			wantFires:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			input := &testBlock{
+				typ:   "test-input",
+				group: blocks.InputGroup,
+				execute: func(*models.BlockExecutionContext) ([]*models.Message, error) {
+					return []*models.Message{models.NewMessage(tt.payload)}, nil
+				},
+			}
+			e, store := newTestEngine(t, testConfig(), input, sinkBlock(func(*models.Message) { <-release }))
+
+			flow := chain("capped", models.Node{ID: "in", Type: "test-input"}, models.Node{ID: "out", Type: "test-sink"})
+			flow.Properties = tt.properties
+			startTestFlow(t, e, store, flow)
+			defer close(release)
+
+			fires := 0
+			for ; fires < 10; fires++ {
+				if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+					if !strings.Contains(err.Error(), "in-flight limits") {
+						t.Fatalf("trigger: %v", err)
+					}
+					break
+				}
+			}
+			if fires != tt.wantFires {
+				t.Errorf("%d fires accepted, want %d", fires, tt.wantFires)
+			}
+
+			usage, err := e.executor.GetResourceUsage(flow.ID)
+			if err != nil {
+				t.Fatalf("resource usage: %v", err)
+			}
+			if !usage.Paused || usage.Throttled != 1 {
+				t.Errorf("usage = paused %v, throttled %d; want paused, throttled 1", usage.Paused, usage.Throttled)
+			}
+		})
+	}
+}
+
+func TestInFlightCapResumesInputs(t *testing.T) {
+	release := make(chan struct{}, 10)
+	e, store := newTestEngine(t, testConfig(),
+		&testBlock{typ: "test-input", group: blocks.InputGroup},
+		sinkBlock(func(*models.Message) { <-release }),
+	)
+
+	flow := chain("capped", models.Node{ID: "in", Type: "test-input"}, models.Node{ID: "out", Type: "test-sink"})
+	flow.Properties = map[string]string{"max_inflight_messages": "1"}
+	startTestFlow(t, e, store, flow)
+
+	if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+		t.Fatalf("trigger: %v", err)
+	}
+	if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err == nil {
+		t.Fatal("second trigger accepted over the cap")
+	}
+
+	release <- struct{}{}
+	resumed := eventually(t, time.Second, func() bool {
+		usage, _ := e.executor.GetResourceUsage(flow.ID)
+		return !usage.Paused
+	})
+	if !resumed {
+		t.Fatal("flow still paused after the in-flight message was processed")
+	}
+	if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+		t.Errorf("trigger after catching up: %v", err)
+	}
+	release <- struct{}{}
+}
@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+// Restart policies configured through the flow's restart_policy property
+const (
+	RestartNever   = "never"
+	RestartOnPanic = "on-panic"
+)
+
+// RestartPolicy controls automatic restarts of flows whose nodes keep panicking
+type RestartPolicy struct {
+	Policy      string        // RestartNever or RestartOnPanic
+	MaxRestarts int           // Restarts allowed before the flow is marked unhealthy
+	PanicLimit  int           // Consecutive panics in one node that trigger a restart
+	Backoff     time.Duration // Base delay before a restart, doubled on each restart
+}
+
+// PanicError is returned when a block panics during Execute, Run or Tick
+type PanicError struct {
+	NodeID string
+	Value  interface{}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("node %s panicked: %v", e.NodeID, e.Value)
+}
+
+// parseRestartPolicy reads the restart policy from flow properties
+func parseRestartPolicy(properties map[string]string) (RestartPolicy, error) {
+	policy := RestartPolicy{
+		Policy:      RestartNever,
+		MaxRestarts: 3,
+		PanicLimit:  3,
+		Backoff:     time.Second,
+	}
+
+	if value, ok := properties["restart_policy"]; ok && value != "" {
+		if value != RestartNever && value != RestartOnPanic {
+			return policy, fmt.Errorf("invalid restart_policy '%s': must be '%s' or '%s'", value, RestartNever, RestartOnPanic)
+		}
+		policy.Policy = value
+	}
+
+	for key, target := range map[string]*int{
+		"max_restarts":         &policy.MaxRestarts,
+		"restart_after_panics": &policy.PanicLimit,
+	} {
+		if value, ok := properties[key]; ok {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return policy, fmt.Errorf("invalid %s '%s': must be a non-negative integer", key, value)
+			}
+			*target = n
+		}
+	}
+	if policy.PanicLimit == 0 {
+		policy.PanicLimit = 1
+	}
+
+	if value, ok := properties["restart_backoff"]; ok {
+		backoff, err := time.ParseDuration(value)
+		if err != nil || backoff < 0 {
+			return policy, fmt.Errorf("invalid restart_backoff '%s': must be a duration", value)
+		}
+		policy.Backoff = backoff
+	}
+
+	return policy, nil
+}
+
+// recordPanic counts a panic on a node and, when the flow's restart policy
+// allows it, schedules a flow restart once the node keeps panicking
+func (fe *FlowExecutor) recordPanic(node *RuntimeNode, flow *RuntimeFlow) {
+	node.stateMu.Lock()
+	node.State.Panics++
+	node.consecutivePanics++
+	consecutive := node.consecutivePanics
+	node.stateMu.Unlock()
+
+	if flow.RestartPolicy.Policy != RestartOnPanic || consecutive < flow.RestartPolicy.PanicLimit {
+		return
+	}
+
+	flow.mutex.Lock()
+	if flow.restarting {
+		flow.mutex.Unlock()
+		return
+	}
+	flow.restarting = true
+	flow.mutex.Unlock()
+
+	reason := fmt.Sprintf("node '%s' panicked %d times in a row", node.ID, consecutive)
+	go fe.restartFlow(flow, reason)
+}
+
+// restartFlow stops an unhealthy flow and starts it again after a backoff,
+// giving up and marking the flow unhealthy once MaxRestarts is exhausted
+func (fe *FlowExecutor) restartFlow(flow *RuntimeFlow, reason string) {
+	if err := fe.StopFlow(flow.ID); err != nil {
+		// Already stopped by someone else; don't resurrect it
+		fe.logger.Warn("Failed to stop flow for restart", map[string]interface{}{
+			"flow_id": flow.ID,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if flow.Restarts >= flow.RestartPolicy.MaxRestarts {
+		flow.mutex.Lock()
+		flow.Unhealthy = true
+		flow.mutex.Unlock()
+
+		fe.recordExecution(flow.ID, models.ExecutionStatusFailed,
+			fmt.Sprintf("%s; restart limit of %d reached, flow left stopped", reason, flow.RestartPolicy.MaxRestarts))
+		fe.logger.Error("Flow restart limit reached", map[string]interface{}{
+			"flow_id":  flow.ID,
+			"restarts": flow.Restarts,
+			"reason":   reason,
+		})
+		return
+	}
+
+	backoff := flow.RestartPolicy.Backoff << flow.Restarts
+	fe.recordExecution(flow.ID, models.ExecutionStatusStopped,
+		fmt.Sprintf("%s; restarting in %s (restart %d of %d)", reason, backoff, flow.Restarts+1, flow.RestartPolicy.MaxRestarts))
+	fe.logger.Warn("Restarting unhealthy flow", map[string]interface{}{
+		"flow_id": flow.ID,
+		"reason":  reason,
+		"backoff": backoff.String(),
+	})
+
+	select {
+	case <-time.After(backoff):
+	case <-fe.shutdown:
+		return
+	}
+
+	restarted, err := fe.PrepareFlow(flow.Definition)
+	if err != nil {
+		fe.logger.Error("Failed to prepare flow for restart", map[string]interface{}{
+			"flow_id": flow.ID,
+			"error":   err.Error(),
+		})
+		return
+	}
+	restarted.Restarts = flow.Restarts + 1
+
+	// Swap and start under one lock, so a flow restarted or replaced manually
+	// in the meantime, or a shutdown, is never overwritten
+	fe.mutex.Lock()
+	defer fe.mutex.Unlock()
+	if fe.flows[flow.ID] != flow || fe.shuttingDown {
+		return
+	}
+	fe.flows[flow.ID] = restarted
+
+	if err := fe.startFlowLocked(flow.ID); err != nil {
+		fe.logger.Error("Failed to restart flow", map[string]interface{}{
+			"flow_id": flow.ID,
+			"error":   err.Error(),
+		})
+	}
+}
+
+// recordExecution persists a lifecycle event of a flow, such as a restart or
+// an automatic stop, in the flow's execution history
+func (fe *FlowExecutor) recordExecution(flowID string, status models.ExecutionStatus, reason string) {
+	if fe.storage == nil {
+		return
+	}
+
+	execution := models.NewFlowExecution(flowID)
+	now := time.Now()
+	execution.Status = status
+	execution.EndedAt = &now
+	execution.Error = reason
+
+	if err := fe.storage.SaveFlowExecution(context.Background(), execution); err != nil {
+		fe.logger.Warn("Failed to record flow execution", map[string]interface{}{
+			"flow_id": flowID,
+			"error":   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"block-flow/internal/blocks"
+)
+
+// defaultRetryBackoff is the delay before a node's first retry when the
+// node sets retries without retryBackoff
+const defaultRetryBackoff = time.Second
+
+// retryPolicy controls how often a node re-executes a failed message
+type retryPolicy struct {
+	retries int           // Retries after the first attempt
+	backoff time.Duration // Delay before the first retry, growing linearly
+}
+
+// parseRetryPolicy reads the node's retries and retryBackoff (ms)
+// properties. Without retries it returns nil: only transient errors are
+// retried, TRANSIENT_RETRIES times
+func parseRetryPolicy(properties map[string]interface{}) (*retryPolicy, error) {
+	retries, ok, err := numberProperty(properties, "retries")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	if retries < 0 {
+		return nil, fmt.Errorf("retries must be non-negative, got %v", retries)
+	}
+
+	policy := &retryPolicy{retries: int(retries), backoff: defaultRetryBackoff}
+	if ms, ok, err := numberProperty(properties, "retryBackoff"); err != nil {
+		return nil, err
+	} else if ok {
+		if ms < 0 {
+			return nil, fmt.Errorf("retryBackoff must be non-negative, got %v", ms)
+		}
+		policy.backoff = time.Duration(ms) * time.Millisecond
+	}
+	return policy, nil
+}
+
+// parseRetryTimeouts reads the node's retryTimeouts property. Timeouts aren't
+// retried unless it is true: the abandoned execution may still be running
+// and each retry would wait for it
+func parseRetryTimeouts(properties map[string]interface{}) (bool, error) {
+	switch value := properties["retryTimeouts"].(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return value, nil
+	case string:
+		retry, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("retryTimeouts must be a boolean, got '%s'", value)
+		}
+		return retry, nil
+	default:
+		return false, fmt.Errorf("retryTimeouts must be a boolean, got %v", value)
+	}
+}
+
+// retryDelay reports whether a failed execution of a node is retried, and
+// after how long. Nodes with a retry policy retry every error but fatal ones;
+// other nodes retry transient errors only. Timeouts are retried only when the
+// node opts in with retryTimeouts
+func (fe *FlowExecutor) retryDelay(node *RuntimeNode, attempt int, err error) (time.Duration, bool) {
+	var timeout *TimeoutError
+	if errors.As(err, &timeout) && !node.retryTimeouts {
+		return 0, false
+	}
+
+	category := blocks.CategoryOf(err)
+	if node.retry == nil {
+		if category != blocks.ErrorTransient || attempt >= fe.config.TransientRetries {
+			return 0, false
+		}
+		return time.Duration(attempt+1) * transientRetryBackoff, true
+	}
+
+	if category == blocks.ErrorFatal || errors.Is(err, errStopping) || attempt >= node.retry.retries {
+		return 0, false
+	}
+	return time.Duration(attempt+1) * node.retry.backoff, true
+}
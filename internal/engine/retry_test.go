@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+)
+
+func TestRetryDelay(t *testing.T) {
+	base := errors.New("boom")
+	timeout := blocks.Transient(&TimeoutError{NodeID: "n", Timeout: time.Second})
+	policy := &retryPolicy{retries: 3, backoff: time.Second}
+
+	tests := []struct {
+		name          string
+		retry         *retryPolicy
+		retryTimeouts bool
+		attempt       int
+		err           error
+		wantRetry     bool
+		wantDelay     time.Duration
+	}{
+		{name: "transient", err: blocks.Transient(base), wantRetry: true, wantDelay: transientRetryBackoff},
+		{name: "transient backs off linearly", attempt: 1, err: blocks.Transient(base), wantRetry: true, wantDelay: 2 * transientRetryBackoff},
+		{name: "transient retries exhausted", attempt: 2, err: blocks.Transient(base)},
+		{name: "invalid", err: blocks.Invalid(base)},
+		{name: "unknown", err: base},
+		{name: "timeout", err: timeout},
+		{name: "timeout opted in", retryTimeouts: true, err: timeout, wantRetry: true, wantDelay: transientRetryBackoff},
+		{name: "policy retries invalid", retry: policy, err: blocks.Invalid(base), wantRetry: true, wantDelay: time.Second},
+		{name: "policy skips fatal", retry: policy, err: blocks.Fatal(base)},
+		{name: "policy skips timeout", retry: policy, err: timeout},
+		{name: "policy timeout opted in", retry: policy, retryTimeouts: true, attempt: 2, err: timeout, wantRetry: true, wantDelay: 3 * time.Second},
+		{name: "policy retries exhausted", retry: policy, attempt: 3, err: blocks.Invalid(base)},
+	}
+
+	fe := &FlowExecutor{config: testConfig()}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &RuntimeNode{ID: "n", retry: tt.retry, retryTimeouts: tt.retryTimeouts}
+			delay, retry := fe.retryDelay(node, tt.attempt, tt.err)
+			if retry != tt.wantRetry || delay != tt.wantDelay {
+				t.Errorf("retryDelay = (%s, %v), want (%s, %v)", delay, retry, tt.wantDelay, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestParseRetryTimeouts(t *testing.T) {
+	tests := []struct {
+		value   interface{}
+		want    bool
+		wantErr bool
+	}{
+		{value: nil, want: false},
+		{value: true, want: true},
+		{value: false, want: false},
+		{value: "true", want: true},
+		{value: "yes", wantErr: true},
+		{value: 1.0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		properties := map[string]interface{}{}
+		if tt.value != nil {
+			properties["retryTimeouts"] = tt.value
+		}
+		got, err := parseRetryTimeouts(properties)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseRetryTimeouts(%v) = (%v, %v), want (%v, error %v)", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
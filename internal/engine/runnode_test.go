@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestRunNodeUsesNodeGroup(t *testing.T) {
+	tests := []struct {
+		name          string
+		group         blocks.BlockGroup
+		unregister    bool // Remove the block type after the flow is prepared
+		wantDelivered bool
+		wantError     bool
+	}{
+		{name: "registered type", group: blocks.PropagationGroup, wantDelivered: true},
+		{name: "type unregistered after prepare", group: blocks.PropagationGroup, unregister: true, wantDelivered: true},
+		{name: "unknown group", group: blocks.BlockGroup("bogus"), wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var delivered atomic.Int32
+			e, _ := newTestEngine(t, testConfig(),
+				&testBlock{typ: "test-input", group: blocks.InputGroup},
+				&testBlock{typ: "test-middle", group: tt.group, outputs: 1},
+				sinkBlock(func(*models.Message) { delivered.Add(1) }),
+			)
+
+			flow := chain("groups",
+				models.Node{ID: "in", Type: "test-input"},
+				models.Node{ID: "middle", Type: "test-middle"},
+				models.Node{ID: "out", Type: "test-sink"},
+			)
+			runtimeFlow, err := e.executor.PrepareFlow(flow)
+			if err != nil {
+				t.Fatalf("prepare flow: %v", err)
+			}
+			e.executor.flows[flow.ID] = runtimeFlow
+			if tt.unregister {
+				e.registry.Unregister("test-middle")
+			}
+			if err := e.executor.StartFlow(flow.ID); err != nil {
+				t.Fatalf("start flow: %v", err)
+			}
+
+			node := runtimeFlow.Nodes["middle"]
+			if tt.wantError {
+				failed := eventually(t, time.Second, func() bool {
+					node.stateMu.Lock()
+					defer node.stateMu.Unlock()
+					return node.State.Status == models.NodeStatusError
+				})
+				if !failed {
+					t.Error("node with an unknown block group not marked failed")
+				}
+				return
+			}
+
+			e.TriggerNode(context.Background(), flow.ID, "in")
+			got := eventually(t, time.Second, func() bool { return delivered.Load() > 0 })
+			if got != tt.wantDelivered {
+				t.Errorf("delivered = %v, want %v", got, tt.wantDelivered)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// stuckStreamBlock is a streaming input block whose Run ignores the flow
+// stopping and only returns once released
+type stuckStreamBlock struct {
+	*testBlock
+	release chan struct{}
+}
+
+func (b *stuckStreamBlock) Run(*models.BlockExecutionContext, map[string]interface{}, func(*models.Message)) error {
+	<-b.release
+	return nil
+}
+
+func TestShutdownAbandonsStuckFlows(t *testing.T) {
+	stuck := &stuckStreamBlock{
+		testBlock: &testBlock{typ: "test-stuck", group: blocks.InputGroup, outputs: 1},
+		release:   make(chan struct{}),
+	}
+	e, store := newTestEngine(t, testConfig(), &testBlock{typ: "test-input", group: blocks.InputGroup}, sinkBlock(nil))
+	e.registry.MustRegister(&blockFactory{block: stuck})
+	defer close(stuck.release)
+
+	startTestFlow(t, e, store, chain("stuck", models.Node{ID: "in", Type: "test-stuck"}, models.Node{ID: "out", Type: "test-sink"}))
+	startTestFlow(t, e, store, chain("healthy", models.Node{ID: "in", Type: "test-input"}, models.Node{ID: "out", Type: "test-sink"}))
+
+	const timeout = 200 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	start := time.Now()
+	err := e.Shutdown(ctx)
+	elapsed := time.Since(start)
+
+	if err == nil || !strings.Contains(err.Error(), "stuck") || strings.Contains(err.Error(), "healthy") {
+		t.Errorf("shutdown error = %v, want only the stuck flow reported", err)
+	}
+	if elapsed < timeout || elapsed > timeout+time.Second {
+		t.Errorf("shutdown returned after %s, want about %s", elapsed, timeout)
+	}
+	if running, _ := e.executor.GetFlowStatus("healthy"); running {
+		t.Error("healthy flow still running after shutdown")
+	}
+}
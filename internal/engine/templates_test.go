@@ -0,0 +1,41 @@
+package engine
+
+import (
+	"testing"
+
+	"block-flow/internal/templates"
+)
+
+func TestTemplateCatalog(t *testing.T) {
+	e, _ := newTestEngine(t, testConfig())
+
+	catalog := templates.List()
+	if len(catalog) == 0 {
+		t.Fatal("template catalog is empty")
+	}
+	for i, template := range catalog {
+		if i > 0 && catalog[i-1].Name >= template.Name {
+			t.Errorf("catalog not sorted by name: %q before %q", catalog[i-1].Name, template.Name)
+		}
+		if template.Title == "" || template.Nodes == 0 || template.Flow != nil {
+			t.Errorf("catalog entry %+v: want a title, a node count and no flow", template)
+		}
+
+		t.Run(template.Name, func(t *testing.T) {
+			flow, ok := templates.Instantiate(template.Name)
+			if !ok {
+				t.Fatal("listed template cannot be instantiated")
+			}
+			if err := flow.Validate(); err != nil {
+				t.Errorf("flow.Validate: %v", err)
+			}
+			if err := e.executor.ValidateFlow(flow); err != nil {
+				t.Errorf("ValidateFlow: %v", err)
+			}
+		})
+	}
+
+	if _, ok := templates.Instantiate("no-such-template"); ok {
+		t.Error("unknown template instantiated")
+	}
+}
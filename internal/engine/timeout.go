@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// TimeoutError is returned when a block's Execute does not finish within the
+// node's execution timeout, or when an execution abandoned on timeout is
+// still running after another timeout. It is transient, but only retried when
+// the node sets retryTimeouts; then it is passed to catch nodes and
+// dead-lettered like any other transient failure
+type TimeoutError struct {
+	NodeID  string
+	Timeout time.Duration
+	Waiting bool // Timed out waiting for an abandoned execution
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Waiting {
+		return fmt.Sprintf("node %s timed out after %s waiting for a previous timed-out execution to return", e.NodeID, e.Timeout)
+	}
+	return fmt.Sprintf("node %s timed out after %s", e.NodeID, e.Timeout)
+}
+
+// parseExecuteTimeout reads the node's executeTimeout (ms) property, falling
+// back to the engine default. Zero disables the timeout
+func parseExecuteTimeout(properties map[string]interface{}, defaultTimeout time.Duration) (time.Duration, error) {
+	ms, ok, err := numberProperty(properties, "executeTimeout")
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return defaultTimeout, nil
+	}
+	if ms < 0 {
+		return 0, fmt.Errorf("executeTimeout must be non-negative, got %v", ms)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// executeWithTimeout runs the block under the node's timeout. The block's
+// context is cancelled when the timeout expires or the flow stops; a block
+// that ignores it is abandoned so the node can move on. release is called once Execute actually
+// returns, so an abandoned execution keeps its concurrency slot, and the node
+// starts no other execution using its state before then (see awaitAbandoned).
+// Called holding the node's execMu
+func (fe *FlowExecutor) executeWithTimeout(node *RuntimeNode, ctx *models.BlockExecutionContext, release func()) ([]*models.Message, error) {
+	if node.Timeout <= 0 {
+		defer release()
+		return fe.safeExecute(node, ctx)
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx.Context, node.Timeout)
+	defer cancel()
+	ctx.Context = execCtx
+
+	type result struct {
+		messages []*models.Message
+		err      error
+	}
+	done := make(chan result, 1)
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		defer release()
+		messages, err := fe.safeExecute(node, ctx)
+		done <- result{messages, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.messages, r.err
+	case <-execCtx.Done():
+		// The block may ignore cancellation either way
+		node.abandoned = finished
+		if !errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+			return nil, errStopping // The flow was stopped mid-execution
+		}
+
+		fe.logger.Warn("Block execution timed out", map[string]interface{}{
+			"node_id": node.ID,
+			"timeout": node.Timeout.String(),
+		})
+		return nil, blocks.Transient(&TimeoutError{NodeID: node.ID, Timeout: node.Timeout})
+	}
+}
+
+// awaitAbandoned waits, up to the node's timeout, for the execution last
+// abandoned on timeout to return. Until it does it may still use the node's
+// state, which isn't safe for concurrent use, so no other execution starts.
+// Called holding the node's execMu
+func (fe *FlowExecutor) awaitAbandoned(node *RuntimeNode, flow *RuntimeFlow) error {
+	if node.abandoned == nil {
+		return nil
+	}
+
+	timer := time.NewTimer(node.Timeout)
+	defer timer.Stop()
+	select {
+	case <-node.abandoned:
+		node.abandoned = nil
+		return nil
+	case <-flow.StopChan:
+		return errStopping
+	case <-node.StopChan:
+		return errStopping
+	case <-timer.C:
+		return blocks.Transient(&TimeoutError{NodeID: node.ID, Timeout: node.Timeout, Waiting: true})
+	}
+}
+
+// abandonedRunning reports whether the execution last abandoned on timeout
+// is still running. Called holding the node's execMu
+func (n *RuntimeNode) abandonedRunning() bool {
+	if n.abandoned == nil {
+		return false
+	}
+	select {
+	case <-n.abandoned:
+		n.abandoned = nil
+		return false
+	default:
+		return true
+	}
+}
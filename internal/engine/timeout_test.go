@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestTimedOutExecutionsDontOverlap(t *testing.T) {
+	tests := []struct {
+		name  string
+		sleep time.Duration // Execute ignores cancellation for this long
+	}{
+		{name: "within the timeout", sleep: 0},
+		{name: "abandoned, then awaited", sleep: 15 * time.Millisecond},
+		{name: "abandoned past the wait", sleep: 40 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, maxRunning atomic.Int32
+			slow := overlapBlock(&running, &maxRunning, tt.sleep)
+			slow.typ = "test-slow"
+			slow.group = blocks.PropagationGroup
+
+			var delivered atomic.Int32
+			cfg := testConfig()
+			cfg.TransientRetries = 0
+			e, store := newTestEngine(t, cfg,
+				&testBlock{typ: "test-input", group: blocks.InputGroup},
+				slow,
+				sinkBlock(func(*models.Message) { delivered.Add(1) }),
+			)
+
+			flow := chain("timeouts",
+				models.Node{ID: "in", Type: "test-input"},
+				models.Node{ID: "slow", Type: "test-slow", Properties: map[string]interface{}{"executeTimeout": 10}},
+				models.Node{ID: "out", Type: "test-sink"},
+			)
+			startTestFlow(t, e, store, flow)
+
+			const messages = 5
+			for i := 0; i < messages; i++ {
+				e.TriggerNode(context.Background(), flow.ID, "in")
+			}
+			handled := eventually(t, 2*time.Second, func() bool {
+				letters, _ := e.GetDeadLetters(flow.ID)
+				return int(delivered.Load())+len(letters) == messages
+			})
+			if !handled {
+				t.Fatal("not every message was delivered or dead-lettered")
+			}
+			// Let the last abandoned execution return before checking
+			eventually(t, time.Second, func() bool { return running.Load() == 0 })
+
+			if max := maxRunning.Load(); max != 1 {
+				t.Errorf("executions of one node overlapped: %d at once", max)
+			}
+		})
+	}
+}
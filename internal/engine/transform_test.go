@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+func TestConnectionTransform(t *testing.T) {
+	t.Run("extracts a nested field before delivery", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			extract string
+			want    interface{}
+		}{
+			{name: "key starting with payload", extract: "payloadSize", want: 3.0},
+			{name: "index into payload field", extract: "payload.a[0]", want: "first"},
+			{name: "payloads field with index", extract: "payloads[1]", want: "y"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				received := make(chan interface{}, 1)
+				input := &testBlock{
+					typ:   "test-input",
+					group: blocks.InputGroup,
+					execute: func(*models.BlockExecutionContext) ([]*models.Message, error) {
+						return []*models.Message{models.NewMessage(map[string]interface{}{
+							"payloadSize": 3.0,
+							"a":           []interface{}{"first", "second"},
+							"payloads":    []interface{}{"x", "y"},
+						})}, nil
+					},
+				}
+				sink := sinkBlock(func(msg *models.Message) { received <- msg.Payload })
+				e, store := newTestEngine(t, testConfig(), input, sink)
+
+				flow := chain("transform", models.Node{ID: "in", Type: "test-input"}, models.Node{ID: "out", Type: "test-sink"})
+				flow.Connections[0].Transform = &models.ConnectionTransform{Extract: tt.extract}
+				startTestFlow(t, e, store, flow)
+
+				if _, err := e.TriggerNode(context.Background(), flow.ID, "in"); err != nil {
+					t.Fatalf("trigger: %v", err)
+				}
+				select {
+				case got := <-received:
+					if !reflect.DeepEqual(got, tt.want) {
+						t.Errorf("delivered payload = %v, want %v", got, tt.want)
+					}
+				case <-time.After(time.Second):
+					t.Fatal("transformed message never delivered")
+				}
+			})
+		}
+	})
+}
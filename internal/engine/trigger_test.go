@@ -0,0 +1,201 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// overlapBlock is an input block (by default) recording how many of its executions ran
+// at once, and mutating its node state like stateful blocks do. Each
+// execution takes hold, ignoring cancellation
+func overlapBlock(running, maxRunning *atomic.Int32, hold time.Duration) *testBlock {
+	return &testBlock{
+		typ:   "test-overlap",
+		group: blocks.InputGroup,
+		execute: func(ctx *models.BlockExecutionContext) ([]*models.Message, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				max := maxRunning.Load()
+				if n <= max || maxRunning.CompareAndSwap(max, n) {
+					break
+				}
+			}
+
+			count, _ := ctx.State["count"].(int)
+			ctx.State["count"] = count + 1
+			time.Sleep(hold)
+			ctx.State["count"] = count + 1
+			return []*models.Message{models.NewMessage(float64(count))}, nil
+		},
+	}
+}
+
+func TestTriggersSerializeWithTicker(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger func(e *Engine, flowID string)
+	}{
+		{
+			name: "node trigger API",
+			trigger: func(e *Engine, flowID string) {
+				e.TriggerNode(context.Background(), flowID, "in")
+			},
+		},
+		{
+			name: "trigger worker",
+			trigger: func(e *Engine, flowID string) {
+				e.TriggerFlow(context.Background(), flowID, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, maxRunning atomic.Int32
+			e, store := newTestEngine(t, testConfig(), overlapBlock(&running, &maxRunning, time.Millisecond), sinkBlock(nil))
+
+			flow := chain("trigger-node",
+				models.Node{ID: "in", Type: "test-overlap", Properties: map[string]interface{}{"interval": 1}},
+				models.Node{ID: "out", Type: "test-sink"},
+			)
+			// Parallel trigger workers race each other as well as the ticker
+			flow.Properties = map[string]string{"trigger_concurrency": "4"}
+			startTestFlow(t, e, store, flow)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 10; j++ {
+						tt.trigger(e, flow.ID)
+					}
+				}()
+			}
+			wg.Wait()
+			drained := eventually(t, 2*time.Second, func() bool {
+				queued, _, _ := e.executor.GetTriggerQueue(flow.ID)
+				return queued == 0
+			})
+			if !drained {
+				t.Fatal("queued triggers were not processed")
+			}
+
+			if max := maxRunning.Load(); max != 1 {
+				t.Errorf("executions of one node overlapped: %d at once", max)
+			}
+		})
+	}
+}
+
+func TestTriggerInputsPriority(t *testing.T) {
+	// The input emits its node ID so the sink sees which node fired
+	input := &testBlock{
+		typ:   "test-input",
+		group: blocks.InputGroup,
+		execute: func(ctx *models.BlockExecutionContext) ([]*models.Message, error) {
+			return []*models.Message{models.NewMessage(ctx.NodeID)}, nil
+		},
+	}
+	received := make(chan string, 8)
+	e, store := newTestEngine(t, testConfig(), input, sinkBlock(func(msg *models.Message) { received <- msg.Payload.(string) }))
+
+	// "a" sorts first by ID, so only its lower priority puts it second
+	flow := &models.Flow{
+		ID: "priority",
+		Nodes: []models.Node{
+			{ID: "a", Type: "test-input", Properties: map[string]interface{}{"interval": 0, "priority": 1}},
+			{ID: "b", Type: "test-input", Properties: map[string]interface{}{"interval": 0, "priority": 5}},
+			{ID: "out", Type: "test-sink"},
+		},
+		Connections: []models.Connection{
+			{ID: "a-out", Source: "a", Target: "out"},
+			{ID: "b-out", Source: "b", Target: "out"},
+		},
+	}
+	startTestFlow(t, e, store, flow)
+
+	for round := 0; round < 5; round++ {
+		if _, err := e.executor.TriggerInputs(flow.ID); err != nil {
+			t.Fatalf("trigger: %v", err)
+		}
+		var order []string
+		for len(order) < 2 {
+			select {
+			case id := <-received:
+				order = append(order, id)
+			case <-time.After(time.Second):
+				t.Fatalf("round %d received only %v", round, order)
+			}
+		}
+		if order[0] != "b" || order[1] != "a" {
+			t.Fatalf("round %d order = %v, want [b a]", round, order)
+		}
+	}
+}
+
+func TestTriggerFlowExpand(t *testing.T) {
+	tests := []struct {
+		name   string
+		expand bool
+		want   []interface{}
+	}{
+		{name: "expanded", expand: true, want: []interface{}{1.0, 2.0, 3.0}},
+		{name: "whole array", want: []interface{}{[]interface{}{1.0, 2.0, 3.0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := make(chan *models.Message, 4)
+			input := &testBlock{
+				typ:   "test-input",
+				group: blocks.InputGroup,
+				execute: func(ctx *models.BlockExecutionContext) ([]*models.Message, error) {
+					return []*models.Message{ctx.Message.Clone()}, nil
+				},
+			}
+			e, store := newTestEngine(t, testConfig(), input, sinkBlock(func(msg *models.Message) { received <- msg }))
+			flow := chain("expand", models.Node{ID: "in", Type: "test-input"}, models.Node{ID: "out", Type: "test-sink"})
+			startTestFlow(t, e, store, flow)
+
+			inputs := []*models.Message{models.NewMessage([]interface{}{1.0, 2.0, 3.0})}
+			if tt.expand {
+				inputs = inputs[0].Expand()
+			}
+			if err := e.TriggerFlow(context.Background(), flow.ID, inputs...); err != nil {
+				t.Fatalf("trigger: %v", err)
+			}
+
+			got := make(map[string]bool)
+			for range tt.want {
+				select {
+				case msg := <-received:
+					got[fmt.Sprint(msg.Payload)] = true
+					if _, ok := msg.GetParts(); ok != tt.expand {
+						t.Errorf("message %v has parts %v, want %v", msg.Payload, ok, tt.expand)
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("received %d of %d messages", len(got), len(tt.want))
+				}
+			}
+			for _, want := range tt.want {
+				if !got[fmt.Sprint(want)] {
+					t.Errorf("payload %v not delivered, got %v", want, got)
+				}
+			}
+			select {
+			case msg := <-received:
+				t.Errorf("unexpected extra message %v", msg.Payload)
+			case <-time.After(50 * time.Millisecond):
+			}
+		})
+	}
+}
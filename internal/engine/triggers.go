@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"block-flow/internal/models"
+)
+
+// ErrTriggerQueueFull is returned when a running flow can't accept another
+// trigger until queued ones have been processed
+var ErrTriggerQueueFull = errors.New("trigger queue full")
+
+// triggerQueue buffers API triggers of a running flow. A fixed number of
+// workers drains it; with a single worker triggers are processed strictly in
+// arrival order
+type triggerQueue struct {
+	pending     chan *models.Message
+	concurrency int
+}
+
+// parseTriggerQueue reads the flow's trigger_concurrency (default 1, serial)
+// and trigger_queue_size (default 100) properties
+func parseTriggerQueue(properties map[string]string) (triggerQueue, error) {
+	concurrency, size := 1, 100
+
+	if value, ok := properties["trigger_concurrency"]; ok && value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return triggerQueue{}, fmt.Errorf("invalid trigger_concurrency '%s': must be a positive integer", value)
+		}
+		concurrency = n
+	}
+
+	if value, ok := properties["trigger_queue_size"]; ok && value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return triggerQueue{}, fmt.Errorf("invalid trigger_queue_size '%s': must be a positive integer", value)
+		}
+		size = n
+	}
+
+	return triggerQueue{
+		pending:     make(chan *models.Message, size),
+		concurrency: concurrency,
+	}, nil
+}
+
+// EnqueueTrigger queues one trigger per input of a running flow, a nil
+// input firing the input nodes themselves. It never blocks: when the queue
+// can't take all inputs none is queued and ErrTriggerQueueFull is returned
+func (fe *FlowExecutor) EnqueueTrigger(flowID string, inputs ...*models.Message) error {
+	runtimeFlow, err := fe.runningFlow(flowID)
+	if err != nil {
+		return err
+	}
+
+	// Producers are serialized, so the free space can only grow meanwhile
+	runtimeFlow.mutex.Lock()
+	defer runtimeFlow.mutex.Unlock()
+
+	pending := runtimeFlow.triggers.pending
+	if free := cap(pending) - len(pending); free < len(inputs) {
+		return fmt.Errorf("flow '%s': %w (%d pending, %d to queue)", flowID, ErrTriggerQueueFull, len(pending), len(inputs))
+	}
+	for _, input := range inputs {
+		pending <- input
+	}
+	return nil
+}
+
+// runTriggerWorker processes queued triggers until the flow stops: each
+// trigger message is emitted once from every input node, and a nil trigger
+// fires the input nodes themselves. Triggers still queued when the flow
+// stops are discarded
+func (fe *FlowExecutor) runTriggerWorker(flow *RuntimeFlow) {
+	defer flow.WaitGroup.Done()
+
+	for {
+		select {
+		case <-flow.StopChan:
+			return
+		case input := <-flow.triggers.pending:
+			if _, err := fe.fireInputs(flow, input); err != nil {
+				fe.logger.Warn("Trigger failed", map[string]interface{}{
+					"flow_id": flow.ID,
+					"error":   err.Error(),
+				})
+			}
+		}
+	}
+}
+
+// RunOnce runs a one-shot execution of a flow started with its inputs
+// paused: the inputs are emitted from its input nodes, and the flow is
+// stopped again once every message derived from them has been handled
+func (fe *FlowExecutor) RunOnce(flowID string, inputs ...*models.Message) error {
+	runtimeFlow, err := fe.runningFlow(flowID)
+	if err != nil {
+		return err
+	}
+
+	// A single delivery spans all inputs: done once every derived message is handled
+	done := make(chan struct{})
+	delivery := models.NewDelivery(func(err error) {
+		if err != nil {
+			fe.logger.Warn("One-shot run failed", map[string]interface{}{
+				"flow_id": flowID,
+				"error":   err.Error(),
+			})
+		}
+		close(done)
+	})
+
+	delivery.Add()
+	var triggerErr error
+	for _, input := range inputs {
+		if _, err := fe.triggerInputs(runtimeFlow, input, delivery); err != nil {
+			triggerErr = err
+			break
+		}
+	}
+	delivery.Done()
+
+	go func() {
+		select {
+		case <-done:
+		case <-runtimeFlow.StopChan:
+			return
+		}
+		if err := fe.StopFlow(flowID); err != nil {
+			fe.logger.Debug("One-shot flow already stopped", map[string]interface{}{
+				"flow_id": flowID,
+			})
+		}
+	}()
+
+	return triggerErr
+}
+
+// GetTriggerQueue returns the number of queued triggers of a flow and the
+// queue's capacity
+func (fe *FlowExecutor) GetTriggerQueue(flowID string) (queued, capacity int, err error) {
+	fe.mutex.RLock()
+	runtimeFlow, exists := fe.flows[flowID]
+	fe.mutex.RUnlock()
+	if !exists {
+		return 0, 0, fmt.Errorf("flow '%s' not found", flowID)
+	}
+	return len(runtimeFlow.triggers.pending), cap(runtimeFlow.triggers.pending), nil
+}
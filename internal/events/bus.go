@@ -0,0 +1,185 @@
+package events
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Event types published by the engine
+const (
+	FlowStarted = "flow_started"
+	FlowStopped = "flow_stopped"
+	NodeError   = "node_error"
+
+	FlowErrorRateExceeded = "flow_error_rate_exceeded"
+)
+
+// Event represents something that happened inside the engine
+type Event struct {
+	Type      string                 `json:"type"`
+	FlowID    string                 `json:"flow_id,omitempty"`
+	NodeID    string                 `json:"node_id,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// Bus is an in-process publish/subscribe event bus. Every subscriber has a
+// bounded buffer; publishing never blocks: events are dropped, and counted,
+// for subscribers whose buffer is full, so one slow subscriber can't stall
+// the publishers or the other subscribers
+type Bus struct {
+	subscribers map[int]*subscriber
+	nextID      int
+	mu          sync.RWMutex
+
+	published atomic.Int64
+	dropped   atomic.Int64
+	rate      rateCounter
+}
+
+// subscriber is one registered event channel
+type subscriber struct {
+	id      int
+	name    string
+	ch      chan Event
+	dropped atomic.Int64
+}
+
+// Stats describes the bus's health
+type Stats struct {
+	Subscribers        int               `json:"subscribers"`
+	Published          int64             `json:"published"`            // Events published since startup
+	PublishedPerSecond float64           `json:"published_per_second"` // Averaged over the last minute
+	Dropped            int64             `json:"dropped"`              // Deliveries dropped for full buffers
+	SubscriberStats    []SubscriberStats `json:"subscriber_stats"`
+}
+
+// SubscriberStats describes one subscriber's buffer
+type SubscriberStats struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Buffer  int    `json:"buffer"`  // Capacity
+	Queued  int    `json:"queued"`  // Events waiting to be read
+	Dropped int64  `json:"dropped"` // Events dropped because the buffer was full
+}
+
+// NewBus creates a new event bus
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[int]*subscriber),
+	}
+}
+
+// Subscribe registers a subscriber with the given buffer size; name
+// identifies it in the bus statistics. It returns the event channel and a
+// function that unsubscribes and closes the channel
+func (b *Bus) Subscribe(name string, buffer int) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	sub := &subscriber{id: id, name: name, ch: make(chan Event, buffer)}
+	b.subscribers[id] = sub
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subscribers, id)
+			close(sub.ch)
+		})
+	}
+
+	return sub.ch, unsubscribe
+}
+
+// Publish delivers an event to every subscriber
+func (b *Bus) Publish(event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	b.published.Add(1)
+	b.rate.add(time.Now())
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, sub := range b.subscribers {
+		select {
+		case sub.ch <- event:
+		default:
+			// Slow subscriber, drop the event
+			sub.dropped.Add(1)
+			b.dropped.Add(1)
+		}
+	}
+}
+
+// Stats returns the bus's counters and the state of every subscriber,
+// ordered by subscription
+func (b *Bus) Stats() Stats {
+	b.mu.RLock()
+	subscribers := make([]SubscriberStats, 0, len(b.subscribers))
+	for _, sub := range b.subscribers {
+		subscribers = append(subscribers, SubscriberStats{
+			ID:      sub.id,
+			Name:    sub.name,
+			Buffer:  cap(sub.ch),
+			Queued:  len(sub.ch),
+			Dropped: sub.dropped.Load(),
+		})
+	}
+	b.mu.RUnlock()
+	sort.Slice(subscribers, func(i, j int) bool { return subscribers[i].ID < subscribers[j].ID })
+
+	return Stats{
+		Subscribers:        len(subscribers),
+		Published:          b.published.Load(),
+		PublishedPerSecond: b.rate.perSecond(time.Now()),
+		Dropped:            b.dropped.Load(),
+		SubscriberStats:    subscribers,
+	}
+}
+
+// rateWindow is how many seconds the publish rate is averaged over
+const rateWindow = 60
+
+// rateCounter counts events in one-second buckets over the last rateWindow
+// seconds
+type rateCounter struct {
+	seconds [rateWindow]int64 // Unix second each bucket counts
+	counts  [rateWindow]int64
+	mu      sync.Mutex
+}
+
+func (r *rateCounter) add(at time.Time) {
+	second := at.Unix()
+	i := second % rateWindow
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.seconds[i] != second {
+		r.seconds[i] = second
+		r.counts[i] = 0
+	}
+	r.counts[i]++
+}
+
+// perSecond returns the average rate over the last rateWindow seconds
+func (r *rateCounter) perSecond(now time.Time) float64 {
+	current := now.Unix()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var total int64
+	for i, second := range r.seconds {
+		if current-second < rateWindow {
+			total += r.counts[i]
+		}
+	}
+	return float64(total) / rateWindow
+}
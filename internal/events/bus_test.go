@@ -0,0 +1,53 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBusSlowSubscriber(t *testing.T) {
+	bus := NewBus()
+	_, unsubscribeSlow := bus.Subscribe("slow", 1) // Never read
+	defer unsubscribeSlow()
+	fast, unsubscribeFast := bus.Subscribe("fast", 10)
+	defer unsubscribeFast()
+
+	const published = 5
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < published; i++ {
+			bus.Publish(Event{Type: FlowStarted, FlowID: "f"})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a full subscriber")
+	}
+
+	for i := 0; i < published; i++ {
+		select {
+		case <-fast:
+		default:
+			t.Fatalf("fast subscriber received %d of %d events", i, published)
+		}
+	}
+
+	stats := bus.Stats()
+	if stats.Published != published || stats.Dropped != published-1 {
+		t.Errorf("published %d, dropped %d; want %d, %d", stats.Published, stats.Dropped, published, published-1)
+	}
+	want := []SubscriberStats{
+		{ID: 0, Name: "slow", Buffer: 1, Queued: 1, Dropped: published - 1},
+		{ID: 1, Name: "fast", Buffer: 10, Queued: 0, Dropped: 0},
+	}
+	if len(stats.SubscriberStats) != len(want) {
+		t.Fatalf("subscriber stats = %+v, want %+v", stats.SubscriberStats, want)
+	}
+	for i, got := range stats.SubscriberStats {
+		if got != want[i] {
+			t.Errorf("subscriber %d stats = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// FlowBundleFormat identifies flow bundles
+const FlowBundleFormat = "block-flow/flows"
+
+// FlowBundleVersion is the version of the bundle schema written by this
+// build. Bundles of later versions are refused
+const FlowBundleVersion = 1
+
+// FlowBundle is the portable form of one or more flows, for moving them
+// between instances
+type FlowBundle struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Flows      []*Flow   `json:"flows"`
+}
+
+// NewFlowBundle bundles flows in the current format
+func NewFlowBundle(flows ...*Flow) *FlowBundle {
+	return &FlowBundle{
+		Format:     FlowBundleFormat,
+		Version:    FlowBundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Flows:      flows,
+	}
+}
+
+// DecodeFlowBundle reads the flows to import from a bundle. A bare flow or a
+// JSON array of flows is accepted as well
+func DecodeFlowBundle(data []byte) ([]*Flow, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var flows []*Flow
+		if err := DecodeJSON(data, &flows); err != nil {
+			return nil, fmt.Errorf("invalid flows: %w", err)
+		}
+		return flows, nil
+	}
+
+	var envelope struct {
+		Format  string          `json:"format"`
+		Version int             `json:"version"`
+		Flows   json.RawMessage `json:"flows"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+
+	if envelope.Format == "" {
+		var flow Flow
+		if err := DecodeJSON(data, &flow); err != nil {
+			return nil, fmt.Errorf("invalid flow: %w", err)
+		}
+		return []*Flow{&flow}, nil
+	}
+
+	if envelope.Format != FlowBundleFormat {
+		return nil, fmt.Errorf("unknown bundle format '%s'", envelope.Format)
+	}
+	if envelope.Version < 1 || envelope.Version > FlowBundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d (this server reads up to %d)", envelope.Version, FlowBundleVersion)
+	}
+	var flows []*Flow
+	if err := DecodeJSON(envelope.Flows, &flows); err != nil {
+		return nil, fmt.Errorf("invalid bundle flows: %w", err)
+	}
+	return flows, nil
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Delivery tracks an originating message through a flow. Every message
+// derived from it (copies delivered to each connected node and the outputs of
+// blocks handling them) shares the Delivery; once all of them have been
+// handled, onDone is called with nil (ack) or with the first failure (nack).
+//
+// Input blocks that need at-least-once semantics attach a Delivery to the
+// messages they emit and acknowledge their source from onDone
+type Delivery struct {
+	pending atomic.Int64
+	err     error
+	done    bool
+	mu      sync.Mutex
+	onDone  func(err error)
+}
+
+// NewDelivery creates a delivery tracker calling onDone once the message
+// and everything derived from it has been handled
+func NewDelivery(onDone func(err error)) *Delivery {
+	return &Delivery{onDone: onDone}
+}
+
+// Add records a new message derived from the originating one. A nil
+// Delivery is a no-op, as are all its methods
+func (d *Delivery) Add() {
+	if d == nil {
+		return
+	}
+	d.pending.Add(1)
+}
+
+// Fail records that handling a derived message failed. The first failure is
+// reported to onDone; the message must still be completed with Done
+func (d *Delivery) Fail(err error) {
+	if d == nil || err == nil {
+		return
+	}
+	d.mu.Lock()
+	if d.err == nil {
+		d.err = err
+	}
+	d.mu.Unlock()
+}
+
+// Done completes a derived message, resolving the delivery when none are left
+func (d *Delivery) Done() {
+	if d == nil || d.pending.Add(-1) > 0 {
+		return
+	}
+
+	d.mu.Lock()
+	if d.done {
+		d.mu.Unlock()
+		return
+	}
+	d.done = true
+	err := d.err
+	d.mu.Unlock()
+
+	if d.onDone != nil {
+		d.onDone(err)
+	}
+}
@@ -17,6 +17,12 @@ func generateID() string {
 	return hex.EncodeToString(bytes)
 }
 
+// NewID returns a new unique identifier, e.g. for a flow imported under a
+// fresh ID
+func NewID() string {
+	return generateID()
+}
+
 // ValidationError represents a validation error
 type ValidationError struct {
 	Message string
@@ -31,11 +37,17 @@ func NewValidationError(message string) *ValidationError {
 	return &ValidationError{Message: message}
 }
 
-// ExecutionError represents an execution error
+// ErrorCategory classifies execution failures so the engine can decide
+// whether to retry, dead-letter, or stop the flow
+type ErrorCategory string
+
+// ExecutionError represents an execution error. Category is optional; the
+// engine classifies errors without one as unknown
 type ExecutionError struct {
-	NodeID  string
-	Message string
-	Cause   error
+	NodeID   string
+	Message  string
+	Cause    error
+	Category ErrorCategory
 }
 
 func (e *ExecutionError) Error() string {
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -40,6 +41,92 @@ type Connection struct {
 	Target     string `json:"target"`      // Target node ID
 	TargetPort int    `json:"target_port"` // Target input port (0-based)
 	Label      string `json:"label,omitempty"`
+
+	// Transform optionally modifies each message before delivery to the target
+	Transform *ConnectionTransform `json:"transform,omitempty"`
+
+	// MinInterval is the minimum time in milliseconds between deliveries
+	// along this connection (0 = unlimited). Over-rate messages are delayed
+	// or dropped according to RatePolicy
+	MinInterval int    `json:"min_interval,omitempty"`
+	RatePolicy  string `json:"rate_policy,omitempty"`
+}
+
+// Rate policies for connections with a MinInterval
+const (
+	RatePolicyDelay = "delay" // Queue messages on the connection until the interval has passed (default)
+	RatePolicyDrop  = "drop"  // Drop messages arriving within the interval
+)
+
+// ConnectionTransform is a lightweight inline change applied to messages
+// travelling along a connection. Empty fields leave the message unchanged
+type ConnectionTransform struct {
+	Extract     string `json:"extract,omitempty"`      // Replace the payload with the value at this path
+	TopicPrefix string `json:"topic_prefix,omitempty"` // Prepend to the message topic
+}
+
+// Validate checks that the transform spec is well formed
+func (t *ConnectionTransform) Validate() error {
+	if t == nil {
+		return nil
+	}
+	return ValidatePath(t.Extract)
+}
+
+// Apply transforms a message in place
+func (t *ConnectionTransform) Apply(msg *Message) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Extract != "" {
+		value, found, err := ResolvePath(msg.Payload, t.Extract)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("path '%s' not found in payload", t.Extract)
+		}
+		msg.Payload = value
+	}
+
+	if t.TopicPrefix != "" {
+		msg.Topic = t.TopicPrefix + msg.Topic
+	}
+
+	return nil
+}
+
+// FlowSummary is the metadata of a stored flow, e.g. for listing flows
+// without their nodes and connections
+type FlowSummary struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Active    bool      `json:"active"`
+	UpdatedAt time.Time `json:"updated_at"`
+	NodeCount int       `json:"node_count"`
+}
+
+// DecodeFlowSummary reads the summary of a flow from its JSON without
+// decoding the nodes themselves
+func DecodeFlowSummary(data []byte) (*FlowSummary, error) {
+	var header struct {
+		ID        string            `json:"id"`
+		Name      string            `json:"name"`
+		Active    bool              `json:"active"`
+		UpdatedAt time.Time         `json:"updated_at"`
+		Nodes     []json.RawMessage `json:"nodes"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+	return &FlowSummary{
+		ID:        header.ID,
+		Name:      header.Name,
+		Active:    header.Active,
+		UpdatedAt: header.UpdatedAt,
+		NodeCount: len(header.Nodes),
+	}, nil
 }
 
 // FlowExecution represents the runtime state of a flow execution
@@ -54,17 +141,42 @@ type FlowExecution struct {
 	Messages  []ExecutionMessage    `json:"messages,omitempty"`
 }
 
+// TrashedFlow is a soft-deleted flow, kept until restored or purged. A flow
+// whose stored data couldn't be decoded is kept as Raw bytes, Flow being nil
+type TrashedFlow struct {
+	ID        string    `json:"id"`
+	Flow      *Flow     `json:"flow"`
+	Raw       []byte    `json:"raw,omitempty"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// ExecutionSummary aggregates a flow's stored executions without their
+// node states and messages
+type ExecutionSummary struct {
+	Total         int             `json:"total"`
+	LastStatus    ExecutionStatus `json:"last_status,omitempty"`
+	LastStartedAt *time.Time      `json:"last_started_at,omitempty"`
+	LastEndedAt   *time.Time      `json:"last_ended_at,omitempty"`
+}
+
 // NodeState represents the runtime state of a node during execution
 type NodeState struct {
-	NodeID      string            `json:"node_id"`
-	Status      NodeStatus        `json:"status"`
-	ExecutedAt  *time.Time        `json:"executed_at,omitempty"`
-	Duration    time.Duration     `json:"duration"`
-	InputCount  int               `json:"input_count"`
-	OutputCount int               `json:"output_count"`
-	Error       string            `json:"error,omitempty"`
-	LastMessage *Message          `json:"last_message,omitempty"`
-	Properties  map[string]string `json:"properties,omitempty"`
+	NodeID           string            `json:"node_id"`
+	Status           NodeStatus        `json:"status"`
+	ExecutedAt       *time.Time        `json:"executed_at,omitempty"`
+	LastEmitAt       *time.Time        `json:"last_emit_at,omitempty"` // Input nodes: last time the node fired
+	NextFireAt       *time.Time        `json:"next_fire_at,omitempty"` // Input nodes: next scheduled fire (unset for manual-only)
+	Duration         time.Duration     `json:"duration"`
+	InputCount       int               `json:"input_count"`
+	OutputCount      int               `json:"output_count"`
+	OversizedDropped int               `json:"oversized_dropped"`       // Messages dropped by the payload size guard
+	DeadlineExceeded int               `json:"deadline_exceeded"`       // Messages dropped for arriving past their deadline
+	Panics           int               `json:"panics"`                  // Recovered panics in Execute
+	CircuitState     string            `json:"circuit_state,omitempty"` // closed, open or half-open when a circuit breaker is configured
+	Breakpoint       string            `json:"breakpoint,omitempty"`    // set, or paused while holding a message at a debug breakpoint
+	Error            string            `json:"error,omitempty"`
+	LastMessage      *Message          `json:"last_message,omitempty"`
+	Properties       map[string]string `json:"properties,omitempty"`
 }
 
 // ExecutionStatus represents the status of flow execution
@@ -141,6 +253,17 @@ func NewFlowExecution(flowID string) *FlowExecution {
 	}
 }
 
+// NewExecutionMessage creates a new execution trace entry
+func NewExecutionMessage(nodeID, messageType string, msg *Message) ExecutionMessage {
+	return ExecutionMessage{
+		ID:        generateID(),
+		Timestamp: time.Now(),
+		NodeID:    nodeID,
+		Type:      messageType,
+		Message:   msg,
+	}
+}
+
 // AddNode adds a node to the flow
 func (f *Flow) AddNode(node Node) {
 	f.Nodes = append(f.Nodes, node)
@@ -153,6 +276,84 @@ func (f *Flow) AddConnection(conn Connection) {
 	f.UpdatedAt = time.Now()
 }
 
+// NodeCloneOffset is how far a cloned node is moved from the original in the UI
+const NodeCloneOffset = 20
+
+// CloneNode appends a copy of a node with a new ID, offset position and its
+// own copy of the properties. Connections are not copied
+func (f *Flow) CloneNode(nodeID string) (*Node, bool) {
+	original, exists := f.GetNode(nodeID)
+	if !exists {
+		return nil, false
+	}
+
+	clone := *original
+	clone.ID = generateID()
+	clone.X += NodeCloneOffset
+	clone.Y += NodeCloneOffset
+	clone.Wires = nil
+	if original.Properties != nil {
+		clone.Properties = copyValue(original.Properties).(map[string]interface{})
+	}
+
+	f.AddNode(clone)
+	return &f.Nodes[len(f.Nodes)-1], true
+}
+
+// RemapNodeIDs gives every node and connection a new ID, rewriting wires
+// and connection endpoints to match. It returns the old to new node ID mapping
+func (f *Flow) RemapNodeIDs() map[string]string {
+	mapping := make(map[string]string, len(f.Nodes))
+	for i := range f.Nodes {
+		newID := generateID()
+		mapping[f.Nodes[i].ID] = newID
+		f.Nodes[i].ID = newID
+	}
+
+	for i := range f.Nodes {
+		for _, targets := range f.Nodes[i].Wires {
+			for j, target := range targets {
+				if newID, ok := mapping[target]; ok {
+					targets[j] = newID
+				}
+			}
+		}
+	}
+	for i := range f.Connections {
+		conn := &f.Connections[i]
+		conn.ID = generateID()
+		if newID, ok := mapping[conn.Source]; ok {
+			conn.Source = newID
+		}
+		if newID, ok := mapping[conn.Target]; ok {
+			conn.Target = newID
+		}
+	}
+
+	f.UpdatedAt = time.Now()
+	return mapping
+}
+
+// copyValue deep-copies JSON-like values (maps and slices)
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = copyValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copyValue(item)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
 // GetNode returns a node by ID
 func (f *Flow) GetNode(nodeID string) (*Node, bool) {
 	for i := range f.Nodes {
@@ -163,25 +364,123 @@ func (f *Flow) GetNode(nodeID string) (*Node, bool) {
 	return nil, false
 }
 
-// RemoveNode removes a node and its connections from the flow
+// RemoveNode removes a node from the flow together with its connections and
+// every wire of the remaining nodes targeting it, so wires and connections
+// stay consistent. It returns false if the node doesn't exist
 func (f *Flow) RemoveNode(nodeID string) bool {
-	// Remove the node
+	index := -1
 	for i, node := range f.Nodes {
 		if node.ID == nodeID {
-			f.Nodes = append(f.Nodes[:i], f.Nodes[i+1:]...)
+			index = i
 			break
 		}
 	}
+	if index < 0 {
+		return false
+	}
+	f.Nodes = append(f.Nodes[:index], f.Nodes[index+1:]...)
 
 	// Remove connections involving this node
 	f.Connections = f.filterConnections(func(conn Connection) bool {
 		return conn.Source != nodeID && conn.Target != nodeID
 	})
 
+	// Scrub wires pointing at the node; ports are kept so indexes stay valid
+	for i := range f.Nodes {
+		for port, targets := range f.Nodes[i].Wires {
+			kept := targets[:0]
+			for _, target := range targets {
+				if target != nodeID {
+					kept = append(kept, target)
+				}
+			}
+			f.Nodes[i].Wires[port] = kept
+		}
+	}
+
 	f.UpdatedAt = time.Now()
 	return true
 }
 
+// Normalize keeps the Node-RED style node wires and the connection list in
+// sync. A non-empty connection list is the source of truth and the wires are
+// regenerated from it (wire port index = SourcePort), so a connection deleted
+// from the list doesn't come back from a stale wire. Only a flow without
+// connections gets them derived from its wires (target port 0)
+func (f *Flow) Normalize() {
+	if len(f.Connections) > 0 {
+		for i := range f.Nodes {
+			for port := range f.Nodes[i].Wires {
+				f.Nodes[i].Wires[port] = []string{}
+			}
+		}
+		for _, conn := range f.Connections {
+			if conn.SourcePort < 0 {
+				continue
+			}
+			node, ok := f.GetNode(conn.Source)
+			if !ok {
+				continue
+			}
+			for len(node.Wires) <= conn.SourcePort {
+				node.Wires = append(node.Wires, []string{})
+			}
+			node.Wires[conn.SourcePort] = append(node.Wires[conn.SourcePort], conn.Target)
+		}
+		return
+	}
+
+	type link struct {
+		source string
+		port   int
+		target string
+	}
+	wired := make(map[link]bool)
+	for _, node := range f.Nodes {
+		for port, targets := range node.Wires {
+			for _, target := range targets {
+				key := link{node.ID, port, target}
+				if wired[key] {
+					continue
+				}
+				wired[key] = true
+				f.Connections = append(f.Connections, Connection{
+					ID:         generateID(),
+					Source:     node.ID,
+					SourcePort: port,
+					Target:     target,
+				})
+			}
+		}
+	}
+}
+
+// HasPath reports whether messages can travel from one node to another
+// along the flow's connections
+func (f *Flow) HasPath(from, to string) bool {
+	targets := make(map[string][]string)
+	for _, conn := range f.Connections {
+		targets[conn.Source] = append(targets[conn.Source], conn.Target)
+	}
+
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			return true
+		}
+		for _, next := range targets[current] {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
 // filterConnections is a helper function to filter connections
 func (f *Flow) filterConnections(predicate func(Connection) bool) []Connection {
 	filtered := make([]Connection, 0)
@@ -213,6 +512,15 @@ func (f *Flow) Validate() error {
 		if !nodeIDs[conn.Target] {
 			return NewValidationError("connection references non-existent target node: " + conn.Target)
 		}
+		if err := conn.Transform.Validate(); err != nil {
+			return NewValidationError("connection " + conn.ID + " has an invalid transform: " + err.Error())
+		}
+		if conn.MinInterval < 0 {
+			return NewValidationError("connection " + conn.ID + " has a negative min_interval")
+		}
+		if conn.RatePolicy != "" && conn.RatePolicy != RatePolicyDelay && conn.RatePolicy != RatePolicyDrop {
+			return NewValidationError("connection " + conn.ID + " has an invalid rate_policy: " + conn.RatePolicy)
+		}
 	}
 
 	return nil
@@ -226,8 +534,9 @@ func (f *Flow) ToJSON() ([]byte, error) {
 // FromJSON creates a flow from JSON data
 func FromJSON(data []byte) (*Flow, error) {
 	var flow Flow
-	if err := json.Unmarshal(data, &flow); err != nil {
+	if err := DecodeJSON(data, &flow); err != nil {
 		return nil, err
 	}
+	flow.Normalize()
 	return &flow, nil
 }
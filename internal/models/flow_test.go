@@ -0,0 +1,178 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlowNormalize(t *testing.T) {
+	type link struct {
+		Source string
+		Port   int
+		Target string
+	}
+
+	tests := []struct {
+		name      string
+		wires     map[string][][]string
+		conns     []link
+		wantWires map[string][][]string
+		wantConns []link
+	}{
+		{
+			name:      "connections derived from wires",
+			wires:     map[string][][]string{"a": {{"b"}, {"c"}}},
+			wantWires: map[string][][]string{"a": {{"b"}, {"c"}}},
+			wantConns: []link{{"a", 0, "b"}, {"a", 1, "c"}},
+		},
+		{
+			name:      "wires derived from connections",
+			conns:     []link{{"a", 0, "b"}, {"b", 0, "c"}},
+			wantWires: map[string][][]string{"a": {{"b"}}, "b": {{"c"}}},
+			wantConns: []link{{"a", 0, "b"}, {"b", 0, "c"}},
+		},
+		{
+			name:      "deleted connection drops its stale wire",
+			wires:     map[string][][]string{"a": {{"b", "c"}}},
+			conns:     []link{{"a", 0, "b"}},
+			wantWires: map[string][][]string{"a": {{"b"}}},
+			wantConns: []link{{"a", 0, "b"}},
+		},
+		{
+			name:      "stale wire on another port keeps the port",
+			wires:     map[string][][]string{"a": {{"b"}, {"c"}}},
+			conns:     []link{{"a", 0, "b"}},
+			wantWires: map[string][][]string{"a": {{"b"}, {}}},
+			wantConns: []link{{"a", 0, "b"}},
+		},
+		{
+			name:      "duplicate wires get one connection",
+			wires:     map[string][][]string{"a": {{"b", "b"}}},
+			wantWires: map[string][][]string{"a": {{"b", "b"}}},
+			wantConns: []link{{"a", 0, "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flow := &Flow{}
+			for _, id := range []string{"a", "b", "c"} {
+				flow.Nodes = append(flow.Nodes, Node{ID: id, Wires: tt.wires[id]})
+			}
+			for _, conn := range tt.conns {
+				flow.Connections = append(flow.Connections, Connection{ID: NewID(), Source: conn.Source, SourcePort: conn.Port, Target: conn.Target})
+			}
+
+			flow.Normalize()
+
+			for _, node := range flow.Nodes {
+				want := tt.wantWires[node.ID]
+				if len(want) == 0 && len(node.Wires) == 0 {
+					continue
+				}
+				if !reflect.DeepEqual(node.Wires, want) {
+					t.Errorf("wires of %s = %v, want %v", node.ID, node.Wires, want)
+				}
+			}
+			conns := make([]link, 0, len(flow.Connections))
+			for _, conn := range flow.Connections {
+				conns = append(conns, link{conn.Source, conn.SourcePort, conn.Target})
+			}
+			if !reflect.DeepEqual(conns, tt.wantConns) {
+				t.Errorf("connections = %v, want %v", conns, tt.wantConns)
+			}
+		})
+	}
+}
+
+func TestFlowCloneNode(t *testing.T) {
+	flow := &Flow{
+		Nodes: []Node{
+			{ID: "a", Type: "function", X: 100, Y: 40, Properties: map[string]interface{}{
+				"expression": "x * 2",
+				"options":    map[string]interface{}{"strict": true},
+				"tags":       []interface{}{"one"},
+			}},
+			{ID: "b", Type: "debug"},
+		},
+		Connections: []Connection{{ID: "a-b", Source: "a", Target: "b"}},
+	}
+	flow.Normalize()
+
+	clone, ok := flow.CloneNode("a")
+	if !ok {
+		t.Fatal("CloneNode(a) = false, want true")
+	}
+	if clone.ID == "" || clone.ID == "a" || clone.ID == "b" {
+		t.Errorf("clone ID = %q, want a fresh one", clone.ID)
+	}
+	if clone.Type != "function" || clone.X != 100+NodeCloneOffset || clone.Y != 40+NodeCloneOffset {
+		t.Errorf("clone = %s at (%v, %v), want a function at the offset position", clone.Type, clone.X, clone.Y)
+	}
+	if len(flow.Nodes) != 3 || len(flow.Connections) != 1 || len(clone.Wires) != 0 {
+		t.Errorf("%d nodes, %d connections, clone wires %v: want the clone added unconnected", len(flow.Nodes), len(flow.Connections), clone.Wires)
+	}
+
+	original, _ := flow.GetNode("a")
+	if !reflect.DeepEqual(clone.Properties, original.Properties) {
+		t.Errorf("clone properties = %v, want %v", clone.Properties, original.Properties)
+	}
+	// Editing the clone, nested values included, leaves the original alone
+	clone.Properties["expression"] = "x"
+	clone.Properties["options"].(map[string]interface{})["strict"] = false
+	clone.Properties["tags"].([]interface{})[0] = "two"
+	want := map[string]interface{}{
+		"expression": "x * 2",
+		"options":    map[string]interface{}{"strict": true},
+		"tags":       []interface{}{"one"},
+	}
+	if !reflect.DeepEqual(original.Properties, want) {
+		t.Errorf("original properties changed to %v", original.Properties)
+	}
+
+	if _, ok := flow.CloneNode("missing"); ok {
+		t.Error("CloneNode(missing) = true, want false")
+	}
+}
+
+func TestFlowRemoveNode(t *testing.T) {
+	flow := &Flow{
+		Nodes: []Node{
+			{ID: "a", Wires: [][]string{{"b", "c"}, {"b"}}},
+			{ID: "b", Wires: [][]string{{"c"}}},
+			{ID: "c"},
+		},
+	}
+	flow.Normalize()
+
+	if !flow.RemoveNode("b") {
+		t.Fatal("RemoveNode(b) = false, want true")
+	}
+
+	wantWires := map[string][][]string{"a": {{"c"}, {}}, "c": nil}
+	if len(flow.Nodes) != len(wantWires) {
+		t.Fatalf("%d nodes left, want %d", len(flow.Nodes), len(wantWires))
+	}
+	for _, node := range flow.Nodes {
+		want, ok := wantWires[node.ID]
+		if !ok {
+			t.Errorf("unexpected node %s left", node.ID)
+			continue
+		}
+		if len(want) == 0 && len(node.Wires) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(node.Wires, want) {
+			t.Errorf("wires of %s = %v, want %v", node.ID, node.Wires, want)
+		}
+	}
+	for _, conn := range flow.Connections {
+		if conn.Source == "b" || conn.Target == "b" {
+			t.Errorf("connection %s -> %s of the removed node kept", conn.Source, conn.Target)
+		}
+	}
+
+	if flow.RemoveNode("missing") {
+		t.Error("RemoveNode(missing) = true, want false")
+	}
+}
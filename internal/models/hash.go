@@ -0,0 +1,102 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// hashNode is the part of a node that contributes to a flow's hash. Wires are
+// left out; they are folded into the connections
+type hashNode struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	Name       string                 `json:"name"`
+	X          float64                `json:"x"`
+	Y          float64                `json:"y"`
+	Properties map[string]interface{} `json:"properties"`
+	Inputs     int                    `json:"inputs"`
+	Outputs    int                    `json:"outputs"`
+}
+
+// hashFlow is the canonical form of a flow that is hashed
+type hashFlow struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Nodes       []hashNode        `json:"nodes"`
+	Connections []string          `json:"connections"`
+	Properties  map[string]string `json:"properties"`
+}
+
+// Hash returns a deterministic SHA-256 hex digest of the flow's content:
+// name, description, nodes, connections and properties. The ID, timestamps,
+// version and active flag are excluded, as are connection IDs, which are
+// generated. Nodes and connections are sorted and wires are merged into the
+// connections, so logically equal flows hash identically regardless of order
+func (f *Flow) Hash() string {
+	canonical := hashFlow{
+		Name:        f.Name,
+		Description: f.Description,
+		Nodes:       make([]hashNode, 0, len(f.Nodes)),
+		Connections: make([]string, 0, len(f.Connections)),
+	}
+	if len(f.Properties) > 0 { // nil and empty hash the same
+		canonical.Properties = f.Properties
+	}
+
+	type link struct {
+		source string
+		port   int
+		target string
+	}
+	linked := make(map[link]bool, len(f.Connections))
+	addConnection := func(conn Connection) {
+		conn.ID = ""
+		data, _ := json.Marshal(conn)
+		canonical.Connections = append(canonical.Connections, string(data))
+	}
+
+	for _, conn := range f.Connections {
+		linked[link{conn.Source, conn.SourcePort, conn.Target}] = true
+		addConnection(conn)
+	}
+
+	for _, node := range f.Nodes {
+		hashed := hashNode{
+			ID:      node.ID,
+			Type:    node.Type,
+			Name:    node.Name,
+			X:       node.X,
+			Y:       node.Y,
+			Inputs:  node.Inputs,
+			Outputs: node.Outputs,
+		}
+		if len(node.Properties) > 0 {
+			hashed.Properties = node.Properties
+		}
+		canonical.Nodes = append(canonical.Nodes, hashed)
+
+		// Wires without a connection count as one, like Normalize would add
+		for port, targets := range node.Wires {
+			for _, target := range targets {
+				key := link{node.ID, port, target}
+				if linked[key] {
+					continue
+				}
+				linked[key] = true
+				addConnection(Connection{Source: node.ID, SourcePort: port, Target: target})
+			}
+		}
+	}
+
+	sort.Slice(canonical.Nodes, func(i, j int) bool {
+		return canonical.Nodes[i].ID < canonical.Nodes[j].ID
+	})
+	sort.Strings(canonical.Connections)
+
+	// Maps are encoded with sorted keys, so the encoding is stable
+	data, _ := json.Marshal(canonical)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
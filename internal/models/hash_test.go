@@ -0,0 +1,95 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFlowHash(t *testing.T) {
+	// base builds the same flow afresh for every case
+	base := func() *Flow {
+		return &Flow{
+			ID:   "f",
+			Name: "orders",
+			Nodes: []Node{
+				{ID: "a", Type: "inject", Properties: map[string]interface{}{"interval": 1000.0}},
+				{ID: "b", Type: "function", Properties: map[string]interface{}{"code": "return msg"}},
+				{ID: "c", Type: "debug"},
+			},
+			Connections: []Connection{
+				{ID: "c1", Source: "a", Target: "b"},
+				{ID: "c2", Source: "b", Target: "c"},
+			},
+			Properties: map[string]string{"max_payload_size": "1024"},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		change    func(f *Flow)
+		wantEqual bool
+	}{
+		{name: "unchanged", change: func(*Flow) {}, wantEqual: true},
+		{
+			name: "node order",
+			change: func(f *Flow) {
+				f.Nodes[0], f.Nodes[2] = f.Nodes[2], f.Nodes[0]
+			},
+			wantEqual: true,
+		},
+		{
+			name: "connection order",
+			change: func(f *Flow) {
+				f.Connections[0], f.Connections[1] = f.Connections[1], f.Connections[0]
+			},
+			wantEqual: true,
+		},
+		{
+			name: "volatile fields",
+			change: func(f *Flow) {
+				f.ID = "other"
+				f.UpdatedAt = time.Now()
+				f.CreatedAt = time.Now()
+				f.Version = "2"
+				f.Active = true
+				f.Connections[0].ID = "regenerated"
+			},
+			wantEqual: true,
+		},
+		{
+			name: "wires instead of connections",
+			change: func(f *Flow) {
+				f.Connections = nil
+				f.Nodes[0].Wires = [][]string{{"b"}}
+				f.Nodes[1].Wires = [][]string{{"c"}}
+			},
+			wantEqual: true,
+		},
+		{name: "flow properties removed", change: func(f *Flow) { f.Properties = nil }},
+		{name: "node property", change: func(f *Flow) { f.Nodes[1].Properties["code"] = "return null" }},
+		{name: "flow property", change: func(f *Flow) { f.Properties["max_payload_size"] = "2048" }},
+		{name: "node type", change: func(f *Flow) { f.Nodes[2].Type = "log" }},
+		{name: "name", change: func(f *Flow) { f.Name = "invoices" }},
+		{name: "connection target", change: func(f *Flow) { f.Connections[1].Target = "a" }},
+		{name: "extra node", change: func(f *Flow) { f.Nodes = append(f.Nodes, Node{ID: "d", Type: "debug"}) }},
+	}
+
+	want := base().Hash()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flow := base()
+			tt.change(flow)
+			if got := flow.Hash(); (got == want) != tt.wantEqual {
+				t.Errorf("hash equal = %v, want %v", got == want, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func TestFlowHashEmptyProperties(t *testing.T) {
+	withNil := &Flow{Name: "f", Nodes: []Node{{ID: "a", Type: "debug"}}}
+	withEmpty := &Flow{Name: "f", Nodes: []Node{{ID: "a", Type: "debug", Properties: map[string]interface{}{}}}, Properties: map[string]string{}}
+	if withNil.Hash() != withEmpty.Hash() {
+		t.Error("nil and empty properties hash differently")
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync/atomic"
+)
+
+// preserveNumbers makes DecodeJSON keep JSON numbers as json.Number instead
+// of float64, so integers such as large IDs survive without precision loss
+var preserveNumbers atomic.Bool
+
+// SetPreserveNumbers toggles decoding of JSON numbers as json.Number
+func SetPreserveNumbers(enabled bool) {
+	preserveNumbers.Store(enabled)
+}
+
+// PreserveNumbers reports whether JSON numbers are decoded as json.Number
+func PreserveNumbers() bool {
+	return preserveNumbers.Load()
+}
+
+// NewDecoder returns a JSON decoder honouring the number handling setting
+func NewDecoder(r io.Reader) *json.Decoder {
+	decoder := json.NewDecoder(r)
+	if preserveNumbers.Load() {
+		decoder.UseNumber()
+	}
+	return decoder
+}
+
+// DecodeJSON unmarshals data like json.Unmarshal, honouring the number
+// handling setting
+func DecodeJSON(data []byte, v interface{}) error {
+	if !preserveNumbers.Load() {
+		return json.Unmarshal(data, v)
+	}
+	return NewDecoder(bytes.NewReader(data)).Decode(v)
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPreserveNumbers(t *testing.T) {
+	const data = `{"id":"m1","payload":{"order_id":9007199254740993}}`
+
+	decode := func(t *testing.T) *Message {
+		t.Helper()
+		var msg Message
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return &msg
+	}
+
+	t.Run("large integer survives a round trip", func(t *testing.T) {
+		SetPreserveNumbers(true)
+		t.Cleanup(func() { SetPreserveNumbers(false) })
+
+		msg := decode(t)
+		id, ok := msg.Payload.(map[string]interface{})["order_id"].(json.Number)
+		if !ok {
+			t.Fatalf("order_id decoded as %T, want json.Number", msg.Payload.(map[string]interface{})["order_id"])
+		}
+		if id.String() != "9007199254740993" {
+			t.Errorf("order_id = %s, want 9007199254740993", id)
+		}
+
+		encoded, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+		if !strings.Contains(string(encoded), `"order_id":9007199254740993`) {
+			t.Errorf("re-encoded message %s lost the integer", encoded)
+		}
+	})
+
+	t.Run("float64 by default", func(t *testing.T) {
+		msg := decode(t)
+		if _, ok := msg.Payload.(map[string]interface{})["order_id"].(float64); !ok {
+			t.Errorf("order_id decoded as %T, want float64", msg.Payload.(map[string]interface{})["order_id"])
+		}
+	})
+}
@@ -2,19 +2,46 @@ package models
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
+// Content types describing how a message payload should be interpreted
+const (
+	ContentTypeJSON   = "application/json"
+	ContentTypeText   = "text/plain"
+	ContentTypeBinary = "application/octet-stream"
+)
+
 // Message represents a message passed between blocks in a flow
 type Message struct {
-	ID        string                 `json:"id"`
-	Payload   interface{}            `json:"payload"` // Main message payload
-	Topic     string                 `json:"topic,omitempty"`
-	Headers   map[string]string      `json:"headers,omitempty"`
-	Timestamp time.Time              `json:"timestamp"`
-	Source    string                 `json:"source"`            // Source node ID
-	Target    string                 `json:"target"`            // Target node ID
-	Context   map[string]interface{} `json:"context,omitempty"` // Execution context
+	ID          string                 `json:"id"`
+	Payload     interface{}            `json:"payload"`                // Main message payload
+	ContentType string                 `json:"content_type,omitempty"` // Payload interpretation; empty means structured JSON
+	Topic       string                 `json:"topic,omitempty"`
+	Headers     map[string]string      `json:"headers,omitempty"`
+	Timestamp   time.Time              `json:"timestamp"`
+	Source      string                 `json:"source"`            // Source node ID
+	Target      string                 `json:"target"`            // Target node ID
+	Context     map[string]interface{} `json:"context,omitempty"` // Execution context
+
+	// Deadline by which the message and everything derived from it must be
+	// processed; nodes drop it once passed. Preserved by Clone
+	Deadline *time.Time `json:"deadline,omitempty"`
+
+	// Port is the output port the emitting block sends the message on.
+	// It is routing information only and is reset by Clone
+	Port int `json:"-"`
+
+	// InputPort is the input port the message was delivered to, set by the
+	// engine on delivery. It is routing information only and is reset by Clone
+	InputPort int `json:"-"`
+
+	// Delivery optionally tracks acknowledgement of the originating message.
+	// The engine propagates it to derived messages; Clone doesn't copy it
+	Delivery *Delivery `json:"-"`
 }
 
 // NewMessage creates a new message
@@ -28,15 +55,40 @@ func NewMessage(payload interface{}) *Message {
 	}
 }
 
-// Clone creates a deep copy of the message
+// NewBinaryMessage creates a new message carrying raw bytes
+func NewBinaryMessage(data []byte) *Message {
+	msg := NewMessage(data)
+	msg.ContentType = ContentTypeBinary
+	return msg
+}
+
+// IsBinary reports whether the payload is raw bytes
+func (m *Message) IsBinary() bool {
+	_, ok := m.Payload.([]byte)
+	return ok || m.ContentType == ContentTypeBinary
+}
+
+// Clone creates a deep copy of the message with a new ID. The timestamp is
+// kept, so it records when the original message entered the flow
 func (m *Message) Clone() *Message {
+	payload := m.Payload // Note: shallow copy of structured payloads
+	if data, ok := payload.([]byte); ok {
+		// Byte slices are mutable, so binary payloads are always copied
+		payload = append([]byte(nil), data...)
+	}
+
 	clone := &Message{
-		ID:        generateID(), // New ID for cloned message
-		Payload:   m.Payload,    // Note: shallow copy of payload
-		Topic:     m.Topic,
-		Timestamp: time.Now(),
-		Source:    m.Source,
-		Target:    m.Target,
+		ID:          generateID(), // New ID for cloned message
+		Payload:     payload,
+		ContentType: m.ContentType,
+		Topic:       m.Topic,
+		Timestamp:   m.Timestamp,
+		Source:      m.Source,
+		Target:      m.Target,
+	}
+	if m.Deadline != nil {
+		deadline := *m.Deadline
+		clone.Deadline = &deadline
 	}
 
 	// Deep copy headers
@@ -58,6 +110,40 @@ func (m *Message) Clone() *Message {
 	return clone
 }
 
+// messageJSON avoids recursion in the custom JSON methods
+type messageJSON Message
+
+// MarshalJSON encodes the message, marking byte payloads as binary so they
+// are base64-encoded and can be decoded back into bytes
+func (m *Message) MarshalJSON() ([]byte, error) {
+	out := messageJSON(*m)
+	if _, ok := m.Payload.([]byte); ok && out.ContentType == "" {
+		out.ContentType = ContentTypeBinary
+	}
+	return json.Marshal(&out)
+}
+
+// UnmarshalJSON decodes the message, restoring base64-encoded binary payloads to bytes
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var in messageJSON
+	if err := DecodeJSON(data, &in); err != nil {
+		return err
+	}
+
+	if in.ContentType == ContentTypeBinary {
+		if encoded, ok := in.Payload.(string); ok {
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return fmt.Errorf("invalid base64 binary payload: %w", err)
+			}
+			in.Payload = decoded
+		}
+	}
+
+	*m = Message(in)
+	return nil
+}
+
 // SetHeader sets a header value
 func (m *Message) SetHeader(key, value string) {
 	if m.Headers == nil {
@@ -102,6 +188,9 @@ type BlockExecutionContext struct {
 	State     map[string]interface{} // Block-specific state storage
 	Debug     bool
 	Timestamp time.Time
+
+	// FlowProperties are the properties of the running flow, read-only
+	FlowProperties map[string]string
 }
 
 // NewBlockExecutionContext creates a new block execution context
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBinaryPayload(t *testing.T) {
+	data := []byte{0x00, 0xff, 0x10, 'b', 'f'}
+
+	t.Run("clone copies the bytes", func(t *testing.T) {
+		msg := NewBinaryMessage(append([]byte(nil), data...))
+		clone := msg.Clone()
+
+		msg.Payload.([]byte)[0] = 0x42
+		got, ok := clone.Payload.([]byte)
+		if !ok || !bytes.Equal(got, data) {
+			t.Errorf("clone payload = %v, want %v", clone.Payload, data)
+		}
+		if clone.ContentType != ContentTypeBinary {
+			t.Errorf("clone content type = %q, want %q", clone.ContentType, ContentTypeBinary)
+		}
+	})
+
+	t.Run("execution serializes bytes as base64", func(t *testing.T) {
+		execution := &FlowExecution{
+			ID:     "e1",
+			FlowID: "f1",
+			Messages: []ExecutionMessage{
+				{ID: "m1", NodeID: "n1", Type: "output", Message: NewMessage(data)},
+			},
+		}
+
+		encoded, err := json.Marshal(execution)
+		if err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+		if want := `"payload":"` + base64.StdEncoding.EncodeToString(data) + `"`; !strings.Contains(string(encoded), want) {
+			t.Errorf("encoded execution %s does not contain %s", encoded, want)
+		}
+
+		var decoded FlowExecution
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		msg := decoded.Messages[0].Message
+		if got, ok := msg.Payload.([]byte); !ok || !bytes.Equal(got, data) {
+			t.Errorf("decoded payload = %v (%T), want %v", msg.Payload, msg.Payload, data)
+		}
+		if !msg.IsBinary() {
+			t.Error("decoded message not reported as binary")
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package models
+
+// partsKey is the message context entry holding sequence information
+const partsKey = "parts"
+
+// Parts locates a message within a sequence of messages split from one
+// original message, so the sequence can be reassembled
+type Parts struct {
+	ID    string `json:"id"`    // Shared by all messages of the sequence
+	Index int    `json:"index"` // Position in the sequence, from 0
+	Count int    `json:"count"` // Number of messages in the sequence
+
+	// How the original payload was split, so it can be rebuilt: "array",
+	// "object" (each message carrying its Key) or "string" (split on Delimiter).
+	// Empty when unknown
+	Type      string `json:"type,omitempty"`
+	Key       string `json:"key,omitempty"`
+	Delimiter string `json:"delimiter,omitempty"`
+}
+
+// Parts types
+const (
+	PartsArray  = "array"
+	PartsObject = "object"
+	PartsString = "string"
+)
+
+// SetParts records the message's place in a sequence in its context
+func (m *Message) SetParts(parts Parts) {
+	if m.Context == nil {
+		m.Context = make(map[string]interface{})
+	}
+	entry := map[string]interface{}{
+		"id":    parts.ID,
+		"index": parts.Index,
+		"count": parts.Count,
+	}
+	if parts.Type != "" {
+		entry["type"] = parts.Type
+	}
+	if parts.Type == PartsObject {
+		entry["key"] = parts.Key
+	}
+	if parts.Type == PartsString {
+		entry["delimiter"] = parts.Delimiter
+	}
+	m.Context[partsKey] = entry
+}
+
+// GetParts returns the message's place in a sequence, if it is part of one
+func (m *Message) GetParts() (Parts, bool) {
+	raw, ok := m.Context[partsKey].(map[string]interface{})
+	if !ok {
+		return Parts{}, false
+	}
+
+	id, err := ToString(raw["id"])
+	if err != nil {
+		return Parts{}, false
+	}
+	index, err := ToNumber(raw["index"])
+	if err != nil {
+		return Parts{}, false
+	}
+	count, err := ToNumber(raw["count"])
+	if err != nil {
+		return Parts{}, false
+	}
+	parts := Parts{ID: id, Index: int(index), Count: int(count)}
+	parts.Type, _ = raw["type"].(string)
+	parts.Key, _ = raw["key"].(string)
+	parts.Delimiter, _ = raw["delimiter"].(string)
+	return parts, true
+}
+
+// ClearParts removes the message from its sequence, e.g. once the sequence
+// has been reassembled
+func (m *Message) ClearParts() {
+	delete(m.Context, partsKey)
+}
+
+// Expand splits a message with an array payload into one message per
+// element, each a clone carrying the element and its Parts. Any other
+// message is returned as is
+func (m *Message) Expand() []*Message {
+	elements, ok := m.Payload.([]interface{})
+	if !ok {
+		return []*Message{m}
+	}
+
+	sequenceID := generateID()
+	messages := make([]*Message, len(elements))
+	for i, element := range elements {
+		msg := m.Clone()
+		msg.Payload = element
+		msg.SetParts(Parts{ID: sequenceID, Index: i, Count: len(elements), Type: PartsArray})
+		messages[i] = msg
+	}
+	return messages
+}
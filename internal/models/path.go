@@ -0,0 +1,160 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is a single step of a payload path: a map key or a slice index
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parsePath parses a dot/bracket path such as "data.items[0].name".
+// A leading "payload" segment is accepted and ignored
+func parsePath(path string) ([]pathSegment, error) {
+	path = strings.TrimSpace(path)
+	if rest, ok := strings.CutPrefix(path, "payload"); ok && (rest == "" || rest[0] == '.' || rest[0] == '[') {
+		path = strings.TrimPrefix(rest, ".")
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	segments := make([]pathSegment, 0)
+	for _, part := range strings.Split(path, ".") {
+		name := part
+		var indexes []string
+		if open := strings.Index(part, "["); open >= 0 {
+			name = part[:open]
+			rest := part[open:]
+			for rest != "" {
+				if rest[0] != '[' {
+					return nil, fmt.Errorf("invalid path '%s': unexpected '%s'", path, rest)
+				}
+				end := strings.Index(rest, "]")
+				if end < 0 {
+					return nil, fmt.Errorf("invalid path '%s': unclosed '['", path)
+				}
+				indexes = append(indexes, rest[1:end])
+				rest = rest[end+1:]
+			}
+		}
+
+		if name == "" && len(indexes) == 0 {
+			return nil, fmt.Errorf("invalid path '%s': empty segment", path)
+		}
+		if name != "" {
+			segments = append(segments, pathSegment{key: name})
+		}
+		for _, idx := range indexes {
+			n, err := strconv.Atoi(idx)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid path '%s': bad index '%s'", path, idx)
+			}
+			segments = append(segments, pathSegment{index: n, isIndex: true})
+		}
+	}
+
+	return segments, nil
+}
+
+// ValidatePath checks that a payload path is syntactically valid
+func ValidatePath(path string) error {
+	_, err := parsePath(path)
+	return err
+}
+
+// ResolvePath looks up a dot/bracket path (e.g. "data.items[0].name") within
+// a value. An empty path returns the value itself. The boolean result is
+// false when any segment of the path does not exist
+func ResolvePath(value interface{}, path string) (interface{}, bool, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	current := value
+	for _, segment := range segments {
+		if segment.isIndex {
+			list, ok := current.([]interface{})
+			if !ok || segment.index >= len(list) {
+				return nil, false, nil
+			}
+			current = list[segment.index]
+			continue
+		}
+
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false, nil
+		}
+		current, ok = object[segment.key]
+		if !ok {
+			return nil, false, nil
+		}
+	}
+
+	return current, true, nil
+}
+
+// SetPath returns a copy of value with the element at path replaced by
+// newValue. Maps and slices along the path are copied, so the original value
+// (which may be shared between messages) is left untouched. Missing map keys
+// are created; slice indexes must already exist. An empty path replaces the
+// whole value
+func SetPath(value interface{}, path string, newValue interface{}) (interface{}, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return setSegments(value, segments, newValue)
+}
+
+func setSegments(current interface{}, segments []pathSegment, newValue interface{}) (interface{}, error) {
+	if len(segments) == 0 {
+		return newValue, nil
+	}
+	segment := segments[0]
+
+	if segment.isIndex {
+		list, ok := current.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot index %T", current)
+		}
+		if segment.index >= len(list) {
+			return nil, fmt.Errorf("index %d out of range", segment.index)
+		}
+		child, err := setSegments(list[segment.index], segments[1:], newValue)
+		if err != nil {
+			return nil, err
+		}
+		copied := append([]interface{}(nil), list...)
+		copied[segment.index] = child
+		return copied, nil
+	}
+
+	var object map[string]interface{}
+	switch v := current.(type) {
+	case map[string]interface{}:
+		object = v
+	case nil:
+		object = map[string]interface{}{}
+	default:
+		return nil, fmt.Errorf("cannot set field '%s' on %T", segment.key, current)
+	}
+
+	child, err := setSegments(object[segment.key], segments[1:], newValue)
+	if err != nil {
+		return nil, err
+	}
+	copied := make(map[string]interface{}, len(object)+1)
+	for k, v := range object {
+		copied[k] = v
+	}
+	copied[segment.key] = child
+	return copied, nil
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrTypeMismatch is wrapped by the value and payload accessors when a value
+// doesn't have the requested type
+var ErrTypeMismatch = errors.New("type mismatch")
+
+func typeMismatch(want string, value interface{}) error {
+	got := "null"
+	if value != nil {
+		got = fmt.Sprintf("%T", value)
+	}
+	return fmt.Errorf("%w: expected %s, got %s", ErrTypeMismatch, want, got)
+}
+
+// ToNumber converts any Go numeric type or json.Number to float64. Strings
+// are not parsed
+func ToNumber(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("%w: invalid number %q", ErrTypeMismatch, v)
+		}
+		return f, nil
+	default:
+		return 0, typeMismatch("number", value)
+	}
+}
+
+// ToString returns a string value. Raw bytes are read as text
+func ToString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	default:
+		return "", typeMismatch("string", value)
+	}
+}
+
+// ToBool returns a boolean value
+func ToBool(value interface{}) (bool, error) {
+	if v, ok := value.(bool); ok {
+		return v, nil
+	}
+	return false, typeMismatch("boolean", value)
+}
+
+// ToMap returns a JSON object value
+func ToMap(value interface{}) (map[string]interface{}, error) {
+	if v, ok := value.(map[string]interface{}); ok {
+		return v, nil
+	}
+	return nil, typeMismatch("object", value)
+}
+
+// PayloadAsNumber returns the payload as a float64, see ToNumber
+func (m *Message) PayloadAsNumber() (float64, error) {
+	n, err := ToNumber(m.Payload)
+	if err != nil {
+		return 0, fmt.Errorf("payload: %w", err)
+	}
+	return n, nil
+}
+
+// PayloadAsString returns the payload as a string, see ToString
+func (m *Message) PayloadAsString() (string, error) {
+	s, err := ToString(m.Payload)
+	if err != nil {
+		return "", fmt.Errorf("payload: %w", err)
+	}
+	return s, nil
+}
+
+// PayloadAsBool returns the payload as a boolean
+func (m *Message) PayloadAsBool() (bool, error) {
+	b, err := ToBool(m.Payload)
+	if err != nil {
+		return false, fmt.Errorf("payload: %w", err)
+	}
+	return b, nil
+}
+
+// PayloadAsMap returns the payload as a JSON object. The map is the payload
+// itself, not a copy
+func (m *Message) PayloadAsMap() (map[string]interface{}, error) {
+	obj, err := ToMap(m.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("payload: %w", err)
+	}
+	return obj, nil
+}
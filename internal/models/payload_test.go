@@ -0,0 +1,66 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPayloadAccessors(t *testing.T) {
+	// accessors returns each PayloadAs* accessor of msg with a uniform signature
+	accessors := func(msg *Message) map[string]func() (interface{}, error) {
+		return map[string]func() (interface{}, error){
+			"number": func() (interface{}, error) { return msg.PayloadAsNumber() },
+			"string": func() (interface{}, error) { return msg.PayloadAsString() },
+			"bool":   func() (interface{}, error) { return msg.PayloadAsBool() },
+			"map":    func() (interface{}, error) { return msg.PayloadAsMap() },
+		}
+	}
+
+	tests := []struct {
+		name     string
+		accessor string
+		payload  interface{}
+		want     interface{}
+	}{
+		{name: "float", accessor: "number", payload: 2.5, want: 2.5},
+		{name: "int", accessor: "number", payload: 7, want: 7.0},
+		{name: "json.Number", accessor: "number", payload: json.Number("12"), want: 12.0},
+		{name: "string", accessor: "string", payload: "text", want: "text"},
+		{name: "bytes as string", accessor: "string", payload: []byte("raw"), want: "raw"},
+		{name: "bool", accessor: "bool", payload: true, want: true},
+		{name: "map", accessor: "map", payload: map[string]interface{}{"a": 1.0}, want: map[string]interface{}{"a": 1.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := accessors(NewMessage(tt.payload))[tt.accessor]()
+			if err != nil {
+				t.Fatalf("PayloadAs %s: %v", tt.accessor, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PayloadAs %s = %v (%T), want %v (%T)", tt.accessor, got, got, tt.want, tt.want)
+			}
+		})
+	}
+
+	mismatches := []struct {
+		accessor string
+		payload  interface{}
+	}{
+		{accessor: "number", payload: "12"},
+		{accessor: "number", payload: json.Number("twelve")},
+		{accessor: "string", payload: 12.0},
+		{accessor: "bool", payload: "true"},
+		{accessor: "map", payload: []interface{}{1.0}},
+		{accessor: "map", payload: nil},
+	}
+	for _, tt := range mismatches {
+		t.Run(tt.accessor+" mismatch", func(t *testing.T) {
+			_, err := accessors(NewMessage(tt.payload))[tt.accessor]()
+			if !errors.Is(err, ErrTypeMismatch) {
+				t.Errorf("PayloadAs %s of %#v = %v, want ErrTypeMismatch", tt.accessor, tt.payload, err)
+			}
+		})
+	}
+}
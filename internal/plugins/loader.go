@@ -0,0 +1,123 @@
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	goplugin "plugin"
+	"sort"
+	"strings"
+
+	"block-flow/internal/blocks"
+)
+
+// Load discovers plugins in dir and registers their blocks. Go plugins are
+// loaded from *.so files; out-of-process HTTP blocks from *.block.json
+// manifests. A failing plugin is reported in its LoadResult and doesn't stop
+// the others from loading.
+//
+// Types in replaceable (blocks registered by a previous load) may be
+// overridden once; any other collision with an existing type is reported as
+// an error in the plugin's LoadResult and the existing block is kept
+func Load(dir string, registry *blocks.Registry, replaceable map[string]bool) ([]LoadResult, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []LoadResult{}, nil
+		}
+		return nil, fmt.Errorf("failed to read plugins directory: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	reg := &registrar{registry: registry, replaceable: make(map[string]bool, len(replaceable))}
+	for blockType := range replaceable {
+		reg.replaceable[blockType] = true
+	}
+
+	results := make([]LoadResult, 0)
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+
+		var result LoadResult
+		switch {
+		case strings.HasSuffix(name, ".so"):
+			result = loadGoPlugin(path, reg)
+		case strings.HasSuffix(name, ".block.json"):
+			result = loadRemoteBlock(path, reg)
+		default:
+			continue
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// registrar registers plugin blocks, letting each replaceable type be
+// overridden by the first plugin that provides it
+type registrar struct {
+	registry    *blocks.Registry
+	replaceable map[string]bool
+}
+
+func (r *registrar) register(factory blocks.BlockFactory) error {
+	blockType := factory.GetBlockInfo().Type
+	if r.replaceable[blockType] {
+		delete(r.replaceable, blockType)
+		r.registry.Replace(factory)
+		return nil
+	}
+	return r.registry.Register(factory)
+}
+
+// loadGoPlugin opens a Go plugin and registers its block factories
+func loadGoPlugin(path string, reg *registrar) LoadResult {
+	result := LoadResult{Source: path, Blocks: []string{}}
+
+	p, err := goplugin.Open(path)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to open plugin: %v", err)
+		return result
+	}
+
+	symbol, err := p.Lookup("NewPlugin")
+	if err != nil {
+		result.Error = "plugin does not export NewPlugin"
+		return result
+	}
+
+	constructor, ok := symbol.(func() Plugin)
+	if !ok {
+		result.Error = fmt.Sprintf("NewPlugin has unexpected type %T", symbol)
+		return result
+	}
+
+	instance := constructor()
+	result.Plugin = instance.GetInfo().Name
+	if err := instance.Initialize(); err != nil {
+		result.Error = fmt.Sprintf("failed to initialize plugin: %v", err)
+		return result
+	}
+
+	var collisions []string
+	for _, factory := range instance.GetBlocks() {
+		blockType := factory.GetBlockInfo().Type
+		if err := reg.register(factory); err != nil {
+			collisions = append(collisions, blockType)
+			continue
+		}
+		result.Blocks = append(result.Blocks, blockType)
+	}
+	if len(collisions) > 0 {
+		result.Error = fmt.Sprintf("block types already registered: %s", strings.Join(collisions, ", "))
+	}
+
+	return result
+}
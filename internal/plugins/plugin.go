@@ -0,0 +1,39 @@
+package plugins
+
+import (
+	"block-flow/internal/blocks"
+)
+
+// Plugin is implemented by block libraries loaded at runtime. Go plugins
+// must export a `NewPlugin func() plugins.Plugin` symbol
+type Plugin interface {
+	// GetInfo returns metadata about the plugin
+	GetInfo() PluginInfo
+
+	// GetBlocks returns the block factories provided by this plugin
+	GetBlocks() []blocks.BlockFactory
+
+	// Initialize is called when the plugin is loaded
+	Initialize() error
+
+	// Shutdown is called when the plugin is unloaded
+	Shutdown() error
+}
+
+// PluginInfo provides metadata about a plugin
+type PluginInfo struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+	Author      string `json:"author"`
+	License     string `json:"license"`
+	Website     string `json:"website,omitempty"`
+}
+
+// LoadResult reports the outcome of loading a single plugin source
+type LoadResult struct {
+	Source string   `json:"source"`           // File the plugin was loaded from
+	Plugin string   `json:"plugin,omitempty"` // Plugin name, when known
+	Blocks []string `json:"blocks"`           // Block types registered
+	Error  string   `json:"error,omitempty"`
+}
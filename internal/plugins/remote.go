@@ -0,0 +1,172 @@
+package plugins
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"block-flow/internal/blocks"
+	"block-flow/internal/models"
+)
+
+// RemoteBlockManifest describes a block implemented by an external HTTP
+// service. The service receives a RemoteExecuteRequest for every message
+// and answers with a RemoteExecuteResponse
+type RemoteBlockManifest struct {
+	blocks.BlockInfo
+	Endpoint   string                      `json:"endpoint"`
+	Timeout    string                      `json:"timeout,omitempty"` // Go duration, default 10s
+	Properties []blocks.PropertyDefinition `json:"properties,omitempty"`
+}
+
+// RemoteExecuteRequest is posted to a remote block's endpoint
+type RemoteExecuteRequest struct {
+	NodeID     string                 `json:"node_id"`
+	FlowID     string                 `json:"flow_id"`
+	Message    *models.Message        `json:"message,omitempty"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// RemoteExecuteResponse is returned by a remote block's endpoint
+type RemoteExecuteResponse struct {
+	Messages []*models.Message `json:"messages"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// loadRemoteBlock reads a remote block manifest and registers the block
+func loadRemoteBlock(path string, reg *registrar) LoadResult {
+	result := LoadResult{Source: path, Blocks: []string{}}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to read manifest: %v", err)
+		return result
+	}
+
+	factory, err := NewRemoteBlockFactory(data)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Plugin = factory.manifest.Name
+	if err := reg.register(factory); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Blocks = append(result.Blocks, factory.manifest.Type)
+	return result
+}
+
+// RemoteBlockFactory creates blocks backed by an external HTTP service
+type RemoteBlockFactory struct {
+	manifest RemoteBlockManifest
+	client   *http.Client
+}
+
+// NewRemoteBlockFactory creates a factory from a JSON manifest
+func NewRemoteBlockFactory(data []byte) (*RemoteBlockFactory, error) {
+	var manifest RemoteBlockManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if manifest.Type == "" || manifest.Endpoint == "" {
+		return nil, fmt.Errorf("manifest must define type and endpoint")
+	}
+	if manifest.BlockGroup == "" {
+		manifest.BlockGroup = blocks.PropagationGroup
+	}
+	if manifest.Name == "" {
+		manifest.Name = manifest.Type
+	}
+
+	timeout := 10 * time.Second
+	if manifest.Timeout != "" {
+		parsed, err := time.ParseDuration(manifest.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout: %w", err)
+		}
+		timeout = parsed
+	}
+
+	return &RemoteBlockFactory{
+		manifest: manifest,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (f *RemoteBlockFactory) CreateBlock() blocks.Block {
+	return &RemoteBlock{manifest: f.manifest, client: f.client}
+}
+
+func (f *RemoteBlockFactory) GetBlockInfo() blocks.BlockInfo {
+	return f.manifest.BlockInfo
+}
+
+// RemoteBlock forwards execution to an external HTTP service
+type RemoteBlock struct {
+	manifest RemoteBlockManifest
+	client   *http.Client
+}
+
+func (b *RemoteBlock) GetType() string                            { return b.manifest.Type }
+func (b *RemoteBlock) GetName() string                            { return b.manifest.Name }
+func (b *RemoteBlock) GetDescription() string                     { return b.manifest.Description }
+func (b *RemoteBlock) GetCategory() string                        { return b.manifest.Category }
+func (b *RemoteBlock) GetBlockGroup() blocks.BlockGroup           { return b.manifest.BlockGroup }
+func (b *RemoteBlock) GetInputs() int                             { return b.manifest.Inputs }
+func (b *RemoteBlock) GetOutputs() int                            { return b.manifest.Outputs }
+func (b *RemoteBlock) GetProperties() []blocks.PropertyDefinition { return b.manifest.Properties }
+
+func (b *RemoteBlock) Validate(properties map[string]interface{}) error {
+	return nil
+}
+
+func (b *RemoteBlock) Execute(ctx *models.BlockExecutionContext, properties map[string]interface{}) ([]*models.Message, error) {
+	body, err := json.Marshal(RemoteExecuteRequest{
+		NodeID:     ctx.NodeID,
+		FlowID:     ctx.FlowID,
+		Message:    ctx.Message,
+		Properties: properties,
+	})
+	if err != nil {
+		return nil, blocks.Invalidf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, b.manifest.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, blocks.Fatal(fmt.Errorf("invalid endpoint: %w", err))
+	}
+	if ctx.Context != nil {
+		req = req.WithContext(ctx.Context)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, blocks.Transient(fmt.Errorf("remote block request failed: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return nil, blocks.Transient(fmt.Errorf("remote block returned status %d", resp.StatusCode))
+	}
+
+	var result RemoteExecuteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, blocks.Transient(fmt.Errorf("invalid remote block response: %w", err))
+	}
+	if result.Error != "" || resp.StatusCode >= 400 {
+		return nil, blocks.Invalidf("remote block error (status %d): %s", resp.StatusCode, result.Error)
+	}
+
+	for _, msg := range result.Messages {
+		if msg.Source == "" {
+			msg.Source = ctx.NodeID
+		}
+	}
+	return result.Messages, nil
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"block-flow/internal/models"
 )
@@ -14,6 +18,8 @@ import (
 // FileStorage implements Storage interface using the file system
 type FileStorage struct {
 	dataDir string
+	index   *executionIndex // Built on the first summary, nil until then
+	flows   flowIndex       // Built on the first listing, nil until then
 	mu      sync.RWMutex
 }
 
@@ -42,9 +48,12 @@ func (fs *FileStorage) SaveFlow(ctx context.Context, flow *models.Flow) error {
 		return fmt.Errorf("failed to marshal flow: %w", err)
 	}
 
-	if err := os.WriteFile(filename, data, 0o644); err != nil {
+	if err := writeFileAtomic(filename, data); err != nil {
 		return fmt.Errorf("failed to write flow file: %w", err)
 	}
+	if fs.flows != nil {
+		fs.flows[flow.ID] = flowHeader{Name: flow.Name, Active: flow.Active}
+	}
 
 	return nil
 }
@@ -65,7 +74,7 @@ func (fs *FileStorage) LoadFlow(ctx context.Context, flowID string) (*models.Flo
 
 	flow, err := models.FromJSON(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal flow: %w", err)
+		return nil, decodeError("flow", err)
 	}
 
 	return flow, nil
@@ -106,6 +115,102 @@ func (fs *FileStorage) LoadAllFlows(ctx context.Context) ([]*models.Flow, error)
 	return flows, nil
 }
 
+// ListFlows returns the page of flows selected by the query and how many
+// flows match in total. Flow files are decoded once, into an index of their
+// names and active flags kept up to date as flows are saved and deleted;
+// afterwards only the flows on the page are read. Unreadable files are
+// neither listed nor counted
+func (fs *FileStorage) ListFlows(ctx context.Context, query FlowQuery) ([]*models.Flow, int, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.flows == nil {
+		index, err := fs.buildFlowIndex()
+		if err != nil {
+			return nil, 0, err
+		}
+		fs.flows = index
+	}
+
+	for {
+		selected := fs.flows.selected(query)
+		flows := make([]*models.Flow, 0)
+		complete := true
+		for i, flowID := range selected {
+			if !query.InPage(i) {
+				continue
+			}
+			flow, err := fs.readFlow(flowID)
+			if err != nil {
+				// Changed on disk since it was indexed; drop it and list again
+				// so the total stays consistent with the page
+				delete(fs.flows, flowID)
+				complete = false
+				break
+			}
+			flows = append(flows, flow)
+		}
+		if complete {
+			return flows, len(selected), nil
+		}
+	}
+}
+
+// buildFlowIndex decodes every stored flow. Files that aren't valid flows
+// are skipped. Callers must hold fs.mu
+func (fs *FileStorage) buildFlowIndex() (flowIndex, error) {
+	flowIDs, err := listJSONFiles(filepath.Join(fs.dataDir, "flows"))
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(flowIndex, len(flowIDs))
+	for _, flowID := range flowIDs {
+		flow, err := fs.readFlow(flowID)
+		if err != nil {
+			continue
+		}
+		index[flowID] = flowHeader{Name: flow.Name, Active: flow.Active}
+	}
+	return index, nil
+}
+
+// readFlow reads and decodes a flow file. Callers must hold fs.mu
+func (fs *FileStorage) readFlow(flowID string) (*models.Flow, error) {
+	data, err := os.ReadFile(filepath.Join(fs.dataDir, "flows", flowID+".json"))
+	if err != nil {
+		return nil, err
+	}
+	return models.FromJSON(data)
+}
+
+// ListFlowSummaries returns the metadata of all flows, ordered by ID. Only
+// the top-level fields of each file are decoded; unreadable files are skipped
+func (fs *FileStorage) ListFlowSummaries(ctx context.Context) ([]*models.FlowSummary, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	flowsDir := filepath.Join(fs.dataDir, "flows")
+	flowIDs, err := listJSONFiles(flowsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*models.FlowSummary, 0, len(flowIDs))
+	for _, flowID := range flowIDs {
+		data, err := os.ReadFile(filepath.Join(flowsDir, flowID+".json"))
+		if err != nil {
+			continue
+		}
+		summary, err := models.DecodeFlowSummary(data)
+		if err != nil {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
 // DeleteFlow deletes a flow file
 func (fs *FileStorage) DeleteFlow(ctx context.Context, flowID string) error {
 	fs.mu.Lock()
@@ -118,6 +223,7 @@ func (fs *FileStorage) DeleteFlow(ctx context.Context, flowID string) error {
 		}
 		return fmt.Errorf("failed to delete flow file: %w", err)
 	}
+	delete(fs.flows, flowID)
 
 	return nil
 }
@@ -132,6 +238,209 @@ func (fs *FileStorage) FlowExists(ctx context.Context, flowID string) bool {
 	return err == nil
 }
 
+// ListFlowIDs returns the IDs of all stored flows without loading them
+func (fs *FileStorage) ListFlowIDs(ctx context.Context) ([]string, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	return listJSONFiles(filepath.Join(fs.dataDir, "flows"))
+}
+
+// TrashFlow moves a flow file to the trash directory, recording when it was
+// deleted. A file that doesn't decode is trashed as raw bytes. A flow
+// trashed again replaces its earlier trash entry
+func (fs *FileStorage) TrashFlow(ctx context.Context, flowID string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	filename := filepath.Join(fs.dataDir, "flows", flowID+".json")
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewStorageError("flow not found", flowID, err)
+		}
+		return fmt.Errorf("failed to read flow file: %w", err)
+	}
+
+	trashDir := filepath.Join(fs.dataDir, "trash")
+	if err := os.MkdirAll(trashDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	data, err = json.MarshalIndent(newTrashEntry(flowID, data), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal trashed flow: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(trashDir, flowID+".json"), data); err != nil {
+		return fmt.Errorf("failed to write trash file: %w", err)
+	}
+
+	if err := os.Remove(filename); err != nil {
+		return fmt.Errorf("failed to delete flow file: %w", err)
+	}
+	delete(fs.flows, flowID)
+	return nil
+}
+
+// loadTrashed reads a trash entry. Callers must hold fs.mu
+func (fs *FileStorage) loadTrashed(flowID string) (*models.TrashedFlow, error) {
+	data, err := os.ReadFile(filepath.Join(fs.dataDir, "trash", flowID+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, NewStorageError("trashed flow not found", flowID, err)
+		}
+		return nil, fmt.Errorf("failed to read trash file: %w", err)
+	}
+
+	var trashed models.TrashedFlow
+	if err := models.DecodeJSON(data, &trashed); err != nil {
+		return nil, decodeError("trashed flow", err)
+	}
+	if trashed.Flow == nil && trashed.Raw == nil {
+		return nil, fmt.Errorf("trash file for %s has no flow", flowID)
+	}
+	trashed.ID = flowID
+	return &trashed, nil
+}
+
+// ListTrash returns the trashed flows, most recently deleted first.
+// Unreadable entries are skipped
+func (fs *FileStorage) ListTrash(ctx context.Context) ([]*models.TrashedFlow, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	flowIDs, err := listJSONFiles(filepath.Join(fs.dataDir, "trash"))
+	if err != nil {
+		return nil, err
+	}
+
+	trashed := make([]*models.TrashedFlow, 0, len(flowIDs))
+	for _, flowID := range flowIDs {
+		entry, err := fs.loadTrashed(flowID)
+		if err != nil {
+			continue
+		}
+		trashed = append(trashed, entry)
+	}
+	sort.Slice(trashed, func(i, j int) bool {
+		return trashed[i].DeletedAt.After(trashed[j].DeletedAt)
+	})
+	return trashed, nil
+}
+
+// RestoreFlow moves a trashed flow back to the flows directory. It fails
+// with ErrExists if a flow with the same ID has been saved since
+func (fs *FileStorage) RestoreFlow(ctx context.Context, flowID string) (*models.Flow, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	trashed, err := fs.loadTrashed(flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	flowsDir := filepath.Join(fs.dataDir, "flows")
+	filename := filepath.Join(flowsDir, flowID+".json")
+	if _, err := os.Stat(filename); err == nil {
+		return nil, NewStorageError("flow already exists", flowID, ErrExists)
+	}
+	if err := os.MkdirAll(flowsDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create flows directory: %w", err)
+	}
+
+	data, err := restoredData(trashed)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(filename, data); err != nil {
+		return nil, fmt.Errorf("failed to write flow file: %w", err)
+	}
+	if fs.flows != nil && trashed.Flow != nil {
+		fs.flows[flowID] = flowHeader{Name: trashed.Flow.Name, Active: trashed.Flow.Active}
+	}
+
+	if err := os.Remove(filepath.Join(fs.dataDir, "trash", flowID+".json")); err != nil {
+		return nil, fmt.Errorf("failed to delete trash file: %w", err)
+	}
+	return trashed.Flow, nil
+}
+
+// PurgeTrash permanently deletes the flows trashed before deletedBefore,
+// returning their IDs
+func (fs *FileStorage) PurgeTrash(ctx context.Context, deletedBefore time.Time) ([]string, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	trashDir := filepath.Join(fs.dataDir, "trash")
+	flowIDs, err := listJSONFiles(trashDir)
+	if err != nil {
+		return nil, err
+	}
+
+	purged := make([]string, 0)
+	for _, flowID := range flowIDs {
+		trashed, err := fs.loadTrashed(flowID)
+		if err != nil || !trashed.DeletedAt.Before(deletedBefore) {
+			continue // Unreadable entries are left for inspection
+		}
+		if err := os.Remove(filepath.Join(trashDir, flowID+".json")); err != nil {
+			return purged, fmt.Errorf("failed to delete trash file: %w", err)
+		}
+		purged = append(purged, flowID)
+	}
+	return purged, nil
+}
+
+// writeFileAtomic writes data to filename through a temporary file in the
+// same directory that is renamed into place, so a crash mid-write leaves
+// either the old file or the new one, never a truncated one. The temporary
+// file doesn't end in .json, so listings skip leftovers
+func writeFileAtomic(filename string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpName, 0o644)
+	}
+	if err == nil {
+		err = os.Rename(tmpName, filename)
+	}
+	if err != nil {
+		os.Remove(tmpName)
+	}
+	return err
+}
+
+// listJSONFiles returns the names, without extension, of the JSON files in
+// dir, sorted. A missing directory has no files
+func listJSONFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	return names, nil
+}
+
 // SaveFlowExecution saves a flow execution to a JSON file
 func (fs *FileStorage) SaveFlowExecution(ctx context.Context, execution *models.FlowExecution) error {
 	fs.mu.Lock()
@@ -150,9 +459,12 @@ func (fs *FileStorage) SaveFlowExecution(ctx context.Context, execution *models.
 		return fmt.Errorf("failed to marshal execution: %w", err)
 	}
 
-	if err := os.WriteFile(filename, data, 0o644); err != nil {
+	if err := writeFileAtomic(filename, data); err != nil {
 		return fmt.Errorf("failed to write execution file: %w", err)
 	}
+	if fs.index != nil {
+		fs.index.put(execution.ID, headerOf(execution))
+	}
 
 	return nil
 }
@@ -173,7 +485,7 @@ func (fs *FileStorage) LoadFlowExecution(ctx context.Context, executionID string
 
 	var execution models.FlowExecution
 	if err := json.Unmarshal(data, &execution); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal execution: %w", err)
+		return nil, decodeError("execution", err)
 	}
 
 	return &execution, nil
@@ -228,10 +540,67 @@ func (fs *FileStorage) DeleteFlowExecution(ctx context.Context, executionID stri
 		}
 		return fmt.Errorf("failed to delete execution file: %w", err)
 	}
+	if fs.index != nil {
+		fs.index.remove(executionID)
+	}
 
 	return nil
 }
 
+// ListFlowExecutionIDs returns the IDs of all stored executions without
+// loading them
+func (fs *FileStorage) ListFlowExecutionIDs(ctx context.Context) ([]string, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	return listJSONFiles(filepath.Join(fs.dataDir, "executions"))
+}
+
+// SummarizeFlowExecutions counts a flow's executions and reports the most
+// recently started one. Executions are read once, into an index kept up to
+// date as executions are saved and deleted; reading failures are returned
+func (fs *FileStorage) SummarizeFlowExecutions(ctx context.Context, flowID string) (models.ExecutionSummary, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.index == nil {
+		index, err := fs.buildExecutionIndex()
+		if err != nil {
+			return models.ExecutionSummary{}, err
+		}
+		fs.index = index
+	}
+	return fs.index.summarize(flowID), nil
+}
+
+// buildExecutionIndex reads the header of every stored execution. Files that
+// aren't valid executions are skipped. Callers must hold fs.mu
+func (fs *FileStorage) buildExecutionIndex() (*executionIndex, error) {
+	execDir := filepath.Join(fs.dataDir, "executions")
+	ids, err := listJSONFiles(execDir)
+	if err != nil {
+		return nil, err
+	}
+
+	index := newExecutionIndex()
+	for _, id := range ids {
+		data, err := os.ReadFile(filepath.Join(execDir, id+".json"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read execution file: %w", err)
+		}
+
+		var header executionHeader
+		if err := json.Unmarshal(data, &header); err != nil {
+			continue
+		}
+		index.put(id, header)
+	}
+	return index, nil
+}
+
 // SaveConfig saves configuration data
 func (fs *FileStorage) SaveConfig(ctx context.Context, key string, value interface{}) error {
 	fs.mu.Lock()
@@ -250,7 +619,7 @@ func (fs *FileStorage) SaveConfig(ctx context.Context, key string, value interfa
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(filename, data, 0o644); err != nil {
+	if err := writeFileAtomic(filename, data); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
@@ -272,7 +641,7 @@ func (fs *FileStorage) LoadConfig(ctx context.Context, key string, target interf
 	}
 
 	if err := json.Unmarshal(data, target); err != nil {
-		return fmt.Errorf("failed to unmarshal config: %w", err)
+		return decodeError("config", err)
 	}
 
 	return nil
@@ -294,6 +663,82 @@ func (fs *FileStorage) DeleteConfig(ctx context.Context, key string) error {
 	return nil
 }
 
+// valueFile returns the file holding a key/value entry. Namespace and key are
+// escaped so arbitrary keys can't leave the namespace directory
+func (fs *FileStorage) valueFile(namespace, key string) string {
+	return filepath.Join(fs.dataDir, "kv", escapeName(namespace), escapeName(key)+".json")
+}
+
+// escapeName makes a string safe to use as a single path element. The
+// special names are encoded in ways PathEscape never produces, so they can't
+// collide with other keys
+func escapeName(name string) string {
+	switch name {
+	case "":
+		return "%00"
+	case ".", "..":
+		return strings.ReplaceAll(name, ".", "%2E")
+	}
+	return url.PathEscape(name)
+}
+
+// SetValue stores a value under a key within a namespace
+func (fs *FileStorage) SetValue(ctx context.Context, namespace, key string, value interface{}) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	filename := fs.valueFile(namespace, key)
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return fmt.Errorf("failed to create kv directory: %w", err)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	if err := writeFileAtomic(filename, data); err != nil {
+		return fmt.Errorf("failed to write value file: %w", err)
+	}
+
+	return nil
+}
+
+// GetValue loads the value stored under a key within a namespace
+func (fs *FileStorage) GetValue(ctx context.Context, namespace, key string, target interface{}) error {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	data, err := os.ReadFile(fs.valueFile(namespace, key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewStorageError("value not found", key, err)
+		}
+		return fmt.Errorf("failed to read value file: %w", err)
+	}
+
+	if err := models.DecodeJSON(data, target); err != nil {
+		return decodeError("value", err)
+	}
+
+	return nil
+}
+
+// DeleteValue deletes the value stored under a key within a namespace
+func (fs *FileStorage) DeleteValue(ctx context.Context, namespace, key string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if err := os.Remove(fs.valueFile(namespace, key)); err != nil {
+		if os.IsNotExist(err) {
+			return NewStorageError("value not found", key, err)
+		}
+		return fmt.Errorf("failed to delete value file: %w", err)
+	}
+
+	return nil
+}
+
 // Health checks if the storage is healthy
 func (fs *FileStorage) Health(ctx context.Context) error {
 	// Check if data directory is accessible
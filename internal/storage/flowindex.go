@@ -0,0 +1,28 @@
+package storage
+
+import "sort"
+
+// flowHeader is the part of a stored flow that listings filter on
+type flowHeader struct {
+	Name   string
+	Active bool
+}
+
+// flowIndex keeps the headers of the stored flows that decode, updated as
+// flows are saved, deleted, trashed and restored, so listing flows reads only
+// the files on the requested page. It isn't safe for concurrent use; storages
+// guard it with their own lock
+type flowIndex map[string]flowHeader // Flow ID → header
+
+// selected returns the IDs of the indexed flows matching the query's
+// filters, in order
+func (x flowIndex) selected(query FlowQuery) []string {
+	ids := make([]string, 0)
+	for id, header := range x {
+		if query.Selects(header.Name, header.Active) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"block-flow/internal/models"
+)
+
+func TestListFlows(t *testing.T) {
+	active := true
+	tests := []struct {
+		name      string
+		query     FlowQuery
+		wantIDs   []string
+		wantTotal int
+	}{
+		{name: "first page", query: FlowQuery{Limit: 2}, wantIDs: []string{"a", "b"}, wantTotal: 4},
+		{name: "last page", query: FlowQuery{Offset: 2, Limit: 2}, wantIDs: []string{"c", "new"}, wantTotal: 4},
+		{name: "filtered by name", query: FlowQuery{Name: "BE"}, wantIDs: []string{"b"}, wantTotal: 1},
+		{name: "filtered by active", query: FlowQuery{Active: &active}, wantIDs: []string{"a", "new"}, wantTotal: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			store := NewFileStorage(dir)
+			for _, flow := range []*models.Flow{
+				{ID: "a", Name: "alpha", Active: true},
+				{ID: "b", Name: "beta"},
+				{ID: "c", Name: "gamma"},
+				{ID: "gone", Name: "deleted later"},
+			} {
+				if err := store.SaveFlow(ctx, flow); err != nil {
+					t.Fatalf("save flow: %v", err)
+				}
+			}
+			// Neither is listed nor counted: one isn't JSON, the other has
+			// a valid header but nodes that aren't a list
+			for name, data := range map[string]string{
+				"broken.json":   "{",
+				"mistyped.json": `{"id":"mistyped","name":"beta mistyped","nodes":"none"}`,
+			} {
+				if err := os.WriteFile(filepath.Join(dir, "flows", name), []byte(data), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Changes after the index is built are reflected in it
+			if _, _, err := store.ListFlows(ctx, FlowQuery{}); err != nil {
+				t.Fatalf("list flows: %v", err)
+			}
+			if err := store.SaveFlow(ctx, &models.Flow{ID: "new", Name: "new", Active: true}); err != nil {
+				t.Fatalf("save flow: %v", err)
+			}
+			if err := store.DeleteFlow(ctx, "gone"); err != nil {
+				t.Fatalf("delete flow: %v", err)
+			}
+
+			flows, total, err := store.ListFlows(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("list flows: %v", err)
+			}
+			ids := make([]string, 0, len(flows))
+			for _, flow := range flows {
+				ids = append(ids, flow.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || total != tt.wantTotal {
+				t.Errorf("listed %v of %d, want %v of %d", ids, total, tt.wantIDs, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestListFlowsFileCorruptedAfterIndexing(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := NewFileStorage(dir)
+	for _, id := range []string{"a", "b", "c"} {
+		if err := store.SaveFlow(ctx, &models.Flow{ID: id, Name: id}); err != nil {
+			t.Fatalf("save flow: %v", err)
+		}
+	}
+	if _, _, err := store.ListFlows(ctx, FlowQuery{}); err != nil {
+		t.Fatalf("list flows: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "flows", "a.json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	flows, total, err := store.ListFlows(ctx, FlowQuery{Limit: 1})
+	if err != nil {
+		t.Fatalf("list flows: %v", err)
+	}
+	if len(flows) != 1 || flows[0].ID != "b" || total != 2 {
+		t.Errorf("listed %d flows of %d, want b of 2", len(flows), total)
+	}
+}
@@ -2,6 +2,9 @@ package storage
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"block-flow/internal/models"
 )
@@ -12,21 +15,83 @@ type Storage interface {
 	SaveFlow(ctx context.Context, flow *models.Flow) error
 	LoadFlow(ctx context.Context, flowID string) (*models.Flow, error)
 	LoadAllFlows(ctx context.Context) ([]*models.Flow, error)
+	ListFlows(ctx context.Context, query FlowQuery) (flows []*models.Flow, total int, err error)
+	ListFlowSummaries(ctx context.Context) ([]*models.FlowSummary, error)
 	DeleteFlow(ctx context.Context, flowID string) error
 	FlowExists(ctx context.Context, flowID string) bool
+	ListFlowIDs(ctx context.Context) ([]string, error)
+
+	// Trash operations: soft-deleted flows stay restorable until purged
+	TrashFlow(ctx context.Context, flowID string) error
+	ListTrash(ctx context.Context) ([]*models.TrashedFlow, error)
+	RestoreFlow(ctx context.Context, flowID string) (*models.Flow, error)
+	PurgeTrash(ctx context.Context, deletedBefore time.Time) ([]string, error)
 
 	// Flow execution operations
 	SaveFlowExecution(ctx context.Context, execution *models.FlowExecution) error
 	LoadFlowExecution(ctx context.Context, executionID string) (*models.FlowExecution, error)
 	LoadFlowExecutions(ctx context.Context, flowID string) ([]*models.FlowExecution, error)
 	DeleteFlowExecution(ctx context.Context, executionID string) error
+	ListFlowExecutionIDs(ctx context.Context) ([]string, error)
+	SummarizeFlowExecutions(ctx context.Context, flowID string) (models.ExecutionSummary, error)
 
 	// Configuration operations
 	SaveConfig(ctx context.Context, key string, value interface{}) error
 	LoadConfig(ctx context.Context, key string, target interface{}) error
 	DeleteConfig(ctx context.Context, key string) error
 
+	// Key/value operations, isolated per namespace (e.g. a flow ID)
+	SetValue(ctx context.Context, namespace, key string, value interface{}) error
+	GetValue(ctx context.Context, namespace, key string, target interface{}) error
+	DeleteValue(ctx context.Context, namespace, key string) error
+
 	// Health and maintenance
 	Health(ctx context.Context) error
 	Close() error
 }
+
+// FlowQuery selects a page of the stored flows, ordered by ID
+type FlowQuery struct {
+	Name   string // Case-insensitive part of the flow name (empty = any)
+	Active *bool  // Only flows with this active flag (nil = any)
+	Offset int
+	Limit  int // 0 = all flows after Offset
+}
+
+// Selects reports whether a flow with this name and active flag matches the
+// query's filters
+func (q FlowQuery) Selects(name string, active bool) bool {
+	if q.Active != nil && active != *q.Active {
+		return false
+	}
+	return strings.Contains(strings.ToLower(name), strings.ToLower(q.Name))
+}
+
+// InPage reports whether the index-th matching flow is on the requested page
+func (q FlowQuery) InPage(index int) bool {
+	return index >= q.Offset && (q.Limit <= 0 || index < q.Offset+q.Limit)
+}
+
+// newTrashEntry wraps the stored data of a flow into a trash entry, keeping
+// the raw bytes when they don't decode so broken flows can be trashed too
+func newTrashEntry(flowID string, data []byte) *models.TrashedFlow {
+	trashed := &models.TrashedFlow{ID: flowID, DeletedAt: time.Now()}
+	if flow, err := models.FromJSON(data); err == nil {
+		trashed.Flow = flow
+	} else {
+		trashed.Raw = data
+	}
+	return trashed
+}
+
+// restoredData returns the data to store for a restored trash entry
+func restoredData(trashed *models.TrashedFlow) ([]byte, error) {
+	if trashed.Flow == nil {
+		return trashed.Raw, nil
+	}
+	data, err := trashed.Flow.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal flow: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,444 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+// MemoryStorage implements the Storage interface in memory, for tests and
+// deployments that don't need to keep anything across restarts. Entries are
+// kept JSON-encoded, as FileStorage keeps them on disk, so callers never
+// share values with the store and decoding behaves the same
+type MemoryStorage struct {
+	flows      map[string][]byte
+	trash      map[string][]byte
+	executions map[string][]byte
+	config     map[string][]byte
+	values     map[string]map[string][]byte // Namespace → key → value
+	index      *executionIndex
+	mu         sync.RWMutex
+}
+
+// NewMemoryStorage creates a new, empty in-memory storage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		flows:      make(map[string][]byte),
+		trash:      make(map[string][]byte),
+		executions: make(map[string][]byte),
+		config:     make(map[string][]byte),
+		values:     make(map[string]map[string][]byte),
+		index:      newExecutionIndex(),
+	}
+}
+
+// SaveFlow stores a flow, replacing any flow with the same ID
+func (ms *MemoryStorage) SaveFlow(ctx context.Context, flow *models.Flow) error {
+	data, err := flow.ToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to marshal flow: %w", err)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.flows[flow.ID] = data
+	return nil
+}
+
+// LoadFlow returns a copy of a stored flow
+func (ms *MemoryStorage) LoadFlow(ctx context.Context, flowID string) (*models.Flow, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return ms.loadFlow(flowID)
+}
+
+// loadFlow decodes a stored flow. Callers must hold ms.mu
+func (ms *MemoryStorage) loadFlow(flowID string) (*models.Flow, error) {
+	data, ok := ms.flows[flowID]
+	if !ok {
+		return nil, NewStorageError("flow not found", flowID, os.ErrNotExist)
+	}
+
+	flow, err := models.FromJSON(data)
+	if err != nil {
+		return nil, decodeError("flow", err)
+	}
+	return flow, nil
+}
+
+// LoadAllFlows returns copies of all stored flows, ordered by ID
+func (ms *MemoryStorage) LoadAllFlows(ctx context.Context) ([]*models.Flow, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	flows := make([]*models.Flow, 0, len(ms.flows))
+	for _, flowID := range sortedKeys(ms.flows) {
+		flow, err := ms.loadFlow(flowID)
+		if err != nil {
+			continue
+		}
+		flows = append(flows, flow)
+	}
+	return flows, nil
+}
+
+// ListFlows returns the page of flows selected by the query and how many
+// flows match in total. Flows that don't decode are neither listed nor
+// counted
+func (ms *MemoryStorage) ListFlows(ctx context.Context, query FlowQuery) ([]*models.Flow, int, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	flows := make([]*models.Flow, 0)
+	total := 0
+	for _, flowID := range sortedKeys(ms.flows) {
+		flow, err := models.FromJSON(ms.flows[flowID])
+		if err != nil || !query.Selects(flow.Name, flow.Active) {
+			continue
+		}
+		if query.InPage(total) {
+			flows = append(flows, flow)
+		}
+		total++
+	}
+	return flows, total, nil
+}
+
+// ListFlowSummaries returns the metadata of all stored flows, ordered by ID
+func (ms *MemoryStorage) ListFlowSummaries(ctx context.Context) ([]*models.FlowSummary, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	summaries := make([]*models.FlowSummary, 0, len(ms.flows))
+	for _, flowID := range sortedKeys(ms.flows) {
+		summary, err := models.DecodeFlowSummary(ms.flows[flowID])
+		if err != nil {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// DeleteFlow deletes a stored flow
+func (ms *MemoryStorage) DeleteFlow(ctx context.Context, flowID string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.flows[flowID]; !ok {
+		return NewStorageError("flow not found", flowID, os.ErrNotExist)
+	}
+	delete(ms.flows, flowID)
+	return nil
+}
+
+// FlowExists checks if a flow is stored
+func (ms *MemoryStorage) FlowExists(ctx context.Context, flowID string) bool {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	_, ok := ms.flows[flowID]
+	return ok
+}
+
+// ListFlowIDs returns the IDs of all stored flows, sorted
+func (ms *MemoryStorage) ListFlowIDs(ctx context.Context) ([]string, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	return sortedKeys(ms.flows), nil
+}
+
+// TrashFlow moves a flow to the trash, recording when it was deleted. A flow
+// that doesn't decode is trashed as raw bytes. A flow trashed again replaces
+// its earlier trash entry
+func (ms *MemoryStorage) TrashFlow(ctx context.Context, flowID string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	data, ok := ms.flows[flowID]
+	if !ok {
+		return NewStorageError("flow not found", flowID, os.ErrNotExist)
+	}
+	data, err := json.Marshal(newTrashEntry(flowID, data))
+	if err != nil {
+		return fmt.Errorf("failed to marshal trashed flow: %w", err)
+	}
+
+	ms.trash[flowID] = data
+	delete(ms.flows, flowID)
+	return nil
+}
+
+// loadTrashed decodes a trash entry. Callers must hold ms.mu
+func (ms *MemoryStorage) loadTrashed(flowID string) (*models.TrashedFlow, error) {
+	data, ok := ms.trash[flowID]
+	if !ok {
+		return nil, NewStorageError("trashed flow not found", flowID, os.ErrNotExist)
+	}
+
+	var trashed models.TrashedFlow
+	if err := models.DecodeJSON(data, &trashed); err != nil {
+		return nil, decodeError("trashed flow", err)
+	}
+	if trashed.Flow == nil && trashed.Raw == nil {
+		return nil, fmt.Errorf("trash entry for %s has no flow", flowID)
+	}
+	trashed.ID = flowID
+	return &trashed, nil
+}
+
+// ListTrash returns the trashed flows, most recently deleted first
+func (ms *MemoryStorage) ListTrash(ctx context.Context) ([]*models.TrashedFlow, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	trashed := make([]*models.TrashedFlow, 0, len(ms.trash))
+	for _, flowID := range sortedKeys(ms.trash) {
+		entry, err := ms.loadTrashed(flowID)
+		if err != nil {
+			continue
+		}
+		trashed = append(trashed, entry)
+	}
+	sort.Slice(trashed, func(i, j int) bool {
+		return trashed[i].DeletedAt.After(trashed[j].DeletedAt)
+	})
+	return trashed, nil
+}
+
+// RestoreFlow moves a trashed flow back to the stored flows. It fails with
+// ErrExists if a flow with the same ID has been saved since
+func (ms *MemoryStorage) RestoreFlow(ctx context.Context, flowID string) (*models.Flow, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	trashed, err := ms.loadTrashed(flowID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := ms.flows[flowID]; ok {
+		return nil, NewStorageError("flow already exists", flowID, ErrExists)
+	}
+
+	data, err := restoredData(trashed)
+	if err != nil {
+		return nil, err
+	}
+	ms.flows[flowID] = data
+	delete(ms.trash, flowID)
+	return trashed.Flow, nil
+}
+
+// PurgeTrash permanently deletes the flows trashed before deletedBefore,
+// returning their IDs
+func (ms *MemoryStorage) PurgeTrash(ctx context.Context, deletedBefore time.Time) ([]string, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	purged := make([]string, 0)
+	for _, flowID := range sortedKeys(ms.trash) {
+		trashed, err := ms.loadTrashed(flowID)
+		if err != nil || !trashed.DeletedAt.Before(deletedBefore) {
+			continue
+		}
+		delete(ms.trash, flowID)
+		purged = append(purged, flowID)
+	}
+	return purged, nil
+}
+
+// SaveFlowExecution stores a flow execution, replacing any with the same ID
+func (ms *MemoryStorage) SaveFlowExecution(ctx context.Context, execution *models.FlowExecution) error {
+	data, err := json.Marshal(execution)
+	if err != nil {
+		return fmt.Errorf("failed to marshal execution: %w", err)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.executions[execution.ID] = data
+	ms.index.put(execution.ID, headerOf(execution))
+	return nil
+}
+
+// LoadFlowExecution returns a copy of a stored flow execution
+func (ms *MemoryStorage) LoadFlowExecution(ctx context.Context, executionID string) (*models.FlowExecution, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return ms.loadExecution(executionID)
+}
+
+// loadExecution decodes a stored execution. Callers must hold ms.mu
+func (ms *MemoryStorage) loadExecution(executionID string) (*models.FlowExecution, error) {
+	data, ok := ms.executions[executionID]
+	if !ok {
+		return nil, NewStorageError("execution not found", executionID, os.ErrNotExist)
+	}
+
+	var execution models.FlowExecution
+	if err := json.Unmarshal(data, &execution); err != nil {
+		return nil, decodeError("execution", err)
+	}
+	return &execution, nil
+}
+
+// LoadFlowExecutions returns copies of all executions of a flow, ordered by
+// execution ID
+func (ms *MemoryStorage) LoadFlowExecutions(ctx context.Context, flowID string) ([]*models.FlowExecution, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	executions := make([]*models.FlowExecution, 0)
+	for _, executionID := range sortedKeys(ms.executions) {
+		execution, err := ms.loadExecution(executionID)
+		if err != nil {
+			continue
+		}
+		if execution.FlowID == flowID {
+			executions = append(executions, execution)
+		}
+	}
+	return executions, nil
+}
+
+// DeleteFlowExecution deletes a stored execution
+func (ms *MemoryStorage) DeleteFlowExecution(ctx context.Context, executionID string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.executions[executionID]; !ok {
+		return NewStorageError("execution not found", executionID, os.ErrNotExist)
+	}
+	delete(ms.executions, executionID)
+	ms.index.remove(executionID)
+	return nil
+}
+
+// ListFlowExecutionIDs returns the IDs of all stored executions, sorted
+func (ms *MemoryStorage) ListFlowExecutionIDs(ctx context.Context) ([]string, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	return sortedKeys(ms.executions), nil
+}
+
+// SummarizeFlowExecutions counts a flow's executions and reports the most
+// recently started one, from the execution index
+func (ms *MemoryStorage) SummarizeFlowExecutions(ctx context.Context, flowID string) (models.ExecutionSummary, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return ms.index.summarize(flowID), nil
+}
+
+// SaveConfig stores configuration data
+func (ms *MemoryStorage) SaveConfig(ctx context.Context, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.config[key] = data
+	return nil
+}
+
+// LoadConfig loads configuration data into target
+func (ms *MemoryStorage) LoadConfig(ctx context.Context, key string, target interface{}) error {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	data, ok := ms.config[key]
+	if !ok {
+		return NewStorageError("config not found", key, os.ErrNotExist)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return decodeError("config", err)
+	}
+	return nil
+}
+
+// DeleteConfig deletes configuration data
+func (ms *MemoryStorage) DeleteConfig(ctx context.Context, key string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.config[key]; !ok {
+		return NewStorageError("config not found", key, os.ErrNotExist)
+	}
+	delete(ms.config, key)
+	return nil
+}
+
+// SetValue stores a value under a key within a namespace
+func (ms *MemoryStorage) SetValue(ctx context.Context, namespace, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.values[namespace] == nil {
+		ms.values[namespace] = make(map[string][]byte)
+	}
+	ms.values[namespace][key] = data
+	return nil
+}
+
+// GetValue loads the value stored under a key within a namespace
+func (ms *MemoryStorage) GetValue(ctx context.Context, namespace, key string, target interface{}) error {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	data, ok := ms.values[namespace][key]
+	if !ok {
+		return NewStorageError("value not found", key, os.ErrNotExist)
+	}
+	if err := models.DecodeJSON(data, target); err != nil {
+		return decodeError("value", err)
+	}
+	return nil
+}
+
+// DeleteValue deletes the value stored under a key within a namespace
+func (ms *MemoryStorage) DeleteValue(ctx context.Context, namespace, key string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.values[namespace][key]; !ok {
+		return NewStorageError("value not found", key, os.ErrNotExist)
+	}
+	delete(ms.values[namespace], key)
+	if len(ms.values[namespace]) == 0 {
+		delete(ms.values, namespace)
+	}
+	return nil
+}
+
+// Health reports the storage as healthy; memory is always available
+func (ms *MemoryStorage) Health(ctx context.Context) error {
+	return nil
+}
+
+// Close closes the storage (no-op for memory storage)
+func (ms *MemoryStorage) Close() error {
+	return nil
+}
+
+// sortedKeys returns the keys of an entry map in order
+func sortedKeys(entries map[string][]byte) []string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
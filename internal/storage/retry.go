@@ -0,0 +1,310 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+// ErrNotFound may be wrapped by backends to report a missing item
+var ErrNotFound = errors.New("not found")
+
+// ErrExists may be wrapped by backends to report that an item to create
+// already exists
+var ErrExists = errors.New("already exists")
+
+// ErrCorrupt may be wrapped by backends to report stored data that can't be
+// decoded
+var ErrCorrupt = errors.New("corrupt data")
+
+// decodeError reports that stored data of the given kind failed to decode
+func decodeError(kind string, err error) error {
+	return fmt.Errorf("failed to unmarshal %s: %w: %w", kind, ErrCorrupt, err)
+}
+
+// IsNotFound reports whether err means the requested item doesn't exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, os.ErrNotExist)
+}
+
+// isPermanent reports whether retrying an operation can't help: the item is
+// missing, the caller gave up or the stored data is corrupt. Backends not
+// wrapping ErrCorrupt are recognized by the JSON decoding errors they return
+func isPermanent(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return IsNotFound(err) ||
+		errors.Is(err, ErrExists) ||
+		errors.Is(err, ErrCorrupt) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &syntaxErr) ||
+		errors.As(err, &typeErr)
+}
+
+// RetryingStorage decorates a Storage, retrying failed operations with a
+// linear backoff so brief backend outages don't surface as errors. Not-found
+// and other permanent errors are returned immediately
+type RetryingStorage struct {
+	inner    Storage
+	attempts int
+	backoff  time.Duration
+}
+
+// NewRetryingStorage wraps inner so each operation is tried up to attempts
+// times, waiting backoff, 2*backoff, ... between tries
+func NewRetryingStorage(inner Storage, attempts int, backoff time.Duration) *RetryingStorage {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &RetryingStorage{
+		inner:    inner,
+		attempts: attempts,
+		backoff:  backoff,
+	}
+}
+
+// do runs op until it succeeds, fails permanently or runs out of attempts
+func (s *RetryingStorage) do(ctx context.Context, name string, op func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = op(); err == nil || isPermanent(err) {
+			return err
+		}
+		if attempt >= s.attempts {
+			break
+		}
+
+		timer := time.NewTimer(time.Duration(attempt) * s.backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+
+	if s.attempts == 1 {
+		return err
+	}
+	return fmt.Errorf("storage unavailable: %s failed after %d attempts: %w", name, s.attempts, err)
+}
+
+// SaveFlow saves a flow
+func (s *RetryingStorage) SaveFlow(ctx context.Context, flow *models.Flow) error {
+	return s.do(ctx, "save flow", func() error {
+		return s.inner.SaveFlow(ctx, flow)
+	})
+}
+
+// LoadFlow loads a flow
+func (s *RetryingStorage) LoadFlow(ctx context.Context, flowID string) (*models.Flow, error) {
+	var flow *models.Flow
+	err := s.do(ctx, "load flow", func() (err error) {
+		flow, err = s.inner.LoadFlow(ctx, flowID)
+		return err
+	})
+	return flow, err
+}
+
+// LoadAllFlows loads all flows
+func (s *RetryingStorage) LoadAllFlows(ctx context.Context) ([]*models.Flow, error) {
+	var flows []*models.Flow
+	err := s.do(ctx, "load flows", func() (err error) {
+		flows, err = s.inner.LoadAllFlows(ctx)
+		return err
+	})
+	return flows, err
+}
+
+// ListFlows lists a page of flows
+func (s *RetryingStorage) ListFlows(ctx context.Context, query FlowQuery) ([]*models.Flow, int, error) {
+	var flows []*models.Flow
+	var total int
+	err := s.do(ctx, "list flows", func() (err error) {
+		flows, total, err = s.inner.ListFlows(ctx, query)
+		return err
+	})
+	return flows, total, err
+}
+
+// ListFlowSummaries lists the metadata of all flows
+func (s *RetryingStorage) ListFlowSummaries(ctx context.Context) ([]*models.FlowSummary, error) {
+	var summaries []*models.FlowSummary
+	err := s.do(ctx, "list flow summaries", func() (err error) {
+		summaries, err = s.inner.ListFlowSummaries(ctx)
+		return err
+	})
+	return summaries, err
+}
+
+// DeleteFlow deletes a flow
+func (s *RetryingStorage) DeleteFlow(ctx context.Context, flowID string) error {
+	return s.do(ctx, "delete flow", func() error {
+		return s.inner.DeleteFlow(ctx, flowID)
+	})
+}
+
+// FlowExists checks if a flow exists. It reports no errors, so it isn't retried
+func (s *RetryingStorage) FlowExists(ctx context.Context, flowID string) bool {
+	return s.inner.FlowExists(ctx, flowID)
+}
+
+// ListFlowIDs lists the IDs of all flows
+func (s *RetryingStorage) ListFlowIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := s.do(ctx, "list flows", func() (err error) {
+		ids, err = s.inner.ListFlowIDs(ctx)
+		return err
+	})
+	return ids, err
+}
+
+// TrashFlow moves a flow to the trash
+func (s *RetryingStorage) TrashFlow(ctx context.Context, flowID string) error {
+	return s.do(ctx, "trash flow", func() error {
+		return s.inner.TrashFlow(ctx, flowID)
+	})
+}
+
+// ListTrash lists the trashed flows
+func (s *RetryingStorage) ListTrash(ctx context.Context) ([]*models.TrashedFlow, error) {
+	var trashed []*models.TrashedFlow
+	err := s.do(ctx, "list trash", func() (err error) {
+		trashed, err = s.inner.ListTrash(ctx)
+		return err
+	})
+	return trashed, err
+}
+
+// RestoreFlow moves a flow out of the trash
+func (s *RetryingStorage) RestoreFlow(ctx context.Context, flowID string) (*models.Flow, error) {
+	var flow *models.Flow
+	err := s.do(ctx, "restore flow", func() (err error) {
+		flow, err = s.inner.RestoreFlow(ctx, flowID)
+		return err
+	})
+	return flow, err
+}
+
+// PurgeTrash permanently deletes flows trashed before a time
+func (s *RetryingStorage) PurgeTrash(ctx context.Context, deletedBefore time.Time) ([]string, error) {
+	var purged []string
+	err := s.do(ctx, "purge trash", func() (err error) {
+		purged, err = s.inner.PurgeTrash(ctx, deletedBefore)
+		return err
+	})
+	return purged, err
+}
+
+// SaveFlowExecution saves a flow execution
+func (s *RetryingStorage) SaveFlowExecution(ctx context.Context, execution *models.FlowExecution) error {
+	return s.do(ctx, "save execution", func() error {
+		return s.inner.SaveFlowExecution(ctx, execution)
+	})
+}
+
+// LoadFlowExecution loads a flow execution
+func (s *RetryingStorage) LoadFlowExecution(ctx context.Context, executionID string) (*models.FlowExecution, error) {
+	var execution *models.FlowExecution
+	err := s.do(ctx, "load execution", func() (err error) {
+		execution, err = s.inner.LoadFlowExecution(ctx, executionID)
+		return err
+	})
+	return execution, err
+}
+
+// LoadFlowExecutions loads the executions of a flow
+func (s *RetryingStorage) LoadFlowExecutions(ctx context.Context, flowID string) ([]*models.FlowExecution, error) {
+	var executions []*models.FlowExecution
+	err := s.do(ctx, "load executions", func() (err error) {
+		executions, err = s.inner.LoadFlowExecutions(ctx, flowID)
+		return err
+	})
+	return executions, err
+}
+
+// DeleteFlowExecution deletes a flow execution
+func (s *RetryingStorage) DeleteFlowExecution(ctx context.Context, executionID string) error {
+	return s.do(ctx, "delete execution", func() error {
+		return s.inner.DeleteFlowExecution(ctx, executionID)
+	})
+}
+
+// ListFlowExecutionIDs lists the IDs of all executions
+func (s *RetryingStorage) ListFlowExecutionIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := s.do(ctx, "list executions", func() (err error) {
+		ids, err = s.inner.ListFlowExecutionIDs(ctx)
+		return err
+	})
+	return ids, err
+}
+
+// SummarizeFlowExecutions aggregates the executions of a flow
+func (s *RetryingStorage) SummarizeFlowExecutions(ctx context.Context, flowID string) (models.ExecutionSummary, error) {
+	var summary models.ExecutionSummary
+	err := s.do(ctx, "summarize executions", func() (err error) {
+		summary, err = s.inner.SummarizeFlowExecutions(ctx, flowID)
+		return err
+	})
+	return summary, err
+}
+
+// SaveConfig saves configuration data
+func (s *RetryingStorage) SaveConfig(ctx context.Context, key string, value interface{}) error {
+	return s.do(ctx, "save config", func() error {
+		return s.inner.SaveConfig(ctx, key, value)
+	})
+}
+
+// LoadConfig loads configuration data
+func (s *RetryingStorage) LoadConfig(ctx context.Context, key string, target interface{}) error {
+	return s.do(ctx, "load config", func() error {
+		return s.inner.LoadConfig(ctx, key, target)
+	})
+}
+
+// DeleteConfig deletes configuration data
+func (s *RetryingStorage) DeleteConfig(ctx context.Context, key string) error {
+	return s.do(ctx, "delete config", func() error {
+		return s.inner.DeleteConfig(ctx, key)
+	})
+}
+
+// SetValue stores a value under a namespaced key
+func (s *RetryingStorage) SetValue(ctx context.Context, namespace, key string, value interface{}) error {
+	return s.do(ctx, "set value", func() error {
+		return s.inner.SetValue(ctx, namespace, key, value)
+	})
+}
+
+// GetValue loads a value stored under a namespaced key
+func (s *RetryingStorage) GetValue(ctx context.Context, namespace, key string, target interface{}) error {
+	return s.do(ctx, "get value", func() error {
+		return s.inner.GetValue(ctx, namespace, key, target)
+	})
+}
+
+// DeleteValue deletes a value stored under a namespaced key
+func (s *RetryingStorage) DeleteValue(ctx context.Context, namespace, key string) error {
+	return s.do(ctx, "delete value", func() error {
+		return s.inner.DeleteValue(ctx, namespace, key)
+	})
+}
+
+// Health reports the backend's health as is; a health probe shouldn't hide
+// an outage behind retries
+func (s *RetryingStorage) Health(ctx context.Context) error {
+	return s.inner.Health(ctx)
+}
+
+// Close closes the underlying storage
+func (s *RetryingStorage) Close() error {
+	return s.inner.Close()
+}
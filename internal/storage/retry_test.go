@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+// flakyStorage fails LoadFlow with the scripted errors before delegating to
+// the wrapped storage
+type flakyStorage struct {
+	Storage
+	errs  []error
+	calls int
+}
+
+func (s *flakyStorage) LoadFlow(ctx context.Context, flowID string) (*models.Flow, error) {
+	s.calls++
+	if s.calls <= len(s.errs) {
+		return nil, s.errs[s.calls-1]
+	}
+	return s.Storage.LoadFlow(ctx, flowID)
+}
+
+func TestRetryingStorage(t *testing.T) {
+	outage := errors.New("connection refused")
+	var strict struct{}
+	decoder := json.NewDecoder(strings.NewReader(`{"bogus": 1}`))
+	decoder.DisallowUnknownFields()
+	unknownField := decoder.Decode(&strict)
+
+	tests := []struct {
+		name        string
+		errs        []error
+		wantCalls   int
+		wantErr     bool
+		unavailable bool // Error reports the storage unavailable
+	}{
+		{name: "succeeds on retry", errs: []error{outage, outage}, wantCalls: 3},
+		{name: "retries exhausted", errs: []error{outage, outage, outage}, wantCalls: 3, wantErr: true, unavailable: true},
+		{name: "not found", errs: []error{ErrNotFound}, wantCalls: 1, wantErr: true},
+		{name: "unknown field", errs: []error{decodeError("flow", unknownField)}, wantCalls: 1, wantErr: true},
+		{name: "unwrapped syntax error", errs: []error{json.Unmarshal([]byte("{"), &strict)}, wantCalls: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			inner := NewMemoryStorage()
+			if err := inner.SaveFlow(ctx, &models.Flow{ID: "f", Name: "f"}); err != nil {
+				t.Fatalf("save flow: %v", err)
+			}
+			flaky := &flakyStorage{Storage: inner, errs: tt.errs}
+			store := NewRetryingStorage(flaky, 3, time.Millisecond)
+
+			flow, err := store.LoadFlow(ctx, "f")
+			if flaky.calls != tt.wantCalls {
+				t.Errorf("backend called %d times, want %d", flaky.calls, tt.wantCalls)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("load flow error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && flow.ID != "f" {
+				t.Errorf("loaded flow %q", flow.ID)
+			}
+			if got := err != nil && strings.Contains(err.Error(), "storage unavailable"); got != tt.unavailable {
+				t.Errorf("load flow error = %v, want storage unavailable %v", err, tt.unavailable)
+			}
+		})
+	}
+}
+
+func TestRetryingStorageCorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "flows"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "flows", "f.json"), []byte(`{"id": "f", "nodes": "none"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	flaky := &flakyStorage{Storage: NewFileStorage(dir)}
+	_, err := NewRetryingStorage(flaky, 3, time.Millisecond).LoadFlow(context.Background(), "f")
+	if !errors.Is(err, ErrCorrupt) {
+		t.Errorf("load flow error = %v, want ErrCorrupt", err)
+	}
+	if flaky.calls != 1 {
+		t.Errorf("backend called %d times, want 1", flaky.calls)
+	}
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"time"
+
+	"block-flow/internal/models"
+)
+
+// executionHeader is the part of a stored execution that summaries need
+type executionHeader struct {
+	FlowID    string                 `json:"flow_id"`
+	Status    models.ExecutionStatus `json:"status"`
+	StartedAt time.Time              `json:"started_at"`
+	EndedAt   *time.Time             `json:"ended_at"`
+}
+
+// executionIndex keeps the headers of stored executions per flow, updated as
+// executions are saved and deleted, so summarizing a flow's executions reads
+// none of them. It isn't safe for concurrent use; storages guard it with
+// their own lock
+type executionIndex struct {
+	flows  map[string]map[string]executionHeader // Flow ID → execution ID → header
+	flowOf map[string]string                     // Execution ID → flow ID
+}
+
+func newExecutionIndex() *executionIndex {
+	return &executionIndex{
+		flows:  make(map[string]map[string]executionHeader),
+		flowOf: make(map[string]string),
+	}
+}
+
+// headerOf returns the header of an execution
+func headerOf(execution *models.FlowExecution) executionHeader {
+	return executionHeader{
+		FlowID:    execution.FlowID,
+		Status:    execution.Status,
+		StartedAt: execution.StartedAt,
+		EndedAt:   execution.EndedAt,
+	}
+}
+
+// put adds an execution, replacing any with the same ID
+func (x *executionIndex) put(executionID string, header executionHeader) {
+	x.remove(executionID)
+	if x.flows[header.FlowID] == nil {
+		x.flows[header.FlowID] = make(map[string]executionHeader)
+	}
+	x.flows[header.FlowID][executionID] = header
+	x.flowOf[executionID] = header.FlowID
+}
+
+// remove drops an execution
+func (x *executionIndex) remove(executionID string) {
+	flowID, ok := x.flowOf[executionID]
+	if !ok {
+		return
+	}
+	delete(x.flowOf, executionID)
+	delete(x.flows[flowID], executionID)
+	if len(x.flows[flowID]) == 0 {
+		delete(x.flows, flowID)
+	}
+}
+
+// summarize counts a flow's executions and reports the most recently
+// started one
+func (x *executionIndex) summarize(flowID string) models.ExecutionSummary {
+	var summary models.ExecutionSummary
+	for _, header := range x.flows[flowID] {
+		summary.Total++
+		if summary.LastStartedAt == nil || header.StartedAt.After(*summary.LastStartedAt) {
+			startedAt := header.StartedAt
+			summary.LastStatus = header.Status
+			summary.LastStartedAt = &startedAt
+			summary.LastEndedAt = header.EndedAt
+		}
+	}
+	return summary
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+func TestSummarizeFlowExecutions(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	execution := func(id, flowID string, status models.ExecutionStatus, started int) *models.FlowExecution {
+		e := models.NewFlowExecution(flowID)
+		e.ID = id
+		e.Status = status
+		e.StartedAt = base.Add(time.Duration(started) * time.Minute)
+		return e
+	}
+
+	tests := []struct {
+		name       string
+		saves      []*models.FlowExecution
+		deletes    []string
+		wantTotal  int
+		wantStatus models.ExecutionStatus
+	}{
+		{name: "no executions", wantTotal: 0},
+		{
+			name: "latest started wins",
+			saves: []*models.FlowExecution{
+				execution("e1", "f", models.ExecutionStatusStopped, 2),
+				execution("e2", "f", models.ExecutionStatusFailed, 1),
+				execution("e3", "other", models.ExecutionStatusRunning, 3),
+			},
+			wantTotal:  2,
+			wantStatus: models.ExecutionStatusStopped,
+		},
+		{
+			name: "resaving updates in place",
+			saves: []*models.FlowExecution{
+				execution("e1", "f", models.ExecutionStatusRunning, 1),
+				execution("e1", "f", models.ExecutionStatusStopped, 1),
+			},
+			wantTotal:  1,
+			wantStatus: models.ExecutionStatusStopped,
+		},
+		{
+			name: "deleted executions are dropped",
+			saves: []*models.FlowExecution{
+				execution("e1", "f", models.ExecutionStatusStopped, 1),
+				execution("e2", "f", models.ExecutionStatusFailed, 2),
+			},
+			deletes:    []string{"e2"},
+			wantTotal:  1,
+			wantStatus: models.ExecutionStatusStopped,
+		},
+	}
+
+	storages := map[string]func(t *testing.T) Storage{
+		"memory": func(*testing.T) Storage { return NewMemoryStorage() },
+		"file":   func(t *testing.T) Storage { return NewFileStorage(t.TempDir()) },
+	}
+
+	for storageName, newStorage := range storages {
+		for _, tt := range tests {
+			t.Run(storageName+"/"+tt.name, func(t *testing.T) {
+				ctx := context.Background()
+				store := newStorage(t)
+				// Half the saves land before the index exists, half after
+				half := len(tt.saves) / 2
+				for _, e := range tt.saves[:half] {
+					if err := store.SaveFlowExecution(ctx, e); err != nil {
+						t.Fatalf("save execution: %v", err)
+					}
+				}
+				if _, err := store.SummarizeFlowExecutions(ctx, "f"); err != nil {
+					t.Fatalf("summarize: %v", err)
+				}
+				for _, e := range tt.saves[half:] {
+					if err := store.SaveFlowExecution(ctx, e); err != nil {
+						t.Fatalf("save execution: %v", err)
+					}
+				}
+				for _, id := range tt.deletes {
+					if err := store.DeleteFlowExecution(ctx, id); err != nil {
+						t.Fatalf("delete execution: %v", err)
+					}
+				}
+
+				summary, err := store.SummarizeFlowExecutions(ctx, "f")
+				if err != nil {
+					t.Fatalf("summarize: %v", err)
+				}
+				if summary.Total != tt.wantTotal || summary.LastStatus != tt.wantStatus {
+					t.Errorf("summary = %d executions, last %q; want %d, last %q", summary.Total, summary.LastStatus, tt.wantTotal, tt.wantStatus)
+				}
+			})
+		}
+	}
+}
+
+func TestSummarizeFlowExecutionsReadError(t *testing.T) {
+	dir := t.TempDir()
+	// A file where the executions directory is expected can't be listed
+	if err := os.WriteFile(filepath.Join(dir, "executions"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewFileStorage(dir)
+	if _, err := store.SummarizeFlowExecutions(context.Background(), "f"); err == nil || IsNotFound(err) {
+		t.Errorf("summarize error = %v, want a storage failure", err)
+	}
+}
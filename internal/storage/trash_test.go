@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"block-flow/internal/models"
+)
+
+func TestTrashThenRestore(t *testing.T) {
+	backends := map[string]func(t *testing.T) Storage{
+		"memory": func(*testing.T) Storage { return NewMemoryStorage() },
+		"file":   func(t *testing.T) Storage { return NewFileStorage(t.TempDir()) },
+	}
+
+	for name, newStorage := range backends {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStorage(t)
+			if err := store.SaveFlow(ctx, &models.Flow{ID: "f", Name: "kept"}); err != nil {
+				t.Fatalf("save flow: %v", err)
+			}
+
+			if err := store.TrashFlow(ctx, "f"); err != nil {
+				t.Fatalf("trash flow: %v", err)
+			}
+			if _, err := store.LoadFlow(ctx, "f"); !IsNotFound(err) {
+				t.Errorf("load trashed flow error = %v, want not found", err)
+			}
+			trashed, err := store.ListTrash(ctx)
+			if err != nil {
+				t.Fatalf("list trash: %v", err)
+			}
+			if len(trashed) != 1 || trashed[0].ID != "f" || trashed[0].Flow == nil || trashed[0].Flow.Name != "kept" {
+				t.Fatalf("trash = %+v, want the flow", trashed)
+			}
+
+			// A flow saved under the same ID since blocks the restore
+			if err := store.SaveFlow(ctx, &models.Flow{ID: "f", Name: "new"}); err != nil {
+				t.Fatalf("save flow: %v", err)
+			}
+			if _, err := store.RestoreFlow(ctx, "f"); !errors.Is(err, ErrExists) {
+				t.Errorf("restore over a new flow error = %v, want ErrExists", err)
+			}
+			if err := store.DeleteFlow(ctx, "f"); err != nil {
+				t.Fatalf("delete flow: %v", err)
+			}
+
+			restored, err := store.RestoreFlow(ctx, "f")
+			if err != nil {
+				t.Fatalf("restore flow: %v", err)
+			}
+			if restored.Name != "kept" {
+				t.Errorf("restored %q, want the trashed flow", restored.Name)
+			}
+			if flow, err := store.LoadFlow(ctx, "f"); err != nil || flow.Name != "kept" {
+				t.Errorf("load restored flow = %v, %v", flow, err)
+			}
+			if trashed, _ := store.ListTrash(ctx); len(trashed) != 0 {
+				t.Errorf("trash holds %d flows after the restore", len(trashed))
+			}
+			if _, err := store.RestoreFlow(ctx, "f"); !IsNotFound(err) {
+				t.Errorf("second restore error = %v, want not found", err)
+			}
+		})
+	}
+}
+
+func TestTrashUndecodableFlow(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	raw := []byte(`{"id": "f", "nodes": "none"}`)
+	if err := os.MkdirAll(filepath.Join(dir, "flows"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "flows", "f.json"), raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := NewFileStorage(dir)
+
+	if err := store.TrashFlow(ctx, "f"); err != nil {
+		t.Fatalf("trash flow: %v", err)
+	}
+	trashed, err := store.ListTrash(ctx)
+	if err != nil {
+		t.Fatalf("list trash: %v", err)
+	}
+	if len(trashed) != 1 || trashed[0].Flow != nil || string(trashed[0].Raw) != string(raw) {
+		t.Fatalf("trash = %+v, want the raw bytes", trashed)
+	}
+
+	if _, err := store.RestoreFlow(ctx, "f"); err != nil {
+		t.Fatalf("restore flow: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "flows", "f.json"))
+	if err != nil || string(data) != string(raw) {
+		t.Errorf("restored file = %q, %v; want the raw bytes", data, err)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileStorage(t.TempDir())
+	for _, id := range []string{"old", "new"} {
+		if err := store.SaveFlow(ctx, &models.Flow{ID: id, Name: id}); err != nil {
+			t.Fatalf("save flow: %v", err)
+		}
+	}
+
+	if err := store.TrashFlow(ctx, "old"); err != nil {
+		t.Fatalf("trash flow: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	cutoff := time.Now()
+	if err := store.TrashFlow(ctx, "new"); err != nil {
+		t.Fatalf("trash flow: %v", err)
+	}
+
+	purged, err := store.PurgeTrash(ctx, cutoff)
+	if err != nil {
+		t.Fatalf("purge trash: %v", err)
+	}
+	if len(purged) != 1 || purged[0] != "old" {
+		t.Errorf("purged %v, want [old]", purged)
+	}
+	trashed, _ := store.ListTrash(ctx)
+	if len(trashed) != 1 || trashed[0].ID != "new" {
+		t.Errorf("trash = %+v, want only new", trashed)
+	}
+}
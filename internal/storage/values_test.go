@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestValues(t *testing.T) {
+	backends := map[string]func(t *testing.T) Storage{
+		"memory": func(*testing.T) Storage { return NewMemoryStorage() },
+		"file":   func(t *testing.T) Storage { return NewFileStorage(t.TempDir()) },
+	}
+
+	type set struct {
+		namespace, key, value string
+	}
+	tests := []struct {
+		name      string
+		sets      []set
+		deletes   [][2]string // Namespace and key
+		namespace string
+		key       string
+		want      string
+		wantMiss  bool
+	}{
+		{name: "set then get", sets: []set{{"f1", "k", "v"}}, namespace: "f1", key: "k", want: "v"},
+		{name: "overwrite", sets: []set{{"f1", "k", "v1"}, {"f1", "k", "v2"}}, namespace: "f1", key: "k", want: "v2"},
+		{name: "miss", sets: []set{{"f1", "k", "v"}}, namespace: "f1", key: "other", wantMiss: true},
+		{name: "namespaced", sets: []set{{"f1", "k", "v"}}, namespace: "f2", key: "k", wantMiss: true},
+		{name: "same key in two namespaces", sets: []set{{"f1", "k", "v1"}, {"f2", "k", "v2"}}, namespace: "f2", key: "k", want: "v2"},
+		{name: "deleted", sets: []set{{"f1", "k", "v"}}, deletes: [][2]string{{"f1", "k"}}, namespace: "f1", key: "k", wantMiss: true},
+		{name: "key with path separators", sets: []set{{"f1", "../a/b", "v"}}, namespace: "f1", key: "../a/b", want: "v"},
+		{name: "dot keys don't collide", sets: []set{{"f1", "..", "v1"}, {"f1", ".", "v2"}}, namespace: "f1", key: "..", want: "v1"},
+	}
+
+	for backend, newStorage := range backends {
+		for _, tt := range tests {
+			t.Run(backend+"/"+tt.name, func(t *testing.T) {
+				ctx := context.Background()
+				store := newStorage(t)
+				for _, s := range tt.sets {
+					if err := store.SetValue(ctx, s.namespace, s.key, s.value); err != nil {
+						t.Fatalf("set %s/%s: %v", s.namespace, s.key, err)
+					}
+				}
+				for _, d := range tt.deletes {
+					if err := store.DeleteValue(ctx, d[0], d[1]); err != nil {
+						t.Fatalf("delete %s/%s: %v", d[0], d[1], err)
+					}
+				}
+
+				var got string
+				err := store.GetValue(ctx, tt.namespace, tt.key, &got)
+				if tt.wantMiss {
+					if !errors.Is(err, os.ErrNotExist) {
+						t.Errorf("get = (%q, %v), want a not-found error", got, err)
+					}
+					return
+				}
+				if err != nil || got != tt.want {
+					t.Errorf("get = (%q, %v), want %q", got, err, tt.want)
+				}
+			})
+		}
+	}
+}
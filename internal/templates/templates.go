@@ -0,0 +1,115 @@
+// Package templates provides the built-in catalog of flow templates users
+// can start from. Each template is a flow definition embedded as JSON; its
+// file name is the template name
+package templates
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"block-flow/internal/models"
+)
+
+//go:embed catalog/*.json
+var catalogFS embed.FS
+
+// Template is a catalog entry
+type Template struct {
+	Name        string       `json:"name"`
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	Nodes       int          `json:"nodes"`
+	Flow        *models.Flow `json:"flow,omitempty"`
+}
+
+var (
+	catalog     map[string]*models.Flow
+	catalogOnce sync.Once
+)
+
+// load parses the embedded templates. They are part of the build, so a
+// malformed template is a programming error
+func load() map[string]*models.Flow {
+	catalogOnce.Do(func() {
+		catalog = make(map[string]*models.Flow)
+		files, err := catalogFS.ReadDir("catalog")
+		if err != nil {
+			panic(fmt.Sprintf("templates: %v", err))
+		}
+		for _, file := range files {
+			data, err := catalogFS.ReadFile(path.Join("catalog", file.Name()))
+			if err != nil {
+				panic(fmt.Sprintf("templates: %v", err))
+			}
+			var flow models.Flow
+			if err := json.Unmarshal(data, &flow); err != nil {
+				panic(fmt.Sprintf("templates: invalid template '%s': %v", file.Name(), err))
+			}
+			catalog[strings.TrimSuffix(file.Name(), ".json")] = &flow
+		}
+	})
+	return catalog
+}
+
+// List returns the catalog sorted by name, without the flow definitions
+func List() []Template {
+	flows := load()
+	list := make([]Template, 0, len(flows))
+	for name, flow := range flows {
+		list = append(list, Template{
+			Name:        name,
+			Title:       flow.Name,
+			Description: flow.Description,
+			Nodes:       len(flow.Nodes),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Get returns a template with its flow definition
+func Get(name string) (*Template, bool) {
+	flow, ok := load()[name]
+	if !ok {
+		return nil, false
+	}
+	return &Template{
+		Name:        name,
+		Title:       flow.Name,
+		Description: flow.Description,
+		Nodes:       len(flow.Nodes),
+		Flow:        flow,
+	}, true
+}
+
+// Instantiate returns a new flow built from a template, with fresh node
+// and connection IDs. The caller assigns the flow ID. The template itself
+// is not modified
+func Instantiate(name string) (*models.Flow, bool) {
+	template, ok := load()[name]
+	if !ok {
+		return nil, false
+	}
+
+	// Round-trip through JSON for a deep copy
+	data, err := json.Marshal(template)
+	if err != nil {
+		panic(fmt.Sprintf("templates: %v", err))
+	}
+	var flow models.Flow
+	if err := models.DecodeJSON(data, &flow); err != nil {
+		panic(fmt.Sprintf("templates: %v", err))
+	}
+
+	flow.Normalize()
+	flow.RemapNodeIDs()
+	flow.CreatedAt = flow.UpdatedAt
+	return &flow, true
+}
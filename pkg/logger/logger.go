@@ -6,15 +6,22 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// Init initializes the global logger with structured logging
-func Init() {
+// Init initializes the global logger with structured logging. level is one of
+// debug, info, warn or error; format is json or text
+func Init(level, format string) {
 	// Set log format
-	logrus.SetFormatter(&logrus.JSONFormatter{
-		TimestampFormat: "2006-01-02T15:04:05.000Z07:00",
-	})
+	if format == "text" {
+		logrus.SetFormatter(&logrus.TextFormatter{
+			FullTimestamp:   true,
+			TimestampFormat: "2006-01-02T15:04:05.000Z07:00",
+		})
+	} else {
+		logrus.SetFormatter(&logrus.JSONFormatter{
+			TimestampFormat: "2006-01-02T15:04:05.000Z07:00",
+		})
+	}
 
 	// Set log level
-	level := os.Getenv("LOG_LEVEL")
 	switch level {
 	case "debug":
 		logrus.SetLevel(logrus.DebugLevel)
@@ -44,3 +51,32 @@ func (hook *DefaultFieldsHook) Fire(entry *logrus.Entry) error {
 	entry.Data["service"] = "block-flow"
 	return nil
 }
+
+// Logger writes structured messages to the global logrus logger, masking
+// sensitive fields (see SetRedactPatterns). It satisfies the engine's Logger
+type Logger struct{}
+
+// New creates a logger writing through logrus
+func New() *Logger {
+	return &Logger{}
+}
+
+// Debug logs debug messages
+func (l *Logger) Debug(message string, fields map[string]interface{}) {
+	logrus.WithFields(RedactFields(fields)).Debug(message)
+}
+
+// Info logs info messages
+func (l *Logger) Info(message string, fields map[string]interface{}) {
+	logrus.WithFields(RedactFields(fields)).Info(message)
+}
+
+// Warn logs warning messages
+func (l *Logger) Warn(message string, fields map[string]interface{}) {
+	logrus.WithFields(RedactFields(fields)).Warn(message)
+}
+
+// Error logs error messages
+func (l *Logger) Error(message string, fields map[string]interface{}) {
+	logrus.WithFields(RedactFields(fields)).Error(message)
+}
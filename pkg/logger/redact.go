@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"net/url"
+	"path"
+	"strings"
+	"sync/atomic"
+)
+
+// Redacted replaces the value of sensitive log fields
+const Redacted = "[REDACTED]"
+
+// DefaultRedactPatterns match common secret-bearing field names
+var DefaultRedactPatterns = []string{
+	"*password*",
+	"*passwd*",
+	"*secret*",
+	"*token*",
+	"*api_key*",
+	"*apikey*",
+	"*credential*",
+	"*private_key*",
+	"authorization",
+	"cookie",
+	"set-cookie",
+}
+
+// redactPatterns holds the active glob patterns (path.Match syntax), lower case
+var redactPatterns atomic.Pointer[[]string]
+
+func init() {
+	SetRedactPatterns(DefaultRedactPatterns)
+}
+
+// SetRedactPatterns replaces the field-name patterns whose values are masked
+// in logs. Patterns use path.Match syntax and match case-insensitively
+func SetRedactPatterns(patterns []string) {
+	lowered := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern = strings.ToLower(strings.TrimSpace(pattern)); pattern != "" {
+			lowered = append(lowered, pattern)
+		}
+	}
+	redactPatterns.Store(&lowered)
+}
+
+// IsSensitive reports whether values logged under key must be masked
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range *redactPatterns.Load() {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactFields returns a copy of fields with sensitive values masked, nested
+// maps included. The input is not modified
+func RedactFields(fields map[string]interface{}) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+
+	redacted := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		if IsSensitive(key) {
+			redacted[key] = Redacted
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			redacted[key] = RedactFields(nested)
+			continue
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
+
+// RedactQuery encodes query parameters for logging with sensitive values masked
+func RedactQuery(values url.Values) string {
+	redacted := make(url.Values, len(values))
+	for key, vals := range values {
+		if !IsSensitive(key) {
+			redacted[key] = vals
+			continue
+		}
+		masked := make([]string, len(vals))
+		for i := range masked {
+			masked[i] = Redacted
+		}
+		redacted[key] = masked
+	}
+	return redacted.Encode()
+}
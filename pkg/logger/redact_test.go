@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestRedactFields(t *testing.T) {
+	fields := map[string]interface{}{
+		"password": "hunter2",
+		"flow_id":  "flow-1",
+		"count":    3,
+		"request": map[string]interface{}{
+			"Auth_Token": "abc",
+			"path":       "/api/v1/flows",
+		},
+	}
+
+	got := RedactFields(fields)
+	want := map[string]interface{}{
+		"password": Redacted,
+		"flow_id":  "flow-1",
+		"count":    3,
+		"request": map[string]interface{}{
+			"Auth_Token": Redacted,
+			"path":       "/api/v1/flows",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RedactFields = %v, want %v", got, want)
+	}
+	if fields["password"] != "hunter2" {
+		t.Error("RedactFields modified its input")
+	}
+}
+
+func TestRedactQuery(t *testing.T) {
+	query := url.Values{
+		"client_secret": {"s1", "s2"},
+		"limit":         {"10"},
+	}
+
+	got, err := url.ParseQuery(RedactQuery(query))
+	if err != nil {
+		t.Fatalf("parse redacted query: %v", err)
+	}
+	want := url.Values{
+		"client_secret": {Redacted, Redacted},
+		"limit":         {"10"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RedactQuery = %v, want %v", got, want)
+	}
+}